@@ -12,10 +12,14 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/fastly/mcp/internal/cache"
 	"github.com/fastly/mcp/internal/fastly"
@@ -39,6 +43,184 @@ func buildCustomValidator(allowedCommands, deniedCommands map[string]bool) *vali
 	return validation.NewValidatorWithCommandsAndDenied(allowedCommands, deniedCommands)
 }
 
+// validatorSources returns the source of each allowlist and denylist entry of
+// the validator buildCustomValidator makes from lists with these sources,
+// including the default lists it falls back to and the disabled families.
+func validatorSources(allowedSources, deniedSources map[string]string) (map[string]string, map[string]string) {
+	if allowedSources == nil {
+		allowedSources = validation.RecordSources(nil, validation.DefaultAllowedCommands(), validation.SourceDefault)
+	}
+	if len(disabledFamilies) > 0 {
+		if deniedSources == nil {
+			deniedSources = validation.RecordSources(nil, validation.DefaultDeniedCommands(), validation.SourceDefault)
+		}
+		deniedSources = validation.RecordSources(deniedSources, disabledFamilies, validation.SourceDisabledFamily)
+	}
+	return allowedSources, deniedSources
+}
+
+// disabledFamilies holds the command families turned off with --disable-family.
+var disabledFamilies map[string]bool
+
+// withDisabledFamilies adds the disabled command families to a denylist. If no
+// denylist was supplied, the families are layered on top of the default one.
+func withDisabledFamilies(deniedCommands map[string]bool) map[string]bool {
+	if len(disabledFamilies) == 0 {
+		return deniedCommands
+	}
+
+	if deniedCommands == nil {
+		deniedCommands = validation.DefaultDeniedCommands()
+	}
+	for family := range disabledFamilies {
+		deniedCommands[family] = true
+	}
+
+	return deniedCommands
+}
+
+// globalValueOptions lists global options that take a value and are parsed with
+// takeValueOption. runCLIMode uses it to skip them when locating the CLI command.
+var globalValueOptions = map[string]bool{
+	"--strip-flags":            true,
+	"--per-page-defaults":      true,
+	"--cache-policy":           true,
+	"--cache-dir":              true,
+	"--cache-threshold":        true,
+	"--cache-max-entries":      true,
+	"--cache-max-bytes":        true,
+	"--max-output-size":        true,
+	"--max-array-items":        true,
+	"--create-flags":           true,
+	"--mask-json-paths":        true,
+	"--env-allowlist":          true,
+	"--output-file-flags":      true,
+	"--first-output-timeout":   true,
+	"--max-retries":            true,
+	"--retry-base-delay":       true,
+	"--auth-breaker-threshold": true,
+	"--auth-breaker-window":    true,
+	"--slow-command-threshold": true,
+	"--max-command-line-flags": true,
+	"--max-command-timeout":    true,
+	"--command-timeout":        true,
+	"--command-timeouts-file":  true,
+	"--danger-policy-file":     true,
+	"--policies-file":          true,
+	"--http-auth-token":        true,
+	"--cors-origin":            true,
+	"--shutdown-grace-period":  true,
+	"--log-redaction":          true,
+	"--non-interactive-mode":   true,
+	"--compute-preset":         true,
+	"--disable-family":         true,
+	"--logging-providers":      true,
+	"--item-soft-limit":        true,
+	"--json-warning-lines":     true,
+	"--summarize-lists":        true,
+	"--max-request-items":      true,
+	"--schema-version":         true,
+	"--result-list-limit":      true,
+	"--tool-prefix":            true,
+	"--export-dir":             true,
+	"--profile":                true,
+}
+
+// globalBoolOptions lists global boolean options that are parsed with
+// takeBoolOption. runCLIMode uses it to skip them when locating the CLI command.
+var globalBoolOptions = map[string]bool{
+	"--validate-before-activate": true,
+	"--allow-self-management":    true,
+	"--normalize-booleans":       true,
+	"--normalize-service-ids":    true,
+	"--purge-all-preflight":      true,
+	"--isolate-env":              true,
+	"--hide-denied-commands":     true,
+	"--review-mutating":          true,
+	"--json-errors":              true,
+	"--strict-purge-batch":       true,
+	"--allow-raw-args":           true,
+	"--log-commands-per-token":   true,
+	"--deterministic-result-ids": true,
+	"--explain":                  true,
+	"--safe-mode":                true,
+	"--unsafe":                   true,
+	"--read-only":                true,
+}
+
+// takeBoolOption handles a global boolean option. It returns true if the
+// argument at index i was the option, and exits if it is given more than once.
+func takeBoolOption(name string, i int, dest *bool) bool {
+	if os.Args[i] != name {
+		return false
+	}
+	if *dest {
+		fmt.Fprintf(os.Stderr, "Error: %s specified multiple times\n", name)
+		os.Exit(1)
+	}
+	*dest = true
+	return true
+}
+
+// takeValueOption handles a global option that requires a value, accepting both
+// "--name value" and "--name=value". It returns false if os.Args[*i] is not the
+// option. A missing value or a repeated option is fatal, like the other options.
+func takeValueOption(name, valueHint string, i *int, dest *string) bool {
+	arg := os.Args[*i]
+
+	var value string
+	switch {
+	case strings.HasPrefix(arg, name+"="):
+		value = strings.TrimPrefix(arg, name+"=")
+	case arg == name:
+		if *i+1 < len(os.Args) && !strings.HasPrefix(os.Args[*i+1], "-") {
+			value = os.Args[*i+1]
+			*i++
+		}
+	default:
+		return false
+	}
+
+	if *dest != "" {
+		fmt.Fprintf(os.Stderr, "Error: %s specified multiple times\n", name)
+		os.Exit(1)
+	}
+	if value == "" {
+		fmt.Fprintf(os.Stderr, "Error: %s requires %s\n", name, valueHint)
+		os.Exit(1)
+	}
+	*dest = value
+	return true
+}
+
+// isGlobalOption reports whether arg is one of the options in globalValueOptions
+// or globalBoolOptions, and whether the following argument is its value and must
+// be skipped as well.
+func isGlobalOption(arg string) (isOption bool, skipNext bool) {
+	if globalBoolOptions[arg] {
+		return true, false
+	}
+	if globalValueOptions[arg] {
+		return true, true
+	}
+	if name, _, found := strings.Cut(arg, "="); found && globalValueOptions[name] {
+		return true, false
+	}
+	return false, false
+}
+
+// splitList splits a comma-separated option value, trimming whitespace and
+// dropping empty entries.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // main is the application entry point that determines the execution mode based on command-line arguments.
 // It supports:
 //   - Default: MCP server over stdio
@@ -49,6 +231,7 @@ func main() {
 	var (
 		httpAddr             string
 		useSSE               bool
+		allowRemoteBind      bool
 		showHelp             bool
 		sanitize             bool
 		allowedCmdsFile      string
@@ -58,6 +241,65 @@ func main() {
 		encryptTokens        bool
 		logCommandsFile      string
 		outputCacheThreshold int
+		stripFlags           string
+		perPageDefaults      string
+		cachePolicies        string
+		cacheDir             string
+		cacheThreshold       string
+		cacheMaxEntries      string
+		cacheMaxBytes        string
+		maxOutputSize        string
+		maxArrayItems        string
+		createFlags          string
+		maskJSONPaths        string
+		envAllowlist         string
+		outputFileFlags      string
+		firstOutputTimeout   string
+		maxRetries           string
+		retryBaseDelay       string
+		authBreakerThreshold string
+		authBreakerWindow    string
+		slowCmdThreshold     string
+		maxCmdLineFlags      string
+		maxCommandTimeout    string
+		commandTimeout       string
+		commandTimeoutsFile  string
+		dangerPolicyFile     string
+		policiesFile         string
+		httpAuthToken        string
+		corsOrigin           string
+		shutdownGracePeriod  string
+		logRedaction         string
+		nonInteractiveMode   string
+		computePreset        string
+		disableFamily        string
+		loggingProviders     string
+		itemSoftLimit        string
+		jsonWarningLines     string
+		summarizeLists       string
+		maxRequestItems      string
+		schemaVersion        string
+		resultListLimit      string
+		toolPrefix           string
+		exportDir            string
+		profile              string
+		validateActivate     bool
+		allowSelfManagement  bool
+		normalizeBooleans    bool
+		normalizeServiceIDs  bool
+		purgeAllPreflight    bool
+		isolateEnv           bool
+		hideDeniedCommands   bool
+		reviewMutating       bool
+		jsonErrors           bool
+		strictPurgeBatch     bool
+		allowRawArgs         bool
+		logPerToken          bool
+		deterministicIDs     bool
+		explainMode          bool
+		safeMode             bool
+		unsafeMode           bool
+		readOnly             bool
 	)
 
 	// Parse and validate all arguments
@@ -179,9 +421,527 @@ func main() {
 			}
 			continue
 		}
+		if takeValueOption("--strip-flags", "a comma-separated list of flag names", &i, &stripFlags) {
+			continue
+		}
+		if takeValueOption("--per-page-defaults", "a comma-separated list of 'command=per-page' entries", &i, &perPageDefaults) {
+			continue
+		}
+		if takeValueOption("--cache-policy", "a comma-separated list of 'command=always|never' entries", &i, &cachePolicies) {
+			continue
+		}
+		if takeValueOption("--cache-dir", "a directory path", &i, &cacheDir) {
+			continue
+		}
+		if takeValueOption("--cache-threshold", "a number of bytes", &i, &cacheThreshold) {
+			continue
+		}
+		if takeValueOption("--cache-max-entries", "a number of results", &i, &cacheMaxEntries) {
+			continue
+		}
+		if takeValueOption("--cache-max-bytes", "a number of bytes", &i, &cacheMaxBytes) {
+			continue
+		}
+		if takeValueOption("--max-output-size", "a number of bytes", &i, &maxOutputSize) {
+			continue
+		}
+		if takeValueOption("--max-array-items", "a number of items", &i, &maxArrayItems) {
+			continue
+		}
+		if takeValueOption("--create-flags", "a comma-separated list of 'flag=value' entries", &i, &createFlags) {
+			continue
+		}
+		if takeValueOption("--mask-json-paths", "a comma-separated list of JSON field paths", &i, &maskJSONPaths) {
+			continue
+		}
+		if takeValueOption("--env-allowlist", "a comma-separated list of environment variable names", &i, &envAllowlist) {
+			continue
+		}
+		if takeValueOption("--output-file-flags", "a comma-separated list of flag names", &i, &outputFileFlags) {
+			continue
+		}
+		if takeValueOption("--first-output-timeout", "a number of seconds", &i, &firstOutputTimeout) {
+			continue
+		}
+		if takeValueOption("--max-retries", "a number of retries", &i, &maxRetries) {
+			continue
+		}
+		if takeValueOption("--retry-base-delay", "a duration such as 500ms or 2s", &i, &retryBaseDelay) {
+			continue
+		}
+		if takeValueOption("--auth-breaker-threshold", "a number of failures", &i, &authBreakerThreshold) {
+			continue
+		}
+		if takeValueOption("--auth-breaker-window", "a duration such as 30s or 5m", &i, &authBreakerWindow) {
+			continue
+		}
+		if takeValueOption("--slow-command-threshold", "a number of seconds", &i, &slowCmdThreshold) {
+			continue
+		}
+		if takeValueOption("--max-command-line-flags", "a number of flags", &i, &maxCmdLineFlags) {
+			continue
+		}
+		if takeValueOption("--max-command-timeout", "a number of seconds", &i, &maxCommandTimeout) {
+			continue
+		}
+		if takeValueOption("--command-timeout", "a duration such as 90s or 2m", &i, &commandTimeout) {
+			continue
+		}
+		if takeValueOption("--command-timeouts-file", "a JSON file path", &i, &commandTimeoutsFile) {
+			continue
+		}
+		if takeValueOption("--danger-policy-file", "a JSON file path", &i, &dangerPolicyFile) {
+			continue
+		}
+		if takeValueOption("--policies-file", "a JSON file path", &i, &policiesFile) {
+			continue
+		}
+		if takeValueOption("--http-auth-token", "a comma-separated list of tokens", &i, &httpAuthToken) {
+			continue
+		}
+		if takeValueOption("--cors-origin", "'*' or a comma-separated list of origins", &i, &corsOrigin) {
+			continue
+		}
+		if takeValueOption("--shutdown-grace-period", "a duration such as 20s", &i, &shutdownGracePeriod) {
+			continue
+		}
+		if takeValueOption("--log-redaction", "'full', 'redact-secrets' or 'redact-all-values'", &i, &logRedaction) {
+			continue
+		}
+		if takeValueOption("--non-interactive-mode", "'auto', 'always' or 'never'", &i, &nonInteractiveMode) {
+			continue
+		}
+		if takeValueOption("--compute-preset", "a list of settings", &i, &computePreset) {
+			continue
+		}
+		if takeValueOption("--disable-family", "a comma-separated list of command families", &i, &disableFamily) {
+			continue
+		}
+		if takeValueOption("--logging-providers", "a comma-separated list of logging providers", &i, &loggingProviders) {
+			continue
+		}
+		if takeValueOption("--item-soft-limit", "a number of items", &i, &itemSoftLimit) {
+			continue
+		}
+		if takeValueOption("--json-warning-lines", "a number of lines", &i, &jsonWarningLines) {
+			continue
+		}
+		if takeValueOption("--summarize-lists", "a number of items", &i, &summarizeLists) {
+			continue
+		}
+		if takeValueOption("--max-request-items", "a number of args and flags", &i, &maxRequestItems) {
+			continue
+		}
+		if takeValueOption("--schema-version", "a response schema version", &i, &schemaVersion) {
+			continue
+		}
+		if takeValueOption("--result-list-limit", "a number of results", &i, &resultListLimit) {
+			continue
+		}
+		if takeValueOption("--tool-prefix", "a tool name prefix", &i, &toolPrefix) {
+			continue
+		}
+		if takeValueOption("--export-dir", "a directory path", &i, &exportDir) {
+			continue
+		}
+		if takeValueOption("--profile", "a Fastly CLI profile name", &i, &profile) {
+			continue
+		}
+		if takeBoolOption("--validate-before-activate", i, &validateActivate) {
+			continue
+		}
+		if takeBoolOption("--allow-self-management", i, &allowSelfManagement) {
+			continue
+		}
+		if takeBoolOption("--safe-mode", i, &safeMode) {
+			continue
+		}
+		if takeBoolOption("--unsafe", i, &unsafeMode) {
+			continue
+		}
+		if takeBoolOption("--read-only", i, &readOnly) {
+			continue
+		}
+		if takeBoolOption("--normalize-booleans", i, &normalizeBooleans) {
+			continue
+		}
+		if takeBoolOption("--normalize-service-ids", i, &normalizeServiceIDs) {
+			continue
+		}
+		if takeBoolOption("--purge-all-preflight", i, &purgeAllPreflight) {
+			continue
+		}
+		if takeBoolOption("--isolate-env", i, &isolateEnv) {
+			continue
+		}
+		if takeBoolOption("--hide-denied-commands", i, &hideDeniedCommands) {
+			continue
+		}
+		if takeBoolOption("--review-mutating", i, &reviewMutating) {
+			continue
+		}
+		if takeBoolOption("--json-errors", i, &jsonErrors) {
+			continue
+		}
+		if takeBoolOption("--strict-purge-batch", i, &strictPurgeBatch) {
+			continue
+		}
+		if takeBoolOption("--allow-raw-args", i, &allowRawArgs) {
+			continue
+		}
+		if takeBoolOption("--log-commands-per-token", i, &logPerToken) {
+			continue
+		}
+		if takeBoolOption("--deterministic-result-ids", i, &deterministicIDs) {
+			continue
+		}
+		if takeBoolOption("--explain", i, &explainMode) {
+			continue
+		}
 		args = append(args, arg)
 	}
 
+	// Apply options that both CLI mode and server mode honor
+	if stripFlags != "" {
+		fastly.SetStripFlags(splitList(stripFlags))
+	}
+	if perPageDefaults != "" {
+		defaults, err := mcp.ParsePerPageDefaults(perPageDefaults)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --per-page-defaults: %v\n", err)
+			os.Exit(1)
+		}
+		mcp.SetPerPageDefaults(defaults)
+	}
+	if cachePolicies != "" {
+		policies, err := cache.ParseCommandCachePolicies(cachePolicies)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --cache-policy: %v\n", err)
+			os.Exit(1)
+		}
+		cache.SetCommandCachePolicies(policies)
+	}
+	if cacheThreshold != "" && outputCacheThreshold != 0 {
+		fmt.Fprintf(os.Stderr, "Error: --cache-threshold and --output-cache-threshold cannot be combined\n")
+		os.Exit(1)
+	}
+	if cacheThreshold == "" && outputCacheThreshold == 0 {
+		cacheThreshold = os.Getenv("FASTLY_MCP_CACHE_THRESHOLD")
+	}
+	if cacheThreshold != "" {
+		threshold, err := strconv.Atoi(cacheThreshold)
+		if err != nil || threshold < cache.MinOutputCacheThreshold {
+			fmt.Fprintf(os.Stderr, "Error: --cache-threshold (or FASTLY_MCP_CACHE_THRESHOLD) requires an integer of at least %d (bytes)\n", cache.MinOutputCacheThreshold)
+			os.Exit(1)
+		}
+		outputCacheThreshold = threshold
+	}
+	if maxOutputSize != "" {
+		size, err := strconv.Atoi(maxOutputSize)
+		if err != nil || size <= 0 {
+			fmt.Fprintf(os.Stderr, "Error: --max-output-size requires a positive integer (bytes)\n")
+			os.Exit(1)
+		}
+		fastly.SetMaxOutputSize(size)
+	}
+	if maxArrayItems != "" {
+		items, err := strconv.Atoi(maxArrayItems)
+		if err != nil || items <= 0 {
+			fmt.Fprintf(os.Stderr, "Error: --max-array-items requires a positive integer (items)\n")
+			os.Exit(1)
+		}
+		fastly.SetMaxJSONArrayItems(items)
+	}
+	if cacheMaxEntries != "" {
+		entries, err := strconv.Atoi(cacheMaxEntries)
+		if err != nil || entries <= 0 {
+			fmt.Fprintf(os.Stderr, "Error: --cache-max-entries requires a positive integer (results)\n")
+			os.Exit(1)
+		}
+		cache.SetMaxEntries(entries)
+	}
+	if cacheMaxBytes != "" {
+		bytes, err := strconv.Atoi(cacheMaxBytes)
+		if err != nil || bytes <= 0 {
+			fmt.Fprintf(os.Stderr, "Error: --cache-max-bytes requires a positive integer (bytes)\n")
+			os.Exit(1)
+		}
+		cache.SetMaxBytes(bytes)
+	}
+	if cacheDir != "" {
+		if err := cache.SetCacheDir(cacheDir); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --cache-dir: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if createFlags != "" {
+		flags, err := fastly.ParseCreateFlags(createFlags)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --create-flags: %v\n", err)
+			os.Exit(1)
+		}
+		fastly.SetCreateFlags(flags)
+	}
+	if maskJSONPaths != "" {
+		fastly.SetMaskPaths(splitList(maskJSONPaths))
+	}
+	if isolateEnv || envAllowlist != "" {
+		fastly.SetEnvAllowlist(append([]string{}, splitList(envAllowlist)...))
+	}
+	if outputFileFlags != "" {
+		fastly.SetOutputFileFlags(splitList(outputFileFlags))
+	}
+	if firstOutputTimeout != "" {
+		seconds, err := strconv.Atoi(firstOutputTimeout)
+		if err != nil || seconds < 0 {
+			fmt.Fprintf(os.Stderr, "Error: --first-output-timeout requires a non-negative integer (seconds)\n")
+			os.Exit(1)
+		}
+		fastly.SetFirstOutputTimeout(time.Duration(seconds) * time.Second)
+	}
+	if maxRetries != "" {
+		retries, err := strconv.Atoi(maxRetries)
+		if err != nil || retries < 0 {
+			fmt.Fprintf(os.Stderr, "Error: --max-retries requires a non-negative integer (retries)\n")
+			os.Exit(1)
+		}
+		fastly.SetMaxRetries(retries)
+	}
+	if retryBaseDelay != "" {
+		delay, err := time.ParseDuration(retryBaseDelay)
+		if err != nil || delay <= 0 {
+			fmt.Fprintf(os.Stderr, "Error: --retry-base-delay requires a positive duration such as 500ms or 2s\n")
+			os.Exit(1)
+		}
+		fastly.SetRetryBaseDelay(delay)
+	}
+	if authBreakerThreshold != "" {
+		failures, err := strconv.Atoi(authBreakerThreshold)
+		if err != nil || failures < 0 {
+			fmt.Fprintf(os.Stderr, "Error: --auth-breaker-threshold requires a non-negative integer (failures)\n")
+			os.Exit(1)
+		}
+		fastly.SetAuthBreakerThreshold(failures)
+	}
+	if authBreakerWindow != "" {
+		window, err := time.ParseDuration(authBreakerWindow)
+		if err != nil || window <= 0 {
+			fmt.Fprintf(os.Stderr, "Error: --auth-breaker-window requires a positive duration such as 30s or 5m\n")
+			os.Exit(1)
+		}
+		fastly.SetAuthBreakerWindow(window)
+	}
+	if slowCmdThreshold != "" {
+		seconds, err := strconv.Atoi(slowCmdThreshold)
+		if err != nil || seconds < 0 {
+			fmt.Fprintf(os.Stderr, "Error: --slow-command-threshold requires a non-negative integer (seconds)\n")
+			os.Exit(1)
+		}
+		fastly.SetSlowCommandThreshold(time.Duration(seconds) * time.Second)
+	}
+	if maxCmdLineFlags != "" {
+		maxFlags, err := strconv.Atoi(maxCmdLineFlags)
+		if err != nil || maxFlags < 0 {
+			fmt.Fprintf(os.Stderr, "Error: --max-command-line-flags requires a non-negative integer (flags)\n")
+			os.Exit(1)
+		}
+		fastly.SetMaxCommandLineFlags(maxFlags)
+	}
+	if maxCommandTimeout != "" {
+		seconds, err := strconv.Atoi(maxCommandTimeout)
+		if err != nil || seconds <= 0 {
+			fmt.Fprintf(os.Stderr, "Error: --max-command-timeout requires a positive integer (seconds)\n")
+			os.Exit(1)
+		}
+		fastly.SetMaxCommandTimeout(time.Duration(seconds) * time.Second)
+	}
+	if commandTimeout == "" {
+		commandTimeout = os.Getenv("FASTLY_MCP_TIMEOUT")
+	}
+	if commandTimeout != "" {
+		timeout, err := time.ParseDuration(commandTimeout)
+		if err != nil || timeout <= 0 || timeout > fastly.MaxRequestTimeout() {
+			fmt.Fprintf(os.Stderr, "Error: --command-timeout (or FASTLY_MCP_TIMEOUT) requires a positive duration such as 90s or 2m, at most the maximum command timeout of %s\n", fastly.MaxRequestTimeout())
+			os.Exit(1)
+		}
+		fastly.SetCommandTimeout(timeout)
+	}
+	if commandTimeoutsFile != "" {
+		timeouts, err := fastly.LoadCommandTimeouts(commandTimeoutsFile, fastly.MaxRequestTimeout())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --command-timeouts-file: %v\n", err)
+			os.Exit(1)
+		}
+		fastly.SetCommandTimeouts(timeouts)
+	}
+	if dangerPolicyFile != "" {
+		policy, err := fastly.LoadDangerPolicy(dangerPolicyFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --danger-policy-file: %v\n", err)
+			os.Exit(1)
+		}
+		fastly.SetDangerPolicy(policy)
+	}
+	if itemSoftLimit != "" {
+		limit, err := strconv.Atoi(itemSoftLimit)
+		if err != nil || limit < 0 {
+			fmt.Fprintf(os.Stderr, "Error: --item-soft-limit requires a non-negative integer (items)\n")
+			os.Exit(1)
+		}
+		fastly.SetItemSoftLimit(limit)
+	}
+	if jsonWarningLines != "" {
+		lines, err := strconv.Atoi(jsonWarningLines)
+		if err != nil || lines < 0 {
+			fmt.Fprintf(os.Stderr, "Error: --json-warning-lines requires a non-negative integer (lines)\n")
+			os.Exit(1)
+		}
+		fastly.SetJSONWarningLines(lines)
+	}
+	if summarizeLists != "" {
+		items, err := strconv.Atoi(summarizeLists)
+		if err != nil || items <= 0 {
+			fmt.Fprintf(os.Stderr, "Error: --summarize-lists requires a positive integer (items)\n")
+			os.Exit(1)
+		}
+		fastly.SetListSummaryThreshold(items)
+	}
+	if maxRequestItems != "" {
+		max, err := strconv.Atoi(maxRequestItems)
+		if err != nil || max <= 0 {
+			fmt.Fprintf(os.Stderr, "Error: --max-request-items requires a positive integer (args and flags)\n")
+			os.Exit(1)
+		}
+		mcp.SetMaxRequestItems(max)
+	}
+	if schemaVersion != "" {
+		version, err := strconv.Atoi(schemaVersion)
+		if err != nil || version < types.SchemaVersion1 || version > types.CurrentSchemaVersion {
+			fmt.Fprintf(os.Stderr, "Error: --schema-version requires a version from %d to %d\n", types.SchemaVersion1, types.CurrentSchemaVersion)
+			os.Exit(1)
+		}
+		mcp.SetSchemaVersion(version)
+	}
+	if resultListLimit != "" {
+		limit, err := strconv.Atoi(resultListLimit)
+		if err != nil || limit <= 0 {
+			fmt.Fprintf(os.Stderr, "Error: --result-list-limit requires a positive integer (results)\n")
+			os.Exit(1)
+		}
+		mcp.SetResultListLimit(limit)
+	}
+	if toolPrefix != "" {
+		if err := mcp.SetToolPrefix(toolPrefix); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --tool-prefix: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if exportDir != "" {
+		if err := mcp.SetExportDir(exportDir); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --export-dir: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if profile != "" {
+		if err := fastly.SetProfile(profile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --profile: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if logRedaction != "" {
+		level, err := mcp.ParseLogRedaction(logRedaction)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --log-redaction: %v\n", err)
+			os.Exit(1)
+		}
+		mcp.SetLogRedaction(level)
+	}
+	if logPerToken {
+		if logCommandsFile == "" {
+			fmt.Fprintf(os.Stderr, "Error: --log-commands-per-token requires --log-commands\n")
+			os.Exit(1)
+		}
+		mcp.SetPerTokenCommandLogs(true)
+	}
+	if nonInteractiveMode != "" {
+		mode, err := fastly.ParseNonInteractiveMode(nonInteractiveMode)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --non-interactive-mode: %v\n", err)
+			os.Exit(1)
+		}
+		fastly.SetNonInteractiveMode(mode)
+	}
+	if computePreset != "" {
+		preset, err := fastly.ParseComputePreset(computePreset)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --compute-preset: %v\n", err)
+			os.Exit(1)
+		}
+		fastly.SetComputePreset(preset)
+	}
+	if disableFamily != "" {
+		families, err := validation.ParseDisabledFamilies(disableFamily)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --disable-family: %v\n", err)
+			os.Exit(1)
+		}
+		disabledFamilies = families
+	}
+	if loggingProviders != "" {
+		providers := splitList(loggingProviders)
+		if len(providers) == 0 {
+			fmt.Fprintf(os.Stderr, "Error: --logging-providers requires at least one provider, e.g. \"s3,bigquery\"\n")
+			os.Exit(1)
+		}
+		fastly.SetAllowedLoggingProviders(providers)
+	}
+	if validateActivate {
+		fastly.SetValidateBeforeActivate(true)
+	}
+	if explainMode {
+		fastly.SetExplainMode(true)
+	}
+	// Safe mode is the default; --safe-mode only makes it explicit
+	if safeMode && unsafeMode {
+		fmt.Fprintf(os.Stderr, "Error: --safe-mode and --unsafe cannot be combined\n")
+		os.Exit(1)
+	}
+	if allowSelfManagement && !unsafeMode {
+		fmt.Fprintf(os.Stderr, "Error: --allow-self-management requires --unsafe, as safe mode denies self-management commands\n")
+		os.Exit(1)
+	}
+	fastly.SetSafeMode(!unsafeMode)
+	if allowSelfManagement {
+		fastly.SetAllowSelfManagement(true)
+	}
+	if readOnly {
+		fastly.SetReadOnly(true)
+	}
+	if normalizeServiceIDs {
+		fastly.SetServiceIDNormalization(true)
+	}
+	if purgeAllPreflight {
+		fastly.SetPurgeAllPreflight(true)
+	}
+	if hideDeniedCommands {
+		fastly.SetHideDeniedCommands(true)
+	}
+	if reviewMutating {
+		fastly.SetReviewMutating(true)
+	}
+	if normalizeBooleans {
+		cache.SetBooleanNormalization(true)
+	}
+	if deterministicIDs {
+		cache.SetDeterministicIDs(true)
+	}
+	if strictPurgeBatch {
+		fastly.SetStrictPurgeBatch(true)
+	}
+	if allowRawArgs {
+		fastly.SetRawArgsEnabled(true)
+	}
+
 	// Second pass: process remaining arguments
 	for i := 0; i < len(args); i++ {
 		arg := args[i]
@@ -197,6 +957,12 @@ func main() {
 			} else {
 				httpAddr = "127.0.0.1:8080"
 			}
+		case "--allow-remote-bind":
+			if allowRemoteBind {
+				fmt.Fprintf(os.Stderr, "Error: --allow-remote-bind specified multiple times\n")
+				os.Exit(1)
+			}
+			allowRemoteBind = true
 		case "--sse":
 			if useSSE {
 				fmt.Fprintf(os.Stderr, "Error: --sse specified multiple times\n")
@@ -205,7 +971,7 @@ func main() {
 			useSSE = true
 		case "help", "--help", "-h":
 			showHelp = true
-		case "list-commands", "execute", "describe", "version":
+		case "list-commands", "execute", "describe", "version", "catalog", "diagnose":
 			// For CLI mode commands, validate all remaining arguments
 			// Need to reconstruct the command args for validation
 			cmdArgs := []string{arg}
@@ -214,7 +980,7 @@ func main() {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
 			}
-			runCLIMode(sanitize, encryptTokens)
+			runCLIMode(sanitize, encryptTokens, jsonErrors)
 			return
 		default:
 			fmt.Fprintf(os.Stderr, "Error: Unknown argument '%s'\n", arg)
@@ -228,9 +994,67 @@ func main() {
 		fmt.Fprintf(os.Stderr, "Error: --sse requires --http\n")
 		os.Exit(1)
 	}
+	if allowRemoteBind && httpAddr == "" {
+		fmt.Fprintf(os.Stderr, "Error: --allow-remote-bind requires --http\n")
+		os.Exit(1)
+	}
+	if policiesFile != "" {
+		// Stdio serves a single client, which keeps the global policy
+		if httpAddr == "" {
+			fmt.Fprintf(os.Stderr, "Error: --policies-file requires --http\n")
+			os.Exit(1)
+		}
+		policies, defaultPolicy, err := validation.LoadPolicies(policiesFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --policies-file: %v\n", err)
+			os.Exit(1)
+		}
+		mcp.SetHTTPPolicies(policies, defaultPolicy)
+	}
+	if httpAuthToken != "" && httpAddr == "" {
+		fmt.Fprintf(os.Stderr, "Error: --http-auth-token requires --http\n")
+		os.Exit(1)
+	}
+	if httpAuthToken == "" && httpAddr != "" {
+		httpAuthToken = os.Getenv("FASTLY_MCP_HTTP_AUTH_TOKEN")
+	}
+	if httpAuthToken != "" {
+		tokens := splitList(httpAuthToken)
+		if len(tokens) == 0 {
+			fmt.Fprintf(os.Stderr, "Error: --http-auth-token (or FASTLY_MCP_HTTP_AUTH_TOKEN) requires at least one token\n")
+			os.Exit(1)
+		}
+		mcp.SetHTTPAuthTokens(tokens)
+	}
+	if logPerToken && !mcp.HTTPAuthEnabled() {
+		fmt.Fprintf(os.Stderr, "Error: --log-commands-per-token requires --http-auth-token (or FASTLY_MCP_HTTP_AUTH_TOKEN)\n")
+		os.Exit(1)
+	}
+	if corsOrigin != "" {
+		if httpAddr == "" {
+			fmt.Fprintf(os.Stderr, "Error: --cors-origin requires --http\n")
+			os.Exit(1)
+		}
+		if err := mcp.SetCORSOrigins(splitList(corsOrigin)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --cors-origin: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if shutdownGracePeriod != "" {
+		if httpAddr == "" {
+			fmt.Fprintf(os.Stderr, "Error: --shutdown-grace-period requires --http\n")
+			os.Exit(1)
+		}
+		period, err := time.ParseDuration(shutdownGracePeriod)
+		if err != nil || period <= 0 {
+			fmt.Fprintf(os.Stderr, "Error: --shutdown-grace-period requires a positive duration such as 20s or 1m\n")
+			os.Exit(1)
+		}
+		mcp.SetShutdownGracePeriod(period)
+	}
 
 	if showHelp {
-		runCLIMode(sanitize, encryptTokens)
+		runCLIMode(sanitize, encryptTokens, jsonErrors)
 		return
 	}
 
@@ -247,6 +1071,7 @@ func main() {
 
 	// Load custom allowed commands from file and/or inline list
 	var allowedCommands map[string]bool
+	var allowedSources map[string]string
 
 	// Load from file if specified
 	if allowedCmdsFile != "" {
@@ -256,6 +1081,7 @@ func main() {
 			os.Exit(1)
 		}
 		allowedCommands = fileCommands
+		allowedSources = validation.RecordSources(allowedSources, fileCommands, validation.SourceFile)
 		fmt.Fprintf(os.Stderr, "Loaded %d allowed commands from %s\n", len(fileCommands), allowedCmdsFile)
 	}
 
@@ -267,6 +1093,8 @@ func main() {
 			os.Exit(1)
 		}
 
+		allowedSources = validation.RecordSources(allowedSources, inlineCommands, validation.SourceInline)
+
 		// Merge with existing commands (if any)
 		if allowedCommands == nil {
 			allowedCommands = inlineCommands
@@ -283,6 +1111,7 @@ func main() {
 
 	// Load custom denied commands from file and/or inline list
 	var deniedCommands map[string]bool
+	var deniedSources map[string]string
 
 	// Load from file if specified
 	if deniedCmdsFile != "" {
@@ -292,6 +1121,7 @@ func main() {
 			os.Exit(1)
 		}
 		deniedCommands = fileCommands
+		deniedSources = validation.RecordSources(deniedSources, fileCommands, validation.SourceFile)
 		fmt.Fprintf(os.Stderr, "Loaded %d denied commands from %s\n", len(fileCommands), deniedCmdsFile)
 	}
 
@@ -303,6 +1133,8 @@ func main() {
 			os.Exit(1)
 		}
 
+		deniedSources = validation.RecordSources(deniedSources, inlineCommands, validation.SourceInline)
+
 		// Merge with existing denied commands (if any)
 		if deniedCommands == nil {
 			deniedCommands = inlineCommands
@@ -318,7 +1150,8 @@ func main() {
 	}
 
 	// Set custom validator if command overrides were loaded
-	if customValidator := buildCustomValidator(allowedCommands, deniedCommands); customValidator != nil {
+	if customValidator := buildCustomValidator(allowedCommands, withDisabledFamilies(deniedCommands)); customValidator != nil {
+		customValidator.SetCommandSources(validatorSources(allowedSources, deniedSources))
 		fastly.SetCustomValidator(customValidator)
 	}
 
@@ -329,6 +1162,13 @@ func main() {
 
 	if httpAddr != "" {
 		addr := mcp.NormalizeAddress(httpAddr)
+		if err := mcp.ValidateBindAddress(addr, allowRemoteBind, mcp.HTTPAuthEnabled()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if warning := mcp.BindAddressWarning(addr, mcp.HTTPAuthEnabled()); warning != "" {
+			fmt.Fprintln(os.Stderr, warning)
+		}
 		mcp.RunHTTPServer(addr, useSSE, logCommandsFile)
 	} else {
 		runMCPServer(logCommandsFile)
@@ -344,7 +1184,7 @@ func validateCLIArgs(args []string) error {
 
 	command := args[0]
 	switch command {
-	case "help", "--help", "-h", "list-commands", "version":
+	case "help", "--help", "-h", "list-commands", "version", "catalog", "diagnose":
 		// These commands don't accept additional arguments
 		if len(args) > 1 {
 			return fmt.Errorf("command '%s' does not accept additional arguments", command)
@@ -383,9 +1223,11 @@ func runMCPServer(logCommandsFile string) {
 //   - list-commands: List all available Fastly operations
 //   - execute: Execute a Fastly command from JSON specification
 //   - describe: Get detailed help for a specific Fastly operation
+//   - catalog: Export the full parsed command catalog
+//   - diagnose: Report the setup step by step, without requiring a working setup
 //
 // This mode bypasses the MCP protocol for direct testing.
-func runCLIMode(sanitize bool, encryptTokens bool) {
+func runCLIMode(sanitize bool, encryptTokens bool, jsonErrors bool) {
 	// Set sanitization option for CLI mode
 	fastly.SetSanitizationEnabled(sanitize)
 
@@ -443,6 +1285,12 @@ func runCLIMode(sanitize bool, encryptTokens bool) {
 		if strings.HasPrefix(os.Args[i], "--output-cache-threshold=") {
 			continue // Skip the combined flag=value argument
 		}
+		if isOption, skipNext := isGlobalOption(os.Args[i]); isOption {
+			if skipNext && i+1 < len(os.Args) {
+				i++ // Skip the value argument too
+			}
+			continue
+		}
 		if command == "" {
 			command = os.Args[i]
 		} else {
@@ -453,7 +1301,9 @@ func runCLIMode(sanitize bool, encryptTokens bool) {
 	// Check if allowed and denied commands were specified in CLI mode
 	// This needs to happen before help command check to ensure proper loading
 	var cliAllowedCommands map[string]bool
+	var cliAllowedSources map[string]string
 	var cliDeniedCommands map[string]bool
+	var cliDeniedSources map[string]string
 
 	// First check for file-based allowed commands
 	for i := 1; i < len(os.Args); i++ {
@@ -465,6 +1315,7 @@ func runCLIMode(sanitize bool, encryptTokens bool) {
 				os.Exit(1)
 			}
 			cliAllowedCommands = fileCommands
+			cliAllowedSources = validation.RecordSources(cliAllowedSources, fileCommands, validation.SourceFile)
 			fmt.Fprintf(os.Stderr, "Loaded %d allowed commands from %s\n", len(fileCommands), allowedCmdsFile)
 			break
 		}
@@ -480,6 +1331,8 @@ func runCLIMode(sanitize bool, encryptTokens bool) {
 				os.Exit(1)
 			}
 
+			cliAllowedSources = validation.RecordSources(cliAllowedSources, inlineCommands, validation.SourceInline)
+
 			// Merge with existing commands (if any)
 			if cliAllowedCommands == nil {
 				cliAllowedCommands = inlineCommands
@@ -506,6 +1359,7 @@ func runCLIMode(sanitize bool, encryptTokens bool) {
 				os.Exit(1)
 			}
 			cliDeniedCommands = fileCommands
+			cliDeniedSources = validation.RecordSources(cliDeniedSources, fileCommands, validation.SourceFile)
 			fmt.Fprintf(os.Stderr, "Loaded %d denied commands from %s\n", len(fileCommands), deniedCmdsFile)
 			break
 		}
@@ -521,6 +1375,8 @@ func runCLIMode(sanitize bool, encryptTokens bool) {
 				os.Exit(1)
 			}
 
+			cliDeniedSources = validation.RecordSources(cliDeniedSources, inlineCommands, validation.SourceInline)
+
 			// Merge with existing denied commands (if any)
 			if cliDeniedCommands == nil {
 				cliDeniedCommands = inlineCommands
@@ -538,7 +1394,8 @@ func runCLIMode(sanitize bool, encryptTokens bool) {
 	}
 
 	// Set custom validator if command overrides were loaded
-	if customValidator := buildCustomValidator(cliAllowedCommands, cliDeniedCommands); customValidator != nil {
+	if customValidator := buildCustomValidator(cliAllowedCommands, withDisabledFamilies(cliDeniedCommands)); customValidator != nil {
+		customValidator.SetCommandSources(validatorSources(cliAllowedSources, cliDeniedSources))
 		fastly.SetCustomValidator(customValidator)
 	}
 
@@ -551,59 +1408,19 @@ func runCLIMode(sanitize bool, encryptTokens bool) {
 		printVersion()
 		return
 	}
+	if command == "diagnose" {
+		diagnoseSetup()
+		return
+	}
 	// Validate Fastly CLI is installed and authenticated
 	if err := fastly.CheckSetup(); err != nil {
-		// Check if Fastly CLI is not installed
-		if strings.Contains(err.Error(), "not found") {
-			// Output human-friendly message for missing CLI
-			fmt.Fprintf(os.Stderr, "Fastly CLI is not installed.\n\n")
-			fmt.Fprintf(os.Stderr, "To install the Fastly CLI:\n")
-			fmt.Fprintf(os.Stderr, "  1. Visit https://developer.fastly.com/reference/cli/\n")
-			fmt.Fprintf(os.Stderr, "  2. Follow the installation instructions for your operating system\n\n")
-			fmt.Fprintf(os.Stderr, "For macOS with Homebrew: brew install fastly/tap/fastly\n")
-			fmt.Fprintf(os.Stderr, "For other systems: Download from https://github.com/fastly/cli/releases\n")
-			os.Exit(1)
-		}
-
-		// Check if this is an authentication error
-		errMsg := strings.ToLower(err.Error())
-		if strings.Contains(errMsg, "not authenticated") ||
-			strings.Contains(errMsg, "no api token found") ||
-			strings.Contains(errMsg, "unauthorized") ||
-			strings.Contains(errMsg, "invalid token") ||
-			strings.Contains(err.Error(), `"authorized":false`) {
-			// Output human-friendly message for authentication errors
-			fmt.Fprintf(os.Stderr, "Authentication required for Fastly CLI.\n\n")
-			fmt.Fprintf(os.Stderr, "Please authenticate using the following steps:\n")
-			fmt.Fprintf(os.Stderr, "  1. Get your API token from https://manage.fastly.com/account/personal/tokens\n")
-			fmt.Fprintf(os.Stderr, "  2. Run 'fastly profile create' to set up authentication\n")
-			fmt.Fprintf(os.Stderr, "  3. Enter a profile name (e.g., 'default') and paste your API token\n\n")
-			fmt.Fprintf(os.Stderr, "Note: FASTLY_API_TOKEN environment variable is not recommended for MCP clients.\n")
-			fmt.Fprintf(os.Stderr, "For more information, visit: https://developer.fastly.com/reference/cli/\n")
-			os.Exit(1)
-		}
-
-		// For other errors, use JSON response
-		errorResponse := types.CommandResponse{
-			Success:      false,
-			Error:        err.Error(),
-			ErrorCode:    "setup_error",
-			Command:      "startup-check",
-			CommandLine:  "fastly service list --per-page 1",
-			Instructions: "The Fastly service is not properly configured. Please ensure proper setup before using these tools.",
-			NextSteps: []string{
-				"Ensure the Fastly CLI is installed on the system",
-				"Run 'fastly profile create' to set up authentication (recommended)",
-				"Get your API token from https://manage.fastly.com/account/personal/tokens",
-				"Check authentication status with 'fastly whoami'",
-			},
-		}
-
-		if strings.Contains(err.Error(), "not found") {
-			errorResponse.ErrorCode = "cli_not_found"
-		}
-		if err := prettyPrintJSON(errorResponse); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to encode error response: %v\n", err)
+		response, message := setupErrorResponse(err)
+		if jsonErrors || message == "" {
+			if err := prettyPrintJSON(response); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to encode error response: %v\n", err)
+			}
+		} else {
+			fmt.Fprint(os.Stderr, message)
 		}
 		os.Exit(1)
 	}
@@ -628,6 +1445,8 @@ func runCLIMode(sanitize bool, encryptTokens bool) {
 			os.Exit(1)
 		}
 		describeCommand(commandArgs)
+	case "catalog":
+		exportCatalog()
 	default:
 		fmt.Printf("Unknown command: %s\n", command)
 		printUsage()
@@ -635,6 +1454,104 @@ func runCLIMode(sanitize bool, encryptTokens bool) {
 	}
 }
 
+// cliNotInstalledMessage is the human-friendly text printed when the Fastly CLI
+// cannot be found.
+const cliNotInstalledMessage = `Fastly CLI is not installed.
+
+To install the Fastly CLI:
+  1. Visit https://developer.fastly.com/reference/cli/
+  2. Follow the installation instructions for your operating system
+
+For macOS with Homebrew: brew install fastly/tap/fastly
+For other systems: Download from https://github.com/fastly/cli/releases
+`
+
+// cliVersionUnsupportedMessage is the human-friendly text printed when the
+// Fastly CLI is older than the minimum supported version. It takes the detected
+// and the required version.
+const cliVersionUnsupportedMessage = `Fastly CLI version %s is not supported; version %s or later is required.
+
+To upgrade the Fastly CLI:
+  For macOS with Homebrew: brew upgrade fastly/tap/fastly
+  For other systems: Download from https://github.com/fastly/cli/releases
+`
+
+// authRequiredMessage is the human-friendly text printed when the Fastly CLI
+// is installed but not authenticated.
+const authRequiredMessage = `Authentication required for Fastly CLI.
+
+Please authenticate using the following steps:
+  1. Get your API token from https://manage.fastly.com/account/personal/tokens
+  2. Run 'fastly profile create' to set up authentication
+  3. Enter a profile name (e.g., 'default') and paste your API token
+
+Note: FASTLY_API_TOKEN environment variable is not recommended for MCP clients.
+For more information, visit: https://developer.fastly.com/reference/cli/
+`
+
+// setupErrorResponse converts a CheckSetup failure into a structured response.
+// For a missing CLI or missing authentication it also returns the human-friendly
+// message printed to stderr by default; other failures return an empty message
+// and are always reported as JSON. With --json-errors the response is printed
+// on stdout in every case.
+func setupErrorResponse(err error) (types.CommandResponse, string) {
+	response := types.CommandResponse{
+		Success:      false,
+		Error:        err.Error(),
+		ErrorCode:    "setup_error",
+		Command:      "startup-check",
+		CommandLine:  "fastly service list --per-page 1",
+		Instructions: "The Fastly service is not properly configured. Please ensure proper setup before using these tools.",
+		NextSteps: []string{
+			"Ensure the Fastly CLI is installed on the system",
+			"Run 'fastly profile create' to set up authentication (recommended)",
+			"Get your API token from https://manage.fastly.com/account/personal/tokens",
+			"Check authentication status with 'fastly whoami'",
+		},
+	}
+
+	var versionErr *fastly.CLIVersionError
+	if errors.As(err, &versionErr) {
+		response.ErrorCode = "cli_version_unsupported"
+		response.Instructions = fmt.Sprintf("The installed Fastly CLI version %s is older than the minimum supported version %s.", versionErr.Detected, versionErr.Required)
+		response.NextSteps = []string{
+			"For macOS with Homebrew: brew upgrade fastly/tap/fastly",
+			"For other systems: Download the latest release from https://github.com/fastly/cli/releases",
+			"Check the installed version with 'fastly version'",
+		}
+		return response, fmt.Sprintf(cliVersionUnsupportedMessage, versionErr.Detected, versionErr.Required)
+	}
+
+	if strings.Contains(err.Error(), "not found") {
+		response.ErrorCode = "cli_not_found"
+		response.Instructions = "The Fastly CLI is not installed."
+		response.NextSteps = []string{
+			"Visit https://developer.fastly.com/reference/cli/ and follow the installation instructions for your operating system",
+			"For macOS with Homebrew: brew install fastly/tap/fastly",
+			"For other systems: Download from https://github.com/fastly/cli/releases",
+		}
+		return response, cliNotInstalledMessage
+	}
+
+	errMsg := strings.ToLower(err.Error())
+	if strings.Contains(errMsg, "not authenticated") ||
+		strings.Contains(errMsg, "no api token found") ||
+		strings.Contains(errMsg, "unauthorized") ||
+		strings.Contains(errMsg, "invalid token") ||
+		strings.Contains(err.Error(), `"authorized":false`) {
+		response.ErrorCode = "auth_required"
+		response.Instructions = "Authentication required for Fastly CLI. FASTLY_API_TOKEN environment variable is not recommended for MCP clients."
+		response.NextSteps = []string{
+			"Get your API token from https://manage.fastly.com/account/personal/tokens",
+			"Run 'fastly profile create' to set up authentication",
+			"Enter a profile name (e.g., 'default') and paste your API token",
+		}
+		return response, authRequiredMessage
+	}
+
+	return response, ""
+}
+
 // prettyPrintJSON outputs JSON data with proper indentation for human readability
 func prettyPrintJSON(data interface{}) error {
 	encoder := json.NewEncoder(os.Stdout)
@@ -656,14 +1573,74 @@ Usage:
 Options:
   --http [addr:port]       Start HTTP server (default: 127.0.0.1:8080)
   --sse                    Use SSE transport instead of StreamableHTTP
+  --allow-remote-bind      Allow --http to listen on a non-loopback address without --http-auth-token
   --sanitize               Enable sanitization of sensitive data (PII, tokens, secrets)
   --allowed-commands-file file  Use custom allowed commands list from file
   --allowed-commands cmds  Use custom allowed commands (comma-separated list)
   --denied-commands-file file   Use custom denied commands list from file
   --denied-commands cmds   Use custom denied commands (comma-separated list)
+  --hide-denied-commands   Report denied commands as not available in describe
+  --review-mutating        Require --user-reviewed for create and update operations, not only destructive ones
+  --disable-family list    Deny every subcommand of these command families, e.g. "tools,object-storage"
+  --logging-providers list Only allow these logging providers, e.g. "s3,bigquery"
   --encrypt-tokens         Encrypt secret tokens in tool responses (for LLM safety)
   --log-commands file      Log MCP commands to the specified file
+  --log-commands-per-token  Log the calls of each --http-auth-token to a separate file
+  --log-redaction level    Flag values in the command log: full, redact-secrets or redact-all-values (default)
   --output-cache-threshold bytes  Set output size threshold for caching (default: 25000)
+  --cache-threshold bytes  Same as --output-cache-threshold, at least 1000 (env: FASTLY_MCP_CACHE_THRESHOLD)
+  --max-output-size bytes  Truncate text output and JSON objects above this size (default: 50000)
+  --max-array-items n      Truncate JSON arrays above this many items (default: 100)
+  --strip-flags flags      MCP-only flags to strip before running the CLI (comma-separated)
+  --per-page-defaults list Default per-page by command, e.g. "service list=50,stats historical=100"
+  --cache-policy list      Cache per command regardless of size, e.g. "service describe=never,log-tail=always"
+  --cache-dir path         Persist cached results to this directory so result IDs survive restarts
+  --cache-max-entries n    Evict the least recently read results beyond n cached results (default: unlimited)
+  --cache-max-bytes bytes  Evict the least recently read results beyond this combined output size (default: unlimited)
+  --create-flags list      Flags appended to create operations that accept them, e.g. "comment=created-by:mcp"
+  --compute-preset list    Defaults for compute build/deploy/publish, e.g. "dir=./edge-app,package=pkg/edge-app.tar.gz"
+  --mask-json-paths paths  JSON field paths to always redact, e.g. "customer.email,tls.private_key"
+  --isolate-env            Run the Fastly CLI with only PATH, HOME and FASTLY_* environment variables
+  --env-allowlist names    Additional environment variables passed to the CLI, e.g. "HTTPS_PROXY,SSL_CERT_*" (implies --isolate-env)
+  --output-file-flags names  Flags that name a file a command writes to, reported with its size (default: output,output-file)
+  --first-output-timeout seconds  Stop commands that print nothing within this time (default: 0, disabled)
+  --max-retries n          Retry read-only commands failing with a transient error up to n times (default: 0)
+  --retry-base-delay duration  Delay before the first retry, doubled for each further retry (default: 500ms)
+  --auth-breaker-threshold n  Fail commands at once after n consecutive authentication failures (default: 3, 0 disables)
+  --auth-breaker-window duration  Window for counting authentication failures and rechecking them (default: 1m)
+  --slow-command-threshold seconds  Note in responses when a command took longer than this (default: 0, disabled)
+  --max-command-line-flags n  Elide flags beyond n in the echoed command_line (default: 0, show all)
+  --max-request-items n    Reject fastly_execute requests and batch steps with more than n args, raw args and flags combined (default: 100)
+  --result-list-limit n    Maximum number of cached results returned by one fastly_result_list call (default: 20)
+  --export-dir path        Directory fastly_result_export writes into (default: fastly-mcp-exports in the temporary directory)
+  --tool-prefix prefix     Register every tool as prefix_name, e.g. acct1_fastly_execute, to run several servers in one client
+  --profile name           Run every Fastly CLI command with this profile; overrides FASTLY_API_TOKEN
+  --schema-version n       Response envelope version for clients that do not request one (default: 2, the current version)
+  --item-soft-limit n      Warn when a response returns more than n items (default: 0, no warning)
+  --json-warning-lines n   Strip up to n warning lines printed before JSON output (default: 3, 0 disables)
+  --summarize-lists n      Return a summary and result_id instead of list results with more than n items
+  --command-timeout duration  Timeout of each command, e.g. 90s or 2m (default: 30s, env: FASTLY_MCP_TIMEOUT)
+  --command-timeouts-file path  JSON object of per-command timeouts, e.g. {"compute deploy": "5m", "service list": "5s"}
+  --danger-policy-file path  JSON object marking command paths "dangerous" or "allow", e.g. {"purge": "dangerous", "config-store-entry create": "allow"}
+  --policies-file path     With --http, named allow/deny policies that clients select with the X-Fastly-MCP-Policy header or /policy/<name>
+  --http-auth-token tokens With --http, require 'Authorization: Bearer' with one of these tokens (comma-separated, env: FASTLY_MCP_HTTP_AUTH_TOKEN)
+  --cors-origin origins    With --http, let browser clients connect from these origins ('*' or comma-separated)
+  --shutdown-grace-period duration  With --http, how long in-flight requests may finish on SIGINT/SIGTERM (default: 20s)
+  --max-command-timeout seconds  Maximum per-call timeout_seconds an agent may request (default: 600)
+  --non-interactive-mode mode  When to append --non-interactive: auto (default, per command), always or never
+  --validate-before-activate  Validate a service version before activating it and refuse if invalid
+  --explain                Explain each flag of a dangerous operation when asking for its review
+  --normalize-booleans     Coerce "true"/"false" strings in known fields of cached results to booleans
+  --normalize-service-ids  Trim service IDs, correct their case against known services and reject malformed ones
+  --deterministic-result-ids  Derive result IDs from the cached content so identical results share one entry
+  --purge-all-preflight    Report the domains and recent traffic of a service when asking to review purge --all
+  --strict-purge-batch     Refuse a batched surrogate-key purge if any key is invalid
+  --safe-mode              Require review of mutating commands, a confirmation token for destructive ones, and deny self-management (default)
+  --unsafe                 Turn off safe mode: only dangerous commands require review
+  --read-only              Reject every command that may modify resources, even when reviewed
+  --allow-self-management  Allow the install and update commands that modify the Fastly CLI itself (requires --unsafe)
+  --allow-raw-args         Accept raw_args in fastly_execute, passed to the CLI after a -- separator
+  --json-errors            In CLI mode, report setup and authentication errors as JSON on stdout
 
 CLI Commands:
   help            Show this help message
@@ -671,6 +1648,8 @@ CLI Commands:
   list-commands   List all available Fastly operations in JSON format
   execute <json>  Execute a Fastly operation from JSON specification
   describe <cmd>  Get detailed help for a specific operation in JSON format
+  catalog         Export all operations with subcommands, flags and categories as JSON
+  diagnose        Check the CLI installation, token, profile and authentication step by step
 
 Example JSON for execute:
   {
@@ -692,6 +1671,7 @@ Example usage:
   fastly-mcp --allowed-commands-file cmds.txt --allowed-commands whoami,help # Merge both sources
   fastly-mcp --denied-commands "stats realtime,log-tail"  # Override default denied commands
   fastly-mcp --denied-commands-file denied.txt            # Load denied commands from file
+  fastly-mcp --disable-family tools,object-storage        # Turn off whole command families
 
 Default denied commands:
   stats realtime, log-tail (real-time monitoring)
@@ -762,6 +1742,28 @@ func listCommands() {
 	}
 }
 
+// exportCatalog describes every available command and subcommand and outputs
+// the combined catalog in JSON format. This runs one help invocation per command
+// path, so it takes noticeably longer than list-commands.
+func exportCatalog() {
+	catalog := fastly.BuildCommandCatalog()
+	if err := prettyPrintJSON(catalog); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to encode catalog: %v\n", err)
+	}
+}
+
+// diagnoseSetup checks the Fastly CLI setup step by step and outputs the report
+// in JSON format. It exits with status 1 when a step failed.
+func diagnoseSetup() {
+	diagnosis := fastly.DiagnoseSetup(context.Background())
+	if err := prettyPrintJSON(diagnosis); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to encode diagnosis: %v\n", err)
+	}
+	if !diagnosis.Healthy {
+		os.Exit(1)
+	}
+}
+
 // executeCommand parses a JSON specification and executes the corresponding Fastly CLI command.
 // The JSON should contain 'command', 'args', and 'flags' fields as defined in types.CommandRequest.
 // It returns a structured response with the command output or error information.
@@ -778,7 +1780,7 @@ func executeCommand(jsonSpec string) {
 		return
 	}
 
-	response := fastly.ExecuteCommand(req)
+	response := fastly.ExecuteCommand(req).ForSchemaVersion(mcp.SchemaVersion())
 	if err := prettyPrintJSON(response); err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to encode response: %v\n", err)
 	}
@@ -11,7 +11,9 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/fastly/mcp/internal/fastly"
 	"github.com/fastly/mcp/internal/types"
+	"github.com/fastly/mcp/internal/validation"
 )
 
 // TestValidateCLIArgs tests the argument validation logic
@@ -50,6 +52,28 @@ func TestValidateCLIArgs(t *testing.T) {
 			wantError: true,
 			errorMsg:  "does not accept additional arguments",
 		},
+		{
+			name:      "Catalog",
+			args:      []string{"catalog"},
+			wantError: false,
+		},
+		{
+			name:      "Catalog with extra args",
+			args:      []string{"catalog", "extra"},
+			wantError: true,
+			errorMsg:  "does not accept additional arguments",
+		},
+		{
+			name:      "Diagnose",
+			args:      []string{"diagnose"},
+			wantError: false,
+		},
+		{
+			name:      "Diagnose with extra args",
+			args:      []string{"diagnose", "extra"},
+			wantError: true,
+			errorMsg:  "does not accept additional arguments",
+		},
 		{
 			name:      "Execute with no args",
 			args:      []string{"execute"},
@@ -241,6 +265,165 @@ func TestBuildCustomValidator(t *testing.T) {
 	})
 }
 
+func TestWithDisabledFamilies(t *testing.T) {
+	disabledFamilies = map[string]bool{"tools": true, "object-storage": true}
+	defer func() { disabledFamilies = nil }()
+
+	validator := buildCustomValidator(nil, withDisabledFamilies(nil))
+	if validator == nil {
+		t.Fatal("expected validator")
+	}
+	if !validator.IsDenied("tools", []string{"domain", "suggest"}) {
+		t.Error("expected 'tools domain suggest' to be denied")
+	}
+	if !validator.IsDenied("object-storage", []string{"access-keys", "list"}) {
+		t.Error("expected 'object-storage access-keys list' to be denied")
+	}
+	if validator.IsDenied("service", []string{"list"}) {
+		t.Error("did not expect 'service list' to be denied")
+	}
+	if !validator.IsDenied("stats", []string{"realtime"}) {
+		t.Error("expected the default denylist to remain in place")
+	}
+
+	denied := withDisabledFamilies(map[string]bool{"service delete": true})
+	if !denied["service delete"] || !denied["tools"] || denied["stats realtime"] {
+		t.Errorf("expected families to be added to a custom denylist, got %v", denied)
+	}
+}
+
+func TestValidatorSources(t *testing.T) {
+	disabledFamilies = map[string]bool{"tools": true}
+	defer func() { disabledFamilies = nil }()
+
+	denied := withDisabledFamilies(nil)
+	validator := buildCustomValidator(nil, denied)
+	validator.SetCommandSources(validatorSources(nil, nil))
+	if reason := validator.DeniedReason("stats", []string{"realtime"}); reason != "denied by the default denylist" {
+		t.Errorf("expected the default denylist, got %q", reason)
+	}
+	if reason := validator.DeniedReason("tools", []string{"domain"}); reason != "disabled with --disable-family tools" {
+		t.Errorf("expected the disabled family, got %q", reason)
+	}
+
+	inline := map[string]bool{"service delete": true}
+	validator = buildCustomValidator(nil, inline)
+	validator.SetCommandSources(validatorSources(nil, validation.RecordSources(nil, inline, validation.SourceInline)))
+	if reason := validator.DeniedReason("service", []string{"delete"}); reason != "denied by the inline denylist" {
+		t.Errorf("expected the inline denylist, got %q", reason)
+	}
+}
+
+func TestIsGlobalOption(t *testing.T) {
+	tests := []struct {
+		arg          string
+		wantOption   bool
+		wantSkipNext bool
+	}{
+		{"--strip-flags", true, true},
+		{"--strip-flags=trace-id", true, false},
+		{"--strip-flag", false, false},
+		{"--validate-before-activate", true, false},
+		{"--allow-self-management", true, false},
+		{"--safe-mode", true, false},
+		{"--unsafe", true, false},
+		{"--read-only", true, false},
+		{"--allow-raw-args", true, false},
+		{"--log-commands-per-token", true, false},
+		{"--deterministic-result-ids", true, false},
+		{"--explain", true, false},
+		{"--normalize-service-ids", true, false},
+		{"--purge-all-preflight", true, false},
+		{"--isolate-env", true, false},
+		{"--hide-denied-commands", true, false},
+		{"--review-mutating", true, false},
+		{"--env-allowlist", true, true},
+		{"--output-file-flags", true, true},
+		{"--cache-dir", true, true},
+		{"--cache-threshold", true, true},
+		{"--cache-max-entries", true, true},
+		{"--cache-max-bytes", true, true},
+		{"--max-output-size", true, true},
+		{"--max-array-items", true, true},
+		{"--json-errors", true, false},
+		{"--disable-family", true, true},
+		{"--logging-providers", true, true},
+		{"--max-request-items", true, true},
+		{"--slow-command-threshold", true, true},
+		{"--command-timeout", true, true},
+		{"--command-timeouts-file", true, true},
+		{"--danger-policy-file", true, true},
+		{"--export-dir", true, true},
+		{"--policies-file", true, true},
+		{"--http-auth-token", true, true},
+		{"--cors-origin", true, true},
+		{"--shutdown-grace-period", true, true},
+		{"--schema-version", true, true},
+		{"--summarize-lists", true, true},
+		{"--json-warning-lines", true, true},
+		{"--max-retries", true, true},
+		{"--retry-base-delay", true, true},
+		{"--auth-breaker-threshold", true, true},
+		{"--auth-breaker-window", true, true},
+		{"--result-list-limit", true, true},
+		{"--tool-prefix", true, true},
+		{"--profile", true, true},
+		{"execute", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			isOption, skipNext := isGlobalOption(tt.arg)
+			if isOption != tt.wantOption || skipNext != tt.wantSkipNext {
+				t.Errorf("isGlobalOption(%q) = (%v, %v), want (%v, %v)",
+					tt.arg, isOption, skipNext, tt.wantOption, tt.wantSkipNext)
+			}
+		})
+	}
+}
+
+func TestSetupErrorResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantCode    string
+		wantMessage bool
+	}{
+		{"cli missing", fmt.Errorf("fastly CLI not found in PATH"), "cli_not_found", true},
+		{"not authenticated", fmt.Errorf("not authenticated with Fastly. Error: unauthorized"), "auth_required", true},
+		{"cli too old", &fastly.CLIVersionError{Detected: "9.2.0", Required: fastly.MinimumCLIVersion}, "cli_version_unsupported", true},
+		{"other failure", fmt.Errorf("fastly CLI timed out"), "setup_error", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, message := setupErrorResponse(tt.err)
+			if response.Success {
+				t.Error("Expected Success to be false")
+			}
+			if response.ErrorCode != tt.wantCode {
+				t.Errorf("Expected error code %q, got %q", tt.wantCode, response.ErrorCode)
+			}
+			if response.Error != tt.err.Error() {
+				t.Errorf("Expected error %q, got %q", tt.err.Error(), response.Error)
+			}
+			if len(response.NextSteps) == 0 {
+				t.Error("Expected next steps in the response")
+			}
+			if (message != "") != tt.wantMessage {
+				t.Errorf("Expected human-friendly message: %v, got %q", tt.wantMessage, message)
+			}
+		})
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	got := splitList(" trace-id, ,agent-note,")
+	if strings.Join(got, "|") != "trace-id|agent-note" {
+		t.Errorf("Expected [trace-id agent-note], got %v", got)
+	}
+}
+
 // Integration tests that execute the binary
 // These tests build and run the actual binary to test end-to-end behavior
 
@@ -358,6 +541,158 @@ func TestMainIntegration(t *testing.T) {
 				"--allowed-commands-file specified multiple times",
 			},
 		},
+		{
+			name:        "--safe-mode with --unsafe",
+			args:        []string{"--safe-mode", "--unsafe", "help"},
+			expectError: true,
+			expectContains: []string{
+				"--safe-mode and --unsafe cannot be combined",
+			},
+		},
+		{
+			name:        "--allow-self-management without --unsafe",
+			args:        []string{"--allow-self-management", "help"},
+			expectError: true,
+			expectContains: []string{
+				"--allow-self-management requires --unsafe",
+			},
+		},
+		{
+			name:        "--command-timeout without a unit",
+			args:        []string{"--command-timeout", "90", "help"},
+			expectError: true,
+			expectContains: []string{
+				"--command-timeout (or FASTLY_MCP_TIMEOUT) requires a positive duration",
+			},
+		},
+		{
+			name:        "--command-timeout above the maximum",
+			args:        []string{"--command-timeout", "11m", "help"},
+			expectError: true,
+			expectContains: []string{
+				"at most the maximum command timeout of 10m0s",
+			},
+		},
+		{
+			name:        "--schema-version that is not supported",
+			args:        []string{"--schema-version", "3", "help"},
+			expectError: true,
+			expectContains: []string{
+				"--schema-version requires a version from 1 to 2",
+			},
+		},
+		{
+			name:        "--command-timeouts-file that does not exist",
+			args:        []string{"--command-timeouts-file", "/nonexistent/timeouts.json", "help"},
+			expectError: true,
+			expectContains: []string{
+				"--command-timeouts-file:",
+			},
+		},
+		{
+			name:        "--danger-policy-file that does not exist",
+			args:        []string{"--danger-policy-file", "/nonexistent/policy.json", "help"},
+			expectError: true,
+			expectContains: []string{
+				"--danger-policy-file:",
+			},
+		},
+		{
+			name:        "--policies-file without --http",
+			args:        []string{"--policies-file", "policies.json"},
+			expectError: true,
+			expectContains: []string{
+				"--policies-file requires --http",
+			},
+		},
+		{
+			name:        "--http-auth-token without --http",
+			args:        []string{"--http-auth-token", "secret"},
+			expectError: true,
+			expectContains: []string{
+				"--http-auth-token requires --http",
+			},
+		},
+		{
+			name:        "--http-auth-token without tokens",
+			args:        []string{"--http", "--http-auth-token", " , ", "help"},
+			expectError: true,
+			expectContains: []string{
+				"requires at least one token",
+			},
+		},
+		{
+			name:        "--log-commands-per-token without --http-auth-token",
+			args:        []string{"--http", "--log-commands", "commands.log", "--log-commands-per-token", "help"},
+			expectError: true,
+			expectContains: []string{
+				"--log-commands-per-token requires --http-auth-token",
+			},
+		},
+		{
+			name:        "--cors-origin without --http",
+			args:        []string{"--cors-origin", "*"},
+			expectError: true,
+			expectContains: []string{
+				"--cors-origin requires --http",
+			},
+		},
+		{
+			name:        "--cors-origin without scheme",
+			args:        []string{"--http", "--cors-origin", "app.example.com", "help"},
+			expectError: true,
+			expectContains: []string{
+				"--cors-origin:",
+			},
+		},
+		{
+			name:        "--shutdown-grace-period without --http",
+			args:        []string{"--shutdown-grace-period", "20s"},
+			expectError: true,
+			expectContains: []string{
+				"--shutdown-grace-period requires --http",
+			},
+		},
+		{
+			name:        "--shutdown-grace-period not a duration",
+			args:        []string{"--http", "--shutdown-grace-period", "20", "help"},
+			expectError: true,
+			expectContains: []string{
+				"requires a positive duration",
+			},
+		},
+		{
+			name:        "--cache-threshold below the minimum",
+			args:        []string{"--cache-threshold", "10", "help"},
+			expectError: true,
+			expectContains: []string{
+				"--cache-threshold (or FASTLY_MCP_CACHE_THRESHOLD) requires an integer of at least 1000",
+			},
+		},
+		{
+			name:        "--max-array-items not a number",
+			args:        []string{"--max-array-items", "many", "help"},
+			expectError: true,
+			expectContains: []string{
+				"--max-array-items requires a positive integer",
+			},
+		},
+		{
+			name:        "--cache-dir with path traversal",
+			args:        []string{"--cache-dir", "/tmp/../etc/fastly-mcp", "help"},
+			expectError: true,
+			expectContains: []string{
+				"--cache-dir: path traversal detected",
+			},
+		},
+		{
+			name:        "--tool-prefix with invalid characters",
+			args:        []string{"--tool-prefix", "acct;1", "help"},
+			expectError: true,
+			expectContains: []string{
+				"--tool-prefix: prefix \"acct;1\" may only contain letters, digits",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -567,6 +902,80 @@ func TestFlagCombinationsIntegration(t *testing.T) {
 	}
 }
 
+// Test that --json-errors reports setup errors as JSON on stdout
+func TestJSONErrorsIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	binary := buildTestBinary(t)
+
+	// A fake CLI that fails every command with an authentication error
+	authDir := t.TempDir()
+	fakeCLI := filepath.Join(authDir, "fastly")
+	script := "#!/bin/sh\necho 'Error: unauthorized' >&2\nexit 1\n"
+	if err := os.WriteFile(fakeCLI, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		env      []string
+		wantCode string
+		wantText string
+	}{
+		{
+			name:     "CLI not installed",
+			env:      []string{"PATH=" + t.TempDir(), "FASTLY_CLI_PATH="},
+			wantCode: "cli_not_found",
+			wantText: "Fastly CLI is not installed",
+		},
+		{
+			name:     "not authenticated",
+			env:      []string{"FASTLY_CLI_PATH=" + fakeCLI},
+			wantCode: "auth_required",
+			wantText: "Authentication required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run := func(args ...string) (string, string) {
+				cmd := exec.Command(binary, args...)
+				cmd.Env = append(os.Environ(), tt.env...)
+				var stdout, stderr bytes.Buffer
+				cmd.Stdout = &stdout
+				cmd.Stderr = &stderr
+				if err := cmd.Run(); err == nil {
+					t.Errorf("Expected %v to fail setup", args)
+				}
+				return stdout.String(), stderr.String()
+			}
+
+			// Without --json-errors the human-friendly text goes to stderr
+			stdout, stderr := run("list-commands")
+			if !strings.Contains(stderr, tt.wantText) {
+				t.Errorf("Expected stderr to contain %q, got: %s", tt.wantText, stderr)
+			}
+			if stdout != "" {
+				t.Errorf("Expected empty stdout, got: %s", stdout)
+			}
+
+			stdout, stderr = run("--json-errors", "list-commands")
+			var response types.CommandResponse
+			if err := json.Unmarshal([]byte(stdout), &response); err != nil {
+				t.Fatalf("Expected JSON on stdout, got %q (stderr: %s): %v", stdout, stderr, err)
+			}
+			if response.Success || response.ErrorCode != tt.wantCode {
+				t.Errorf("Expected failed response with code %q, got %+v", tt.wantCode, response)
+			}
+			if strings.Contains(stderr, tt.wantText) {
+				t.Errorf("Expected no human-friendly text on stderr, got: %s", stderr)
+			}
+		})
+	}
+}
+
 // Test allowed commands file loading
 func TestAllowedCommandsFileIntegration(t *testing.T) {
 	if testing.Short() {
@@ -8,9 +8,13 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"runtime"
+	"strings"
 	"sync"
+	"syscall"
 	"time"
 
+	"github.com/fastly/mcp/internal/fastly"
 	"github.com/fastly/mcp/internal/types"
 	"github.com/fastly/mcp/internal/version"
 )
@@ -18,6 +22,7 @@ import (
 // Job represents a background streaming command.
 type Job struct {
 	ID        string
+	Label     string
 	Command   string
 	Args      []string
 	Flags     []types.Flag
@@ -76,12 +81,13 @@ func (j *Job) Start(ctx context.Context, timeout time.Duration) error {
 			fullArgs = append(fullArgs, fmt.Sprintf("--%s", flag.Name))
 		}
 	}
+	fullArgs = append(fullArgs, fastly.ProfileArgs()...)
 
 	j.cmd = exec.CommandContext(jobCtx, fastlyPath, fullArgs...)
 
 	// Set environment
 	versionedAddon := fmt.Sprintf("mcp/%s", version.GetVersion())
-	j.cmd.Env = append(os.Environ(),
+	j.cmd.Env = append(fastly.ChildEnvironment(),
 		fmt.Sprintf("FASTLY_CLI_ADDON=%s", versionedAddon),
 		fmt.Sprintf("FASTLY_USER_AGENT_EXTENSION=%s", versionedAddon))
 
@@ -202,7 +208,10 @@ func (j *Job) Info() JobInfo {
 		ID:           j.ID,
 		Command:      j.Command,
 		Args:         j.Args,
+		Label:        j.Label,
+		CommandLine:  j.commandLine(),
 		Status:       j.Status,
+		Alive:        j.Status == JobStatusRunning && j.cmd != nil && processAlive(j.cmd.Process),
 		StartedAt:    j.StartedAt,
 		StoppedAt:    j.StoppedAt,
 		OutputSize:   j.buffer.TotalSize(),
@@ -220,6 +229,33 @@ func (j *Job) Info() JobInfo {
 	return info
 }
 
+// processAlive reports whether the process still exists, by sending it signal
+// 0, which checks for the process without affecting it. Windows supports no
+// signal but Kill, so there the process is assumed alive until monitor sees it
+// exit.
+func processAlive(process *os.Process) bool {
+	if process == nil {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	return process.Signal(syscall.Signal(0)) == nil
+}
+
+// commandLine renders the command the job was started with, flags included.
+func (j *Job) commandLine() string {
+	parts := append([]string{"fastly", j.Command}, j.Args...)
+	for _, flag := range j.Flags {
+		if flag.Value != "" {
+			parts = append(parts, fmt.Sprintf("--%s=%s", flag.Name, flag.Value))
+		} else {
+			parts = append(parts, fmt.Sprintf("--%s", flag.Name))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
 // Read returns paginated output from the job.
 func (j *Job) Read(offset, limit int64) JobOutput {
 	j.mu.RLock()
@@ -276,6 +312,7 @@ func (j *Job) DetailedStatus(maxDataSize int64) StatusResponse {
 	return StatusResponse{
 		Success:      true,
 		JobID:        j.ID,
+		Label:        j.Label,
 		Command:      j.Command,
 		Args:         j.Args,
 		Status:       j.Status,
@@ -3,8 +3,11 @@ package background
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/fastly/mcp/internal/types"
 )
@@ -17,6 +20,7 @@ type Manager struct {
 	jobTimeout  time.Duration
 	cleanupAge  time.Duration
 	stopCleanup chan struct{}
+	stopOnce    sync.Once
 	mu          sync.RWMutex
 }
 
@@ -92,8 +96,17 @@ func (m *Manager) cleanup() {
 	}
 }
 
-// Start creates and starts a new background job.
-func (m *Manager) Start(ctx context.Context, command string, args []string, flags []types.Flag) (*StartResponse, error) {
+// Start creates and starts a new background job. The optional label is stored
+// with the job and shown in list and status responses.
+func (m *Manager) Start(ctx context.Context, command string, args []string, flags []types.Flag, label string) (*StartResponse, error) {
+	label = strings.TrimSpace(label)
+	if utf8.RuneCountInString(label) > MaxLabelLength {
+		return &StartResponse{
+			Success: false,
+			Error:   fmt.Sprintf("label exceeds maximum length of %d characters", MaxLabelLength),
+		}, nil
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -114,6 +127,7 @@ func (m *Manager) Start(ctx context.Context, command string, args []string, flag
 
 	// Create the job
 	job := NewJob(command, args, flags, m.maxDataSize)
+	job.Label = label
 
 	// Start the job
 	if err := job.Start(ctx, m.jobTimeout); err != nil {
@@ -128,6 +142,7 @@ func (m *Manager) Start(ctx context.Context, command string, args []string, flag
 	return &StartResponse{
 		Success: true,
 		JobID:   job.ID,
+		Label:   job.Label,
 		Status:  job.Status,
 		Instructions: fmt.Sprintf(
 			"Job started. Use fastly_background_read with job_id='%s' to read output. "+
@@ -200,6 +215,27 @@ func (m *Manager) List() *ListResponse {
 	}
 }
 
+// Summary returns the IDs of running and failed jobs, sorted for stable output.
+func (m *Manager) Summary() JobsSummary {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var summary JobsSummary
+	for _, job := range m.jobs {
+		info := job.Info()
+		switch info.Status {
+		case JobStatusRunning:
+			summary.Running = append(summary.Running, info.ID)
+		case JobStatusError:
+			summary.Failed = append(summary.Failed, info.ID)
+		}
+	}
+
+	sort.Strings(summary.Running)
+	sort.Strings(summary.Failed)
+	return summary
+}
+
 // Status returns detailed status for a job.
 func (m *Manager) Status(jobID string) (*StatusResponse, error) {
 	job, err := m.Get(jobID)
@@ -237,25 +273,40 @@ func (m *Manager) Query(jobID string, pattern string, maxResults int) (*JobQuery
 	return &result, nil
 }
 
-// StopAll stops all running jobs. Used for graceful shutdown.
-func (m *Manager) StopAll() {
+// StopAll stops every running job and reports the result for each, sorted by
+// job ID. It is used for cleanup at the end of a conversation and for graceful
+// shutdown.
+func (m *Manager) StopAll() *StopAllResponse {
 	m.mu.RLock()
-	jobs := make([]*Job, 0)
-	for _, job := range m.jobs {
+	jobIDs := make([]string, 0)
+	for id, job := range m.jobs {
 		if job.IsRunning() {
-			jobs = append(jobs, job)
+			jobIDs = append(jobIDs, id)
 		}
 	}
 	m.mu.RUnlock()
+	sort.Strings(jobIDs)
 
-	for _, job := range jobs {
-		_ = job.Stop()
+	resp := &StopAllResponse{
+		Success: true,
+		Results: make([]StopResponse, 0, len(jobIDs)),
 	}
+	for _, id := range jobIDs {
+		result, _ := m.Stop(id)
+		if !result.Success {
+			resp.Success = false
+		}
+		resp.Results = append(resp.Results, *result)
+	}
+	resp.Count = len(resp.Results)
+
+	return resp
 }
 
-// Shutdown stops all jobs and the cleanup goroutine.
+// Shutdown stops all jobs and the cleanup goroutine. It is safe to call more
+// than once.
 func (m *Manager) Shutdown() {
-	close(m.stopCleanup)
+	m.stopOnce.Do(func() { close(m.stopCleanup) })
 	m.StopAll()
 }
 
@@ -2,6 +2,11 @@ package background
 
 import (
 	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -22,6 +27,7 @@ func TestNewManager(t *testing.T) {
 	}
 
 	m.Shutdown()
+	m.Shutdown()
 }
 
 func TestManager_List_Empty(t *testing.T) {
@@ -47,7 +53,7 @@ func TestManager_StartValidation(t *testing.T) {
 	// Note: This test assumes log-tail is a valid streaming command
 	resp, err := m.Start(ctx, "log-tail", []string{}, []types.Flag{
 		{Name: "service-id", Value: "test123"},
-	})
+	}, "")
 
 	// The start might fail if fastly CLI isn't installed, but that's okay for unit tests
 	// We're testing the manager logic, not the CLI
@@ -91,7 +97,7 @@ func TestManager_MaxJobsLimit(t *testing.T) {
 	m.mu.Unlock()
 
 	ctx := context.Background()
-	resp, _ := m.Start(ctx, "log-tail", []string{}, nil)
+	resp, _ := m.Start(ctx, "log-tail", []string{}, nil, "")
 
 	if resp.Success {
 		t.Error("Expected failure due to max jobs limit")
@@ -187,3 +193,228 @@ func TestManager_MaxDataSize(t *testing.T) {
 		t.Errorf("Expected maxDataSize %d, got %d", customSize, m.MaxDataSize())
 	}
 }
+
+func TestManager_Summary(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "fastly")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\nexec sleep 30\n"), 0o700); err != nil {
+		t.Fatalf("Failed to write mock CLI: %v", err)
+	}
+	t.Setenv("FASTLY_CLI_PATH", script)
+
+	m := NewManager(5, DefaultMaxDataSize, DefaultJobTimeout, DefaultCleanupAge)
+	defer m.Shutdown()
+
+	if line := m.Summary().StatusLine(); line != "" {
+		t.Errorf("Expected empty status line without jobs, got %q", line)
+	}
+
+	first, _ := m.Start(context.Background(), "log-tail", nil, nil, "")
+	second, _ := m.Start(context.Background(), "log-tail", nil, nil, "")
+	if !first.Success || !second.Success {
+		t.Fatalf("Expected jobs to start, got %+v and %+v", first, second)
+	}
+
+	// A failed job is reported until it is cleaned up
+	m.mu.Lock()
+	m.jobs["job_failed"] = &Job{
+		ID:        "job_failed",
+		Command:   "log-tail",
+		Status:    JobStatusError,
+		StartedAt: time.Now(),
+		Error:     "process exited with code 1",
+		buffer:    NewLineBuffer(1024),
+		done:      make(chan struct{}),
+	}
+	m.mu.Unlock()
+
+	summary := m.Summary()
+	if len(summary.Running) != 2 || len(summary.Failed) != 1 || summary.Failed[0] != "job_failed" {
+		t.Fatalf("Expected 2 running and 1 failed job, got %+v", summary)
+	}
+	line := summary.StatusLine()
+	if !strings.Contains(line, "2 running") || !strings.Contains(line, first.JobID) || !strings.Contains(line, "1 failed (job_failed)") {
+		t.Errorf("Unexpected status line: %q", line)
+	}
+
+	if _, err := m.Stop(first.JobID); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if _, err := m.Stop(second.JobID); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+
+	summary = m.Summary()
+	if len(summary.Running) != 0 {
+		t.Errorf("Expected no running jobs after stop, got %+v", summary)
+	}
+	if line := summary.StatusLine(); strings.Contains(line, "running") {
+		t.Errorf("Expected stopped jobs to be cleared from the status line, got %q", line)
+	}
+}
+
+func TestManager_StopAll(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "fastly")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\nexec sleep 30\n"), 0o700); err != nil {
+		t.Fatalf("Failed to write mock CLI: %v", err)
+	}
+	t.Setenv("FASTLY_CLI_PATH", script)
+
+	m := NewManager(5, DefaultMaxDataSize, DefaultJobTimeout, DefaultCleanupAge)
+	defer m.Shutdown()
+
+	if resp := m.StopAll(); !resp.Success || resp.Count != 0 {
+		t.Errorf("Expected an empty successful result without jobs, got %+v", resp)
+	}
+
+	var jobIDs []string
+	for i := 0; i < 3; i++ {
+		started, _ := m.Start(context.Background(), "log-tail", nil, nil, "")
+		if !started.Success {
+			t.Fatalf("Expected job to start, got %+v", started)
+		}
+		jobIDs = append(jobIDs, started.JobID)
+	}
+
+	// Jobs that are no longer running are not reported
+	m.mu.Lock()
+	m.jobs["job_completed"] = &Job{
+		ID:        "job_completed",
+		Command:   "log-tail",
+		Status:    JobStatusCompleted,
+		StartedAt: time.Now(),
+		buffer:    NewLineBuffer(1024),
+		done:      make(chan struct{}),
+	}
+	m.mu.Unlock()
+
+	resp := m.StopAll()
+	if !resp.Success {
+		t.Errorf("Expected success, got %+v", resp)
+	}
+	if resp.Count != len(jobIDs) || len(resp.Results) != len(jobIDs) {
+		t.Fatalf("Expected %d results, got %+v", len(jobIDs), resp)
+	}
+
+	sort.Strings(jobIDs)
+	for i, result := range resp.Results {
+		if result.JobID != jobIDs[i] {
+			t.Errorf("Result %d: expected job %s, got %s", i, jobIDs[i], result.JobID)
+		}
+		if !result.Success || result.Status != JobStatusStopped {
+			t.Errorf("Result %d: expected a stopped job, got %+v", i, result)
+		}
+	}
+
+	if m.RunningCount() != 0 {
+		t.Errorf("Expected no running jobs after StopAll, got %d", m.RunningCount())
+	}
+}
+
+func TestManager_ListMetadata(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "fastly")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho first\necho second\nexec sleep 30\n"), 0o700); err != nil {
+		t.Fatalf("Failed to write mock CLI: %v", err)
+	}
+	t.Setenv("FASTLY_CLI_PATH", script)
+
+	m := NewManager(5, DefaultMaxDataSize, DefaultJobTimeout, DefaultCleanupAge)
+	defer m.Shutdown()
+
+	started, _ := m.Start(context.Background(), "log-tail", nil, []types.Flag{
+		{Name: "service-id", Value: "svc123"},
+		{Name: "only-errors"},
+	}, "")
+	if !started.Success {
+		t.Fatalf("Expected job to start, got %+v", started)
+	}
+
+	// Wait for the output to be collected
+	deadline := time.Now().Add(5 * time.Second)
+	var info JobInfo
+	for {
+		resp := m.List()
+		if resp.Count != 1 {
+			t.Fatalf("Expected 1 job, got %d", resp.Count)
+		}
+		info = resp.Jobs[0]
+		if info.LineCount == 2 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if info.Command != "log-tail" {
+		t.Errorf("Expected command log-tail, got %q", info.Command)
+	}
+	if want := "fastly log-tail --service-id=svc123 --only-errors"; info.CommandLine != want {
+		t.Errorf("Expected command line %q, got %q", want, info.CommandLine)
+	}
+	if info.Status != JobStatusRunning || !info.Alive {
+		t.Errorf("Expected a live running job, got status %s alive %v", info.Status, info.Alive)
+	}
+	if info.StartedAt.IsZero() || info.Duration <= 0 {
+		t.Errorf("Expected start time and duration, got %v and %v", info.StartedAt, info.Duration)
+	}
+	if info.LineCount != 2 {
+		t.Errorf("Expected 2 lines, got %d", info.LineCount)
+	}
+
+	if _, err := m.Stop(started.JobID); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	info = m.List().Jobs[0]
+	if info.Alive || info.Status != JobStatusStopped {
+		t.Errorf("Expected a stopped job that is no longer alive, got status %s alive %v", info.Status, info.Alive)
+	}
+}
+
+func TestProcessAlive(t *testing.T) {
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Failed to start process: %v", err)
+	}
+	if !processAlive(cmd.Process) {
+		t.Error("Expected a running process to be alive")
+	}
+
+	_ = cmd.Process.Kill()
+	_ = cmd.Wait()
+	if processAlive(cmd.Process) {
+		t.Error("Expected a killed process not to be alive")
+	}
+	if processAlive(nil) {
+		t.Error("Expected no process not to be alive")
+	}
+}
+
+func TestManager_Label(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "fastly")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\nexec sleep 30\n"), 0o700); err != nil {
+		t.Fatalf("Failed to write mock CLI: %v", err)
+	}
+	t.Setenv("FASTLY_CLI_PATH", script)
+
+	m := NewManager(5, DefaultMaxDataSize, DefaultJobTimeout, DefaultCleanupAge)
+	defer m.Shutdown()
+
+	started, _ := m.Start(context.Background(), "log-tail", nil, nil, "  production errors ")
+	if !started.Success || started.Label != "production errors" {
+		t.Fatalf("Expected a labeled job to start, got %+v", started)
+	}
+	defer m.Stop(started.JobID)
+
+	list := m.List()
+	if list.Count != 1 || list.Jobs[0].Label != "production errors" {
+		t.Errorf("Expected the label in the job list, got %+v", list.Jobs)
+	}
+
+	status, err := m.Status(started.JobID)
+	if err != nil || status.Label != "production errors" {
+		t.Errorf("Expected the label in the job status, got %+v (%v)", status, err)
+	}
+
+	tooLong, _ := m.Start(context.Background(), "log-tail", nil, nil, strings.Repeat("a", MaxLabelLength+1))
+	if tooLong.Success || !strings.Contains(tooLong.Error, "label exceeds") {
+		t.Errorf("Expected an overlong label to be rejected, got %+v", tooLong)
+	}
+}
@@ -4,6 +4,8 @@
 package background
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/fastly/mcp/internal/types"
@@ -17,6 +19,7 @@ const (
 	DefaultCleanupTick  = 1 * time.Minute
 	DefaultReadLimit    = 100 // lines per read
 	DefaultShutdownWait = 5 * time.Second
+	MaxLabelLength      = 100 // characters in a job label
 )
 
 type JobStatus string
@@ -31,9 +34,12 @@ const (
 // JobInfo provides a summary of a background job for listing purposes.
 type JobInfo struct {
 	ID           string        `json:"id"`
+	Label        string        `json:"label,omitempty"`
 	Command      string        `json:"command"`
 	Args         []string      `json:"args"`
+	CommandLine  string        `json:"command_line"`
 	Status       JobStatus     `json:"status"`
+	Alive        bool          `json:"alive"`
 	StartedAt    time.Time     `json:"started_at"`
 	StoppedAt    *time.Time    `json:"stopped_at,omitempty"`
 	Duration     time.Duration `json:"duration"`
@@ -73,12 +79,14 @@ type StartRequest struct {
 	Command string       `json:"command"`
 	Args    []string     `json:"args"`
 	Flags   []types.Flag `json:"flags,omitempty"`
+	Label   string       `json:"label,omitempty"`
 }
 
 // StartResponse contains the result of starting a background job.
 type StartResponse struct {
 	Success      bool      `json:"success"`
 	JobID        string    `json:"job_id,omitempty"`
+	Label        string    `json:"label,omitempty"`
 	Status       JobStatus `json:"status,omitempty"`
 	Error        string    `json:"error,omitempty"`
 	Instructions string    `json:"instructions,omitempty"`
@@ -95,10 +103,18 @@ type StopResponse struct {
 	Error      string    `json:"error,omitempty"`
 }
 
+// StopAllResponse contains the result of stopping every running background job.
+type StopAllResponse struct {
+	Success bool           `json:"success"`
+	Results []StopResponse `json:"results"`
+	Count   int            `json:"count"`
+}
+
 // StatusResponse contains detailed status of a background job.
 type StatusResponse struct {
 	Success      bool       `json:"success"`
 	JobID        string     `json:"job_id"`
+	Label        string     `json:"label,omitempty"`
 	Command      string     `json:"command"`
 	Args         []string   `json:"args"`
 	Status       JobStatus  `json:"status"`
@@ -118,3 +134,27 @@ type ListResponse struct {
 	Jobs    []JobInfo `json:"jobs"`
 	Count   int       `json:"count"`
 }
+
+// JobsSummary lists the IDs of running and failed jobs.
+type JobsSummary struct {
+	Running []string `json:"running,omitempty"`
+	Failed  []string `json:"failed,omitempty"`
+}
+
+// StatusLine returns a one-line reminder of the running and failed jobs, or an
+// empty string when there are none.
+func (s JobsSummary) StatusLine() string {
+	var parts []string
+	if len(s.Running) > 0 {
+		parts = append(parts, fmt.Sprintf("%d running (%s)", len(s.Running), strings.Join(s.Running, ", ")))
+	}
+	if len(s.Failed) > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed (%s)", len(s.Failed), strings.Join(s.Failed, ", ")))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+
+	return "Background jobs: " + strings.Join(parts, ", ") +
+		". Read output with fastly_background_read and stop jobs with fastly_background_stop when done."
+}
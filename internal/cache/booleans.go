@@ -0,0 +1,62 @@
+package cache
+
+import "strings"
+
+// booleanFields are fields that the Fastly CLI reports as "true"/"false"
+// strings in some commands and as JSON booleans in others. Names are compared
+// case-insensitively with underscores removed, so "AutoLoadbalance" and
+// "auto_loadbalance" are the same field.
+var booleanFields = map[string]bool{
+	"active":          true,
+	"autoloadbalance": true,
+	"deployed":        true,
+	"enabled":         true,
+	"locked":          true,
+	"staging":         true,
+	"testing":         true,
+	"usessl":          true,
+	"sslcheckcert":    true,
+}
+
+// normalizeBooleans controls whether boolean-like strings in known fields are
+// coerced to JSON booleans when output is cached. It can be configured via
+// SetBooleanNormalization().
+var normalizeBooleans = false
+
+// SetBooleanNormalization enables or disables coercion of "true"/"false"
+// strings in known boolean fields of cached JSON output, so that queries and
+// comparisons see the same type regardless of the command.
+func SetBooleanNormalization(enabled bool) {
+	normalizeBooleans = enabled
+}
+
+// isBooleanField reports whether a field name is a known boolean field.
+func isBooleanField(name string) bool {
+	return booleanFields[strings.ToLower(strings.ReplaceAll(name, "_", ""))]
+}
+
+// normalizeBooleanStrings replaces "true"/"false" strings in known boolean
+// fields with JSON booleans, recursing into nested objects and arrays.
+// Values that are not recognizable booleans are left unchanged.
+func normalizeBooleanStrings(data interface{}) interface{} {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if s, ok := value.(string); ok && isBooleanField(key) {
+				switch strings.ToLower(strings.TrimSpace(s)) {
+				case "true":
+					v[key] = true
+				case "false":
+					v[key] = false
+				}
+				continue
+			}
+			v[key] = normalizeBooleanStrings(value)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeBooleanStrings(item)
+		}
+	}
+	return data
+}
@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBooleanStringsQueryableAsBooleans(t *testing.T) {
+	SetBooleanNormalization(true)
+	defer SetBooleanNormalization(false)
+
+	store := NewResultStore(10*time.Minute, 1*time.Hour)
+
+	output := `[
+		{"Number":1,"Active":"true","Locked":"false","Comment":"true"},
+		{"Number":2,"Active":false,"Locked":"TRUE","Backends":[{"use_ssl":"true"}]},
+		{"Number":3,"Active":"maybe"}
+	]`
+	id := store.Store(output, "service-version", []string{"list"}, nil)
+
+	data, err := store.Read(id, 0, 10)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	items := data.([]interface{})
+
+	first := items[0].(map[string]interface{})
+	if first["Active"] != true || first["Locked"] != false {
+		t.Errorf("Expected boolean fields coerced, got %v", first)
+	}
+	if first["Comment"] != "true" {
+		t.Errorf("Expected unknown field left as string, got %#v", first["Comment"])
+	}
+
+	second := items[1].(map[string]interface{})
+	backend := second["Backends"].([]interface{})[0].(map[string]interface{})
+	if second["Locked"] != true || backend["use_ssl"] != true {
+		t.Errorf("Expected nested boolean fields coerced, got %v", second)
+	}
+
+	if third := items[2].(map[string]interface{}); third["Active"] != "maybe" {
+		t.Errorf("Expected unrecognized value unchanged, got %#v", third["Active"])
+	}
+
+	results, err := store.Query(id, "Locked=true")
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	matches := results.([]interface{})
+	if len(matches) != 1 || matches[0].(map[string]interface{})["Number"] != float64(2) {
+		t.Errorf("Expected only version 2 to match Locked=true, got %v", matches)
+	}
+}
+
+func TestBooleanStringsUnchangedByDefault(t *testing.T) {
+	store := NewResultStore(10*time.Minute, 1*time.Hour)
+
+	id := store.Store(`[{"Active":"true"}]`, "service-version", []string{"list"}, nil)
+	data, err := store.Read(id, 0, 10)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if value := data.([]interface{})[0].(map[string]interface{})["Active"]; value != "true" {
+		t.Errorf("Expected string value without normalization, got %#v", value)
+	}
+}
@@ -0,0 +1,69 @@
+package cache
+
+// maxEntries caps the number of cached results. Zero means no cap. It can be
+// configured via SetMaxEntries().
+var maxEntries = 0
+
+// maxBytes caps the combined size of the distinct cached outputs. Zero means no
+// cap. It can be configured via SetMaxBytes().
+var maxBytes = 0
+
+// SetMaxEntries limits the number of cached results. When a new result
+// exceeds the limit, the least recently accessed results are evicted. Zero or
+// a negative value removes the limit.
+func SetMaxEntries(entries int) {
+	if entries < 0 {
+		entries = 0
+	}
+	maxEntries = entries
+}
+
+// SetMaxBytes limits the combined size in bytes of the distinct cached
+// outputs. When a new result exceeds the budget, the least recently accessed
+// results are evicted. Zero or a negative value removes the limit.
+func SetMaxBytes(bytes int) {
+	if bytes < 0 {
+		bytes = 0
+	}
+	maxBytes = bytes
+}
+
+// evict removes the least recently accessed results until the store is within
+// maxEntries and maxBytes. The result with ID keep, which was just stored, is
+// never evicted, so an output larger than the budget is still served until it
+// expires. The caller holds rs.mu.
+func (rs *ResultStore) evict(keep string) {
+	for rs.overCapacity() {
+		var oldest *CachedResult
+		for id, result := range rs.results {
+			if id == keep {
+				continue
+			}
+			if oldest == nil || result.LastAccess.Before(oldest.LastAccess) ||
+				(result.LastAccess.Equal(oldest.LastAccess) && result.ID < oldest.ID) {
+				oldest = result
+			}
+		}
+		if oldest == nil {
+			return
+		}
+		rs.remove(oldest.ID)
+		rs.evictions++
+	}
+}
+
+// overCapacity reports whether the store holds more results or bytes than
+// allowed. The caller holds rs.mu.
+func (rs *ResultStore) overCapacity() bool {
+	if maxEntries > 0 && len(rs.results) > maxEntries {
+		return true
+	}
+	if maxBytes > 0 {
+		total := 0
+		for _, content := range rs.contents {
+			total += len(content.rawOutput)
+		}
+		return total > maxBytes
+	}
+	return false
+}
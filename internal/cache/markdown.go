@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RenderMarkdownTable renders a JSON array of objects as a Markdown table for
+// human review. Columns are the union of all object keys in sorted order.
+// Nested objects and arrays are rendered as inline JSON, and missing or null
+// values as empty cells.
+func RenderMarkdownTable(items []interface{}) (string, error) {
+	columnSet := make(map[string]bool)
+	rows := make([]map[string]interface{}, 0, len(items))
+	for i, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("item %d is not an object; markdown format requires an array of objects", i)
+		}
+		for key := range obj {
+			columnSet[key] = true
+		}
+		rows = append(rows, obj)
+	}
+
+	if len(columnSet) == 0 {
+		return "", nil
+	}
+
+	columns := make([]string, 0, len(columnSet))
+	for column := range columnSet {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	var b strings.Builder
+	writeMarkdownRow(&b, columns)
+
+	separator := make([]string, len(columns))
+	for i := range separator {
+		separator[i] = "---"
+	}
+	writeMarkdownRow(&b, separator)
+
+	for _, row := range rows {
+		cells := make([]string, len(columns))
+		for i, column := range columns {
+			cells[i] = markdownCell(row[column])
+		}
+		writeMarkdownRow(&b, cells)
+	}
+
+	return b.String(), nil
+}
+
+// writeMarkdownRow writes one table row with escaped cells.
+func writeMarkdownRow(b *strings.Builder, cells []string) {
+	b.WriteString("|")
+	for _, cell := range cells {
+		b.WriteString(" " + escapeMarkdownCell(cell) + " |")
+	}
+	b.WriteString("\n")
+}
+
+// markdownCell formats a JSON value for a table cell.
+func markdownCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(data)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// escapeMarkdownCell keeps a cell on one line and prevents pipes from
+// breaking the table structure.
+func escapeMarkdownCell(cell string) string {
+	cell = strings.ReplaceAll(cell, "\r\n", " ")
+	cell = strings.ReplaceAll(cell, "\n", " ")
+	return strings.ReplaceAll(cell, "|", "\\|")
+}
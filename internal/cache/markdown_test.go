@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdownTable(t *testing.T) {
+	var services []interface{}
+	input := `[
+		{"ID":"svc1","Name":"production","ActiveVersion":3,"Domains":["www.example.com"]},
+		{"ID":"svc2","Name":"staging | test","Comment":"line one\nline two","Owner":{"team":"edge"}}
+	]`
+	if err := json.Unmarshal([]byte(input), &services); err != nil {
+		t.Fatalf("Invalid test input: %v", err)
+	}
+
+	table, err := RenderMarkdownTable(services)
+	if err != nil {
+		t.Fatalf("RenderMarkdownTable failed: %v", err)
+	}
+
+	expected := "| ActiveVersion | Comment | Domains | ID | Name | Owner |\n" +
+		"| --- | --- | --- | --- | --- | --- |\n" +
+		"| 3 |  | [\"www.example.com\"] | svc1 | production |  |\n" +
+		"|  | line one line two |  | svc2 | staging \\| test | {\"team\":\"edge\"} |\n"
+	if table != expected {
+		t.Errorf("Unexpected table:\n%s\nwant:\n%s", table, expected)
+	}
+
+	// Every row has the same number of cells as the header
+	lines := strings.Split(strings.TrimSpace(table), "\n")
+	headerCells := strings.Count(lines[0], " |")
+	for _, line := range lines[1:] {
+		if cells := strings.Count(line, " |"); cells != headerCells {
+			t.Errorf("Expected %d cells, got %d in %q", headerCells, cells, line)
+		}
+	}
+}
+
+func TestRenderMarkdownTableRequiresObjects(t *testing.T) {
+	if _, err := RenderMarkdownTable([]interface{}{"svc1", "svc2"}); err == nil {
+		t.Error("Expected error for an array of strings")
+	}
+}
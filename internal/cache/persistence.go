@@ -0,0 +1,145 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/fastly/mcp/internal/validation"
+)
+
+// cacheDir is the directory the global store persists results to. When empty,
+// results are kept in memory only. It can be configured via SetCacheDir().
+var cacheDir = ""
+
+// resultIDPattern matches the IDs generated for cached results, which are
+// used as file names in the cache directory.
+var resultIDPattern = regexp.MustCompile(`^result_[0-9a-f]+$`)
+
+// persistedResult is the on-disk form of a cached result.
+type persistedResult struct {
+	ID        string         `json:"id"`
+	RawOutput string         `json:"raw_output"`
+	Metadata  ResultMetadata `json:"metadata"`
+	CreatedAt time.Time      `json:"created_at"`
+	Owner     string         `json:"owner,omitempty"`
+}
+
+// SetCacheDir makes the global store write each cached result to a file in dir
+// and reload unexpired results from it on startup, so result IDs survive a
+// server restart. It must be called before the store is first used. The
+// directory is created if needed.
+func SetCacheDir(dir string) error {
+	if err := validation.NewValidator().ValidatePath(dir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	cacheDir = dir
+	return nil
+}
+
+// persistTo enables persistence to dir and loads the unexpired results found
+// there. Expired result files are deleted.
+func (rs *ResultStore) persistTo(dir string) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	rs.dir = dir
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() || !resultIDPattern.MatchString(id) {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		var persisted persistedResult
+		if err := json.Unmarshal(data, &persisted); err != nil || persisted.ID != id {
+			continue
+		}
+		if now.Sub(persisted.CreatedAt) > rs.ttl {
+			rs.unpersist(id)
+			continue
+		}
+
+		key := contentKey(persisted.RawOutput)
+		content, shared := rs.contents[key]
+		if !shared {
+			dataType, parsed := parseOutput(persisted.RawOutput)
+			content = &sharedContent{rawOutput: persisted.RawOutput, dataType: dataType, data: parsed}
+			rs.contents[key] = content
+		}
+		content.refs++
+
+		rs.results[id] = &CachedResult{
+			ID:         id,
+			Data:       content.data,
+			RawOutput:  content.rawOutput,
+			Metadata:   persisted.Metadata,
+			CreatedAt:  persisted.CreatedAt,
+			LastAccess: persisted.CreatedAt,
+			contentKey: key,
+			owner:      persisted.Owner,
+		}
+	}
+}
+
+// persist writes a cached result to the cache directory, if persistence is
+// enabled. Persistence is best effort: a result that cannot be written is
+// still served from memory. The caller holds rs.mu.
+func (rs *ResultStore) persist(result *CachedResult) {
+	path, ok := rs.resultPath(result.ID)
+	if !ok {
+		return
+	}
+
+	data, err := json.Marshal(persistedResult{
+		ID:        result.ID,
+		RawOutput: result.RawOutput,
+		Metadata:  result.Metadata,
+		CreatedAt: result.CreatedAt,
+		Owner:     result.owner,
+	})
+	if err != nil {
+		return
+	}
+
+	// Write to a temporary file first so a crash never leaves a partial result
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+	}
+}
+
+// unpersist deletes the file of a cached result, if persistence is enabled.
+func (rs *ResultStore) unpersist(id string) {
+	if path, ok := rs.resultPath(id); ok {
+		_ = os.Remove(path)
+	}
+}
+
+// resultPath returns the file a result is persisted to. Only generated result
+// IDs map to a file, so an ID can never point outside the cache directory.
+func (rs *ResultStore) resultPath(id string) (string, bool) {
+	if rs.dir == "" || !resultIDPattern.MatchString(id) {
+		return "", false
+	}
+	return filepath.Join(rs.dir, id+".json"), true
+}
@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+// CachePolicy overrides the size heuristic of ShouldCache for a command.
+type CachePolicy string
+
+const (
+	// CachePolicyAlways caches the output of a command whatever its size.
+	CachePolicyAlways CachePolicy = "always"
+
+	// CachePolicyNever always returns the output of a command inline.
+	// Inline output is still subject to the usual truncation limits.
+	CachePolicyNever CachePolicy = "never"
+)
+
+// commandCachePolicies maps command paths (e.g. "service describe") to their
+// cache policy. It can be configured via SetCommandCachePolicies().
+var commandCachePolicies = map[string]CachePolicy{}
+
+// SetCommandCachePolicies configures per-command cache policies. Keys are
+// command paths; the longest matching path wins, so "service describe"
+// overrides "service".
+func SetCommandCachePolicies(policies map[string]CachePolicy) {
+	normalized := make(map[string]CachePolicy, len(policies))
+	for path, policy := range policies {
+		normalized[strings.Join(strings.Fields(path), " ")] = policy
+	}
+	commandCachePolicies = normalized
+}
+
+// ParseCommandCachePolicies parses a comma-separated list of "command path=policy"
+// entries, e.g. "service describe=never,log-tail=always".
+func ParseCommandCachePolicies(spec string) (map[string]CachePolicy, error) {
+	policies := make(map[string]CachePolicy)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		path, value, found := strings.Cut(entry, "=")
+		path = strings.Join(strings.Fields(path), " ")
+		if !found || path == "" {
+			return nil, fmt.Errorf("invalid cache policy %q: expected 'command=always' or 'command=never'", entry)
+		}
+
+		policy := CachePolicy(strings.ToLower(strings.TrimSpace(value)))
+		if policy != CachePolicyAlways && policy != CachePolicyNever {
+			return nil, fmt.Errorf("invalid cache policy %q: policy must be 'always' or 'never'", entry)
+		}
+		policies[path] = policy
+	}
+	return policies, nil
+}
+
+// commandCachePolicy returns the configured policy for the longest command path
+// that prefixes the given command and arguments.
+func commandCachePolicy(command string, args []string) (CachePolicy, bool) {
+	return types.LongestCommandPathMatch(commandCachePolicies, command, args)
+}
+
+// ShouldCacheCommand determines if the output of a command should be cached.
+// A configured cache policy takes precedence over the size heuristic of
+// ShouldCache. Empty output is never cached.
+func ShouldCacheCommand(output string, command string, args []string) bool {
+	if policy, ok := commandCachePolicy(command, args); ok && strings.TrimSpace(output) != "" {
+		return policy == CachePolicyAlways
+	}
+	return ShouldCache(output)
+}
@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCommandCachePolicies(t *testing.T) {
+	policies, err := ParseCommandCachePolicies("service  describe=never, log-tail=ALWAYS,")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if policies["service describe"] != CachePolicyNever || policies["log-tail"] != CachePolicyAlways || len(policies) != 2 {
+		t.Errorf("Unexpected policies: %v", policies)
+	}
+
+	for _, spec := range []string{"service describe", "=never", "service list=sometimes"} {
+		if _, err := ParseCommandCachePolicies(spec); err == nil {
+			t.Errorf("Expected error for %q", spec)
+		}
+	}
+}
+
+func TestShouldCacheCommand(t *testing.T) {
+	SetCommandCachePolicies(map[string]CachePolicy{
+		"service":          CachePolicyAlways,
+		"service describe": CachePolicyNever,
+	})
+	defer SetCommandCachePolicies(nil)
+
+	large := strings.Repeat("x", DefaultOutputCacheThreshold+1)
+
+	tests := []struct {
+		name     string
+		output   string
+		command  string
+		args     []string
+		expected bool
+	}{
+		{"Never policy above threshold", large, "service", []string{"describe"}, false},
+		{"Always policy below threshold", "small", "service", []string{"list"}, true},
+		{"Always policy with empty output", "  \n", "service", []string{"list"}, false},
+		{"No policy falls back to size", large, "backend", []string{"list"}, true},
+		{"No policy small output", "small", "backend", []string{"list"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ShouldCacheCommand(tt.output, tt.command, tt.args); got != tt.expected {
+				t.Errorf("ShouldCacheCommand() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package cache
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Queries over cached JSON support a small JSONPath subset:
+//
+//   - Dotted paths into nested objects: "tls.cert_hostname"
+//   - Array segments that iterate or index arrays: "backends[].name", "backends[0]"
+//   - Filters on a path: "tls.cert_hostname=example.com"
+//   - Projections over array items: "[].name" or "[].{name,tls.cert_hostname}"
+//   - A filter followed by a projection: "status=active | [].name"
+
+// projectionSeparator separates a filter from the projection applied to the
+// items it matched.
+const projectionSeparator = "|"
+
+// pathSegment is one step of a query path: an object key, optionally followed
+// by an array step that iterates every element or selects one index.
+type pathSegment struct {
+	key     string
+	array   bool
+	iterate bool
+	index   int
+}
+
+// parsePath splits a query path such as "backends[].tls.cert_hostname" into
+// segments.
+func parsePath(path string) ([]pathSegment, error) {
+	var segments []pathSegment
+	for _, part := range strings.Split(path, ".") {
+		segment := pathSegment{key: part}
+		if open := strings.Index(part, "["); open >= 0 {
+			if !strings.HasSuffix(part, "]") {
+				return nil, fmt.Errorf("invalid path %s: unclosed '['", path)
+			}
+			segment.key = part[:open]
+			segment.array = true
+			selector := part[open+1 : len(part)-1]
+			if selector == "" {
+				segment.iterate = true
+			} else {
+				index, err := strconv.Atoi(selector)
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("invalid path %s: array index must be a non-negative integer", path)
+				}
+				segment.index = index
+			}
+		}
+		if segment.key == "" && !segment.array {
+			return nil, fmt.Errorf("invalid path %s: empty field name", path)
+		}
+		segments = append(segments, segment)
+	}
+	return segments, nil
+}
+
+// lookupPath returns the value at path within data. Iterating array segments
+// return the values found for each element, skipping elements without them.
+func lookupPath(data interface{}, path string) (interface{}, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return lookupSegments(data, segments, path, 0)
+}
+
+func lookupSegments(data interface{}, segments []pathSegment, path string, depth int) (interface{}, error) {
+	if depth == len(segments) {
+		return data, nil
+	}
+	segment := segments[depth]
+	traversed := func() string {
+		parts := strings.Split(path, ".")[:depth]
+		return strings.Join(append(parts, segment.key), ".")
+	}
+
+	current := data
+	if segment.key != "" {
+		obj, ok := current.(map[string]interface{})
+		if !ok {
+			if depth == 0 {
+				return nil, fmt.Errorf("data is not a JSON object")
+			}
+			return nil, fmt.Errorf("path %s is not an object", strings.Join(strings.Split(path, ".")[:depth], "."))
+		}
+		val, exists := obj[segment.key]
+		if !exists {
+			return nil, fmt.Errorf("path %s not found", path)
+		}
+		current = val
+	}
+	if !segment.array {
+		return lookupSegments(current, segments, path, depth+1)
+	}
+
+	arr, ok := current.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("path %s is not an array", traversed())
+	}
+	if !segment.iterate {
+		if segment.index >= len(arr) {
+			return nil, fmt.Errorf("index %d out of range for path %s (%d items)", segment.index, traversed(), len(arr))
+		}
+		return lookupSegments(arr[segment.index], segments, path, depth+1)
+	}
+
+	values := make([]interface{}, 0, len(arr))
+	for _, item := range arr {
+		if val, err := lookupSegments(item, segments, path, depth+1); err == nil {
+			values = append(values, val)
+		}
+	}
+	return values, nil
+}
+
+// lookupField returns the value of field in an array item, preferring a key
+// with that exact name over a nested path so that keys containing dots still
+// match.
+func lookupField(item interface{}, field string) (interface{}, bool) {
+	if obj, ok := item.(map[string]interface{}); ok {
+		if val, exists := obj[field]; exists {
+			return val, true
+		}
+	}
+	val, err := lookupPath(item, field)
+	return val, err == nil
+}
+
+// isProjection reports whether expr selects fields from array items, as in
+// "[].name".
+func isProjection(expr string) bool {
+	return strings.HasPrefix(strings.TrimSpace(expr), "[]")
+}
+
+// splitProjection separates "filter | [].fields" into its filter and
+// projection. The projection is empty when the query has none.
+func splitProjection(query string) (string, string) {
+	if isProjection(query) {
+		return "", strings.TrimSpace(query)
+	}
+	if i := strings.LastIndex(query, projectionSeparator); i >= 0 && isProjection(query[i+1:]) {
+		return strings.TrimSpace(query[:i]), strings.TrimSpace(query[i+1:])
+	}
+	return query, ""
+}
+
+// projectItems applies a projection such as "[].name" or "[].{name,tls.cert_hostname}"
+// to array items. A single path returns the values found; a field list returns
+// objects holding only those fields, keyed by path.
+func projectItems(items []interface{}, projection string) ([]interface{}, error) {
+	rest := strings.TrimPrefix(strings.TrimSpace(projection), "[]")
+	if rest == "" {
+		return items, nil
+	}
+	if !strings.HasPrefix(rest, ".") {
+		return nil, fmt.Errorf("invalid projection %s: expected [].field or [].{field,...}", projection)
+	}
+	rest = rest[1:]
+
+	if strings.HasPrefix(rest, "{") {
+		if !strings.HasSuffix(rest, "}") {
+			return nil, fmt.Errorf("invalid projection %s: unclosed '{'", projection)
+		}
+		var fields []string
+		for _, field := range strings.Split(rest[1:len(rest)-1], ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				if _, err := parsePath(field); err != nil {
+					return nil, err
+				}
+				fields = append(fields, field)
+			}
+		}
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("invalid projection %s: no fields selected", projection)
+		}
+
+		projected := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			selected := make(map[string]interface{}, len(fields))
+			for _, field := range fields {
+				if val, ok := lookupField(item, field); ok {
+					selected[field] = val
+				}
+			}
+			projected = append(projected, selected)
+		}
+		return projected, nil
+	}
+
+	if _, err := parsePath(rest); err != nil {
+		return nil, err
+	}
+	projected := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		if val, ok := lookupField(item, rest); ok {
+			projected = append(projected, val)
+		}
+	}
+	return projected, nil
+}
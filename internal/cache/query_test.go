@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResultStore_QueryNestedArray(t *testing.T) {
+	store := NewResultStore(10*time.Minute, 1*time.Hour)
+
+	id := store.Store(`[
+		{"name": "origin-1", "address": "10.0.0.1", "tls": {"cert_hostname": "example.com"}, "pools": [{"name": "primary"}]},
+		{"name": "origin-2", "address": "10.0.0.2", "tls": {"cert_hostname": "other.org"}, "pools": [{"name": "secondary"}]},
+		{"name": "origin-3", "address": "10.0.0.3"},
+		{"name": "origin.dotted", "tls.cert_hostname": "dotted.example.com"}
+	]`, "backend", []string{"list"}, nil)
+
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{"nested filter", "tls.cert_hostname=example.com", `[{"address":"10.0.0.1","name":"origin-1","pools":[{"name":"primary"}],"tls":{"cert_hostname":"example.com"}},{"name":"origin.dotted","tls.cert_hostname":"dotted.example.com"}]`},
+		{"filter through an array", "pools[].name=secondary", `[{"address":"10.0.0.2","name":"origin-2","pools":[{"name":"secondary"}],"tls":{"cert_hostname":"other.org"}}]`},
+		{"projection of one field", "[].name", `["origin-1","origin-2","origin-3","origin.dotted"]`},
+		{"projection skips missing paths", "[].tls.cert_hostname", `["example.com","other.org","dotted.example.com"]`},
+		{"projection of several fields", "[].{name,tls.cert_hostname}", `[{"name":"origin-1","tls.cert_hostname":"example.com"},{"name":"origin-2","tls.cert_hostname":"other.org"},{"name":"origin-3"},{"name":"origin.dotted","tls.cert_hostname":"dotted.example.com"}]`},
+		{"filter then projection", "address=10.0.0.2 | [].name", `["origin-2"]`},
+		{"filter then indexed projection", "name=origin-1 | [].pools[0].name", `["primary"]`},
+		{"simple syntax still works", "name=origin-3", `[{"address":"10.0.0.3","name":"origin-3"}]`},
+		{"text search still works", "OTHER.ORG", `[{"address":"10.0.0.2","name":"origin-2","pools":[{"name":"secondary"}],"tls":{"cert_hostname":"other.org"}}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := store.Query(id, tt.query)
+			if err != nil {
+				t.Fatalf("Query(%q) failed: %v", tt.query, err)
+			}
+			got, _ := json.Marshal(results)
+			if string(got) != tt.expected {
+				t.Errorf("Query(%q) = %s, want %s", tt.query, got, tt.expected)
+			}
+		})
+	}
+
+	for _, query := range []string{"[].{}", "[]name", "pools[x].name=primary"} {
+		if _, err := store.Query(id, query); err == nil {
+			t.Errorf("Expected Query(%q) to fail", query)
+		}
+	}
+}
+
+func TestResultStore_QueryNestedObject(t *testing.T) {
+	store := NewResultStore(10*time.Minute, 1*time.Hour)
+
+	id := store.Store(`{
+		"service": {"name": "www", "versions": [{"number": 1, "active": false}, {"number": 2, "active": true}]},
+		"backends": [{"name": "origin-1"}, {"name": "origin-2"}]
+	}`, "service", []string{"describe"}, nil)
+
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{"dotted path", "service.name", `"www"`},
+		{"top-level key", "backends", `[{"name":"origin-1"},{"name":"origin-2"}]`},
+		{"iterate an array", "backends[].name", `["origin-1","origin-2"]`},
+		{"index an array", "service.versions[1].number", `2`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := store.Query(id, tt.query)
+			if err != nil {
+				t.Fatalf("Query(%q) failed: %v", tt.query, err)
+			}
+			got, _ := json.Marshal(result)
+			if string(got) != tt.expected {
+				t.Errorf("Query(%q) = %s, want %s", tt.query, got, tt.expected)
+			}
+		})
+	}
+
+	errorTests := []struct {
+		query   string
+		errText string
+	}{
+		{"service.missing", "path service.missing not found"},
+		{"service.name.first", "path service.name is not an object"},
+		{"service[].name", "path service is not an array"},
+		{"service.versions[5]", "index 5 out of range"},
+		{"missing", "key missing not found"},
+	}
+	for _, tt := range errorTests {
+		if _, err := store.Query(id, tt.query); err == nil || !strings.Contains(err.Error(), tt.errText) {
+			t.Errorf("Query(%q) error = %v, want %q", tt.query, err, tt.errText)
+		}
+	}
+}
@@ -2,9 +2,11 @@ package cache
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -16,9 +18,24 @@ import (
 type ResultStore struct {
 	mu              sync.RWMutex
 	results         map[string]*CachedResult
+	contents        map[string]*sharedContent
 	ttl             time.Duration
 	cleanupInterval time.Duration
 	stopCleanup     chan bool
+	stopOnce        sync.Once
+	dir             string // Directory results are persisted to, if any
+	evictions       int    // Number of results evicted to stay within capacity
+}
+
+// deterministicIDs controls whether result IDs are derived from the cached
+// content instead of random bytes. It can be configured via SetDeterministicIDs().
+var deterministicIDs = false
+
+// SetDeterministicIDs enables or disables content-hash result IDs. When
+// enabled, identical results map to the same stable ID and are cached once,
+// which makes IDs reproducible in tests.
+func SetDeterministicIDs(enabled bool) {
+	deterministicIDs = enabled
 }
 
 // globalStore is the singleton instance of ResultStore.
@@ -31,6 +48,9 @@ var (
 func GetStore() *ResultStore {
 	storeOnce.Do(func() {
 		globalStore = NewResultStore(DefaultCacheTTL, DefaultCleanupInterval)
+		if cacheDir != "" {
+			globalStore.persistTo(cacheDir)
+		}
 	})
 	return globalStore
 }
@@ -39,6 +59,7 @@ func GetStore() *ResultStore {
 func NewResultStore(ttl time.Duration, cleanupInterval time.Duration) *ResultStore {
 	rs := &ResultStore{
 		results:         make(map[string]*CachedResult),
+		contents:        make(map[string]*sharedContent),
 		ttl:             ttl,
 		cleanupInterval: cleanupInterval,
 		stopCleanup:     make(chan bool),
@@ -65,6 +86,12 @@ func (rs *ResultStore) cleanupLoop() {
 	}
 }
 
+// Shutdown stops the cleanup goroutine. Cached results stay readable, but
+// expired ones are no longer removed. It is safe to call more than once.
+func (rs *ResultStore) Shutdown() {
+	rs.stopOnce.Do(func() { close(rs.stopCleanup) })
+}
+
 // cleanup removes expired entries.
 func (rs *ResultStore) cleanup() {
 	rs.mu.Lock()
@@ -73,33 +100,109 @@ func (rs *ResultStore) cleanup() {
 	now := time.Now()
 	for id, result := range rs.results {
 		if now.Sub(result.CreatedAt) > rs.ttl {
-			delete(rs.results, id)
+			rs.remove(id)
+		}
+	}
+}
+
+// remove deletes a cached result, along with its file when results are
+// persisted, and releases its reference to the shared content, which is
+// dropped with its last reference. The caller holds rs.mu.
+func (rs *ResultStore) remove(id string) {
+	result, exists := rs.results[id]
+	if !exists {
+		return
+	}
+	delete(rs.results, id)
+	rs.unpersist(id)
+
+	if content, ok := rs.contents[result.contentKey]; ok {
+		content.refs--
+		if content.refs <= 0 {
+			delete(rs.contents, result.contentKey)
 		}
 	}
 }
 
-// Store caches a command output and returns its ID.
+// Store caches a command output and returns its ID. Results with identical
+// output share one parsed copy of it, whichever command produced them. With
+// deterministic IDs, storing an identical result again refreshes the existing
+// entry and returns its ID instead of adding a duplicate.
 func (rs *ResultStore) Store(output string, command string, args []string, flags []types.Flag) string {
-	id := generateID()
+	return rs.StoreFor("", output, command, args, flags)
+}
 
-	// Parse the output to determine type and structure
-	dataType, data := parseOutput(output)
-	metadata := generateMetadata(output, dataType, data, command, args, flags)
+// StoreFor is like Store but records the caller that stored the result, so
+// that ClearOwner removes it only on behalf of that caller. An empty owner is
+// the single client of stdio and CLI mode.
+func (rs *ResultStore) StoreFor(owner string, output string, command string, args []string, flags []types.Flag) string {
+	var id string
+	if deterministicIDs {
+		id = contentID(output, command, args, flags)
+		if rs.refresh(id) {
+			return id
+		}
+	} else {
+		id = generateID()
+	}
+
+	// Parse the output to determine type and structure, unless an identical
+	// output is already cached
+	key := contentKey(output)
+	rs.mu.RLock()
+	content, shared := rs.contents[key]
+	rs.mu.RUnlock()
+	if !shared {
+		dataType, data := parseOutput(output)
+		content = &sharedContent{rawOutput: output, dataType: dataType, data: data}
+	}
+	metadata := generateMetadata(content.rawOutput, content.dataType, content.data, command, args, flags)
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	// An identical deterministic result may have been stored meanwhile
+	if existing, exists := rs.results[id]; exists {
+		existing.CreatedAt = time.Now()
+		existing.LastAccess = time.Now()
+		rs.persist(existing)
+		return id
+	}
+	if existing, ok := rs.contents[key]; ok {
+		content = existing
+	} else {
+		rs.contents[key] = content
+	}
+	content.refs++
 
-	result := &CachedResult{
+	rs.results[id] = &CachedResult{
 		ID:         id,
-		Data:       data,
-		RawOutput:  output,
+		Data:       content.data,
+		RawOutput:  content.rawOutput,
 		Metadata:   metadata,
 		CreatedAt:  time.Now(),
 		LastAccess: time.Now(),
+		contentKey: key,
+		owner:      owner,
 	}
+	rs.persist(rs.results[id])
+	rs.evict(id)
+
+	return id
+}
 
+// refresh restarts the TTL of a cached result. It reports whether the result exists.
+func (rs *ResultStore) refresh(id string) bool {
 	rs.mu.Lock()
-	rs.results[id] = result
-	rs.mu.Unlock()
+	defer rs.mu.Unlock()
 
-	return id
+	existing, exists := rs.results[id]
+	if exists {
+		existing.CreatedAt = time.Now()
+		existing.LastAccess = time.Now()
+		rs.persist(existing)
+	}
+	return exists
 }
 
 // Get retrieves a cached result by ID.
@@ -119,6 +222,46 @@ func (rs *ResultStore) Get(id string) (*CachedResult, error) {
 	return result, nil
 }
 
+// Delete removes a cached result before it expires.
+func (rs *ResultStore) Delete(id string) error {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if _, exists := rs.results[id]; !exists {
+		return fmt.Errorf("result with ID %s not found or expired", id)
+	}
+	rs.remove(id)
+	return nil
+}
+
+// Clear removes every cached result and returns how many were removed.
+func (rs *ResultStore) Clear() int {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	count := len(rs.results)
+	for id := range rs.results {
+		rs.remove(id)
+	}
+	return count
+}
+
+// ClearOwner removes the cached results stored by owner with StoreFor and
+// returns how many were removed. Results of other callers are kept.
+func (rs *ResultStore) ClearOwner(owner string) int {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	count := 0
+	for id, result := range rs.results {
+		if result.owner == owner {
+			rs.remove(id)
+			count++
+		}
+	}
+	return count
+}
+
 // Read retrieves a portion of cached data.
 func (rs *ResultStore) Read(id string, offset, limit int) (interface{}, error) {
 	if offset < 0 {
@@ -186,29 +329,34 @@ func (rs *ResultStore) Query(id string, filter string) (interface{}, error) {
 	return nil, fmt.Errorf("unsupported data type for query: %s", result.Metadata.DataType)
 }
 
-// queryJSONArray filters a JSON array based on the filter string.
+// queryJSONArray filters a JSON array based on the filter string. Filters may
+// use nested paths and end with a projection (see query.go).
 func (rs *ResultStore) queryJSONArray(data interface{}, filter string) (interface{}, error) {
 	arr, ok := data.([]interface{})
 	if !ok {
 		return nil, fmt.Errorf("data is not a JSON array")
 	}
 
-	// Simple contains filter for now
-	// Format: "field=value" or "field contains value"
+	filter, projection := splitProjection(filter)
+
+	// Format: "field=value", "nested.field=value" or a full text search term
 	var results []interface{}
 
-	if strings.Contains(filter, "=") {
+	if filter == "" {
+		results = arr
+	} else if strings.Contains(filter, "=") {
 		parts := strings.SplitN(filter, "=", 2)
 		field := strings.TrimSpace(parts[0])
 		value := strings.TrimSpace(parts[1])
+		if _, err := parsePath(field); err != nil {
+			return nil, err
+		}
 
 		for _, item := range arr {
-			if obj, ok := item.(map[string]interface{}); ok {
-				if fieldValue, exists := obj[field]; exists {
-					if fmt.Sprintf("%v", fieldValue) == value ||
-						strings.Contains(fmt.Sprintf("%v", fieldValue), value) {
-						results = append(results, item)
-					}
+			if fieldValue, exists := lookupField(item, field); exists {
+				if fmt.Sprintf("%v", fieldValue) == value ||
+					strings.Contains(fmt.Sprintf("%v", fieldValue), value) {
+					results = append(results, item)
 				}
 			}
 		}
@@ -223,41 +371,30 @@ func (rs *ResultStore) queryJSONArray(data interface{}, filter string) (interfac
 		}
 	}
 
+	if projection != "" {
+		return projectItems(results, projection)
+	}
 	return results, nil
 }
 
-// queryJSONObject returns specific paths from a JSON object.
+// queryJSONObject returns specific paths from a JSON object, such as
+// "service.name", "backends[0].address" or "backends[].name".
 func (rs *ResultStore) queryJSONObject(data interface{}, filter string) (interface{}, error) {
 	obj, ok := data.(map[string]interface{})
 	if !ok {
 		return nil, fmt.Errorf("data is not a JSON object")
 	}
 
-	// Simple path access for now (e.g., "service.name")
-	if strings.Contains(filter, ".") {
-		parts := strings.Split(filter, ".")
-		current := obj
-		for i, part := range parts {
-			if val, exists := current[part]; exists {
-				if i == len(parts)-1 {
-					return val, nil
-				}
-				if nextObj, ok := val.(map[string]interface{}); ok {
-					current = nextObj
-				} else {
-					return nil, fmt.Errorf("path %s is not an object", strings.Join(parts[:i+1], "."))
-				}
-			} else {
-				return nil, fmt.Errorf("path %s not found", filter)
-			}
-		}
-	}
-
 	// Return specific key if it exists
 	if val, exists := obj[filter]; exists {
 		return val, nil
 	}
 
+	// Path access into nested objects and arrays
+	if strings.ContainsAny(filter, ".[") {
+		return lookupPath(obj, filter)
+	}
+
 	return nil, fmt.Errorf("key %s not found", filter)
 }
 
@@ -318,18 +455,72 @@ func (rs *ResultStore) GetSummary(id string) (map[string]interface{}, error) {
 	case "text":
 		lines := strings.Split(result.RawOutput, "\n")
 		summary["total_lines"] = len(lines)
+		if sections := buildSectionIndex(lines); sections != nil {
+			summary["sections"] = sections
+		}
 	}
 
 	return summary, nil
 }
 
-// List returns all active cached results.
+// buildSectionIndex splits a large text result into consecutive line ranges,
+// each labelled with its first non-blank line, so callers can read the part
+// they need instead of paging through the whole output.
+func buildSectionIndex(lines []string) []TextSection {
+	if len(lines) <= SectionIndexLines {
+		return nil
+	}
+
+	size := SectionIndexLines
+	if perSection := (len(lines) + MaxIndexSections - 1) / MaxIndexSections; perSection > size {
+		size = perSection
+	}
+
+	var sections []TextSection
+	for offset := 0; offset < len(lines); offset += size {
+		end := min(offset+size, len(lines))
+		sections = append(sections, TextSection{
+			Offset: offset,
+			Limit:  end - offset,
+			Label:  sectionLabel(lines[offset:end]),
+		})
+	}
+	return sections
+}
+
+// sectionLabel returns the first non-blank line, cut to MaxSectionLabelLength.
+func sectionLabel(lines []string) string {
+	for _, line := range lines {
+		label := strings.TrimSpace(line)
+		if label == "" {
+			continue
+		}
+		if runes := []rune(label); len(runes) > MaxSectionLabelLength {
+			label = string(runes[:MaxSectionLabelLength]) + "..."
+		}
+		return label
+	}
+	return ""
+}
+
+// List returns all active cached results, most recently created first.
 func (rs *ResultStore) List() []map[string]interface{} {
 	rs.mu.RLock()
 	defer rs.mu.RUnlock()
 
-	var results []map[string]interface{}
+	ordered := make([]*CachedResult, 0, len(rs.results))
 	for _, result := range rs.results {
+		ordered = append(ordered, result)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		if !ordered[i].CreatedAt.Equal(ordered[j].CreatedAt) {
+			return ordered[i].CreatedAt.After(ordered[j].CreatedAt)
+		}
+		return ordered[i].ID < ordered[j].ID
+	})
+
+	var results []map[string]interface{}
+	for _, result := range ordered {
 		results = append(results, map[string]interface{}{
 			"id":            result.ID,
 			"command":       result.Metadata.Command,
@@ -338,12 +529,52 @@ func (rs *ResultStore) List() []map[string]interface{} {
 			"size":          result.Metadata.TotalSize,
 			"created_at":    result.CreatedAt,
 			"ttl_remaining": rs.ttl - time.Since(result.CreatedAt),
+			"service_id":    resultServiceID(result.Metadata.Flags),
 		})
 	}
 
 	return results
 }
 
+// resultServiceID returns the --service-id a result was produced with, if any.
+func resultServiceID(flags []types.Flag) string {
+	for _, flag := range flags {
+		if flag.Name == "service-id" {
+			return flag.Value
+		}
+	}
+	return ""
+}
+
+// Stats returns the number of cached results, the number and combined size of
+// their distinct outputs and the creation times of the oldest and newest
+// result. Expired results awaiting cleanup are included, since they still use
+// memory.
+func (rs *ResultStore) Stats() StoreStats {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+
+	stats := StoreStats{
+		Entries:       len(rs.results),
+		UniqueOutputs: len(rs.contents),
+		TTLSeconds:    int(rs.ttl.Seconds()),
+		Evictions:     rs.evictions,
+	}
+	for _, content := range rs.contents {
+		stats.TotalBytes += len(content.rawOutput)
+	}
+	for _, result := range rs.results {
+		if stats.Oldest.IsZero() || result.CreatedAt.Before(stats.Oldest) {
+			stats.Oldest = result.CreatedAt
+		}
+		if result.CreatedAt.After(stats.Newest) {
+			stats.Newest = result.CreatedAt
+		}
+	}
+
+	return stats
+}
+
 // parseOutput determines the type of output and parses it if JSON.
 func parseOutput(output string) (string, interface{}) {
 	trimmed := strings.TrimSpace(output)
@@ -356,6 +587,9 @@ func parseOutput(output string) (string, interface{}) {
 	if json.Valid([]byte(trimmed)) {
 		var data interface{}
 		if err := json.Unmarshal([]byte(trimmed), &data); err == nil {
+			if normalizeBooleans {
+				data = normalizeBooleanStrings(data)
+			}
 			switch v := data.(type) {
 			case []interface{}:
 				return "json_array", v
@@ -403,6 +637,27 @@ func generateID() string {
 	return "result_" + hex.EncodeToString(bytes)
 }
 
+// contentKey returns the hash by which identical outputs share content.
+func contentKey(output string) string {
+	sum := sha256.Sum256([]byte(output))
+	return hex.EncodeToString(sum[:])
+}
+
+// contentID derives the ID of a cached result from its output and the command
+// that produced it, so identical results always get the same ID.
+func contentID(output string, command string, args []string, flags []types.Flag) string {
+	hash := sha256.New()
+	key, _ := json.Marshal(struct {
+		Command string       `json:"command"`
+		Args    []string     `json:"args"`
+		Flags   []types.Flag `json:"flags"`
+	}{command, args, flags})
+	hash.Write(key)
+	hash.Write([]byte{0})
+	hash.Write([]byte(output))
+	return "result_" + hex.EncodeToString(hash.Sum(nil)[:8])
+}
+
 // min returns the minimum of two integers.
 func min(a, b int) int {
 	if a < b {
@@ -2,6 +2,9 @@ package cache
 
 import (
 	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -246,3 +249,372 @@ func TestResultStore_Expiration(t *testing.T) {
 		t.Fatal("Result should have expired")
 	}
 }
+
+func TestResultStore_Shutdown(t *testing.T) {
+	store := NewResultStore(10*time.Millisecond, 10*time.Millisecond)
+	store.Shutdown()
+	store.Shutdown()
+
+	store.Store("test data", "test", nil, nil)
+	time.Sleep(50 * time.Millisecond)
+
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	if len(store.results) != 1 {
+		t.Errorf("Expected the stopped cleanup to leave the expired result, got %d results", len(store.results))
+	}
+}
+
+func TestResultStore_ListMostRecentFirst(t *testing.T) {
+	store := NewResultStore(10*time.Minute, 1*time.Hour)
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		ids = append(ids, store.Store("test data", "test", nil, nil))
+		time.Sleep(time.Millisecond)
+	}
+
+	list := store.List()
+	if len(list) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(list))
+	}
+	for i, want := range []string{ids[2], ids[1], ids[0]} {
+		if list[i]["id"] != want {
+			t.Errorf("Expected result %d to be %s, got %v", i, want, list[i]["id"])
+		}
+	}
+}
+
+func TestResultStore_Stats(t *testing.T) {
+	store := NewResultStore(10*time.Minute, 1*time.Hour)
+
+	empty := store.Stats()
+	if empty.Entries != 0 || empty.TotalBytes != 0 || !empty.Oldest.IsZero() {
+		t.Errorf("Expected empty stats, got %+v", empty)
+	}
+
+	outputs := []string{`[{"id": 1}]`, "plain text output", `{"name": "service"}`}
+	before := time.Now()
+	totalBytes := 0
+	for _, output := range outputs {
+		store.Store(output, "service", []string{"list"}, nil)
+		totalBytes += len(output)
+	}
+	after := time.Now()
+
+	stats := store.Stats()
+	if stats.Entries != len(outputs) {
+		t.Errorf("Expected %d entries, got %d", len(outputs), stats.Entries)
+	}
+	if stats.TotalBytes != totalBytes {
+		t.Errorf("Expected %d bytes, got %d", totalBytes, stats.TotalBytes)
+	}
+	if stats.Oldest.Before(before) || stats.Newest.After(after) || stats.Newest.Before(stats.Oldest) {
+		t.Errorf("Unexpected timestamps: oldest %v, newest %v", stats.Oldest, stats.Newest)
+	}
+	if stats.TTLSeconds != 600 {
+		t.Errorf("Expected TTL of 600 seconds, got %d", stats.TTLSeconds)
+	}
+}
+
+func TestResultStore_SummarySectionIndex(t *testing.T) {
+	store := NewResultStore(10*time.Minute, 1*time.Hour)
+
+	// 250 lines: a header every 100 lines, the second section starting blank
+	lines := make([]string, 250)
+	for i := range lines {
+		lines[i] = "detail line"
+	}
+	lines[0] = "== backends =="
+	lines[100] = ""
+	lines[101] = "== domains =="
+	lines[200] = "== " + strings.Repeat("x", 200)
+	id := store.Store(strings.Join(lines, "\n"), "service", []string{"describe"}, nil)
+
+	summary, err := store.GetSummary(id)
+	if err != nil {
+		t.Fatalf("Failed to get summary: %v", err)
+	}
+	sections, ok := summary["sections"].([]TextSection)
+	if !ok || len(sections) != 3 {
+		t.Fatalf("Expected 3 sections, got %#v", summary["sections"])
+	}
+	if sections[0] != (TextSection{Offset: 0, Limit: 100, Label: "== backends =="}) {
+		t.Errorf("Unexpected first section: %+v", sections[0])
+	}
+	if sections[1] != (TextSection{Offset: 100, Limit: 100, Label: "== domains =="}) {
+		t.Errorf("Expected blank lines to be skipped for the label, got %+v", sections[1])
+	}
+	if sections[2].Offset != 200 || sections[2].Limit != 50 || len([]rune(sections[2].Label)) != MaxSectionLabelLength+3 {
+		t.Errorf("Expected a truncated label on the last section, got %+v", sections[2])
+	}
+
+	short := store.Store("one\ntwo\nthree", "service", []string{"describe"}, nil)
+	summary, err = store.GetSummary(short)
+	if err != nil {
+		t.Fatalf("Failed to get summary: %v", err)
+	}
+	if _, ok := summary["sections"]; ok {
+		t.Error("Expected no section index for a short text result")
+	}
+}
+
+func TestBuildSectionIndexCapsSections(t *testing.T) {
+	lines := make([]string, SectionIndexLines*MaxIndexSections*2)
+	sections := buildSectionIndex(lines)
+	if len(sections) != MaxIndexSections {
+		t.Errorf("Expected %d sections, got %d", MaxIndexSections, len(sections))
+	}
+	if sections[0].Limit != SectionIndexLines*2 {
+		t.Errorf("Expected sections of %d lines, got %d", SectionIndexLines*2, sections[0].Limit)
+	}
+}
+
+func TestResultStore_DeterministicIDs(t *testing.T) {
+	SetDeterministicIDs(true)
+	defer SetDeterministicIDs(false)
+
+	store := NewResultStore(10*time.Minute, 1*time.Hour)
+	output := `[{"id": 1, "name": "service1"}]`
+	flags := []types.Flag{{Name: "json"}}
+
+	first := store.Store(output, "service", []string{"list"}, flags)
+	second := store.Store(output, "service", []string{"list"}, flags)
+	if first != second {
+		t.Errorf("Expected identical output to get the same ID, got %s and %s", first, second)
+	}
+	if entries := store.Stats().Entries; entries != 1 {
+		t.Errorf("Expected identical output to be cached once, got %d entries", entries)
+	}
+	if other := NewResultStore(10*time.Minute, 1*time.Hour).Store(output, "service", []string{"list"}, flags); other != first {
+		t.Errorf("Expected the ID to be stable across stores, got %s and %s", first, other)
+	}
+
+	if changed := store.Store(`[{"id": 2}]`, "service", []string{"list"}, flags); changed == first {
+		t.Error("Expected different output to get a different ID")
+	}
+	if otherCommand := store.Store(output, "backend", []string{"list"}, flags); otherCommand == first {
+		t.Error("Expected the same output from another command to get a different ID")
+	}
+}
+
+func TestResultStore_SharedContent(t *testing.T) {
+	store := NewResultStore(10*time.Minute, 1*time.Hour)
+	output := `[{"id": 1, "name": "service1"}, {"id": 2, "name": "service2"}]`
+
+	first := store.Store(output, "service", []string{"list"}, nil)
+	second := store.Store(output, "service", []string{"list"}, nil)
+	if first == second {
+		t.Fatal("Expected random IDs for two stores")
+	}
+	if len(store.contents) != 1 {
+		t.Fatalf("Expected one underlying entry, got %d", len(store.contents))
+	}
+
+	a, err := store.Get(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := store.Get(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.contentKey != b.contentKey || &a.Data.([]interface{})[0] != &b.Data.([]interface{})[0] {
+		t.Error("Expected both IDs to resolve to the same parsed content")
+	}
+
+	stats := store.Stats()
+	if stats.Entries != 2 || stats.UniqueOutputs != 1 || stats.TotalBytes != len(output) {
+		t.Errorf("Expected 2 entries sharing %d bytes, got %+v", len(output), stats)
+	}
+
+	store.mu.Lock()
+	store.remove(first)
+	remaining := len(store.contents)
+	store.remove(second)
+	store.mu.Unlock()
+	if remaining != 1 {
+		t.Error("Expected the content to stay while a result still references it")
+	}
+	if len(store.contents) != 0 {
+		t.Error("Expected the content to be dropped with its last reference")
+	}
+}
+
+func TestResultStore_Persistence(t *testing.T) {
+	dir := t.TempDir()
+
+	store := NewResultStore(time.Hour, time.Hour)
+	store.persistTo(dir)
+	jsonID := store.Store(`[{"name": "origin-1"}, {"name": "origin-2"}]`, "backend", []string{"list"}, nil)
+	textID := store.Store("line one\nline two", "log-tail", nil, nil)
+
+	// Expired and foreign files are not loaded
+	expired, _ := json.Marshal(persistedResult{ID: "result_0000000000000000", RawOutput: "old", CreatedAt: time.Now().Add(-2 * time.Hour)})
+	if err := os.WriteFile(filepath.Join(dir, "result_0000000000000000.json"), expired, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.json"), []byte(`{"id": "notes"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// A restarted store serves the same IDs
+	restarted := NewResultStore(time.Hour, time.Hour)
+	restarted.persistTo(dir)
+
+	data, err := restarted.Read(jsonID, 1, 1)
+	if err != nil {
+		t.Fatalf("Expected %s to survive a restart: %v", jsonID, err)
+	}
+	if items, ok := data.([]interface{}); !ok || len(items) != 1 || items[0].(map[string]interface{})["name"] != "origin-2" {
+		t.Errorf("Unexpected data after restart: %v", data)
+	}
+	if lines, err := restarted.Read(textID, 0, 10); err != nil || len(lines.([]string)) != 2 {
+		t.Errorf("Expected the text result to survive a restart, got %v (%v)", lines, err)
+	}
+	if _, err := restarted.Get("result_0000000000000000"); err == nil {
+		t.Error("Expected the expired result not to be loaded")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "result_0000000000000000.json")); !os.IsNotExist(err) {
+		t.Errorf("Expected the expired result file to be deleted, got %v", err)
+	}
+
+	// Cleanup deletes the files of expired results
+	restarted.mu.Lock()
+	restarted.results[textID].CreatedAt = time.Now().Add(-2 * time.Hour)
+	restarted.mu.Unlock()
+	restarted.cleanup()
+	if _, err := os.Stat(filepath.Join(dir, textID+".json")); !os.IsNotExist(err) {
+		t.Errorf("Expected cleanup to delete the file of %s, got %v", textID, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, jsonID+".json")); err != nil {
+		t.Errorf("Expected the file of %s to remain: %v", jsonID, err)
+	}
+}
+
+func TestSetCacheDir(t *testing.T) {
+	defer func() { cacheDir = "" }()
+
+	dir := filepath.Join(t.TempDir(), "results")
+	if err := SetCacheDir(dir); err != nil {
+		t.Fatalf("SetCacheDir failed: %v", err)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		t.Errorf("Expected the cache directory to be created, got %v", err)
+	}
+	if err := SetCacheDir(dir + "/../other"); err == nil {
+		t.Error("Expected a traversal path to be rejected")
+	}
+}
+
+func TestResultStore_EvictLeastRecentlyAccessed(t *testing.T) {
+	SetMaxEntries(3)
+	defer SetMaxEntries(0)
+
+	store := NewResultStore(time.Hour, time.Hour)
+	var ids []string
+	for i := 0; i < 3; i++ {
+		ids = append(ids, store.Store(fmt.Sprintf("output %d", i), "service", []string{"list"}, nil))
+		time.Sleep(time.Millisecond)
+	}
+
+	// Reading the oldest result makes the second one the least recently accessed
+	if _, err := store.Read(ids[0], 0, 10); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	time.Sleep(time.Millisecond)
+	newest := store.Store("output 3", "service", []string{"list"}, nil)
+
+	if _, err := store.Get(ids[1]); err == nil {
+		t.Errorf("Expected the least recently accessed result %s to be evicted", ids[1])
+	}
+	for _, id := range []string{ids[0], ids[2], newest} {
+		if _, err := store.Get(id); err != nil {
+			t.Errorf("Expected %s to survive: %v", id, err)
+		}
+	}
+	if stats := store.Stats(); stats.Entries != 3 || stats.Evictions != 1 {
+		t.Errorf("Expected 3 entries after 1 eviction, got %+v", stats)
+	}
+}
+
+func TestResultStore_EvictOverByteBudget(t *testing.T) {
+	SetMaxBytes(250)
+	defer SetMaxBytes(0)
+
+	store := NewResultStore(time.Hour, time.Hour)
+	first := store.Store(strings.Repeat("a", 100), "service", []string{"list"}, nil)
+	time.Sleep(time.Millisecond)
+	second := store.Store(strings.Repeat("b", 100), "service", []string{"list"}, nil)
+	time.Sleep(time.Millisecond)
+	third := store.Store(strings.Repeat("c", 100), "service", []string{"list"}, nil)
+
+	if _, err := store.Get(first); err == nil {
+		t.Error("Expected the oldest result to be evicted to stay within the byte budget")
+	}
+	if stats := store.Stats(); stats.TotalBytes != 200 {
+		t.Errorf("Expected 200 bytes cached, got %+v", stats)
+	}
+
+	// A result larger than the whole budget is kept on its own
+	huge := store.Store(strings.Repeat("d", 400), "service", []string{"list"}, nil)
+	if _, err := store.Get(huge); err != nil {
+		t.Errorf("Expected the oversized result to be kept: %v", err)
+	}
+	for _, id := range []string{second, third} {
+		if _, err := store.Get(id); err == nil {
+			t.Errorf("Expected %s to be evicted for the oversized result", id)
+		}
+	}
+}
+
+func TestResultStore_Delete(t *testing.T) {
+	store := NewResultStore(10*time.Minute, 1*time.Hour)
+	first := store.Store(`[{"id": 1}]`, "service", []string{"list"}, nil)
+	second := store.Store("plain text", "version", []string{"list"}, nil)
+
+	if err := store.Delete(first); err != nil {
+		t.Fatalf("Expected the result to be deleted, got %v", err)
+	}
+	if _, err := store.Get(first); err == nil {
+		t.Error("Expected a deleted result to be gone")
+	}
+	if err := store.Delete(first); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected deleting an absent ID to fail, got %v", err)
+	}
+	if _, err := store.Get(second); err != nil {
+		t.Errorf("Expected other results to remain, got %v", err)
+	}
+
+	store.Store(`[{"id": 2}]`, "backend", []string{"list"}, nil)
+	if cleared := store.Clear(); cleared != 2 {
+		t.Errorf("Expected 2 results cleared, got %d", cleared)
+	}
+	if stats := store.Stats(); stats.Entries != 0 || stats.TotalBytes != 0 {
+		t.Errorf("Expected an empty store, got %+v", stats)
+	}
+}
+
+func TestResultStore_ClearOwner(t *testing.T) {
+	dir := t.TempDir()
+	store := NewResultStore(10*time.Minute, 1*time.Hour)
+	store.persistTo(dir)
+	mine := store.StoreFor("client:alice", `[{"id": 1}]`, "service", []string{"list"}, nil)
+	theirs := store.StoreFor("client:bob", `[{"id": 2}]`, "service", []string{"list"}, nil)
+	unowned := store.Store(`[{"id": 3}]`, "service", []string{"list"}, nil)
+
+	// The owner survives a restart
+	restarted := NewResultStore(10*time.Minute, 1*time.Hour)
+	restarted.persistTo(dir)
+	if cleared := restarted.ClearOwner("client:alice"); cleared != 1 {
+		t.Errorf("Expected only the result of the owner to be cleared, got %d", cleared)
+	}
+	if _, err := restarted.Get(mine); err == nil {
+		t.Error("Expected the result of the owner to be gone")
+	}
+	for _, id := range []string{theirs, unowned} {
+		if _, err := restarted.Get(id); err != nil {
+			t.Errorf("Expected the results of other callers to remain, got %v", err)
+		}
+	}
+}
@@ -17,6 +17,17 @@ type CachedResult struct {
 	CreatedAt   time.Time      `json:"created_at"`
 	AccessCount int            `json:"access_count"`
 	LastAccess  time.Time      `json:"last_access"`
+	contentKey  string         // Key of the shared content in the store
+	owner       string         // Caller that stored the result, see StoreFor
+}
+
+// sharedContent is an output cached once for all results that produced it.
+// Results are removed one by one; the content goes with its last reference.
+type sharedContent struct {
+	rawOutput string
+	dataType  string
+	data      interface{}
+	refs      int
 }
 
 // ResultMetadata contains information about the cached result.
@@ -44,6 +55,25 @@ type Preview struct {
 	Truncated  bool        `json:"truncated"`             // Whether preview is truncated
 }
 
+// StoreStats describes the memory footprint of a result store.
+type StoreStats struct {
+	Entries       int       `json:"entries"`                    // Number of cached results
+	UniqueOutputs int       `json:"unique_outputs"`             // Number of distinct outputs stored for them
+	TotalBytes    int       `json:"total_bytes"`                // Combined size of the distinct cached outputs
+	Oldest        time.Time `json:"oldest_created_at,omitzero"` // Creation time of the oldest result
+	Newest        time.Time `json:"newest_created_at,omitzero"` // Creation time of the newest result
+	TTLSeconds    int       `json:"ttl_seconds"`                // How long results are kept
+	Evictions     int       `json:"evictions"`                  // Results evicted to stay within the entry or byte limit
+}
+
+// TextSection is one entry of the section index of a cached text result. Offset
+// and Limit can be passed straight to fastly_result_read.
+type TextSection struct {
+	Offset int    `json:"offset"` // First line of the section
+	Limit  int    `json:"limit"`  // Number of lines in the section
+	Label  string `json:"label"`  // First non-blank line of the section
+}
+
 // CachedResponse is returned when a command output is cached.
 type CachedResponse struct {
 	Success      bool           `json:"success"`
@@ -81,6 +111,10 @@ const (
 	// DefaultOutputCacheThreshold is the default minimum size (in bytes) for caching.
 	DefaultOutputCacheThreshold = 25000 // 25KB
 
+	// MinOutputCacheThreshold is the smallest cache threshold (in bytes) accepted
+	// from configuration. Smaller thresholds would cache nearly every output.
+	MinOutputCacheThreshold = 1000 // 1KB
+
 	// MaxPreviewItems is the maximum number of items to include in preview.
 	MaxPreviewItems = 5
 
@@ -89,6 +123,23 @@ const (
 
 	// DefaultReadLimit is the default number of items/lines to return.
 	DefaultReadLimit = 20
+
+	// MaxMultiReadResults is the maximum number of results read in one multi-read call.
+	MaxMultiReadResults = 10
+
+	// MaxMultiReadSize is the maximum combined size (in bytes) of a multi-read response.
+	MaxMultiReadSize = 50000 // 50KB
+
+	// SectionIndexLines is the number of lines per section in the index of a
+	// text result. Shorter results get no index.
+	SectionIndexLines = 100
+
+	// MaxIndexSections is the maximum number of sections in a text result index.
+	// Sections grow beyond SectionIndexLines to stay within it.
+	MaxIndexSections = 50
+
+	// MaxSectionLabelLength is the maximum length (in runes) of a section label.
+	MaxSectionLabelLength = 80
 )
 
 // Variables for configurable settings.
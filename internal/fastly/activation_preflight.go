@@ -0,0 +1,122 @@
+package fastly
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+// globalValidateBeforeActivate controls whether service version activation is
+// preceded by a validation preflight. It can be configured via SetValidateBeforeActivate().
+var globalValidateBeforeActivate = false
+
+// preflightFlags are the activation flags forwarded to the validation command.
+var preflightFlags = map[string]bool{
+	"service-id":   true,
+	"service-name": true,
+	"version":      true,
+}
+
+// unsupportedValidationPatterns indicate that the installed Fastly CLI has no
+// validation command, in which case the preflight is skipped.
+var unsupportedValidationPatterns = []string{
+	"unknown command",
+	"expected command",
+	"unexpected validate",
+}
+
+// SetValidateBeforeActivate enables or disables the validation preflight for
+// service-version activate. When enabled, the target version is validated first
+// and activation is refused if validation fails.
+func SetValidateBeforeActivate(enabled bool) {
+	globalValidateBeforeActivate = enabled
+}
+
+// isActivation reports whether a request activates a service version.
+func isActivation(command string, args []string) bool {
+	return command == "service-version" && len(args) > 0 && args[0] == "activate"
+}
+
+// runActivationPreflight validates the version targeted by an activation. It
+// returns a failure response when activation must be refused, or a note to add
+// to the response when the preflight could not run. Both are empty when the
+// preflight is disabled, does not apply, or validation passed.
+func runActivationPreflight(ctx context.Context, req types.CommandRequest, flags []types.Flag) (*types.CommandResponse, string) {
+	if !globalValidateBeforeActivate || !isActivation(req.Command, req.Args) {
+		return nil, ""
+	}
+
+	args := []string{"service-version", "validate"}
+	var validateFlags []types.Flag
+	for _, flag := range flags {
+		if preflightFlags[flag.Name] {
+			validateFlags = append(validateFlags, flag)
+			args = append(args, "--"+flag.Name, flag.Value)
+		}
+	}
+	if shouldInjectNonInteractive("service-version", []string{"validate"}) {
+		args = append(args, "--non-interactive")
+	}
+	args = append(args, ProfileArgs()...)
+
+	result := RunFastlyCommand(CommandRunConfig{
+		Context:            ctx,
+		Command:            "fastly",
+		Args:               args,
+		Timeout:            globalCommandTimeout,
+		FirstOutputTimeout: globalFirstOutputTimeout,
+	})
+
+	if result.Error == nil {
+		return nil, ""
+	}
+
+	output := strings.TrimSpace(CleanANSI(result.Stderr + "\n" + result.Stdout))
+	if globalSanitizeOpts.Enabled {
+		output = SanitizeOutput(output, globalSanitizeOpts)
+	}
+
+	// A validation that did not finish says nothing about the version
+	if result.Cancelled || result.TimedOut || result.Stalled {
+		reason := "timed out"
+		if result.Cancelled {
+			reason = "was cancelled"
+		} else if result.Stalled {
+			reason = "produced no output"
+		}
+		response := NewResponseBuilder().
+			WithCommand(req.Command, req.Args, flags).
+			WithError(fmt.Errorf("activation refused: the validation preflight %s before it finished", reason), "preflight_unavailable").
+			WithInstructions("The version could not be validated before activation, so it was not activated. This does not mean the version is invalid. Production traffic is unchanged.", []string{
+				"Retry the activation; the validation preflight runs again",
+				"If it keeps failing, check that the Fastly API is reachable, or run the validation command below on its own",
+				"Validation command: " + BuildCommandLine("service-version", []string{"validate"}, validateFlags),
+			}).
+			Build()
+		response.Output = output
+		response.Metadata = GetOperationMetadata(req.Command, req.Args)
+		return &response, ""
+	}
+
+	lowerOutput := strings.ToLower(output)
+	for _, pattern := range unsupportedValidationPatterns {
+		if strings.Contains(lowerOutput, pattern) {
+			return nil, "Validation preflight skipped: the installed Fastly CLI cannot validate service versions."
+		}
+	}
+
+	response := NewResponseBuilder().
+		WithCommand(req.Command, req.Args, flags).
+		WithError(fmt.Errorf("activation refused: the version did not pass validation"), "validation_failed").
+		WithInstructions("The version was validated before activation and the validation failed, so it was not activated. Production traffic is unchanged.", []string{
+			"Review the validation errors in the output",
+			"Fix the configuration of the draft version and try activating again",
+			"Validation command: " + BuildCommandLine("service-version", []string{"validate"}, validateFlags),
+		}).
+		Build()
+	response.Output = output
+	response.Metadata = GetOperationMetadata(req.Command, req.Args)
+	return &response, ""
+}
@@ -0,0 +1,161 @@
+package fastly
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+// activationRequest is a reviewed activation of version 3 of a service.
+var activationRequest = types.CommandRequest{
+	Command: "service-version",
+	Args:    []string{"activate"},
+	Flags: []types.Flag{
+		{Name: "service-id", Value: "SVC123"},
+		{Name: "version", Value: "3"},
+		{Name: "user-reviewed"},
+	},
+}
+
+func TestActivationBlockedByFailedValidation(t *testing.T) {
+	callsFile := installMockFastly(t, `case "$*" in
+*"service-version validate"*)
+	echo "Error: backend origin_a has no address" >&2
+	exit 1 ;;
+*)
+	echo "Activated service SVC123 version 3" ;;
+esac`)
+
+	SetValidateBeforeActivate(true)
+	defer SetValidateBeforeActivate(false)
+
+	result := ExecuteCommand(activationRequest)
+
+	if result.Success {
+		t.Fatal("Expected activation to be refused")
+	}
+	if result.ErrorCode != "validation_failed" {
+		t.Errorf("Expected error code 'validation_failed', got %q", result.ErrorCode)
+	}
+	if !strings.Contains(result.Output, "backend origin_a has no address") {
+		t.Errorf("Expected validation errors in the output, got %q", result.Output)
+	}
+
+	calls, err := os.ReadFile(callsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(calls), "service-version validate --service-id SVC123 --version 3") {
+		t.Errorf("Expected the version to be validated, got calls:\n%s", calls)
+	}
+	if strings.Contains(string(calls), "service-version activate") {
+		t.Errorf("Expected activation not to run, got calls:\n%s", calls)
+	}
+}
+
+func TestActivationPreflightUnavailable(t *testing.T) {
+	callsFile := installMockFastly(t, `case "$*" in
+*"service-version validate"*)
+	sleep 1 ;;
+*)
+	echo "Activated service SVC123 version 3" ;;
+esac`)
+
+	SetValidateBeforeActivate(true)
+	defer SetValidateBeforeActivate(false)
+	SetCommandTimeout(200 * time.Millisecond)
+	defer SetCommandTimeout(CommandTimeout)
+
+	result := ExecuteCommand(activationRequest)
+
+	if result.Success {
+		t.Fatal("Expected activation to be refused")
+	}
+	if result.ErrorCode != "preflight_unavailable" || !strings.Contains(result.Error, "timed out") {
+		t.Errorf("Expected a timed out preflight to be reported as unavailable, got %q: %s", result.ErrorCode, result.Error)
+	}
+	if !strings.Contains(result.NextSteps[0], "Retry") {
+		t.Errorf("Expected retry instructions, got %v", result.NextSteps)
+	}
+
+	calls, err := os.ReadFile(callsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(calls), "service-version activate") {
+		t.Errorf("Expected activation not to run, got calls:\n%s", calls)
+	}
+}
+
+func TestActivationAfterSuccessfulValidation(t *testing.T) {
+	callsFile := installMockFastly(t, `echo "ok"`)
+
+	SetValidateBeforeActivate(true)
+	defer SetValidateBeforeActivate(false)
+
+	result := ExecuteCommand(activationRequest)
+
+	if !result.Success {
+		t.Fatalf("Expected activation to succeed, got error: %s", result.Error)
+	}
+
+	calls, err := os.ReadFile(callsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(calls)), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "service-version validate") || !strings.HasPrefix(lines[1], "service-version activate") {
+		t.Errorf("Expected validate then activate, got calls:\n%s", calls)
+	}
+}
+
+func TestActivationPreflightSkippedWhenUnsupported(t *testing.T) {
+	callsFile := installMockFastly(t, `case "$*" in
+*"service-version validate"*)
+	echo "error: expected command but got \"validate\"" >&2
+	exit 1 ;;
+*)
+	echo "Activated" ;;
+esac`)
+
+	SetValidateBeforeActivate(true)
+	defer SetValidateBeforeActivate(false)
+
+	result := ExecuteCommand(activationRequest)
+
+	if !result.Success {
+		t.Fatalf("Expected activation to proceed, got error: %s", result.Error)
+	}
+	if !strings.Contains(result.Instructions, "Validation preflight skipped") {
+		t.Errorf("Expected a note that the preflight was skipped, got %q", result.Instructions)
+	}
+
+	calls, err := os.ReadFile(callsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(calls), "service-version activate") {
+		t.Errorf("Expected activation to run, got calls:\n%s", calls)
+	}
+}
+
+func TestActivationPreflightDisabled(t *testing.T) {
+	callsFile := installMockFastly(t, `echo "Activated"`)
+
+	result := ExecuteCommand(activationRequest)
+
+	if !result.Success {
+		t.Fatalf("Expected activation to succeed, got error: %s", result.Error)
+	}
+
+	calls, err := os.ReadFile(callsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(calls), "validate") {
+		t.Errorf("Expected no validation without --validate-before-activate, got calls:\n%s", calls)
+	}
+}
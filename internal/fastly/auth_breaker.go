@@ -0,0 +1,209 @@
+package fastly
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+// authBreaker stops sending commands to the Fastly API after repeated
+// authentication failures, so that an invalid token fails every call at once
+// instead of after a round trip each. There is one breaker per set of
+// credentials, see authCredentials, so that a client with a bad profile or
+// token does not block the clients whose credentials work.
+type authBreaker struct {
+	mu sync.Mutex
+	// probeArgs select the credentials of the breaker for its setup check
+	probeArgs []string
+	// failures holds the times of the consecutive auth_required results
+	failures []time.Time
+	// open is set once the threshold of failures is reached
+	open bool
+	// probedAt is when the breaker last opened or checked the setup again
+	probedAt time.Time
+	// lastError is the error of the last auth_required result
+	lastError string
+}
+
+// globalAuthBreakerThreshold is the number of consecutive auth_required
+// results within globalAuthBreakerWindow that open the breaker; 0 disables
+// it. It can be configured via SetAuthBreakerThreshold().
+var globalAuthBreakerThreshold = 3
+
+// globalAuthBreakerWindow is the window in which consecutive auth failures are
+// counted, and the interval at which an open breaker checks whether
+// authentication works again. It can be configured via SetAuthBreakerWindow().
+var globalAuthBreakerWindow = time.Minute
+
+// globalAuthBreakers holds the breakers by authCredentials key. A breaker is
+// dropped once a command with its credentials succeeds.
+var (
+	globalAuthBreakersMu sync.Mutex
+	globalAuthBreakers   = map[string]*authBreaker{}
+)
+
+// authCredentials returns the key of the credentials a command runs with and
+// the flags that select them: the --token or --profile flag of the request,
+// or an empty key for the profile of the server. Tokens are keyed by a hash.
+func authCredentials(flags []types.Flag) (string, []string) {
+	if token := flagValue(flags, "token"); token != "" {
+		sum := sha256.Sum256([]byte(token))
+		return "token:" + hex.EncodeToString(sum[:8]), []string{"--token", token}
+	}
+	if profile := flagValue(flags, "profile"); profile != "" {
+		return "profile:" + profile, []string{"--profile", profile}
+	}
+	return "", nil
+}
+
+// SetAuthBreakerThreshold sets the number of consecutive authentication
+// failures after which commands fail immediately. Use 0 to disable the breaker.
+func SetAuthBreakerThreshold(failures int) {
+	if failures < 0 {
+		failures = 0
+	}
+	globalAuthBreakerThreshold = failures
+	resetAuthBreakers()
+}
+
+// SetAuthBreakerWindow sets the window in which consecutive authentication
+// failures are counted.
+func SetAuthBreakerWindow(window time.Duration) {
+	if window > 0 {
+		globalAuthBreakerWindow = window
+	}
+}
+
+// ResetAuthBreaker closes the breaker of the server's profile and forgets its
+// recorded failures. It is called when CheckSetup confirms that
+// authentication works.
+func ResetAuthBreaker() {
+	resetAuthBreaker("")
+}
+
+// resetAuthBreaker drops the breaker of a credentials key.
+func resetAuthBreaker(key string) {
+	globalAuthBreakersMu.Lock()
+	defer globalAuthBreakersMu.Unlock()
+	delete(globalAuthBreakers, key)
+}
+
+// resetAuthBreakers drops the breakers of all credentials.
+func resetAuthBreakers() {
+	globalAuthBreakersMu.Lock()
+	defer globalAuthBreakersMu.Unlock()
+	globalAuthBreakers = map[string]*authBreaker{}
+}
+
+// recordAuthResult records the error code of a command that reached the
+// Fastly API with the credentials its flags select. An auth_required result
+// counts towards opening the breaker of those credentials and any other
+// result ends their run of consecutive failures.
+func recordAuthResult(flags []types.Flag, errorCode, errorMessage string) {
+	if globalAuthBreakerThreshold == 0 {
+		return
+	}
+
+	key, probeArgs := authCredentials(flags)
+	if errorCode != "auth_required" {
+		resetAuthBreaker(key)
+		return
+	}
+
+	globalAuthBreakersMu.Lock()
+	b, ok := globalAuthBreakers[key]
+	if !ok {
+		b = &authBreaker{probeArgs: probeArgs}
+		globalAuthBreakers[key] = b
+	}
+	globalAuthBreakersMu.Unlock()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	recent := b.failures[:0]
+	for _, at := range b.failures {
+		if now.Sub(at) < globalAuthBreakerWindow {
+			recent = append(recent, at)
+		}
+	}
+	b.failures = append(recent, now)
+	b.lastError = errorMessage
+
+	if len(b.failures) >= globalAuthBreakerThreshold && !b.open {
+		b.open = true
+		b.probedAt = now
+	}
+}
+
+// authBreakerOpen reports whether commands with the credentials their flags
+// select should fail without running. Once per window an open breaker checks
+// its credentials again, with CheckSetup for the server's profile and with
+// 'fastly whoami' otherwise, and closes when authentication works again.
+func authBreakerOpen(flags []types.Flag) (bool, int, string) {
+	if globalAuthBreakerThreshold == 0 {
+		return false, 0, ""
+	}
+
+	key, _ := authCredentials(flags)
+	globalAuthBreakersMu.Lock()
+	b, ok := globalAuthBreakers[key]
+	globalAuthBreakersMu.Unlock()
+	if !ok {
+		return false, 0, ""
+	}
+
+	b.mu.Lock()
+	if !b.open {
+		b.mu.Unlock()
+		return false, 0, ""
+	}
+	probe := time.Since(b.probedAt) >= globalAuthBreakerWindow
+	if probe {
+		b.probedAt = time.Now()
+	}
+	b.mu.Unlock()
+
+	if probe && probeCredentials(b.probeArgs) {
+		resetAuthBreaker(key)
+		return false, 0, ""
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.open, len(b.failures), b.lastError
+}
+
+// probeCredentials reports whether the credentials selected by probeArgs
+// authenticate. The server's profile is checked with CheckSetup, which also
+// resets its breaker.
+func probeCredentials(probeArgs []string) bool {
+	if len(probeArgs) == 0 {
+		return CheckSetup() == nil
+	}
+	result := RunFastlyCommand(CommandRunConfig{
+		Command: "fastly",
+		Args:    append(append([]string{"whoami"}, probeArgs...), ProfileArgs()...),
+		Timeout: SetupCheckTimeout,
+	})
+	return result.Error == nil
+}
+
+// AuthBreakerError creates the response for a command that was not run because
+// authentication failed repeatedly
+func AuthBreakerError(command string, args []string, flags []types.Flag, failures int, lastError string) types.CommandResponse {
+	return NewResponseBuilder().
+		WithCommand(command, args, flags).
+		WithError(fmt.Errorf("authentication failed %d times in a row; commands are not sent to the Fastly API until authentication works again. Last error: %s", failures, lastError), "auth_required").
+		WithInstructions("Authentication is failing for every command. Fix the credentials before calling any other Fastly command; the server checks them again every "+globalAuthBreakerWindow.String()+".", []string{
+			"Ask the user to run 'fastly profile create' or 'fastly profile switch' to set up a working token",
+			"Get your API token from https://manage.fastly.com/account/personal/tokens",
+			"Do not retry other commands until the user confirms authentication is fixed",
+		}).
+		Build()
+}
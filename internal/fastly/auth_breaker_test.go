@@ -0,0 +1,159 @@
+package fastly
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+// authScript fails every call as unauthenticated until a file named authed
+// exists next to the mock binary.
+const authScript = `if [ ! -f "$(dirname "$0")/authed" ]; then echo 'Error: unauthorized' >&2; exit 1; fi
+echo '[]'`
+
+func countCalls(t *testing.T, callsFile string) int {
+	t.Helper()
+	calls, err := os.ReadFile(callsFile)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	return strings.Count(string(calls), "\n")
+}
+
+func TestAuthBreaker(t *testing.T) {
+	callsFile := installMockFastly(t, authScript)
+
+	SetAuthBreakerThreshold(2)
+	defer SetAuthBreakerThreshold(3)
+
+	req := types.CommandRequest{Command: "service", Args: []string{"list"}}
+	for i := 0; i < 2; i++ {
+		if result := ExecuteCommand(req); result.ErrorCode != "auth_required" {
+			t.Fatalf("Expected an auth failure, got %+v", result)
+		}
+	}
+
+	// The breaker is open: the command fails without running the CLI
+	result := ExecuteCommand(req)
+	if result.ErrorCode != "auth_required" || !strings.Contains(result.Error, "2 times in a row") || !strings.Contains(result.Error, "unauthorized") {
+		t.Errorf("Expected the cached auth error, got %+v", result)
+	}
+	if n := countCalls(t, callsFile); n != 2 {
+		t.Errorf("Expected the CLI to run twice, ran %d times", n)
+	}
+
+	// A successful setup check closes the breaker
+	if err := os.WriteFile(filepath.Join(filepath.Dir(callsFile), "authed"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckSetup(); err != nil {
+		t.Fatalf("CheckSetup failed: %v", err)
+	}
+	if result := ExecuteCommand(req); !result.Success {
+		t.Errorf("Expected the command to run after the setup check, got %+v", result)
+	}
+}
+
+func TestAuthBreakerProbe(t *testing.T) {
+	callsFile := installMockFastly(t, authScript)
+
+	SetAuthBreakerThreshold(2)
+	SetAuthBreakerWindow(50 * time.Millisecond)
+	defer SetAuthBreakerThreshold(3)
+	defer SetAuthBreakerWindow(time.Minute)
+
+	req := types.CommandRequest{Command: "service", Args: []string{"list"}}
+	ExecuteCommand(req)
+	ExecuteCommand(req)
+	if open, _, _ := authBreakerOpen(nil); !open {
+		t.Fatal("Expected the breaker to open")
+	}
+
+	// After the window the open breaker checks the setup again
+	if err := os.WriteFile(filepath.Join(filepath.Dir(callsFile), "authed"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if result := ExecuteCommand(req); !result.Success {
+		t.Errorf("Expected the breaker to close once authentication works, got %+v", result)
+	}
+}
+
+func TestAuthBreakerConsecutive(t *testing.T) {
+	installMockFastly(t, `echo '[]'`)
+
+	SetAuthBreakerThreshold(2)
+	defer SetAuthBreakerThreshold(3)
+
+	// Any other result ends the run of failures
+	recordAuthResult(nil, "auth_required", "Error: unauthorized")
+	recordAuthResult(nil, "not_found", "Error: 404")
+	recordAuthResult(nil, "auth_required", "Error: unauthorized")
+	if open, _, _ := authBreakerOpen(nil); open {
+		t.Error("Expected non-consecutive failures to leave the breaker closed")
+	}
+
+	SetAuthBreakerThreshold(0)
+	recordAuthResult(nil, "auth_required", "Error: unauthorized")
+	recordAuthResult(nil, "auth_required", "Error: unauthorized")
+	if open, _, _ := authBreakerOpen(nil); open {
+		t.Error("Expected a disabled breaker to stay closed")
+	}
+}
+
+func TestAuthBreakerPerCredentials(t *testing.T) {
+	callsFile := installMockFastly(t, `case "$*" in *"--profile bad"*) echo 'Error: unauthorized' >&2; exit 1;; esac
+echo '[]'`)
+
+	SetAuthBreakerThreshold(2)
+	defer SetAuthBreakerThreshold(3)
+
+	bad := types.CommandRequest{Command: "service", Args: []string{"list"}, Flags: []types.Flag{{Name: "profile", Value: "bad"}}}
+	for i := 0; i < 3; i++ {
+		if result := ExecuteCommand(bad); result.ErrorCode != "auth_required" {
+			t.Fatalf("Expected an auth failure, got %+v", result)
+		}
+	}
+	if n := countCalls(t, callsFile); n != 2 {
+		t.Errorf("Expected the breaker of the bad profile to open after 2 calls, ran %d times", n)
+	}
+
+	// Other credentials keep running
+	for _, flags := range [][]types.Flag{nil, {{Name: "profile", Value: "good"}}, {{Name: "token", Value: "other-token"}}} {
+		if result := ExecuteCommand(types.CommandRequest{Command: "service", Args: []string{"list"}, Flags: flags}); !result.Success {
+			t.Errorf("Expected a command with flags %v to run, got %+v", flags, result)
+		}
+	}
+	if open, _, _ := authBreakerOpen(bad.Flags); !open {
+		t.Error("Expected the breaker of the bad profile to stay open")
+	}
+}
+
+func TestAuthBreakerProbeUsesCredentials(t *testing.T) {
+	callsFile := installMockFastly(t, authScript)
+
+	SetAuthBreakerThreshold(2)
+	SetAuthBreakerWindow(50 * time.Millisecond)
+	defer SetAuthBreakerThreshold(3)
+	defer SetAuthBreakerWindow(time.Minute)
+
+	req := types.CommandRequest{Command: "service", Args: []string{"list"}, Flags: []types.Flag{{Name: "profile", Value: "staging"}}}
+	ExecuteCommand(req)
+	ExecuteCommand(req)
+
+	if err := os.WriteFile(filepath.Join(filepath.Dir(callsFile), "authed"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if result := ExecuteCommand(req); !result.Success {
+		t.Errorf("Expected the breaker to close once the profile authenticates, got %+v", result)
+	}
+	calls, _ := os.ReadFile(callsFile)
+	if !strings.Contains(string(calls), "whoami --profile staging") {
+		t.Errorf("Expected the probe to check the profile of the breaker, got %q", calls)
+	}
+}
@@ -0,0 +1,45 @@
+package fastly
+
+import (
+	"encoding/base64"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+// OutputEncodingBase64 marks a response whose output holds base64-encoded
+// bytes rather than text.
+const OutputEncodingBase64 = "base64"
+
+// needsBinarySafeEncoding reports whether the output of a binary-safe request
+// must be encoded. Valid UTF-8 output is returned as text as usual.
+func needsBinarySafeEncoding(req types.CommandRequest, stdout string) bool {
+	return req.BinarySafe && !utf8.ValidString(stdout)
+}
+
+// applyBinarySafeOutput returns the raw command output base64-encoded, so that
+// bytes which are not valid UTF-8 survive the JSON response unchanged. The
+// output is truncated before encoding when the encoded form would exceed the
+// maximum output size.
+func applyBinarySafeOutput(response *types.CommandResponse, stdout string) {
+	if globalSanitizeOpts.Enabled {
+		stdout = SanitizeOutput(stdout, globalSanitizeOpts)
+	}
+
+	raw := []byte(stdout)
+	if maxRaw := base64.StdEncoding.DecodedLen(globalMaxOutputSize); len(raw) > maxRaw {
+		raw = raw[:maxRaw]
+		response.Pagination = &types.PaginationInfo{
+			TotalSize:      len(stdout),
+			ReturnedSize:   len(raw),
+			Truncated:      true,
+			TruncationNote: fmt.Sprintf("Output truncated. Showing the first %d of %d bytes before encoding. Consider using an output file flag to save the full output.", len(raw), len(stdout)),
+		}
+		response.Truncation = truncationFromPagination(TruncationKindText, response.Pagination)
+	}
+
+	response.Output = base64.StdEncoding.EncodeToString(raw)
+	response.OutputEncoding = OutputEncodingBase64
+	response.Instructions = "Command executed successfully. The output is not valid UTF-8 text, so it is base64-encoded in 'output' (output_encoding is 'base64'). Decode it before use."
+}
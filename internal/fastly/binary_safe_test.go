@@ -0,0 +1,75 @@
+package fastly
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestBinarySafeOutput(t *testing.T) {
+	installMockFastly(t, `printf 'PK\003\004\377\376data'`)
+
+	raw := "PK\x03\x04\xff\xfedata"
+	result := ExecuteCommand(types.CommandRequest{
+		Command:    "compute",
+		Args:       []string{"pack"},
+		BinarySafe: true,
+	})
+	if !result.Success {
+		t.Fatalf("Expected success, got %+v", result)
+	}
+	if result.OutputEncoding != OutputEncodingBase64 {
+		t.Fatalf("Expected the output to be flagged as base64, got %q", result.OutputEncoding)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(result.Output)
+	if err != nil {
+		t.Fatalf("Expected valid base64 output: %v", err)
+	}
+	if string(decoded) != raw {
+		t.Errorf("Expected the original bytes %q, got %q", raw, decoded)
+	}
+
+	// Without the mode the invalid bytes are replaced in text output
+	result = ExecuteCommand(types.CommandRequest{
+		Command: "compute",
+		Args:    []string{"pack"},
+	})
+	if result.OutputEncoding != "" || result.Output == base64.StdEncoding.EncodeToString([]byte(raw)) {
+		t.Errorf("Expected text output without binary-safe mode, got %+v", result)
+	}
+}
+
+func TestBinarySafeOutputLeavesTextAlone(t *testing.T) {
+	installMockFastly(t, `echo "plain text"`)
+
+	result := ExecuteCommand(types.CommandRequest{
+		Command:    "service",
+		Args:       []string{"list"},
+		BinarySafe: true,
+	})
+	if result.OutputEncoding != "" {
+		t.Errorf("Expected valid UTF-8 output not to be encoded, got %q", result.OutputEncoding)
+	}
+	if strings.TrimSpace(result.Output) != "plain text" {
+		t.Errorf("Expected the text output, got %q", result.Output)
+	}
+}
+
+func TestBinarySafeOutputTruncated(t *testing.T) {
+	SetMaxOutputSize(8)
+	defer SetMaxOutputSize(0)
+
+	response := types.CommandResponse{}
+	applyBinarySafeOutput(&response, "\xff\xfe0123456789")
+	if response.Pagination == nil || !response.Pagination.Truncated {
+		t.Fatalf("Expected the output to be truncated, got %+v", response)
+	}
+	if len(response.Output) > 8 {
+		t.Errorf("Expected the encoded output to fit the maximum, got %d bytes", len(response.Output))
+	}
+	if _, err := base64.StdEncoding.DecodeString(response.Output); err != nil {
+		t.Errorf("Expected the truncated output to remain valid base64: %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package fastly
+
+import (
+	"strings"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+// MaxCatalogDepth limits how deep BuildCommandCatalog descends into subcommands.
+// Fastly CLI commands are at most three levels deep (e.g. "vcl snippet create").
+const MaxCatalogDepth = 3
+
+// BuildCommandCatalog walks every available command and describes it, along with
+// its subcommands, producing a combined catalog of descriptions, flags, categories
+// and danger classification. It runs one help invocation per command path, so it
+// is considerably heavier than a single describe call and intended for tooling
+// and documentation generation.
+func BuildCommandCatalog() types.CommandCatalog {
+	commandList := GetCommandList()
+
+	catalog := types.CommandCatalog{
+		Commands: make([]types.CatalogEntry, 0, len(commandList.Commands)),
+	}
+	for _, command := range commandList.Commands {
+		catalog.Commands = append(catalog.Commands, describeCatalogEntry([]string{command.Name}, command.Description))
+	}
+
+	return catalog
+}
+
+// describeCatalogEntry describes a command path and, up to MaxCatalogDepth,
+// each of its subcommands.
+func describeCatalogEntry(cmdPath []string, fallbackDescription string) types.CatalogEntry {
+	info := DescribeCommand(cmdPath)
+	entry := newCatalogEntry(cmdPath, info, fallbackDescription)
+
+	if len(cmdPath) < MaxCatalogDepth {
+		for _, sub := range info.Subcommands {
+			subPath := append(append([]string{}, cmdPath...), sub.Name)
+			entry.Subcommands = append(entry.Subcommands, describeCatalogEntry(subPath, sub.Description))
+		}
+	}
+
+	return entry
+}
+
+// newCatalogEntry builds the catalog entry for a described command path,
+// without its subcommands.
+func newCatalogEntry(cmdPath []string, info types.HelpInfo, fallbackDescription string) types.CatalogEntry {
+	name := strings.Join(cmdPath, " ")
+
+	entry := types.CatalogEntry{
+		Name:          name,
+		Description:   info.Description,
+		Category:      info.Category,
+		ResourceType:  info.ResourceType,
+		RequiredFlags: info.RequiredFlags,
+		Flags:         info.Flags,
+	}
+	if entry.Description == "" || entry.Description == "Invalid operation" {
+		entry.Description = fallbackDescription
+	}
+	entry.Severity, entry.Warning = IsDangerousOperation(name)
+	var reviewText string
+	if entry.Dangerous, reviewText = pathReviewRequirement(name, len(info.Subcommands) == 0); entry.Dangerous {
+		entry.Warning = reviewText
+	}
+
+	return entry
+}
@@ -0,0 +1,131 @@
+package fastly
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestBuildCommandCatalog(t *testing.T) {
+	mockHelp := map[string]string{
+		"--help": `USAGE
+  fastly [<flags>] <command> [<args> ...]
+
+COMMANDS
+  backend       Manipulate Fastly service version backends
+  service       Manipulate Fastly services
+
+SEE ALSO
+  fastly help`,
+		"service --help": `USAGE
+  fastly service <command> [<args> ...]
+
+Manipulate Fastly services
+
+COMMANDS
+  delete    Delete a Fastly service
+  list      List Fastly services`,
+		"service delete --help": `USAGE
+  fastly service delete [<flags>]
+
+Delete a Fastly service
+
+OPTIONAL FLAGS
+  -f, --force            Force deletion of an active service
+  -s, --service-id=SERVICE-ID  Service ID`,
+		"service list --help": `USAGE
+  fastly service list [<flags>]
+
+List Fastly services
+
+OPTIONAL FLAGS
+  -j, --json             Render output as JSON
+      --per-page=PER-PAGE  Number of records per page`,
+		"backend --help": `USAGE
+  fastly backend <command> [<args> ...]
+
+Manipulate Fastly service version backends
+
+COMMANDS
+  create    Create a backend on a Fastly service version`,
+		"backend create --help": `USAGE
+  fastly backend create --version=VERSION --name=NAME [<flags>]
+
+Create a backend on a Fastly service version
+
+REQUIRED FLAGS
+  -n, --name=NAME        Backend name
+      --version=VERSION  Service version`,
+	}
+
+	originalExecutor := testCommandExecutor
+	testCommandExecutor = func(ctx context.Context, name string, args ...string) (string, error) {
+		return mockHelp[strings.Join(args, " ")], nil
+	}
+	defer func() {
+		testCommandExecutor = originalExecutor
+	}()
+
+	catalog := BuildCommandCatalog()
+
+	find := func(entries []types.CatalogEntry, name string) *types.CatalogEntry {
+		for i := range entries {
+			if entries[i].Name == name {
+				return &entries[i]
+			}
+		}
+		return nil
+	}
+	hasFlagInfo := func(flags []types.FlagInfo, name string) bool {
+		for _, flag := range flags {
+			if flag.Name == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	service := find(catalog.Commands, "service")
+	if service == nil {
+		t.Fatalf("Expected 'service' in catalog, got %+v", catalog.Commands)
+	}
+	if service.Category != "configuration" {
+		t.Errorf("Expected service category 'configuration', got %q", service.Category)
+	}
+
+	list := find(service.Subcommands, "service list")
+	if list == nil {
+		t.Fatalf("Expected 'service list' subcommand, got %+v", service.Subcommands)
+	}
+	if !hasFlagInfo(list.Flags, "json") || !hasFlagInfo(list.Flags, "per-page") {
+		t.Errorf("Expected json and per-page flags on service list, got %+v", list.Flags)
+	}
+	if list.Dangerous {
+		t.Error("service list should not be classified as dangerous")
+	}
+
+	del := find(service.Subcommands, "service delete")
+	if del == nil {
+		t.Fatal("Expected 'service delete' subcommand")
+	}
+	if !del.Dangerous || del.Warning == "" {
+		t.Errorf("Expected service delete to be dangerous with a warning, got %+v", del)
+	}
+
+	backend := find(catalog.Commands, "backend")
+	if backend == nil {
+		t.Fatal("Expected 'backend' in catalog")
+	}
+	create := find(backend.Subcommands, "backend create")
+	if create == nil {
+		t.Fatal("Expected 'backend create' subcommand")
+	}
+	if !hasFlagInfo(create.RequiredFlags, "name") || !hasFlagInfo(create.RequiredFlags, "version") {
+		t.Errorf("Expected required name and version flags, got %+v", create.RequiredFlags)
+	}
+	if create.ResourceType != "service-component" {
+		t.Errorf("Expected resource type 'service-component', got %q", create.ResourceType)
+	}
+}
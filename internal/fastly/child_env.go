@@ -0,0 +1,96 @@
+package fastly
+
+import (
+	"os"
+	"runtime"
+	"strings"
+)
+
+// DefaultEnvAllowlist lists the environment variables passed to the Fastly CLI
+// when environment isolation is enabled. A trailing '*' matches any suffix.
+var DefaultEnvAllowlist = []string{"PATH", "HOME", "FASTLY_*"}
+
+// windowsEnvAllowlist lists the variables Windows programs need to locate their
+// configuration and temporary directories and to use the network stack.
+var windowsEnvAllowlist = []string{"SYSTEMROOT", "USERPROFILE", "APPDATA", "LOCALAPPDATA", "TEMP", "TMP"}
+
+// globalEnvAllowlist holds the environment variable patterns passed to child
+// CLI processes. When nil, children inherit the full environment. It can be
+// configured via SetEnvAllowlist().
+var globalEnvAllowlist []string
+
+// SetEnvAllowlist restricts the environment of child CLI processes to variables
+// matching the given patterns, in addition to DefaultEnvAllowlist. Passing nil
+// restores the inherited environment.
+func SetEnvAllowlist(patterns []string) {
+	if patterns == nil {
+		globalEnvAllowlist = nil
+		return
+	}
+
+	allowlist := append([]string{}, DefaultEnvAllowlist...)
+	if runtime.GOOS == "windows" {
+		allowlist = append(allowlist, windowsEnvAllowlist...)
+	}
+	for _, pattern := range patterns {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			allowlist = append(allowlist, pattern)
+		}
+	}
+	globalEnvAllowlist = allowlist
+}
+
+// ChildEnvironment returns the environment a child CLI process starts from:
+// the full environment of the server, or only the allowlisted variables when
+// environment isolation is enabled. FASTLY_API_TOKEN is left out when a
+// profile is configured with SetProfile, so the profile's token is used.
+func ChildEnvironment() []string {
+	environ := os.Environ()
+	if globalEnvAllowlist != nil {
+		environ = filterEnvironment(environ, globalEnvAllowlist)
+	}
+	if globalProfile != "" {
+		environ = withoutEnvironment(environ, "FASTLY_API_TOKEN")
+	}
+	return environ
+}
+
+// filterEnvironment keeps the "NAME=value" entries whose name matches one of
+// the patterns. Names are compared case-insensitively on Windows, where
+// environment variable names are not case-sensitive.
+func filterEnvironment(environ []string, patterns []string) []string {
+	filtered := []string{}
+	for _, entry := range environ {
+		name, _, _ := strings.Cut(entry, "=")
+		for _, pattern := range patterns {
+			if envNameMatches(name, pattern) {
+				filtered = append(filtered, entry)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// withoutEnvironment drops the "NAME=value" entries of the named variable.
+func withoutEnvironment(environ []string, name string) []string {
+	kept := make([]string, 0, len(environ))
+	for _, entry := range environ {
+		if entryName, _, _ := strings.Cut(entry, "="); !envNameMatches(entryName, name) {
+			kept = append(kept, entry)
+		}
+	}
+	return kept
+}
+
+// envNameMatches reports whether an environment variable name matches a
+// pattern, which is either an exact name or a prefix followed by '*'.
+func envNameMatches(name, pattern string) bool {
+	if runtime.GOOS == "windows" {
+		name, pattern = strings.ToUpper(name), strings.ToUpper(pattern)
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(name, prefix)
+	}
+	return name == pattern
+}
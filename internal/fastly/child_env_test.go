@@ -0,0 +1,69 @@
+package fastly
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFilterEnvironment(t *testing.T) {
+	environ := []string{
+		"PATH=/usr/bin",
+		"HOME=/home/user",
+		"FASTLY_API_TOKEN=secret",
+		"FASTLYX=other",
+		"AWS_SECRET_ACCESS_KEY=leak",
+		"HTTPS_PROXY=http://proxy:3128",
+		"PATHEXT=.EXE",
+	}
+
+	got := filterEnvironment(environ, append(append([]string{}, DefaultEnvAllowlist...), "HTTPS_PROXY"))
+	want := []string{"PATH=/usr/bin", "HOME=/home/user", "FASTLY_API_TOKEN=secret", "HTTPS_PROXY=http://proxy:3128"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("filterEnvironment() = %q, want %q", got, want)
+	}
+}
+
+func TestChildEnvironmentIsolation(t *testing.T) {
+	dir := t.TempDir()
+	mockPath := filepath.Join(dir, "fastly")
+	if err := os.WriteFile(mockPath, []byte("#!/bin/sh\nenv\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("FASTLY_CLI_PATH", mockPath)
+	t.Setenv("FASTLY_API_TOKEN", "token")
+	t.Setenv("UNRELATED_SECRET", "leak")
+	t.Setenv("EXTRA_SETTING", "kept")
+
+	names := func() map[string]bool {
+		t.Helper()
+		result := RunFastlyCommand(CommandRunConfig{Command: "fastly"})
+		if result.Error != nil {
+			t.Fatalf("RunFastlyCommand failed: %v", result.Error)
+		}
+		found := map[string]bool{}
+		for _, line := range strings.Split(strings.TrimSpace(result.Stdout), "\n") {
+			name, _, _ := strings.Cut(line, "=")
+			found[name] = true
+		}
+		return found
+	}
+
+	if env := names(); !env["UNRELATED_SECRET"] {
+		t.Fatal("Expected the full environment to be inherited by default")
+	}
+
+	SetEnvAllowlist([]string{"EXTRA_*"})
+	defer SetEnvAllowlist(nil)
+
+	env := names()
+	if env["UNRELATED_SECRET"] {
+		t.Errorf("Expected UNRELATED_SECRET not to reach the child, got %v", env)
+	}
+	for _, name := range []string{"PATH", "FASTLY_API_TOKEN", "EXTRA_SETTING", "FASTLY_CLI_ADDON", "FASTLY_USER_AGENT_EXTENSION"} {
+		if !env[name] {
+			t.Errorf("Expected %s to reach the child, got %v", name, env)
+		}
+	}
+}
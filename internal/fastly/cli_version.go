@@ -0,0 +1,112 @@
+package fastly
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+)
+
+// cliVersionRegex matches the version in the output of 'fastly version', e.g.
+// "Fastly CLI version v10.8.3 (4b4b8e0)"
+var cliVersionRegex = regexp.MustCompile(`(?i)fastly cli version v?(\d+)\.(\d+)\.(\d+)`)
+
+// semverRegex matches the first semantic version in output of another shape
+var semverRegex = regexp.MustCompile(`\bv?(\d+)\.(\d+)\.(\d+)`)
+
+// CLIVersion is a comparable Fastly CLI release version.
+type CLIVersion struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// String formats the version as major.minor.patch.
+func (v CLIVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Less reports whether v is an older release than other.
+func (v CLIVersion) Less(other CLIVersion) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor < other.Minor
+	}
+	return v.Patch < other.Patch
+}
+
+// ParseCLIVersion extracts the release version from the output of
+// 'fastly version'. Pre-release and build suffixes are ignored. It reports
+// false when the output holds no version, or only the 0.0.0 of a development
+// build, which cannot be compared with releases.
+func ParseCLIVersion(output string) (CLIVersion, bool) {
+	match := cliVersionRegex.FindStringSubmatch(output)
+	if match == nil {
+		match = semverRegex.FindStringSubmatch(output)
+	}
+	if match == nil {
+		return CLIVersion{}, false
+	}
+
+	var parts [3]int
+	for i := range parts {
+		n, err := strconv.Atoi(match[i+1])
+		if err != nil {
+			return CLIVersion{}, false
+		}
+		parts[i] = n
+	}
+	version := CLIVersion{Major: parts[0], Minor: parts[1], Patch: parts[2]}
+	if version == (CLIVersion{}) {
+		return CLIVersion{}, false
+	}
+	return version, true
+}
+
+// CLIVersionError reports a Fastly CLI release older than MinimumCLIVersion.
+type CLIVersionError struct {
+	// Detected is the version of the installed CLI
+	Detected string
+	// Required is the minimum supported version
+	Required string
+}
+
+func (e *CLIVersionError) Error() string {
+	return fmt.Sprintf("fastly CLI version %s is unsupported; version %s or later is required. Upgrade the CLI from https://github.com/fastly/cli/releases", e.Detected, e.Required)
+}
+
+// detectedCLIVersion is the version found by the last setup check, guarded by
+// detectedCLIVersionMu
+var detectedCLIVersion string
+
+var detectedCLIVersionMu sync.RWMutex
+
+// DetectedCLIVersion returns the Fastly CLI version found by the last setup
+// check or diagnosis, or an empty string if it is not known.
+func DetectedCLIVersion() string {
+	detectedCLIVersionMu.RLock()
+	defer detectedCLIVersionMu.RUnlock()
+	return detectedCLIVersion
+}
+
+// checkCLIVersion records the version in the output of 'fastly version' and
+// returns a CLIVersionError when it is older than MinimumCLIVersion. Output
+// without a comparable version passes, so that development builds work.
+func checkCLIVersion(output string) error {
+	version, ok := ParseCLIVersion(output)
+	if !ok {
+		return nil
+	}
+
+	detectedCLIVersionMu.Lock()
+	detectedCLIVersion = version.String()
+	detectedCLIVersionMu.Unlock()
+
+	minimum, _ := ParseCLIVersion(MinimumCLIVersion)
+	if version.Less(minimum) {
+		return &CLIVersionError{Detected: version.String(), Required: MinimumCLIVersion}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package fastly
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseCLIVersion(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   CLIVersion
+		ok     bool
+	}{
+		{"release", "Fastly CLI version v10.8.3 (4b4b8e0)\nBuilt with go version go1.21.5 linux/amd64\n", CLIVersion{10, 8, 3}, true},
+		{"with viceroy", "Fastly CLI version v11.2.0 (f3c91ad)\nBuilt with go version go1.22.4 darwin/arm64 (2024-06-12)\nViceroy version: viceroy 0.10.1\n", CLIVersion{11, 2, 0}, true},
+		{"old release", "Fastly CLI version v0.43.0 (1b4f7e4)\nBuilt with go version go1.16.3 linux/amd64\n", CLIVersion{0, 43, 0}, true},
+		{"pre-release", "Fastly CLI version v12.0.0-rc.1 (abc1234)", CLIVersion{12, 0, 0}, true},
+		{"without prefix", "fastly 10.4.1", CLIVersion{10, 4, 1}, true},
+		{"development build", "Fastly CLI version v0.0.0-unknown (unknown)\nBuilt with go version go1.22.4 linux/amd64\n", CLIVersion{}, false},
+		{"no version", "[]", CLIVersion{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseCLIVersion(tt.output)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("ParseCLIVersion() = %v, %v, want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCLIVersionLess(t *testing.T) {
+	tests := []struct {
+		a, b CLIVersion
+		less bool
+	}{
+		{CLIVersion{9, 9, 9}, CLIVersion{10, 0, 0}, true},
+		{CLIVersion{10, 1, 0}, CLIVersion{10, 0, 9}, false},
+		{CLIVersion{10, 0, 1}, CLIVersion{10, 0, 2}, true},
+		{CLIVersion{10, 0, 0}, CLIVersion{10, 0, 0}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.a.Less(tt.b); got != tt.less {
+			t.Errorf("%v.Less(%v) = %v, want %v", tt.a, tt.b, got, tt.less)
+		}
+	}
+}
+
+func TestCheckSetupRejectsOldCLI(t *testing.T) {
+	installMockFastly(t, `case "$1" in
+version) echo 'Fastly CLI version v9.2.0 (1a2b3c4)' ;;
+*) echo 'Alice <alice@example.com>' ;;
+esac`)
+
+	err := CheckSetup()
+	var versionErr *CLIVersionError
+	if !errors.As(err, &versionErr) {
+		t.Fatalf("Expected a CLIVersionError, got %v", err)
+	}
+	if versionErr.Detected != "9.2.0" || versionErr.Required != MinimumCLIVersion {
+		t.Errorf("Expected the detected and required versions, got %+v", versionErr)
+	}
+	if DetectedCLIVersion() != "9.2.0" {
+		t.Errorf("Expected the detected version to be recorded, got %q", DetectedCLIVersion())
+	}
+}
+
+func TestCheckSetupAcceptsSupportedCLI(t *testing.T) {
+	installMockFastly(t, `case "$1" in
+version) echo 'Fastly CLI version v10.8.3 (4b4b8e0)' ;;
+*) echo 'Alice <alice@example.com>' ;;
+esac`)
+
+	if err := CheckSetup(); err != nil {
+		t.Errorf("Expected a supported CLI to pass, got %v", err)
+	}
+	if DetectedCLIVersion() != "10.8.3" {
+		t.Errorf("Expected the detected version to be recorded, got %q", DetectedCLIVersion())
+	}
+}
@@ -0,0 +1,21 @@
+package fastly
+
+import "fmt"
+
+// deprecatedCommands maps command names that the Fastly CLI has renamed to
+// their current names. Agents trained on older CLI versions keep using the old
+// names, which are no longer available.
+var deprecatedCommands = map[string]string{
+	"domain-v1": "domain",
+}
+
+// resolveCommandAlias rewrites a deprecated command to its current name. It
+// returns the command to run and a warning for the agent, which is empty when
+// the command is not deprecated.
+func resolveCommandAlias(command string) (string, string) {
+	current, deprecated := deprecatedCommands[command]
+	if !deprecated {
+		return command, ""
+	}
+	return current, fmt.Sprintf("Warning: '%s' is a deprecated command name and was run as '%s'. Use '%s' in future calls.", command, current, current)
+}
@@ -0,0 +1,49 @@
+package fastly
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestResolveCommandAlias(t *testing.T) {
+	command, note := resolveCommandAlias("domain-v1")
+	if command != "domain" {
+		t.Errorf("Expected domain-v1 to be rewritten to domain, got %q", command)
+	}
+	if !strings.Contains(note, "deprecated") || !strings.Contains(note, "'domain'") {
+		t.Errorf("Expected deprecation warning, got %q", note)
+	}
+
+	if command, note := resolveCommandAlias("backend"); command != "backend" || note != "" {
+		t.Errorf("Expected current command unchanged, got %q with note %q", command, note)
+	}
+}
+
+func TestExecuteCommandForwardsDeprecatedAlias(t *testing.T) {
+	callsFile := installMockFastly(t, `echo '[]'`)
+
+	result := ExecuteCommand(types.CommandRequest{
+		Command: "domain-v1 list",
+		Flags:   []types.Flag{{Name: "json"}},
+	})
+	if !result.Success {
+		t.Fatalf("Expected success, got %+v", result)
+	}
+
+	calls, err := os.ReadFile(callsFile)
+	if err != nil {
+		t.Fatalf("Failed to read calls: %v", err)
+	}
+	if !strings.HasPrefix(string(calls), "domain list") {
+		t.Errorf("Expected the CLI to run 'domain list', got %q", calls)
+	}
+	if result.Command != "domain list" {
+		t.Errorf("Expected response command 'domain list', got %q", result.Command)
+	}
+	if !strings.Contains(result.Instructions, "deprecated") {
+		t.Errorf("Expected a deprecation warning in instructions, got %q", result.Instructions)
+	}
+}
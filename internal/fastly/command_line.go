@@ -0,0 +1,35 @@
+package fastly
+
+import (
+	"fmt"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+// globalMaxCommandLineFlags limits the number of flags rendered in the
+// command_line echoed in responses; 0 renders all flags. It can be configured
+// via SetMaxCommandLineFlags().
+var globalMaxCommandLineFlags = 0
+
+// SetMaxCommandLineFlags sets the maximum number of flags rendered in the echoed
+// command line. Use 0 to render all flags.
+func SetMaxCommandLineFlags(max int) {
+	if max < 0 {
+		max = 0
+	}
+	globalMaxCommandLineFlags = max
+}
+
+// echoCommandLine returns the command line to echo in a response. When the
+// command has more flags than the configured maximum, the remaining flags are
+// elided and the second return value is true; callers then report the full flag
+// list in structured metadata. Otherwise fullCmdLine is returned unchanged.
+func echoCommandLine(fullCmdLine string, command string, args []string, flags []types.Flag) (string, bool) {
+	if globalMaxCommandLineFlags == 0 || len(flags) <= globalMaxCommandLineFlags {
+		return fullCmdLine, false
+	}
+
+	shown := BuildCommandLine(command, args, flags[:globalMaxCommandLineFlags])
+	elided := len(flags) - globalMaxCommandLineFlags
+	return fmt.Sprintf("%s ... (%d more flags, see metadata.flags)", shown, elided), true
+}
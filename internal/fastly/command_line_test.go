@@ -0,0 +1,60 @@
+package fastly
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestEchoCommandLineElidesFlags(t *testing.T) {
+	installMockFastly(t, `echo "ok"`)
+
+	SetMaxCommandLineFlags(2)
+	defer SetMaxCommandLineFlags(0)
+
+	var flags []types.Flag
+	for i := 1; i <= 6; i++ {
+		flags = append(flags, types.Flag{Name: fmt.Sprintf("header-%d", i), Value: fmt.Sprintf("value-%d", i)})
+	}
+
+	result := ExecuteCommand(types.CommandRequest{
+		Command: "service",
+		Args:    []string{"list"},
+		Flags:   flags,
+	})
+	if !result.Success {
+		t.Fatalf("Expected success, got %+v", result)
+	}
+
+	if !strings.Contains(result.CommandLine, "--header-2 value-2") || strings.Contains(result.CommandLine, "--header-3") {
+		t.Errorf("Expected command line with only the first 2 flags, got %q", result.CommandLine)
+	}
+	if !strings.Contains(result.CommandLine, "4 more flags") {
+		t.Errorf("Expected elision note in command line, got %q", result.CommandLine)
+	}
+	if result.Metadata == nil || len(result.Metadata.Flags) != 6 || result.Metadata.Flags[5].Name != "header-6" {
+		t.Errorf("Expected all 6 flags in metadata, got %+v", result.Metadata)
+	}
+}
+
+func TestEchoCommandLineWithinLimit(t *testing.T) {
+	installMockFastly(t, `echo "ok"`)
+
+	SetMaxCommandLineFlags(2)
+	defer SetMaxCommandLineFlags(0)
+
+	result := ExecuteCommand(types.CommandRequest{
+		Command: "service",
+		Args:    []string{"list"},
+		Flags:   []types.Flag{{Name: "page", Value: "1"}},
+	})
+
+	if result.CommandLine != "fastly service list --page 1 --non-interactive" {
+		t.Errorf("Expected full command line, got %q", result.CommandLine)
+	}
+	if result.Metadata == nil || len(result.Metadata.Flags) != 0 {
+		t.Errorf("Expected no flag list in metadata, got %+v", result.Metadata)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/fastly/mcp/internal/version"
@@ -14,10 +15,15 @@ import (
 
 // CommandRunConfig holds configuration for executing a command
 type CommandRunConfig struct {
+	// Context, when set, kills the command as soon as it is cancelled
+	Context context.Context
 	Command string
 	Args    []string
 	Timeout time.Duration
-	Env     []string // Additional environment variables
+	// FirstOutputTimeout stops the command if it writes nothing to stdout or
+	// stderr within this window. Zero disables the check.
+	FirstOutputTimeout time.Duration
+	Env                []string // Additional environment variables
 }
 
 // CommandRunResult holds the result of executing a command
@@ -26,16 +32,40 @@ type CommandRunResult struct {
 	Stderr   string
 	Error    error
 	TimedOut bool
+	// Stalled is set when the command was stopped for producing no output
+	// within FirstOutputTimeout. It is never set together with TimedOut.
+	Stalled bool
+	// Cancelled is set when the command was killed because Context was cancelled
+	Cancelled bool
+}
+
+// activityWriter forwards writes to an underlying writer and records that
+// output has been seen.
+type activityWriter struct {
+	w    *bytes.Buffer
+	seen *atomic.Bool
+}
+
+func (a activityWriter) Write(p []byte) (int, error) {
+	if len(p) > 0 {
+		a.seen.Store(true)
+	}
+	return a.w.Write(p)
 }
 
 // RunFastlyCommand executes a fastly command with the given configuration.
 // This is a shared helper that consolidates common command execution logic.
 func RunFastlyCommand(config CommandRunConfig) CommandRunResult {
 	if config.Timeout == 0 {
-		config.Timeout = CommandTimeout // Default timeout
+		config.Timeout = globalCommandTimeout // Default timeout
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
+	parent := config.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	ctx, cancel := context.WithTimeout(parent, config.Timeout)
 	defer cancel()
 
 	// Check if FASTLY_CLI_PATH is set to use a specific binary location
@@ -50,12 +80,24 @@ func RunFastlyCommand(config CommandRunConfig) CommandRunResult {
 	cmd := exec.CommandContext(ctx, commandPath, config.Args...)
 
 	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	var outputSeen, stalled atomic.Bool
+	cmd.Stdout = activityWriter{w: &stdout, seen: &outputSeen}
+	cmd.Stderr = activityWriter{w: &stderr, seen: &outputSeen}
+
+	// Stop the command early if it stays silent for the whole first-output window
+	if config.FirstOutputTimeout > 0 && config.FirstOutputTimeout < config.Timeout {
+		timer := time.AfterFunc(config.FirstOutputTimeout, func() {
+			if !outputSeen.Load() {
+				stalled.Store(true)
+				cancel()
+			}
+		})
+		defer timer.Stop()
+	}
 
 	// Set environment with FASTLY_CLI_ADDON=mcp/version and any additional env vars
 	versionedAddon := fmt.Sprintf("mcp/%s", version.GetVersion())
-	env := append(os.Environ(),
+	env := append(ChildEnvironment(),
 		fmt.Sprintf("FASTLY_CLI_ADDON=%s", versionedAddon),
 		fmt.Sprintf("FASTLY_USER_AGENT_EXTENSION=%s", versionedAddon))
 	if config.Env != nil {
@@ -98,8 +140,12 @@ func RunFastlyCommand(config CommandRunConfig) CommandRunResult {
 		}
 	}
 
-	// Check if the context timed out
-	if ctx.Err() == context.DeadlineExceeded {
+	// Check if the caller cancelled, the command stalled or the context timed out
+	if parent.Err() == context.Canceled {
+		result.Cancelled = true
+	} else if stalled.Load() {
+		result.Stalled = true
+	} else if ctx.Err() == context.DeadlineExceeded {
 		result.TimedOut = true
 	}
 
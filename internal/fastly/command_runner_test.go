@@ -1,11 +1,16 @@
 package fastly
 
 import (
+	"context"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"syscall"
 	"testing"
 	"time"
 
+	"github.com/fastly/mcp/internal/types"
 	"github.com/fastly/mcp/internal/version"
 )
 
@@ -121,3 +126,165 @@ echo "CUSTOM_VAR=$CUSTOM_VAR"
 		t.Errorf("Expected CUSTOM_VAR=test123, got: %s", result.Stdout)
 	}
 }
+
+func TestRunFastlyCommand_StalledWithoutOutput(t *testing.T) {
+	installMockFastly(t, `exec sleep 5`)
+
+	start := time.Now()
+	result := RunFastlyCommand(CommandRunConfig{
+		Command:            "fastly",
+		Args:               []string{"service", "list"},
+		Timeout:            5 * time.Second,
+		FirstOutputTimeout: 200 * time.Millisecond,
+	})
+
+	if !result.Stalled {
+		t.Fatalf("Expected command with no output to be reported as stalled, got %+v", result)
+	}
+	if result.TimedOut {
+		t.Error("Expected stalled command not to be reported as timed out")
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("Expected stalled command to be stopped early, took %s", elapsed)
+	}
+}
+
+func TestRunFastlyCommand_SlowButProgressing(t *testing.T) {
+	installMockFastly(t, `echo "fetching"; sleep 0.5; echo "done"`)
+
+	result := RunFastlyCommand(CommandRunConfig{
+		Command:            "fastly",
+		Args:               []string{"service", "list"},
+		Timeout:            5 * time.Second,
+		FirstOutputTimeout: 200 * time.Millisecond,
+	})
+
+	if result.Error != nil || result.Stalled || result.TimedOut {
+		t.Fatalf("Expected progressing command to complete, got %+v", result)
+	}
+	if !strings.Contains(result.Stdout, "done") {
+		t.Errorf("Expected full output, got %q", result.Stdout)
+	}
+}
+
+func TestRunFastlyCommand_TimeoutAfterOutput(t *testing.T) {
+	installMockFastly(t, `echo "fetching"; exec sleep 5`)
+
+	result := RunFastlyCommand(CommandRunConfig{
+		Command:            "fastly",
+		Args:               []string{"service", "list"},
+		Timeout:            600 * time.Millisecond,
+		FirstOutputTimeout: 200 * time.Millisecond,
+	})
+
+	if !result.TimedOut {
+		t.Fatalf("Expected command that started output to hit the total timeout, got %+v", result)
+	}
+	if result.Stalled {
+		t.Error("Expected command that produced output not to be reported as stalled")
+	}
+}
+
+func TestExecuteCommandStalled(t *testing.T) {
+	installMockFastly(t, `exec sleep 5`)
+
+	SetFirstOutputTimeout(200 * time.Millisecond)
+	defer SetFirstOutputTimeout(FirstOutputTimeout)
+
+	result := ExecuteCommand(types.CommandRequest{
+		Command: "service",
+		Args:    []string{"list"},
+	})
+
+	if result.Success {
+		t.Fatal("Expected stalled command to fail")
+	}
+	if result.ErrorCode != "stalled" {
+		t.Errorf("Expected error code 'stalled', got %q", result.ErrorCode)
+	}
+}
+
+func TestExecuteCommandStallCheckSkippedForRaisedTimeout(t *testing.T) {
+	installMockFastly(t, `sleep 1; echo '[]'`)
+
+	SetFirstOutputTimeout(200 * time.Millisecond)
+	defer SetFirstOutputTimeout(FirstOutputTimeout)
+
+	// A silent command that asked for more than the command timeout outlives the check
+	result := ExecuteCommand(types.CommandRequest{
+		Command:        "stats",
+		Args:           []string{"historical"},
+		TimeoutSeconds: int(CommandTimeout.Seconds()) * 2,
+	})
+	if !result.Success {
+		t.Fatalf("Expected the silent command with a raised timeout to succeed, got %+v", result)
+	}
+
+	result = ExecuteCommand(types.CommandRequest{Command: "stats", Args: []string{"historical"}})
+	if result.ErrorCode != "stalled" {
+		t.Errorf("Expected the same command with the default timeout to stall, got %+v", result)
+	}
+}
+
+func TestFirstOutputTimeoutDisabledByDefault(t *testing.T) {
+	if FirstOutputTimeout != 0 || stallTimeout(CommandTimeout) != 0 {
+		t.Errorf("Expected the first output timeout to be disabled by default, got %s", FirstOutputTimeout)
+	}
+}
+
+func TestRunFastlyCommand_CancelledByContext(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "pid")
+	installMockFastly(t, `echo $$ > "`+pidFile+`"; exec sleep 5`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(300*time.Millisecond, cancel)
+
+	start := time.Now()
+	result := RunFastlyCommand(CommandRunConfig{
+		Context: ctx,
+		Command: "fastly",
+		Args:    []string{"service", "list"},
+		Timeout: 5 * time.Second,
+	})
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected cancellation to stop the command promptly, took %s", elapsed)
+	}
+	if !result.Cancelled {
+		t.Fatalf("Expected command to be reported as cancelled, got %+v", result)
+	}
+	if result.TimedOut || result.Stalled {
+		t.Errorf("Expected only Cancelled to be set, got %+v", result)
+	}
+
+	pidBytes, err := os.ReadFile(pidFile)
+	if err != nil {
+		t.Fatalf("Failed to read child pid: %v", err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(pidBytes)))
+	if err != nil {
+		t.Fatalf("Invalid child pid %q: %v", pidBytes, err)
+	}
+	if proc, err := os.FindProcess(pid); err == nil && proc.Signal(syscall.Signal(0)) == nil {
+		t.Errorf("Expected child process %d to be terminated", pid)
+	}
+}
+
+func TestExecuteCommandContextCancelled(t *testing.T) {
+	installMockFastly(t, `exec sleep 5`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+
+	result := ExecuteCommandContext(ctx, types.CommandRequest{
+		Command: "service",
+		Args:    []string{"list"},
+	})
+
+	if result.Success {
+		t.Fatal("Expected cancelled command to fail")
+	}
+	if result.ErrorCode != "cancelled" {
+		t.Errorf("Expected error code 'cancelled', got %q", result.ErrorCode)
+	}
+}
@@ -0,0 +1,84 @@
+package fastly
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+// DefaultFamilyTimeouts are the default timeouts of command families that
+// routinely run longer than CommandTimeout, such as building and deploying
+// a Compute package. They apply unless a longer command timeout is configured.
+var DefaultFamilyTimeouts = map[string]time.Duration{
+	"compute": 5 * time.Minute,
+}
+
+// globalCommandTimeouts maps command paths (e.g. "compute deploy", "service
+// list") to the timeout used for matching commands instead of the command
+// timeout. It can be configured via SetCommandTimeouts().
+var globalCommandTimeouts = map[string]time.Duration{}
+
+// SetCommandTimeouts configures per-command timeouts. Keys are command paths;
+// the longest matching path wins, so "compute deploy" overrides "compute".
+func SetCommandTimeouts(timeouts map[string]time.Duration) {
+	normalized := make(map[string]time.Duration, len(timeouts))
+	for path, timeout := range timeouts {
+		normalized[strings.Join(strings.Fields(path), " ")] = timeout
+	}
+	globalCommandTimeouts = normalized
+}
+
+// LoadCommandTimeouts reads per-command timeouts from a JSON file holding an
+// object of command paths and durations, e.g. {"compute deploy": "5m"}. Each
+// duration must be positive and at most max.
+func LoadCommandTimeouts(path string, max time.Duration) (map[string]time.Duration, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries map[string]string
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("expected a JSON object of command paths and durations: %w", err)
+	}
+
+	timeouts := make(map[string]time.Duration, len(entries))
+	for path, value := range entries {
+		if strings.TrimSpace(path) == "" {
+			return nil, fmt.Errorf("empty command path")
+		}
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("invalid timeout %q for %q: expected a positive duration such as 90s or 2m", value, path)
+		}
+		if timeout > max {
+			return nil, fmt.Errorf("timeout %s for %q exceeds the maximum command timeout of %s", timeout, path, max)
+		}
+		timeouts[path] = timeout
+	}
+	return timeouts, nil
+}
+
+// commandTimeoutOverride returns the configured timeout for the longest command
+// path that prefixes the given command and arguments.
+func commandTimeoutOverride(command string, args []string) (time.Duration, bool) {
+	return types.LongestCommandPathMatch(globalCommandTimeouts, command, args)
+}
+
+// familyTimeout returns the default timeout of the command family a command
+// belongs to, capped at the maximum command timeout.
+func familyTimeout(command string) (time.Duration, bool) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return 0, false
+	}
+	timeout, ok := DefaultFamilyTimeouts[fields[0]]
+	if !ok {
+		return 0, false
+	}
+	return min(timeout, globalMaxCommandTimeout), true
+}
@@ -0,0 +1,109 @@
+package fastly
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestRequestTimeoutPerCommand(t *testing.T) {
+	SetCommandTimeouts(map[string]time.Duration{
+		"compute":          2 * time.Minute,
+		"compute  deploy":  5 * time.Minute,
+		"service list":     5 * time.Second,
+		"stats historical": 90 * time.Second,
+	})
+	defer SetCommandTimeouts(nil)
+
+	tests := []struct {
+		name     string
+		req      types.CommandRequest
+		expected time.Duration
+	}{
+		{"most specific path wins", types.CommandRequest{Command: "compute", Args: []string{"deploy"}}, 5 * time.Minute},
+		{"shorter path matches", types.CommandRequest{Command: "compute", Args: []string{"build"}}, 2 * time.Minute},
+		{"command given as a path", types.CommandRequest{Command: "service list"}, 5 * time.Second},
+		{"extra args still match", types.CommandRequest{Command: "stats", Args: []string{"historical", "extra"}}, 90 * time.Second},
+		{"sibling path falls back", types.CommandRequest{Command: "service", Args: []string{"describe"}}, CommandTimeout},
+		{"per-call timeout wins", types.CommandRequest{Command: "compute", Args: []string{"deploy"}, TimeoutSeconds: 30}, 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := requestTimeout(tt.req); got != tt.expected {
+				t.Errorf("requestTimeout() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRequestTimeoutFamilyDefault(t *testing.T) {
+	if got := requestTimeout(types.CommandRequest{Command: "compute", Args: []string{"deploy"}}); got != DefaultFamilyTimeouts["compute"] {
+		t.Errorf("Expected compute deploy to use the family default, got %v", got)
+	}
+	if got := requestTimeout(types.CommandRequest{Command: "service", Args: []string{"list"}}); got != CommandTimeout {
+		t.Errorf("Expected service list to use the command timeout, got %v", got)
+	}
+
+	// The family default never shortens a longer command timeout
+	SetCommandTimeout(8 * time.Minute)
+	defer SetCommandTimeout(CommandTimeout)
+	if got := requestTimeout(types.CommandRequest{Command: "compute", Args: []string{"deploy"}}); got != 8*time.Minute {
+		t.Errorf("Expected the longer command timeout to apply, got %v", got)
+	}
+}
+
+func TestLoadCommandTimeouts(t *testing.T) {
+	write := func(content string) string {
+		path := filepath.Join(t.TempDir(), "timeouts.json")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	timeouts, err := LoadCommandTimeouts(write(`{"compute deploy": "5m", "service list": "5s"}`), MaxCommandTimeout)
+	if err != nil {
+		t.Fatalf("LoadCommandTimeouts failed: %v", err)
+	}
+	if timeouts["compute deploy"] != 5*time.Minute || timeouts["service list"] != 5*time.Second {
+		t.Errorf("Unexpected timeouts: %v", timeouts)
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		errText string
+	}{
+		{"not an object", `["compute deploy"]`, "JSON object"},
+		{"not a duration", `{"compute deploy": "300"}`, "positive duration"},
+		{"negative", `{"compute deploy": "-1m"}`, "positive duration"},
+		{"above the maximum", `{"compute deploy": "11m"}`, "exceeds the maximum"},
+		{"empty path", `{" ": "1m"}`, "empty command path"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadCommandTimeouts(write(tt.content), MaxCommandTimeout)
+			if err == nil || !strings.Contains(err.Error(), tt.errText) {
+				t.Errorf("Expected an error containing %q, got %v", tt.errText, err)
+			}
+		})
+	}
+}
+
+func TestEffectiveTimeoutInMetadata(t *testing.T) {
+	installMockFastly(t, `echo "ok"`)
+
+	SetCommandTimeouts(map[string]time.Duration{"service list": 5 * time.Second})
+	defer SetCommandTimeouts(nil)
+
+	result := ExecuteCommand(types.CommandRequest{Command: "service", Args: []string{"list"}})
+	if !result.Success || result.Metadata == nil || result.Metadata.TimeoutSeconds != 5 {
+		t.Errorf("Expected the per-command timeout in the metadata, got %+v", result.Metadata)
+	}
+}
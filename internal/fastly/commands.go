@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"os"
 	"os/exec"
 	"strings"
 
@@ -28,7 +27,7 @@ var defaultCommandExecutor commandExecutor = func(ctx context.Context, name stri
 
 	// Set environment with FASTLY_CLI_ADDON=mcp/version and FASTLY_USER_AGENT_EXTENSION
 	versionedAddon := fmt.Sprintf("mcp/%s", version.GetVersion())
-	cmd.Env = append(os.Environ(),
+	cmd.Env = append(ChildEnvironment(),
 		fmt.Sprintf("FASTLY_CLI_ADDON=%s", versionedAddon),
 		fmt.Sprintf("FASTLY_USER_AGENT_EXTENSION=%s", versionedAddon))
 
@@ -51,7 +50,14 @@ var testCommandExecutor commandExecutor
 // that are not allowed by the security validator. The function handles timeouts and
 // provides appropriate error responses if the CLI is not available.
 func GetCommandList() types.CommandListResponse {
-	ctx, cancel := context.WithTimeout(context.Background(), CommandTimeout)
+	return GetCommandListContext(context.Background())
+}
+
+// GetCommandListContext is like GetCommandList but lists the commands allowed
+// by the validator of ctx, see WithValidator.
+func GetCommandListContext(ctx context.Context) types.CommandListResponse {
+	validator := ContextValidator(ctx)
+	ctx, cancel := context.WithTimeout(ctx, globalCommandTimeout)
 	defer cancel()
 
 	executor := defaultCommandExecutor
@@ -73,7 +79,7 @@ func GetCommandList() types.CommandListResponse {
 		}
 	}
 
-	commands := parseCommandList(output)
+	commands := parseCommandList(output, validator)
 
 	return types.CommandListResponse{
 		Description: "These are all available Fastly API operations. Each operation may have sub-operations and parameters.",
@@ -100,7 +106,7 @@ func GetCommandList() types.CommandListResponse {
 //	  command1    Description of command1
 //	  command2    Description of command2 that might
 //	              wrap to multiple lines
-func parseCommandList(helpOutput string) []types.SubcommandInfo {
+func parseCommandList(helpOutput string, validator *validation.Validator) []types.SubcommandInfo {
 	var commands []types.SubcommandInfo
 	lines := strings.Split(helpOutput, "\n")
 	inCommands := false
@@ -151,13 +157,7 @@ func parseCommandList(helpOutput string) []types.SubcommandInfo {
 					}
 
 					if cmd != "" && desc != "" && !strings.HasPrefix(cmd, "-") {
-						// Only include commands that are allowed by the security validator.
-						// globalValidator is a package-level variable that can be customized
-						// via SetCustomValidator(), defaulting to a standard validator if nil.
-						validator := globalValidator
-						if validator == nil {
-							validator = validation.NewValidator()
-						}
+						// Only include commands that are allowed by the security validator
 						if err := validator.ValidateCommand(cmd); err == nil {
 							commands = append(commands, types.SubcommandInfo{
 								Name:        cmd,
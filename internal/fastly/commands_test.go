@@ -62,7 +62,7 @@ SEE ALSO`,
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := parseCommandList(tt.input)
+			result := parseCommandList(tt.input, GetValidator())
 			if !reflect.DeepEqual(result, tt.expected) {
 				t.Errorf("parseCommandList() = %v, want %v", result, tt.expected)
 			}
@@ -0,0 +1,146 @@
+package fastly
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/fastly/mcp/internal/cache"
+	"github.com/fastly/mcp/internal/types"
+)
+
+// computeBuildSubcommands are the compute subcommands that run the language
+// toolchain and can fail with a build log.
+var computeBuildSubcommands = map[string]bool{
+	"build":   true,
+	"deploy":  true,
+	"publish": true,
+}
+
+// computeToolchain describes a missing language toolchain and how to install it.
+type computeToolchain struct {
+	// patterns are lowercase fragments of the build log that show the toolchain is missing
+	patterns []string
+	// name is the toolchain as shown to the agent
+	name string
+	// nextSteps explain how to install it
+	nextSteps []string
+}
+
+// computeToolchains are checked in order against a failed build log.
+var computeToolchains = []computeToolchain{
+	{
+		patterns: []string{"rustup: command not found", "rustup: not found", "rustup not installed", "rustup is not installed", "cargo: command not found", "cargo: not found", "target may not be installed"},
+		name:     "Rust",
+		nextSteps: []string{
+			"Ask the human user to install Rust with rustup from https://rustup.rs",
+			"Add the WebAssembly target with 'rustup target add wasm32-wasip1'",
+			"Retry the build once the toolchain is installed",
+		},
+	},
+	{
+		patterns: []string{"npm: command not found", "npm: not found", "node: command not found", "node: not found"},
+		name:     "JavaScript",
+		nextSteps: []string{
+			"Ask the human user to install Node.js and npm from https://nodejs.org",
+			"Run 'npm install' in the project directory",
+			"Retry the build once the toolchain is installed",
+		},
+	},
+	{
+		patterns: []string{"tinygo: command not found", "tinygo: not found", "go: command not found", "go: not found"},
+		name:     "Go",
+		nextSteps: []string{
+			"Ask the human user to install Go from https://go.dev/dl and TinyGo from https://tinygo.org if the project uses it",
+			"Retry the build once the toolchain is installed",
+		},
+	},
+}
+
+// compileErrorRegex matches the first line of a compiler error in the build
+// log of a Rust, JavaScript or Go project.
+var compileErrorRegex = regexp.MustCompile(`^(error(\[E\d+\])?: |ERROR in |SyntaxError: |TypeError: |\S+\.(rs|go|js|ts):\d+:\d+: )`)
+
+// isComputeBuild reports whether a request runs a Compute package build.
+func isComputeBuild(command string, args []string) bool {
+	return command == "compute" && len(args) > 0 && computeBuildSubcommands[args[0]]
+}
+
+// computeBuildFailure is the salient failure extracted from a build log.
+type computeBuildFailure struct {
+	message   string
+	code      string
+	summary   string
+	nextSteps []string
+}
+
+// extractComputeBuildFailure finds the key failure in a compute build log: a
+// missing toolchain or the first compiler error with its location. It reports
+// false when the log contains neither.
+func extractComputeBuildFailure(log string) (computeBuildFailure, bool) {
+	lines := strings.Split(log, "\n")
+
+	for _, toolchain := range computeToolchains {
+		for _, line := range lines {
+			lower := strings.ToLower(line)
+			for _, pattern := range toolchain.patterns {
+				if strings.Contains(lower, pattern) {
+					return computeBuildFailure{
+						message:   strings.TrimSpace(line),
+						code:      "compute_toolchain_missing",
+						summary:   fmt.Sprintf("The Compute build failed because the %s toolchain is not installed.", toolchain.name),
+						nextSteps: toolchain.nextSteps,
+					}, true
+				}
+			}
+		}
+	}
+
+	for i, line := range lines {
+		if !compileErrorRegex.MatchString(strings.TrimSpace(line)) {
+			continue
+		}
+		message := []string{strings.TrimSpace(line)}
+		// Rust reports the location on the following lines
+		for _, next := range lines[i+1 : min(i+3, len(lines))] {
+			if trimmed := strings.TrimSpace(next); strings.HasPrefix(trimmed, "-->") {
+				message = append(message, trimmed)
+				break
+			}
+		}
+		return computeBuildFailure{
+			message: strings.Join(message, "\n"),
+			code:    "compute_compile_error",
+			summary: "The Compute build failed with a compile error in the project source.",
+			nextSteps: []string{
+				"Fix the error at the location shown in the error message",
+				"Retry the build after fixing the source",
+			},
+		}, true
+	}
+
+	return computeBuildFailure{}, false
+}
+
+// applyComputeBuildFailure replaces the error of a failed compute build with
+// the salient failure from its log and targeted guidance. The full log is
+// cached so the agent can still read it. Responses for logs without a
+// recognized failure are left unchanged. The log is cached for caller.
+func applyComputeBuildFailure(response *types.CommandResponse, req types.CommandRequest, caller string) {
+	if !isComputeBuild(req.Command, req.Args) {
+		return
+	}
+	failure, ok := extractComputeBuildFailure(response.Error)
+	if !ok {
+		return
+	}
+
+	resultID := cache.GetStore().StoreFor(caller, response.Error, req.Command, req.Args, req.Flags)
+	response.Error = failure.message
+	response.ErrorCode = failure.code
+	response.Instructions = failure.summary + " The full build log is cached as " + resultID + "."
+	response.NextSteps = append(append([]string{}, failure.nextSteps...),
+		fmt.Sprintf("Use fastly_result_read or fastly_result_query with result_id %s to inspect the full build log", resultID))
+	response.ResultID = resultID
+	response.Cached = true
+}
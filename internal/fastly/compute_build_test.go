@@ -0,0 +1,68 @@
+package fastly
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/cache"
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestComputeBuildMissingToolchain(t *testing.T) {
+	installMockFastly(t, `echo "✓ Verifying fastly.toml"
+echo "✓ Identifying package name"
+echo "✗ Running [scripts.build]" >&2
+echo "sh: 1: cargo: not found" >&2
+echo "ERROR: error during execution process (see 'build output' above for details)." >&2
+exit 1`)
+
+	result := ExecuteCommand(types.CommandRequest{Command: "compute", Args: []string{"build"}})
+
+	if result.Success {
+		t.Fatal("Expected the build to fail")
+	}
+	if result.ErrorCode != "compute_toolchain_missing" {
+		t.Errorf("Expected error code 'compute_toolchain_missing', got %q", result.ErrorCode)
+	}
+	if result.Error != "sh: 1: cargo: not found" {
+		t.Errorf("Expected only the salient failure as the error, got %q", result.Error)
+	}
+	if !strings.Contains(result.Instructions, "Rust toolchain is not installed") {
+		t.Errorf("Expected toolchain guidance, got %q", result.Instructions)
+	}
+	if len(result.NextSteps) == 0 || !strings.Contains(result.NextSteps[0], "rustup") {
+		t.Errorf("Expected rustup in the next steps, got %v", result.NextSteps)
+	}
+
+	cached, err := cache.GetStore().Get(result.ResultID)
+	if err != nil {
+		t.Fatalf("Expected the full build log to be cached: %v", err)
+	}
+	if !strings.Contains(cached.RawOutput, "Running [scripts.build]") {
+		t.Errorf("Expected the cached log to be complete, got %q", cached.RawOutput)
+	}
+}
+
+func TestExtractComputeBuildFailure(t *testing.T) {
+	log := `   Compiling edge-app v0.1.0 (/work/edge-app)
+error[E0425]: cannot find value ` + "`reqest`" + ` in this scope
+ --> src/main.rs:7:20
+  |
+7 |     Ok(Response::from(reqest))
+error: could not compile ` + "`edge-app`" + ` due to previous error`
+
+	failure, ok := extractComputeBuildFailure(log)
+	if !ok || failure.code != "compute_compile_error" {
+		t.Fatalf("Expected a compile error, got %+v", failure)
+	}
+	if failure.message != "error[E0425]: cannot find value `reqest` in this scope\n--> src/main.rs:7:20" {
+		t.Errorf("Expected the first error with its location, got %q", failure.message)
+	}
+
+	if _, ok := extractComputeBuildFailure("ERROR: unexpected response from the API"); ok {
+		t.Error("Expected no build failure in an unrelated error")
+	}
+	if isComputeBuild("compute", []string{"validate"}) {
+		t.Error("Expected compute validate not to be a build")
+	}
+}
@@ -0,0 +1,123 @@
+package fastly
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fastly/mcp/internal/types"
+	"github.com/fastly/mcp/internal/validation"
+)
+
+// ComputeManifestFile is the name of the Compute project manifest, looked up in
+// the project directory.
+const ComputeManifestFile = "fastly.toml"
+
+// ComputePreset holds defaults injected into compute commands so agents do not
+// have to get the project flags right on every call.
+type ComputePreset struct {
+	// Dir is the project directory passed as --dir
+	Dir string
+	// Package is the package path passed as --package
+	Package string
+}
+
+// globalComputePreset is the preset applied to compute commands. It is nil
+// unless configured via SetComputePreset().
+var globalComputePreset *ComputePreset
+
+// computePresetFlags lists, per compute subcommand, the preset flags it accepts.
+var computePresetFlags = map[string][]string{
+	"build":    {"dir"},
+	"deploy":   {"dir", "package"},
+	"publish":  {"dir", "package"},
+	"validate": {"package"},
+}
+
+// SetComputePreset configures the compute preset. A nil preset disables it.
+func SetComputePreset(preset *ComputePreset) {
+	globalComputePreset = preset
+}
+
+// ParseComputePreset parses a comma-separated list of "name=value" entries,
+// e.g. "dir=./edge-app,package=pkg/edge-app.tar.gz". Values are checked with the
+// same rules as path flags sent by agents.
+func ParseComputePreset(spec string) (*ComputePreset, error) {
+	validator := validation.NewValidator()
+
+	preset := &ComputePreset{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, value, found := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		value = strings.TrimSpace(value)
+		if !found || value == "" {
+			return nil, fmt.Errorf("invalid compute preset %q: expected 'dir=path' or 'package=path'", entry)
+		}
+		if err := validator.ValidatePath(value); err != nil {
+			return nil, fmt.Errorf("invalid compute preset %q: %w", entry, err)
+		}
+
+		switch name {
+		case "dir":
+			preset.Dir = value
+		case "package":
+			preset.Package = value
+		default:
+			return nil, fmt.Errorf("invalid compute preset %q: unknown setting %q, expected 'dir' or 'package'", entry, name)
+		}
+	}
+	return preset, nil
+}
+
+// applyComputePreset injects the configured preset into a compute command and
+// returns the names of the flags that were added. Flags the caller already
+// passed are left as is. When the command works on a project directory, the
+// manifest must exist there; otherwise an error is returned.
+func applyComputePreset(command string, args []string, flags []types.Flag) ([]types.Flag, []string, error) {
+	if globalComputePreset == nil || command != "compute" || len(args) == 0 {
+		return flags, nil, nil
+	}
+	presetFlags, ok := computePresetFlags[args[0]]
+	if !ok {
+		return flags, nil, nil
+	}
+
+	values := map[string]string{
+		"dir":     globalComputePreset.Dir,
+		"package": globalComputePreset.Package,
+	}
+
+	var added []string
+	usesDir := false
+	for _, name := range presetFlags {
+		if name == "dir" {
+			usesDir = true
+		}
+		if values[name] == "" || hasFlag(flags, name) {
+			continue
+		}
+		flags = append(flags, types.Flag{Name: name, Value: values[name]})
+		added = append(added, name)
+	}
+
+	if usesDir {
+		dir := "."
+		for _, flag := range flags {
+			if flag.Name == "dir" && flag.Value != "" {
+				dir = flag.Value
+			}
+		}
+		manifest := filepath.Join(dir, ComputeManifestFile)
+		if _, err := os.Stat(manifest); err != nil {
+			return flags, added, fmt.Errorf("compute manifest %s not found", manifest)
+		}
+	}
+
+	return flags, added, nil
+}
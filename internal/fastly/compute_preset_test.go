@@ -0,0 +1,92 @@
+package fastly
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestComputePresetInjectsDefaults(t *testing.T) {
+	callsFile := installMockFastly(t, `echo "Built package"`)
+
+	projectDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(projectDir, ComputeManifestFile), []byte("name = \"edge-app\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	SetComputePreset(&ComputePreset{Dir: projectDir, Package: "pkg/edge-app.tar.gz"})
+	defer SetComputePreset(nil)
+
+	result := ExecuteCommand(types.CommandRequest{Command: "compute", Args: []string{"build"}})
+	if !result.Success {
+		t.Fatalf("Expected compute build to succeed, got %+v", result)
+	}
+
+	calls, err := os.ReadFile(callsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(calls), "compute build --dir "+projectDir) {
+		t.Errorf("Expected the preset --dir to be injected, got %q", calls)
+	}
+	if strings.Contains(string(calls), "--package") {
+		t.Errorf("Expected no --package for compute build, got %q", calls)
+	}
+	if strings.Join(result.Metadata.InjectedFlags, ",") != "dir" {
+		t.Errorf("Expected injected_flags [dir], got %v", result.Metadata.InjectedFlags)
+	}
+}
+
+func TestComputePresetKeepsExplicitFlags(t *testing.T) {
+	installMockFastly(t, `echo "ok"`)
+
+	projectDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(projectDir, ComputeManifestFile), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	SetComputePreset(&ComputePreset{Dir: "does-not-exist", Package: "pkg/edge-app.tar.gz"})
+	defer SetComputePreset(nil)
+
+	flags, added, err := applyComputePreset("compute", []string{"deploy"}, []types.Flag{{Name: "dir", Value: projectDir}})
+	if err != nil {
+		t.Fatalf("Expected the explicit --dir to be used for the manifest check, got %v", err)
+	}
+	if strings.Join(added, ",") != "package" || len(flags) != 2 || flags[0].Value != projectDir {
+		t.Errorf("Expected only --package to be added, got %v (%v)", flags, added)
+	}
+}
+
+func TestComputePresetMissingManifest(t *testing.T) {
+	callsFile := installMockFastly(t, `echo "ok"`)
+
+	SetComputePreset(&ComputePreset{Dir: t.TempDir()})
+	defer SetComputePreset(nil)
+
+	result := ExecuteCommand(types.CommandRequest{Command: "compute", Args: []string{"build"}})
+	if result.Success || result.ErrorCode != "compute_manifest_not_found" {
+		t.Fatalf("Expected compute_manifest_not_found, got %+v", result)
+	}
+	if _, err := os.ReadFile(callsFile); err == nil {
+		t.Error("Expected the CLI not to be invoked without a manifest")
+	}
+}
+
+func TestParseComputePreset(t *testing.T) {
+	preset, err := ParseComputePreset("dir=./edge-app, package=pkg/edge-app.tar.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preset.Dir != "./edge-app" || preset.Package != "pkg/edge-app.tar.gz" {
+		t.Errorf("Unexpected preset %+v", preset)
+	}
+
+	for _, spec := range []string{"dir", "lang=rust", "dir=../outside"} {
+		if _, err := ParseComputePreset(spec); err == nil {
+			t.Errorf("Expected an error for %q", spec)
+		}
+	}
+}
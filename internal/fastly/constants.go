@@ -4,10 +4,31 @@ package fastly
 import "time"
 
 const (
-	// CommandTimeout is the maximum time a Fastly CLI command can run before being forcefully terminated.
+	// CommandTimeout is the default maximum time a Fastly CLI command can run before being forcefully terminated.
 	// This prevents commands from hanging indefinitely and ensures the MCP server remains responsive.
+	// It can be changed with SetCommandTimeout().
 	CommandTimeout = 30 * time.Second
 
+	// SetupCheckTimeout is the time the setup check's whoami probe may take. It is independent of
+	// the command timeout so that a long configured timeout does not delay startup.
+	SetupCheckTimeout = 10 * time.Second
+
+	// MinimumCLIVersion is the oldest Fastly CLI release the server supports. Older releases
+	// differ in flags and JSON output, so the setup check rejects them.
+	MinimumCLIVersion = "10.0.0"
+
+	// MaxCommandTimeout is the default upper bound for a per-call timeout requested by an agent.
+	// Known-slow operations such as compute deploy may ask for more than CommandTimeout, but never
+	// more than this, so a single call cannot tie up the server indefinitely.
+	MaxCommandTimeout = 10 * time.Minute
+
+	// FirstOutputTimeout is how long a Fastly CLI command may run without writing any output
+	// before it is considered stalled; zero, the default, disables the check. A command that never
+	// starts producing output is often waiting on something that will not arrive (an interactive
+	// prompt, an SSO browser flow or an unreachable network), but commands such as stats historical
+	// or compute deploy also stay silent until they print their whole result at the end.
+	FirstOutputTimeout time.Duration = 0
+
 	// MaxOutputSize is the maximum size of command output to return in a single response (in bytes).
 	// Outputs larger than this will be truncated to prevent memory issues and ensure reasonable response times.
 	// Set to 50KB to handle most command outputs while preventing excessive memory usage.
@@ -17,4 +38,11 @@ const (
 	// This limit helps manage response size for list operations that could potentially return thousands of items.
 	// When exceeded, the array is truncated and a warning is included in the response.
 	MaxJSONArrayItems = 100
+
+	// MaxPurgeBatchKeys is the maximum number of surrogate keys accepted in one batched purge.
+	// It matches the number of keys the Fastly API accepts in a single bulk purge request.
+	MaxPurgeBatchKeys = 256
+
+	// MaxSurrogateKeyLength is the maximum length of a single surrogate key (in bytes).
+	MaxSurrogateKeyLength = 1024
 )
@@ -0,0 +1,133 @@
+package fastly
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/fastly/mcp/internal/types"
+	"github.com/fastly/mcp/internal/validation"
+)
+
+// globalCreateFlags are flags appended to every create operation, e.g. a
+// --comment tagging resources as created through MCP. They can be configured
+// via SetCreateFlags().
+var globalCreateFlags []types.Flag
+
+// createFlagSupport caches the flags each create command path accepts, as
+// listed by its help, so the help is read once per path.
+var (
+	createFlagSupportMu sync.Mutex
+	createFlagSupport   = map[string]map[string]bool{}
+)
+
+// SetCreateFlags configures the flags appended to create operations. A flag the
+// caller already passed is left as is, and a flag the command does not accept
+// is skipped.
+func SetCreateFlags(flags []types.Flag) {
+	globalCreateFlags = flags
+
+	createFlagSupportMu.Lock()
+	defer createFlagSupportMu.Unlock()
+	createFlagSupport = map[string]map[string]bool{}
+}
+
+// ParseCreateFlags parses a comma-separated list of "flag=value" entries, e.g.
+// "comment=created-by:mcp". Flag names and values are checked with the same
+// rules as flags sent by agents.
+func ParseCreateFlags(spec string) ([]types.Flag, error) {
+	validator := validation.NewValidator()
+
+	var flags []types.Flag
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, value, found := strings.Cut(entry, "=")
+		name = strings.TrimLeft(strings.TrimSpace(name), "-")
+		value = strings.TrimSpace(value)
+		if !found || name == "" || value == "" {
+			return nil, fmt.Errorf("invalid create flag %q: expected 'flag=value'", entry)
+		}
+		if err := validator.ValidateFlagName(name); err != nil {
+			return nil, fmt.Errorf("invalid create flag %q: %w", entry, err)
+		}
+		if err := validator.ValidateFlagValue(value); err != nil {
+			return nil, fmt.Errorf("invalid create flag %q: %w", entry, err)
+		}
+
+		flags = append(flags, types.Flag{Name: name, Value: value})
+	}
+	return flags, nil
+}
+
+// applyCreateFlags appends the configured create flags to a create operation
+// and returns the names of the flags that were added and of those skipped
+// because the help of the command does not list them. Not every create
+// command accepts a flag such as --comment, and passing it anyway would make
+// the Fastly CLI fail.
+func applyCreateFlags(ctx context.Context, command string, args []string, flags []types.Flag) ([]types.Flag, []string, []string) {
+	if len(globalCreateFlags) == 0 {
+		return flags, nil, nil
+	}
+	if operationType, _ := GetOperationType(command, args); operationType != "create" {
+		return flags, nil, nil
+	}
+
+	supported := createFlagsSupported(ctx, []string{command, args[0]})
+	var added, skipped []string
+	for _, createFlag := range globalCreateFlags {
+		if hasFlag(flags, createFlag.Name) {
+			continue
+		}
+		if !supported[createFlag.Name] {
+			skipped = append(skipped, createFlag.Name)
+			continue
+		}
+		flags = append(flags, createFlag)
+		added = append(added, createFlag.Name)
+	}
+	return flags, added, skipped
+}
+
+// createFlagsSupported returns the flags the help of a command path lists. A
+// help that cannot be read is not cached and supports no flags, so the create
+// runs as sent.
+func createFlagsSupported(ctx context.Context, cmdPath []string) map[string]bool {
+	path := strings.Join(cmdPath, " ")
+	createFlagSupportMu.Lock()
+	supported, ok := createFlagSupport[path]
+	createFlagSupportMu.Unlock()
+	if ok {
+		return supported
+	}
+
+	info := DescribeCommandContext(ctx, cmdPath, DescribeOptions{IncludeHiddenFlags: true})
+	if info.Description == "Invalid operation" || info.UsageSyntax == "" {
+		return nil
+	}
+	supported = map[string]bool{}
+	for _, list := range [][]types.FlagInfo{info.RequiredFlags, info.Flags, info.HiddenFlags} {
+		for _, flag := range list {
+			supported[flag.Name] = true
+		}
+	}
+
+	createFlagSupportMu.Lock()
+	defer createFlagSupportMu.Unlock()
+	createFlagSupport[path] = supported
+	return supported
+}
+
+// hasFlag reports whether a flag with the given name is present.
+func hasFlag(flags []types.Flag, name string) bool {
+	for _, flag := range flags {
+		if flag.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package fastly
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestParseCreateFlags(t *testing.T) {
+	flags, err := ParseCreateFlags("comment=created-by:mcp, --owner = platform-team")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(flags) != 2 || flags[0].Name != "comment" || flags[0].Value != "created-by:mcp" || flags[1].Name != "owner" || flags[1].Value != "platform-team" {
+		t.Errorf("Unexpected flags: %+v", flags)
+	}
+
+	for _, spec := range []string{"comment", "comment=", "=value", "comment=$(whoami)"} {
+		if _, err := ParseCreateFlags(spec); err == nil {
+			t.Errorf("Expected error for %q", spec)
+		}
+	}
+}
+
+// mockCreateHelp makes the help of every command list the given optional
+// flags and returns how often a help was read.
+func mockCreateHelp(t *testing.T, flags ...string) *int {
+	t.Helper()
+	reads := 0
+	originalExecutor := testCommandExecutor
+	testCommandExecutor = func(ctx context.Context, name string, args ...string) (string, error) {
+		reads++
+		help := "USAGE\n  fastly " + strings.Join(args[:len(args)-1], " ") + " [<flags>]\n\nOPTIONAL FLAGS\n"
+		for _, flag := range flags {
+			help += "      --" + flag + "=" + strings.ToUpper(flag) + "  The " + flag + "\n"
+		}
+		return help, nil
+	}
+	t.Cleanup(func() {
+		testCommandExecutor = originalExecutor
+	})
+	return &reads
+}
+
+func TestCreateFlagsAppendedToCreate(t *testing.T) {
+	callsFile := installMockFastly(t, `echo "created"`)
+	mockCreateHelp(t, "name", "comment")
+
+	SetCreateFlags([]types.Flag{{Name: "comment", Value: "created-by:mcp"}})
+	defer SetCreateFlags(nil)
+
+	result := ExecuteCommand(types.CommandRequest{
+		Command: "service",
+		Args:    []string{"create"},
+		Flags:   []types.Flag{{Name: "name", Value: "tagged-service"}, {Name: "user-reviewed"}},
+	})
+	if !result.Success {
+		t.Fatalf("Expected success, got %+v", result)
+	}
+
+	calls, err := os.ReadFile(callsFile)
+	if err != nil {
+		t.Fatalf("Failed to read calls: %v", err)
+	}
+	if !strings.Contains(string(calls), "--comment created-by:mcp") {
+		t.Errorf("Expected configured tag in CLI call, got %q", calls)
+	}
+	if result.Metadata == nil || len(result.Metadata.InjectedFlags) != 1 || result.Metadata.InjectedFlags[0] != "comment" {
+		t.Errorf("Expected injected_flags [comment] in metadata, got %+v", result.Metadata)
+	}
+}
+
+func TestCreateFlagsRespectExistingAndSkipOtherOperations(t *testing.T) {
+	callsFile := installMockFastly(t, `echo "ok"`)
+	mockCreateHelp(t, "name", "comment")
+
+	SetCreateFlags([]types.Flag{{Name: "comment", Value: "created-by:mcp"}})
+	defer SetCreateFlags(nil)
+
+	// A comment passed by the caller is kept
+	ExecuteCommand(types.CommandRequest{
+		Command: "service",
+		Args:    []string{"create"},
+		Flags:   []types.Flag{{Name: "name", Value: "svc"}, {Name: "comment", Value: "custom"}, {Name: "user-reviewed"}},
+	})
+
+	// Non-create operations are not tagged
+	ExecuteCommand(types.CommandRequest{
+		Command: "service",
+		Args:    []string{"list"},
+	})
+
+	calls, err := os.ReadFile(callsFile)
+	if err != nil {
+		t.Fatalf("Failed to read calls: %v", err)
+	}
+	if strings.Contains(string(calls), "created-by:mcp") {
+		t.Errorf("Expected no configured tag in CLI calls, got %q", calls)
+	}
+}
+
+func TestCreateFlagsSkippedWhenUnsupported(t *testing.T) {
+	callsFile := installMockFastly(t, `echo "created"`)
+	reads := mockCreateHelp(t, "name", "comment")
+
+	SetCreateFlags([]types.Flag{{Name: "comment", Value: "created-by:mcp"}, {Name: "owner", Value: "platform-team"}})
+	defer SetCreateFlags(nil)
+
+	for i := 0; i < 2; i++ {
+		result := ExecuteCommand(types.CommandRequest{
+			Command: "service",
+			Args:    []string{"create"},
+			Flags:   []types.Flag{{Name: "name", Value: "svc"}, {Name: "user-reviewed"}},
+		})
+		if !result.Success {
+			t.Fatalf("Expected success, got %+v", result)
+		}
+		if len(result.Metadata.InjectedFlags) != 1 || result.Metadata.InjectedFlags[0] != "comment" {
+			t.Errorf("Expected injected_flags [comment], got %v", result.Metadata.InjectedFlags)
+		}
+		if len(result.Metadata.SkippedFlags) != 1 || result.Metadata.SkippedFlags[0] != "owner" {
+			t.Errorf("Expected skipped_flags [owner], got %v", result.Metadata.SkippedFlags)
+		}
+	}
+	if *reads != 1 {
+		t.Errorf("Expected the help to be read once per command path, read %d times", *reads)
+	}
+
+	calls, err := os.ReadFile(callsFile)
+	if err != nil {
+		t.Fatalf("Failed to read calls: %v", err)
+	}
+	if strings.Contains(string(calls), "--owner") {
+		t.Errorf("Expected the unsupported flag not to be passed, got %q", calls)
+	}
+}
@@ -0,0 +1,140 @@
+package fastly
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+var (
+	// createdLineRegex matches the confirmation the Fastly CLI prints after a
+	// create, e.g. "SUCCESS: Created backend my-backend (service SVC version 3)".
+	createdLineRegex = regexp.MustCompile(`Created ([A-Za-z0-9 ._-]+?) '?([^\s'()]+)'?(?: \(([^)]*)\))?\.?\s*$`)
+
+	// createdServiceRegex and createdVersionRegex pick the service and version
+	// out of the parenthesized details of a confirmation line.
+	createdServiceRegex = regexp.MustCompile(`service:? '?([A-Za-z0-9]+)'?`)
+	createdVersionRegex = regexp.MustCompile(`version:? (\d+)`)
+
+	// fastlyIDRegex matches the random IDs Fastly assigns to resources.
+	fastlyIDRegex = regexp.MustCompile(`^[A-Za-z0-9]{20,22}$`)
+)
+
+// createdResourceKeys are the JSON fields, compared case-insensitively with
+// underscores removed, that carry each part of a created resource.
+var createdResourceKeys = map[string]string{
+	"id":             "id",
+	"name":           "name",
+	"serviceid":      "service_id",
+	"version":        "version",
+	"serviceversion": "version",
+}
+
+// extractCreatedResource identifies the resource made by a successful create
+// operation from its JSON or text output. The service ID falls back to the
+// --service-id flag. It returns nil for other operations and for output that
+// does not name the resource.
+func extractCreatedResource(command string, args []string, flags []types.Flag, output string) *types.CreatedResource {
+	if !isCreatePath(args) {
+		return nil
+	}
+
+	resource := createdResourceFromJSON(output)
+	if resource == nil {
+		resource = createdResourceFromText(output)
+	}
+	if resource == nil {
+		return nil
+	}
+
+	resource.Type = createdResourceType(command, args)
+	if resource.ServiceID == "" {
+		for _, flag := range flags {
+			if flag.Name == "service-id" {
+				resource.ServiceID = flag.Value
+			}
+		}
+	}
+	return resource
+}
+
+// isCreatePath reports whether the subcommand path creates a resource, which
+// for nested commands such as "logging s3 create" is not the first argument.
+func isCreatePath(args []string) bool {
+	for _, arg := range args {
+		if arg == "create" {
+			return true
+		}
+	}
+	return false
+}
+
+// createdResourceType names the resource from the command path before
+// "create", e.g. "logging s3" for "logging s3 create".
+func createdResourceType(command string, args []string) string {
+	parts := []string{command}
+	for _, arg := range args {
+		if arg == "create" {
+			break
+		}
+		parts = append(parts, arg)
+	}
+	return strings.Join(parts, " ")
+}
+
+// createdResourceFromJSON reads the resource from a JSON object.
+func createdResourceFromJSON(output string) *types.CreatedResource {
+	var object map[string]interface{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(output)), &object); err != nil {
+		return nil
+	}
+
+	resource := &types.CreatedResource{}
+	for key, value := range object {
+		switch createdResourceKeys[strings.ToLower(strings.ReplaceAll(key, "_", ""))] {
+		case "id":
+			resource.ID, _ = value.(string)
+		case "name":
+			resource.Name, _ = value.(string)
+		case "service_id":
+			resource.ServiceID, _ = value.(string)
+		case "version":
+			if number, ok := value.(float64); ok {
+				resource.Version = int(number)
+			}
+		}
+	}
+	if resource.ID == "" && resource.Name == "" {
+		return nil
+	}
+	return resource
+}
+
+// createdResourceFromText reads the resource from a "Created ..." line. The
+// reported value is taken as an ID when it looks like one, else as a name.
+func createdResourceFromText(output string) *types.CreatedResource {
+	for _, line := range strings.Split(output, "\n") {
+		match := createdLineRegex.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+
+		resource := &types.CreatedResource{}
+		if fastlyIDRegex.MatchString(match[2]) {
+			resource.ID = match[2]
+		} else {
+			resource.Name = match[2]
+		}
+		if service := createdServiceRegex.FindStringSubmatch(match[3]); service != nil {
+			resource.ServiceID = service[1]
+		}
+		if version := createdVersionRegex.FindStringSubmatch(match[3]); version != nil {
+			resource.Version, _ = strconv.Atoi(version[1])
+		}
+		return resource
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package fastly
+
+import (
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestCreatedResourceFromBackendCreate(t *testing.T) {
+	installMockFastly(t, `echo "SUCCESS: Created backend origin-eu (service SU1Z0isxPaozGVKXdv0eY version 3)"`)
+
+	result := ExecuteCommand(types.CommandRequest{
+		Command: "backend",
+		Args:    []string{"create"},
+		Flags: []types.Flag{
+			{Name: "service-id", Value: "SU1Z0isxPaozGVKXdv0eY"},
+			{Name: "version", Value: "3"},
+			{Name: "name", Value: "origin-eu"},
+			{Name: "address", Value: "eu.example.com"},
+			{Name: "user-reviewed"},
+		},
+	})
+	if !result.Success {
+		t.Fatalf("Expected success, got %+v", result)
+	}
+
+	want := types.CreatedResource{Type: "backend", Name: "origin-eu", ServiceID: "SU1Z0isxPaozGVKXdv0eY", Version: 3}
+	if result.CreatedResource == nil || *result.CreatedResource != want {
+		t.Errorf("Expected created resource %+v, got %+v", want, result.CreatedResource)
+	}
+}
+
+func TestExtractCreatedResource(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		flags   []types.Flag
+		output  string
+		want    *types.CreatedResource
+	}{
+		{
+			name:    "service id in text",
+			command: "service",
+			args:    []string{"create"},
+			output:  "SUCCESS: Created service 7Jfw0aTyiGCzcnDRVVJQ2k",
+			want:    &types.CreatedResource{Type: "service", ID: "7Jfw0aTyiGCzcnDRVVJQ2k"},
+		},
+		{
+			name:    "multi-word resource with service from flag",
+			command: "logging",
+			args:    []string{"s3", "create"},
+			flags:   []types.Flag{{Name: "service-id", Value: "SVC123"}},
+			output:  "SUCCESS: Created S3 logging endpoint access-logs (service SVC123 version 2)",
+			want:    &types.CreatedResource{Type: "logging s3", Name: "access-logs", ServiceID: "SVC123", Version: 2},
+		},
+		{
+			name:    "json object",
+			command: "backend",
+			args:    []string{"create"},
+			output:  `{"Name": "origin-us", "ServiceID": "SVC123", "ServiceVersion": 4}`,
+			want:    &types.CreatedResource{Type: "backend", Name: "origin-us", ServiceID: "SVC123", Version: 4},
+		},
+		{
+			name:    "not a create",
+			command: "backend",
+			args:    []string{"list"},
+			output:  "SUCCESS: Created backend origin-eu",
+		},
+		{
+			name:    "nothing created",
+			command: "backend",
+			args:    []string{"create"},
+			output:  "Done",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractCreatedResource(tt.command, tt.args, tt.flags, tt.output)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package fastly
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+// Danger policy levels for a command path.
+const (
+	// DangerPolicyDangerous always requires human review of matching commands.
+	DangerPolicyDangerous = "dangerous"
+	// DangerPolicyAllow runs matching commands without human review, also in
+	// safe mode.
+	DangerPolicyAllow = "allow"
+)
+
+// globalDangerPolicy maps command paths (e.g. "purge", "config-store-entry
+// create") to a danger policy level that replaces both the keyword
+// classification of IsDangerousOperation and the safe-mode review. It can be
+// configured via SetDangerPolicy().
+var globalDangerPolicy = map[string]string{}
+
+// SetDangerPolicy configures per-command danger policy levels. Keys are command
+// paths; the longest matching path wins, so "config-store-entry create" can be
+// allowed while "config-store-entry" stays dangerous.
+func SetDangerPolicy(policy map[string]string) {
+	normalized := make(map[string]string, len(policy))
+	for path, level := range policy {
+		normalized[strings.Join(strings.Fields(path), " ")] = level
+	}
+	globalDangerPolicy = normalized
+}
+
+// LoadDangerPolicy reads a danger policy from a JSON file holding an object of
+// command paths and levels, e.g. {"purge": "dangerous", "config-store-entry
+// create": "allow"}.
+func LoadDangerPolicy(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries map[string]string
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("expected a JSON object of command paths and levels: %w", err)
+	}
+
+	for path, level := range entries {
+		if strings.TrimSpace(path) == "" {
+			return nil, fmt.Errorf("empty command path")
+		}
+		if level != DangerPolicyDangerous && level != DangerPolicyAllow {
+			return nil, fmt.Errorf("invalid level %q for %q: expected %q or %q", level, path, DangerPolicyDangerous, DangerPolicyAllow)
+		}
+	}
+	return entries, nil
+}
+
+// dangerPolicyOverride returns the configured danger policy level for the
+// longest command path that prefixes the given command and arguments.
+func dangerPolicyOverride(command string, args []string) (string, bool) {
+	return types.LongestCommandPathMatch(globalDangerPolicy, command, args)
+}
@@ -0,0 +1,122 @@
+package fastly
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestDangerPolicy(t *testing.T) {
+	installMockFastly(t, `echo '{"ok": true}'`)
+
+	SetDangerPolicy(map[string]string{
+		"config-store-entry  create": DangerPolicyAllow,
+		"version activate":           DangerPolicyDangerous,
+	})
+	defer SetDangerPolicy(nil)
+
+	tests := []struct {
+		name          string
+		req           types.CommandRequest
+		requireReview bool
+	}{
+		{"allowed path runs without review", types.CommandRequest{Command: "config-store-entry", Args: []string{"create"}, Flags: []types.Flag{{Name: "store-id", Value: "abc123"}}}, false},
+		{"sibling path keeps the keyword check", types.CommandRequest{Command: "config-store-entry", Args: []string{"delete"}, Flags: []types.Flag{{Name: "store-id", Value: "abc123"}}}, true},
+		{"dangerous path requires review", types.CommandRequest{Command: "version", Args: []string{"activate"}, Flags: []types.Flag{{Name: "service-id", Value: "abc123"}}}, true},
+		{"unlisted path keeps the default", types.CommandRequest{Command: "service", Args: []string{"list"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ExecuteCommand(tt.req)
+			blocked := result.ErrorCode == "user_confirmation_required"
+			if blocked != tt.requireReview {
+				t.Errorf("Expected review required = %v, got %+v", tt.requireReview, result)
+			}
+		})
+	}
+
+	// The policy also takes precedence over safe mode
+	SetSafeMode(true)
+	defer SetSafeMode(false)
+	result := ExecuteCommand(types.CommandRequest{Command: "config-store-entry", Args: []string{"create"}, Flags: []types.Flag{{Name: "store-id", Value: "abc123"}}})
+	if result.ErrorCode == "user_confirmation_required" {
+		t.Errorf("Expected an allowed path to run without review in safe mode, got %+v", result)
+	}
+	result = ExecuteCommand(types.CommandRequest{Command: "config-store", Args: []string{"create"}, Flags: []types.Flag{{Name: "name", Value: "settings"}}})
+	if result.ErrorCode != "user_confirmation_required" {
+		t.Errorf("Expected safe mode to require review of unlisted paths, got %+v", result)
+	}
+}
+
+func TestDangerPolicyDescribe(t *testing.T) {
+	SetDangerPolicy(map[string]string{
+		"service delete": DangerPolicyAllow,
+		"service list":   DangerPolicyDangerous,
+	})
+	defer SetDangerPolicy(nil)
+
+	tests := []struct {
+		cmdPath   string
+		dangerous bool
+	}{
+		{"service delete", false},
+		{"service list", true},
+		{"backend delete", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cmdPath, func(t *testing.T) {
+			info := parseHelpOutput(tt.cmdPath, "Run the command\n\nREQUIRED FLAGS\n  -s, --service-id=SERVICE-ID  Service ID\n")
+			if flagged := strings.HasPrefix(info.Description, "⚠️"); flagged != tt.dangerous {
+				t.Errorf("Expected describe to flag the command = %v, got %q", tt.dangerous, info.Description)
+			}
+			if asked := strings.Contains(info.Instructions, "user-reviewed"); asked != tt.dangerous {
+				t.Errorf("Expected the instructions to ask for --user-reviewed = %v, got %q", tt.dangerous, info.Instructions)
+			}
+			if entry := newCatalogEntry(strings.Fields(tt.cmdPath), info, ""); entry.Dangerous != tt.dangerous {
+				t.Errorf("Expected the catalog entry dangerous = %v, got %+v", tt.dangerous, entry)
+			}
+		})
+	}
+}
+
+func TestLoadDangerPolicy(t *testing.T) {
+	write := func(content string) string {
+		path := filepath.Join(t.TempDir(), "policy.json")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	policy, err := LoadDangerPolicy(write(`{"purge": "dangerous", "config-store-entry create": "allow"}`))
+	if err != nil {
+		t.Fatalf("LoadDangerPolicy failed: %v", err)
+	}
+	if policy["purge"] != DangerPolicyDangerous || policy["config-store-entry create"] != DangerPolicyAllow {
+		t.Errorf("Unexpected policy: %v", policy)
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		errText string
+	}{
+		{"not an object", `["purge"]`, "JSON object"},
+		{"unknown level", `{"purge": "sometimes"}`, "invalid level"},
+		{"empty path", `{" ": "allow"}`, "empty command path"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadDangerPolicy(write(tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.errText) {
+				t.Errorf("Expected an error containing %q, got %v", tt.errText, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package fastly
+
+import "github.com/fastly/mcp/internal/validation"
+
+// globalHideDeniedCommands controls whether denied commands are hidden from
+// describe as if they did not exist. It can be configured via
+// SetHideDeniedCommands().
+var globalHideDeniedCommands = false
+
+// SetHideDeniedCommands enables or disables hiding denied commands from
+// describe. When enabled, describing a denied command reports it as not
+// available, exactly like a command outside the allowlist.
+func SetHideDeniedCommands(enabled bool) {
+	globalHideDeniedCommands = enabled
+}
+
+// isHiddenFromDescribe reports whether describing cmdPath must be refused
+// because it, or one of its parent paths, is denied.
+func isHiddenFromDescribe(validator *validation.Validator, cmdPath []string) bool {
+	return globalHideDeniedCommands && len(cmdPath) > 0 && validator.IsDenied(cmdPath[0], cmdPath[1:])
+}
@@ -0,0 +1,43 @@
+package fastly
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDescribeHidesDeniedCommands(t *testing.T) {
+	mockHelp := `USAGE
+  fastly stats <command> [<args> ...]
+
+View statistics (historical and realtime) for a Fastly service
+
+SUBCOMMANDS
+  historical  View historical stats for a Fastly service
+  realtime    View realtime stats for a Fastly service
+  regions     List stats regions
+`
+
+	originalExecutor := testCommandExecutor
+	testCommandExecutor = func(ctx context.Context, name string, args ...string) (string, error) {
+		return mockHelp, nil
+	}
+	defer func() {
+		testCommandExecutor = originalExecutor
+	}()
+
+	// By default denied commands can still be described
+	if info := DescribeCommand([]string{"stats", "realtime"}); info.Description == "Command not available" {
+		t.Errorf("Expected the denied command to be described by default, got %+v", info)
+	}
+
+	SetHideDeniedCommands(true)
+	defer SetHideDeniedCommands(false)
+
+	info := DescribeCommand([]string{"stats", "realtime"})
+	if info.Description != "Command not available" || info.Instructions != "The command 'stats realtime' is not available." {
+		t.Errorf("Expected the denied command to be reported as not available, got %+v", info)
+	}
+	if info := DescribeCommand([]string{"log-tail"}); info.Description != "Command not available" {
+		t.Errorf("Expected a denied top-level command to be reported as not available, got %+v", info)
+	}
+}
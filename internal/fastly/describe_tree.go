@@ -0,0 +1,101 @@
+package fastly
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/fastly/mcp/internal/types"
+	"github.com/fastly/mcp/internal/validation"
+)
+
+const (
+	// DefaultDescribeDepth is the number of subcommand levels a recursive
+	// describe walks when no depth is given.
+	DefaultDescribeDepth = 2
+
+	// MaxDescribeDepth caps the depth of a recursive describe. No Fastly
+	// command has more levels below it than the catalog descends.
+	MaxDescribeDepth = MaxCatalogDepth
+
+	// MaxDescribeInvocations bounds the help invocations of one recursive
+	// describe. Subcommands beyond it are listed without their flags.
+	MaxDescribeInvocations = 50
+)
+
+// describeTree is an assembled subcommand tree.
+type describeTree struct {
+	entries   []types.CatalogEntry
+	truncated bool
+}
+
+// describeTreeCache holds assembled trees by command path and depth. Help
+// output only changes with the CLI binary, so entries live for the process.
+var (
+	describeTreeMu    sync.Mutex
+	describeTreeCache = make(map[string]describeTree)
+)
+
+// describeSubcommandTree describes the subcommands of cmdPath allowed by the
+// validator of ctx down to depth levels, returning the tree and whether
+// MaxDescribeInvocations cut it short.
+func describeSubcommandTree(ctx context.Context, cmdPath []string, subcommands []types.SubcommandInfo, depth int) ([]types.CatalogEntry, bool) {
+	if depth <= 0 {
+		depth = DefaultDescribeDepth
+	}
+	if depth > MaxDescribeDepth {
+		depth = MaxDescribeDepth
+	}
+
+	// Trees are cached per validator set with WithValidator, as each filters
+	// out its own denied subcommands
+	validator, _ := ctx.Value(validatorContextKey{}).(*validation.Validator)
+	key := fmt.Sprintf("%s|%d|%p", strings.Join(cmdPath, " "), depth, validator)
+	describeTreeMu.Lock()
+	tree, ok := describeTreeCache[key]
+	describeTreeMu.Unlock()
+	if ok {
+		return tree.entries, tree.truncated
+	}
+
+	walker := &treeWalker{remaining: MaxDescribeInvocations, validator: validator}
+	tree = describeTree{entries: walker.walk(cmdPath, subcommands, depth), truncated: walker.truncated}
+
+	describeTreeMu.Lock()
+	describeTreeCache[key] = tree
+	describeTreeMu.Unlock()
+
+	return tree.entries, tree.truncated
+}
+
+// treeWalker tracks the help invocation budget while a tree is assembled.
+type treeWalker struct {
+	remaining int
+	truncated bool
+	// validator filters the described subcommands; nil means GetValidator()
+	validator *validation.Validator
+}
+
+// walk describes each subcommand of cmdPath and, while depth allows, its own
+// subcommands. Once the budget is spent, subcommands are only named.
+func (w *treeWalker) walk(cmdPath []string, subcommands []types.SubcommandInfo, depth int) []types.CatalogEntry {
+	entries := make([]types.CatalogEntry, 0, len(subcommands))
+	for _, sub := range subcommands {
+		subPath := append(append([]string{}, cmdPath...), sub.Name)
+		if w.remaining <= 0 {
+			w.truncated = true
+			entries = append(entries, types.CatalogEntry{Name: strings.Join(subPath, " "), Description: sub.Description})
+			continue
+		}
+		w.remaining--
+
+		info := DescribeCommandContext(WithValidator(context.Background(), w.validator), subPath, DescribeOptions{})
+		entry := newCatalogEntry(subPath, info, sub.Description)
+		if depth > 1 && len(info.Subcommands) > 0 {
+			entry.Subcommands = w.walk(subPath, info.Subcommands, depth-1)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
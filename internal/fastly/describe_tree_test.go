@@ -0,0 +1,138 @@
+package fastly
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestDescribeRecursive(t *testing.T) {
+	mockHelp := map[string]string{
+		"vcl --help": `USAGE
+  fastly vcl <command> [<args> ...]
+
+Manipulate Fastly service version VCL
+
+COMMANDS
+  custom     Manipulate Fastly service version custom VCL files
+  snippet    Manipulate Fastly VCL snippets`,
+		"vcl custom --help": `USAGE
+  fastly vcl custom <command> [<args> ...]
+
+Manipulate Fastly service version custom VCL files
+
+COMMANDS
+  list    List the uploaded VCLs`,
+		"vcl custom list --help": `USAGE
+  fastly vcl custom list --version=VERSION [<flags>]
+
+List the uploaded VCLs
+
+REQUIRED FLAGS
+      --version=VERSION  Service version`,
+		"vcl snippet --help": `USAGE
+  fastly vcl snippet <command> [<args> ...]
+
+Manipulate Fastly VCL snippets
+
+COMMANDS
+  delete    Delete a specific snippet
+  list      List the uploaded VCL snippets`,
+		"vcl snippet delete --help": `USAGE
+  fastly vcl snippet delete --name=NAME --version=VERSION [<flags>]
+
+Delete a specific snippet`,
+		"vcl snippet list --help": `USAGE
+  fastly vcl snippet list --version=VERSION [<flags>]
+
+List the uploaded VCL snippets`,
+	}
+
+	var invocations []string
+	originalExecutor := testCommandExecutor
+	testCommandExecutor = func(ctx context.Context, name string, args ...string) (string, error) {
+		invocations = append(invocations, strings.Join(args, " "))
+		return mockHelp[strings.Join(args, " ")], nil
+	}
+	defer func() {
+		testCommandExecutor = originalExecutor
+		describeTreeCache = make(map[string]describeTree)
+	}()
+
+	info := DescribeCommandWithOptions([]string{"vcl"}, DescribeOptions{Recursive: true, Depth: 2})
+	if info.SubcommandTreeTruncated {
+		t.Error("Did not expect the tree to be truncated")
+	}
+
+	find := func(entries []types.CatalogEntry, name string) *types.CatalogEntry {
+		for i := range entries {
+			if entries[i].Name == name {
+				return &entries[i]
+			}
+		}
+		return nil
+	}
+
+	snippet := find(info.SubcommandTree, "vcl snippet")
+	if snippet == nil || snippet.Description != "Manipulate Fastly VCL snippets" {
+		t.Fatalf("Expected 'vcl snippet' in the tree, got %+v", info.SubcommandTree)
+	}
+	deleteEntry := find(snippet.Subcommands, "vcl snippet delete")
+	if deleteEntry == nil || !deleteEntry.Dangerous {
+		t.Errorf("Expected a dangerous 'vcl snippet delete' entry, got %+v", snippet.Subcommands)
+	}
+	if find(snippet.Subcommands, "vcl snippet list") == nil {
+		t.Errorf("Expected 'vcl snippet list' in the tree, got %+v", snippet.Subcommands)
+	}
+	custom := find(info.SubcommandTree, "vcl custom")
+	if custom == nil {
+		t.Fatalf("Expected 'vcl custom' in the tree, got %+v", info.SubcommandTree)
+	}
+	list := find(custom.Subcommands, "vcl custom list")
+	if list == nil || len(list.RequiredFlags) != 1 || list.RequiredFlags[0].Name != "version" {
+		t.Errorf("Expected 'vcl custom list' with its required flag, got %+v", custom.Subcommands)
+	}
+	if len(invocations) != 6 {
+		t.Errorf("Expected one help invocation per command path, got %v", invocations)
+	}
+
+	invocations = nil
+	DescribeCommandWithOptions([]string{"vcl"}, DescribeOptions{Recursive: true, Depth: 2})
+	if len(invocations) != 1 {
+		t.Errorf("Expected the tree to be served from the cache, got invocations %v", invocations)
+	}
+
+	shallow := DescribeCommandWithOptions([]string{"vcl"}, DescribeOptions{Recursive: true, Depth: 1})
+	if entry := find(shallow.SubcommandTree, "vcl snippet"); entry == nil || entry.Subcommands != nil {
+		t.Errorf("Expected depth 1 to stop at direct subcommands, got %+v", shallow.SubcommandTree)
+	}
+
+	plain := DescribeCommand([]string{"vcl"})
+	if plain.SubcommandTree != nil || len(plain.Subcommands) != 2 {
+		t.Errorf("Expected the default describe to stay single-level, got %+v", plain)
+	}
+}
+
+func TestTreeWalkerBudget(t *testing.T) {
+	originalExecutor := testCommandExecutor
+	testCommandExecutor = func(ctx context.Context, name string, args ...string) (string, error) {
+		return "USAGE\n  fastly " + strings.Join(args[:len(args)-1], " ") + " [<flags>]\n\nDescription", nil
+	}
+	defer func() {
+		testCommandExecutor = originalExecutor
+	}()
+
+	walker := &treeWalker{remaining: 1}
+	entries := walker.walk([]string{"service"}, []types.SubcommandInfo{
+		{Name: "list", Description: "List services"},
+		{Name: "describe", Description: "Describe a service"},
+	}, 1)
+	if !walker.truncated || len(entries) != 2 {
+		t.Fatalf("Expected a truncated tree with both entries, got %+v", entries)
+	}
+	if entries[1].Name != "service describe" || entries[1].Description != "Describe a service" {
+		t.Errorf("Expected entries beyond the budget to be named only, got %+v", entries[1])
+	}
+}
@@ -0,0 +1,201 @@
+package fastly
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+// Statuses of a setup diagnosis step.
+const (
+	// DiagnosisOK marks a step that passed
+	DiagnosisOK = "ok"
+	// DiagnosisWarning marks a step that passed with a caveat
+	DiagnosisWarning = "warning"
+	// DiagnosisFailed marks a step that failed
+	DiagnosisFailed = "failed"
+	// DiagnosisSkipped marks a step that could not run because an earlier one failed
+	DiagnosisSkipped = "skipped"
+)
+
+// profileSectionRegex matches the profile sections of the Fastly CLI config file
+var profileSectionRegex = regexp.MustCompile(`^\s*\[profile\.([^\]]+)\]`)
+
+// locateFastlyCLI checks that the Fastly CLI binary exists, at FASTLY_CLI_PATH
+// when it is set and in PATH otherwise.
+func locateFastlyCLI() error {
+	customPath := os.Getenv("FASTLY_CLI_PATH")
+	if customPath != "" {
+		// Validate the custom path exists and is executable
+		if _, err := os.Stat(customPath); err != nil {
+			return fmt.Errorf("FASTLY_CLI_PATH is set to '%s' but file does not exist: %w", customPath, err)
+		}
+		return nil
+	}
+
+	// Try to find fastly in PATH
+	if _, err := exec.LookPath("fastly"); err != nil {
+		currentPath := os.Getenv("PATH")
+		pathDirs := strings.Split(currentPath, string(os.PathListSeparator))
+		return fmt.Errorf("fastly CLI not found in PATH. Searched directories: %v. Please install it from https://developer.fastly.com/reference/cli/ or set FASTLY_CLI_PATH environment variable to the binary location", pathDirs)
+	}
+	return nil
+}
+
+// fastlyConfigPath returns the location of the Fastly CLI config file, which
+// holds the profiles created with 'fastly profile create'. The CLI keeps it
+// under ~/.config, or under the user configuration directory of the platform,
+// such as %AppData% on Windows; the first existing file wins.
+func fastlyConfigPath() (string, error) {
+	var candidates []string
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		candidates = append(candidates, filepath.Join(dir, "fastly", "config.toml"))
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		candidates = append(candidates, filepath.Join(home, ".config", "fastly", "config.toml"))
+	}
+	if dir, err := os.UserConfigDir(); err == nil {
+		candidates = append(candidates, filepath.Join(dir, "fastly", "config.toml"))
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("neither the home nor the user configuration directory is known")
+	}
+
+	for _, path := range candidates {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return candidates[0], nil
+}
+
+// configProfiles returns the names of the profiles in a Fastly CLI config file.
+func configProfiles(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	var profiles []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if match := profileSectionRegex.FindStringSubmatch(scanner.Text()); match != nil {
+			profiles = append(profiles, strings.Trim(match[1], `"`))
+		}
+	}
+	return profiles, scanner.Err()
+}
+
+// DiagnoseSetup checks the Fastly CLI setup step by step, like CheckSetup, but
+// reports every step instead of the first error, so that a missing CLI, a
+// missing token and an invalid token can be told apart:
+//  1. cli_installed: the fastly binary is found
+//  2. binary_security: the binary passes the security checks
+//  3. cli_version: 'fastly version' runs and reports at least MinimumCLIVersion
+//  4. api_token: whether FASTLY_API_TOKEN is set
+//  5. profile: whether the CLI config file holds a profile
+//  6. authentication: 'fastly whoami' succeeds with the configured token
+//
+// Steps that depend on a failed step are skipped. A healthy setup closes the
+// authentication circuit breaker.
+func DiagnoseSetup(ctx context.Context) types.SetupDiagnosis {
+	var diagnosis types.SetupDiagnosis
+	add := func(step types.DiagnosticStep) {
+		diagnosis.Steps = append(diagnosis.Steps, step)
+		if step.Status == DiagnosisFailed && diagnosis.FailedStep == "" {
+			diagnosis.FailedStep = step.Name
+		}
+	}
+	skipped := func(name string) types.DiagnosticStep {
+		return types.DiagnosticStep{Name: name, Status: DiagnosisSkipped, Detail: "Skipped because the " + diagnosis.FailedStep + " step failed"}
+	}
+
+	// The CLI itself
+	cliUsable := false
+	if err := locateFastlyCLI(); err != nil {
+		add(types.DiagnosticStep{Name: "cli_installed", Status: DiagnosisFailed, Detail: err.Error(), Fix: "Install the Fastly CLI from https://www.fastly.com/documentation/reference/cli/ or set FASTLY_CLI_PATH to the binary"})
+		add(skipped("binary_security"))
+		add(skipped("cli_version"))
+	} else {
+		add(types.DiagnosticStep{Name: "cli_installed", Status: DiagnosisOK, Detail: "The fastly binary was found"})
+		if err := ValidateBinarySecurity(); err != nil {
+			add(types.DiagnosticStep{Name: "binary_security", Status: DiagnosisFailed, Detail: err.Error(), Fix: "Fix the permissions of the fastly binary, e.g. with chmod o-w, or move it to a directory only you can write to"})
+			add(skipped("cli_version"))
+		} else {
+			add(types.DiagnosticStep{Name: "binary_security", Status: DiagnosisOK, Detail: "The fastly binary passed the security checks"})
+			result := RunFastlyCommand(CommandRunConfig{Context: ctx, Command: "fastly", Args: []string{"version"}, Timeout: SetupCheckTimeout})
+			if result.Error != nil {
+				add(types.DiagnosticStep{Name: "cli_version", Status: DiagnosisFailed, Detail: "fastly version failed: " + GetErrorMessage(result), Fix: "Reinstall the Fastly CLI; the binary does not run"})
+			} else if err := checkCLIVersion(result.Stdout); err != nil {
+				diagnosis.CLIVersion = DetectedCLIVersion()
+				add(types.DiagnosticStep{Name: "cli_version", Status: DiagnosisFailed, Detail: err.Error(), Fix: fmt.Sprintf("Upgrade the Fastly CLI to version %s or later from https://github.com/fastly/cli/releases", MinimumCLIVersion)})
+			} else {
+				version, _, _ := strings.Cut(strings.TrimSpace(CleanANSI(result.Stdout)), "\n")
+				if detected, ok := ParseCLIVersion(result.Stdout); ok {
+					diagnosis.CLIVersion = detected.String()
+				} else {
+					version += " (development build or unrecognized version, not checked)"
+				}
+				add(types.DiagnosticStep{Name: "cli_version", Status: DiagnosisOK, Detail: version})
+				cliUsable = true
+			}
+		}
+	}
+
+	// The credentials
+	tokenSet := os.Getenv("FASTLY_API_TOKEN") != "" && globalProfile == ""
+	if globalProfile != "" {
+		add(types.DiagnosticStep{Name: "api_token", Status: DiagnosisOK, Detail: fmt.Sprintf("Commands run with the '%s' profile; FASTLY_API_TOKEN is not passed to the CLI", globalProfile)})
+	} else if tokenSet {
+		add(types.DiagnosticStep{Name: "api_token", Status: DiagnosisWarning, Detail: "FASTLY_API_TOKEN is set and takes precedence over profiles", Fix: "Prefer a profile created with 'fastly profile create'; FASTLY_API_TOKEN is not recommended for MCP clients"})
+	} else {
+		add(types.DiagnosticStep{Name: "api_token", Status: DiagnosisOK, Detail: "FASTLY_API_TOKEN is not set; the CLI uses a profile"})
+	}
+
+	if path, err := fastlyConfigPath(); err != nil {
+		add(types.DiagnosticStep{Name: "profile", Status: DiagnosisWarning, Detail: "The CLI config file could not be located: " + err.Error()})
+	} else if profiles, err := configProfiles(path); err != nil && !os.IsNotExist(err) {
+		add(types.DiagnosticStep{Name: "profile", Status: DiagnosisWarning, Detail: fmt.Sprintf("%s could not be read: %v", path, err)})
+	} else if globalProfile != "" && !slices.Contains(profiles, globalProfile) {
+		add(types.DiagnosticStep{Name: "profile", Status: DiagnosisFailed, Detail: fmt.Sprintf("The configured '%s' profile is not in %s", globalProfile, path), Fix: fmt.Sprintf("Run 'fastly profile create %s' or pass the name of an existing profile to --profile", globalProfile)})
+	} else if len(profiles) > 0 {
+		add(types.DiagnosticStep{Name: "profile", Status: DiagnosisOK, Detail: fmt.Sprintf("%s holds profiles: %s", path, strings.Join(profiles, ", "))})
+	} else if tokenSet {
+		add(types.DiagnosticStep{Name: "profile", Status: DiagnosisOK, Detail: fmt.Sprintf("No profile in %s; FASTLY_API_TOKEN is used instead", path)})
+	} else {
+		add(types.DiagnosticStep{Name: "profile", Status: DiagnosisFailed, Detail: fmt.Sprintf("No token is configured: FASTLY_API_TOKEN is not set and %s holds no profile", path), Fix: "Run 'fastly profile create' to store an API token"})
+	}
+
+	// The token against the API
+	if !cliUsable || diagnosis.FailedStep != "" {
+		add(skipped("authentication"))
+	} else {
+		result := RunFastlyCommand(CommandRunConfig{Context: ctx, Command: "fastly", Args: append([]string{"whoami"}, ProfileArgs()...), Timeout: SetupCheckTimeout})
+		switch {
+		case result.Error == nil:
+			who, _, _ := strings.Cut(strings.TrimSpace(CleanANSI(result.Stdout)), "\n")
+			add(types.DiagnosticStep{Name: "authentication", Status: DiagnosisOK, Detail: "fastly whoami succeeded: " + who})
+		case result.TimedOut:
+			add(types.DiagnosticStep{Name: "authentication", Status: DiagnosisFailed, Detail: fmt.Sprintf("fastly whoami timed out after %s", SetupCheckTimeout), Fix: "Check the network connection and any proxy settings for api.fastly.com"})
+		case IsAuthenticationError(result.Stdout, result.Stderr):
+			add(types.DiagnosticStep{Name: "authentication", Status: DiagnosisFailed, Detail: "The configured token was rejected: " + GetErrorMessage(result), Fix: "The token is invalid, expired or revoked; create a new one at https://manage.fastly.com/account/personal/tokens and store it with 'fastly profile update' or 'fastly profile create'"})
+		default:
+			add(types.DiagnosticStep{Name: "authentication", Status: DiagnosisFailed, Detail: "fastly whoami failed: " + GetErrorMessage(result), Fix: "Check the error message; the API could not be reached with the configured token"})
+		}
+	}
+
+	diagnosis.Healthy = diagnosis.FailedStep == ""
+	if diagnosis.Healthy {
+		ResetAuthBreaker()
+	}
+	return diagnosis
+}
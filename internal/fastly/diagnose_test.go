@@ -0,0 +1,109 @@
+package fastly
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+// diagnoseScript answers 'fastly version', and 'fastly whoami' until a file
+// named unauthorized exists next to the mock binary.
+const diagnoseScript = `case "$1" in
+version) echo 'Fastly CLI version v10.0.0' ;;
+whoami) if [ -f "$(dirname "$0")/unauthorized" ]; then echo 'Error: unauthorized' >&2; exit 1; fi; echo 'Alice <alice@example.com>' ;;
+esac`
+
+func stepStatus(diagnosis types.SetupDiagnosis, name string) string {
+	for _, step := range diagnosis.Steps {
+		if step.Name == name {
+			return step.Status
+		}
+	}
+	return ""
+}
+
+func TestDiagnoseSetup(t *testing.T) {
+	callsFile := installMockFastly(t, diagnoseScript)
+	dir := filepath.Dir(callsFile)
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("FASTLY_API_TOKEN", "")
+
+	// No token at all
+	diagnosis := DiagnoseSetup(context.Background())
+	if diagnosis.Healthy || diagnosis.FailedStep != "profile" {
+		t.Errorf("Expected the profile step to fail without a token, got %+v", diagnosis)
+	}
+	if stepStatus(diagnosis, "cli_version") != DiagnosisOK || stepStatus(diagnosis, "authentication") != DiagnosisSkipped {
+		t.Errorf("Expected the CLI to pass and authentication to be skipped, got %+v", diagnosis.Steps)
+	}
+
+	// A profile holding a rejected token
+	if err := os.MkdirAll(filepath.Join(configHome, "fastly"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	config := "config_version = 4\n\n[profile.dev]\ndefault = true\ntoken = \"abc\"\n"
+	if err := os.WriteFile(filepath.Join(configHome, "fastly", "config.toml"), []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "unauthorized"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	diagnosis = DiagnoseSetup(context.Background())
+	if diagnosis.FailedStep != "authentication" || stepStatus(diagnosis, "profile") != DiagnosisOK {
+		t.Errorf("Expected the authentication step to fail with a profile, got %+v", diagnosis)
+	}
+	last := diagnosis.Steps[len(diagnosis.Steps)-1]
+	if !strings.Contains(last.Detail, "rejected") || last.Fix == "" {
+		t.Errorf("Expected the rejected token to be reported with a fix, got %+v", last)
+	}
+
+	// A working token
+	if err := os.Remove(filepath.Join(dir, "unauthorized")); err != nil {
+		t.Fatal(err)
+	}
+	diagnosis = DiagnoseSetup(context.Background())
+	if !diagnosis.Healthy || diagnosis.FailedStep != "" || diagnosis.CLIVersion != "10.0.0" {
+		t.Errorf("Expected a healthy setup of CLI 10.0.0, got %+v", diagnosis)
+	}
+
+	// FASTLY_API_TOKEN stands in for a profile but is reported as a warning
+	if err := os.Remove(filepath.Join(configHome, "fastly", "config.toml")); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("FASTLY_API_TOKEN", "abc")
+	diagnosis = DiagnoseSetup(context.Background())
+	if !diagnosis.Healthy || stepStatus(diagnosis, "api_token") != DiagnosisWarning {
+		t.Errorf("Expected a healthy setup with a token warning, got %+v", diagnosis)
+	}
+}
+
+func TestDiagnoseSetupMissingCLI(t *testing.T) {
+	t.Setenv("FASTLY_CLI_PATH", filepath.Join(t.TempDir(), "fastly"))
+
+	diagnosis := DiagnoseSetup(context.Background())
+	if diagnosis.Healthy || diagnosis.FailedStep != "cli_installed" {
+		t.Errorf("Expected the cli_installed step to fail, got %+v", diagnosis)
+	}
+	for _, name := range []string{"binary_security", "cli_version", "authentication"} {
+		if status := stepStatus(diagnosis, name); status != DiagnosisSkipped {
+			t.Errorf("Expected %s to be skipped, got %q", name, status)
+		}
+	}
+}
+
+func TestDiagnoseSetupOldCLI(t *testing.T) {
+	installMockFastly(t, `echo 'Fastly CLI version v9.2.0 (1a2b3c4)'`)
+
+	diagnosis := DiagnoseSetup(context.Background())
+	if diagnosis.FailedStep != "cli_version" || diagnosis.CLIVersion != "9.2.0" {
+		t.Errorf("Expected the cli_version step to fail for 9.2.0, got %+v", diagnosis)
+	}
+	if status := stepStatus(diagnosis, "authentication"); status != DiagnosisSkipped {
+		t.Errorf("Expected authentication to be skipped, got %q", status)
+	}
+}
@@ -0,0 +1,121 @@
+package fastly
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+// DiffByKey compares two lists of JSON objects, matching items by the value of
+// keyField. Field names are matched case-insensitively so that both "Name" and
+// "name" work as keys. Fields listed in ignoreFields are left out of the
+// comparison, which is useful for bookkeeping fields such as timestamps or
+// version numbers that always differ. Items without the key field are skipped.
+func DiffByKey(from, to []interface{}, keyField string, ignoreFields []string) types.ResourceDiff {
+	ignored := make(map[string]bool, len(ignoreFields))
+	for _, field := range ignoreFields {
+		ignored[strings.ToLower(field)] = true
+	}
+
+	fromItems, fromKeys := indexByKey(from, keyField)
+	toItems, toKeys := indexByKey(to, keyField)
+
+	diff := types.ResourceDiff{
+		Added:   []interface{}{},
+		Removed: []interface{}{},
+		Changed: []types.ResourceChange{},
+	}
+
+	for _, key := range fromKeys {
+		if _, exists := toItems[key]; !exists {
+			diff.Removed = append(diff.Removed, fromItems[key])
+		}
+	}
+
+	for _, key := range toKeys {
+		newItem := toItems[key]
+		oldItem, exists := fromItems[key]
+		if !exists {
+			diff.Added = append(diff.Added, newItem)
+			continue
+		}
+
+		fields := diffFields(oldItem, newItem, ignored)
+		if len(fields) == 0 {
+			diff.Unchanged++
+			continue
+		}
+		diff.Changed = append(diff.Changed, types.ResourceChange{Key: key, Fields: fields})
+	}
+
+	return diff
+}
+
+// indexByKey maps each object in items by its key field value, and returns the
+// keys in sorted order so diffs are deterministic.
+func indexByKey(items []interface{}, keyField string) (map[string]map[string]interface{}, []string) {
+	index := make(map[string]map[string]interface{}, len(items))
+	var keys []string
+
+	for _, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		value, ok := lookupField(obj, keyField)
+		if !ok || value == nil {
+			continue
+		}
+		key := fmt.Sprintf("%v", value)
+		if _, duplicate := index[key]; duplicate {
+			continue
+		}
+		index[key] = obj
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return index, keys
+}
+
+// diffFields returns the fields that differ between two objects.
+func diffFields(oldItem, newItem map[string]interface{}, ignored map[string]bool) map[string]types.FieldChange {
+	fields := map[string]types.FieldChange{}
+
+	for name, oldValue := range oldItem {
+		if ignored[strings.ToLower(name)] {
+			continue
+		}
+		newValue, exists := newItem[name]
+		if !exists || !reflect.DeepEqual(oldValue, newValue) {
+			fields[name] = types.FieldChange{From: oldValue, To: newValue}
+		}
+	}
+
+	for name, newValue := range newItem {
+		if ignored[strings.ToLower(name)] {
+			continue
+		}
+		if _, exists := oldItem[name]; !exists {
+			fields[name] = types.FieldChange{From: nil, To: newValue}
+		}
+	}
+
+	return fields
+}
+
+// lookupField returns the value of a field, matching its name case-insensitively.
+func lookupField(obj map[string]interface{}, field string) (interface{}, bool) {
+	if value, ok := obj[field]; ok {
+		return value, true
+	}
+	for name, value := range obj {
+		if strings.EqualFold(name, field) {
+			return value, true
+		}
+	}
+	return nil, false
+}
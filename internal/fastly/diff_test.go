@@ -0,0 +1,50 @@
+package fastly
+
+import (
+	"testing"
+)
+
+func TestDiffByKey(t *testing.T) {
+	from := []interface{}{
+		map[string]interface{}{"Name": "origin_a", "Address": "a.example.com", "Port": float64(443), "ServiceVersion": float64(1)},
+		map[string]interface{}{"Name": "origin_b", "Address": "b.example.com", "Port": float64(443), "ServiceVersion": float64(1)},
+		map[string]interface{}{"Name": "origin_c", "Address": "c.example.com", "Port": float64(80), "ServiceVersion": float64(1)},
+	}
+	to := []interface{}{
+		map[string]interface{}{"Name": "origin_a", "Address": "a.example.com", "Port": float64(443), "ServiceVersion": float64(2)},
+		map[string]interface{}{"Name": "origin_c", "Address": "c.example.com", "Port": float64(443), "ServiceVersion": float64(2)},
+		map[string]interface{}{"Name": "origin_d", "Address": "d.example.com", "Port": float64(443), "ServiceVersion": float64(2)},
+	}
+
+	diff := DiffByKey(from, to, "name", []string{"ServiceVersion"})
+
+	if len(diff.Added) != 1 || diff.Added[0].(map[string]interface{})["Name"] != "origin_d" {
+		t.Errorf("Expected origin_d to be added, got %v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].(map[string]interface{})["Name"] != "origin_b" {
+		t.Errorf("Expected origin_b to be removed, got %v", diff.Removed)
+	}
+	if len(diff.Changed) != 1 {
+		t.Fatalf("Expected one changed item, got %v", diff.Changed)
+	}
+	change := diff.Changed[0]
+	if change.Key != "origin_c" {
+		t.Errorf("Expected origin_c to be changed, got %s", change.Key)
+	}
+	if len(change.Fields) != 1 || change.Fields["Port"].From != float64(80) || change.Fields["Port"].To != float64(443) {
+		t.Errorf("Expected only Port to change from 80 to 443, got %v", change.Fields)
+	}
+	if diff.Unchanged != 1 {
+		t.Errorf("Expected one unchanged item (ignored fields excluded), got %d", diff.Unchanged)
+	}
+}
+
+func TestDiffByKeyEmptyLists(t *testing.T) {
+	diff := DiffByKey(nil, nil, "Name", nil)
+	if diff.Added == nil || diff.Removed == nil || diff.Changed == nil {
+		t.Error("Expected empty (non-nil) slices so the diff serializes as arrays")
+	}
+	if len(diff.Added)+len(diff.Removed)+len(diff.Changed)+diff.Unchanged != 0 {
+		t.Errorf("Expected an empty diff, got %+v", diff)
+	}
+}
@@ -31,16 +31,27 @@ var errorCodePatterns = []errorCodePattern{
 		patterns: []string{"SYSTEM ERROR: Failed to execute", "SYSTEM ERROR: Command"},
 		code:     "system_execution_error",
 	},
+	{
+		// Certificate verification failures, often caused by a wrong system clock.
+		// Checked before the generic patterns because x509 messages mention validity.
+		patterns: []string{"x509:", "certificate has expired or is not yet valid", "tls: failed to verify certificate", "certificate signed by unknown authority"},
+		code:     "tls_error",
+	},
 	{
 		patterns: []string{"unauthorized", "authentication", "no api token"},
 		code:     "auth_required",
 	},
+	{
+		// A valid token that lacks the scope for the operation
+		patterns: []string{"insufficient scope", "insufficient_scope", "insufficient permission", "forbidden", "403"},
+		code:     "insufficient_permission",
+	},
 	{
 		patterns: []string{"not found", "404"},
 		code:     "not_found",
 	},
 	{
-		patterns: []string{"permission", "forbidden", "403"},
+		patterns: []string{"permission"},
 		code:     "permission_denied",
 	},
 	{
@@ -73,9 +84,11 @@ var errorCodePatterns = []errorCodePattern{
 //
 // Common error codes returned:
 //   - "binary_security_error": Binary security validation failures (world-writable, etc.)
+//   - "tls_error": TLS certificate verification failures (x509), e.g. from clock skew
 //   - "auth_required": Authentication or API token issues
 //   - "not_found": Resource not found (404 errors)
-//   - "permission_denied": Permission or forbidden errors (403)
+//   - "insufficient_permission": Token lacks the scope for the operation (403, forbidden)
+//   - "permission_denied": Other permission errors
 //   - "validation_error": Invalid input or validation failures
 //   - "already_exists": Duplicate resource errors
 //   - "rate_limit": Rate limiting errors (429)
@@ -0,0 +1,197 @@
+package fastly
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fastly/mcp/internal/types"
+	"github.com/fastly/mcp/internal/validation"
+)
+
+const (
+	// MaxBatchSteps is the maximum number of commands in one batch.
+	MaxBatchSteps = 20
+
+	// PlanTokenTTL is how long an issued batch plan can be executed.
+	PlanTokenTTL = 10 * time.Minute
+)
+
+// batchPlan is an issued plan: the resolved steps and the signature of the
+// steps as the caller submitted them.
+type batchPlan struct {
+	signature string
+	steps     []types.CommandRequest
+	issuedAt  time.Time
+}
+
+// batchPlans holds the plans issued by PlanBatch, keyed by plan token.
+var batchPlans = struct {
+	mu      sync.Mutex
+	entries map[string]batchPlan
+}{entries: make(map[string]batchPlan)}
+
+// PlanBatch resolves the steps of a batch into the command lines they run,
+// classifies each by danger and issues a plan token without executing anything.
+// The token is bound to signature, which identifies the steps as the caller
+// submitted them, and approves the review of every step at once.
+func PlanBatch(steps []types.CommandRequest, signature string) types.BatchResponse {
+	return PlanBatchContext(context.Background(), steps, signature)
+}
+
+// PlanBatchContext is like PlanBatch but validates the steps with the
+// validator of ctx, see WithValidator.
+func PlanBatchContext(ctx context.Context, steps []types.CommandRequest, signature string) types.BatchResponse {
+	if len(steps) == 0 {
+		return batchError(fmt.Errorf("batch has no steps"), "invalid_batch", "Pass at least one step.")
+	}
+	if len(steps) > MaxBatchSteps {
+		return batchError(fmt.Errorf("batch of %d steps exceeds the maximum of %d", len(steps), MaxBatchSteps), "invalid_batch", "Split the work into smaller batches.")
+	}
+
+	validator := ContextValidator(ctx)
+
+	resolved := make([]types.CommandRequest, len(steps))
+	plan := make([]types.BatchStep, len(steps))
+	for i, step := range steps {
+		req := resolveBatchStep(step)
+		if err := validateBatchStep(validator, req); err != nil {
+			return batchError(fmt.Errorf("step %d: %w", i, err), "invalid_batch", "No plan was made. Fix the step and plan the batch again.")
+		}
+		resolved[i] = req
+
+		operationType, _ := GetOperationType(req.Command, req.Args)
+		dangerous, warning := reviewRequirement(req.Command, req.Args)
+		plan[i] = types.BatchStep{
+			Index:         i,
+			CommandLine:   BuildCommandLine(req.Command, req.Args, req.Flags),
+			OperationType: operationType,
+			Dangerous:     dangerous,
+			Destructive:   isDestructiveOperation(req.Command, req.Args),
+			Warning:       warning,
+		}
+	}
+
+	token := randomToken("plan-")
+	now := time.Now()
+
+	batchPlans.mu.Lock()
+	for t, entry := range batchPlans.entries {
+		if now.Sub(entry.issuedAt) > PlanTokenTTL {
+			delete(batchPlans.entries, t)
+		}
+	}
+	batchPlans.entries[token] = batchPlan{signature: signature, steps: resolved, issuedAt: now}
+	batchPlans.mu.Unlock()
+
+	return types.BatchResponse{
+		Success:      true,
+		PlanToken:    token,
+		Plan:         plan,
+		Instructions: fmt.Sprintf("Nothing was executed. Show the plan to the human user; approving it approves every step, including those marked dangerous. The plan token is valid once, for %s.", PlanTokenTTL),
+		NextSteps: []string{
+			"Ask the human user to review every command line of the plan",
+			"Only after receiving human approval, call fastly_execute_batch again with the same steps and plan_token " + token,
+			"Do NOT change the steps; the plan token is bound to them",
+		},
+	}
+}
+
+// ExecutePlannedBatch runs the steps of the plan issued under token, in order,
+// stopping at the first failed step. The plan must have been issued for the same
+// signature and is used up by the first attempt to execute it.
+func ExecutePlannedBatch(ctx context.Context, token, signature string) types.BatchResponse {
+	batchPlans.mu.Lock()
+	plan, ok := batchPlans.entries[token]
+	delete(batchPlans.entries, token)
+	batchPlans.mu.Unlock()
+
+	if !ok || plan.signature != signature || time.Since(plan.issuedAt) > PlanTokenTTL {
+		return batchError(fmt.Errorf("plan token is unknown, expired, already used or was issued for different steps"), "plan_token_invalid",
+			"Nothing was executed. Plan the batch again with plan_only and have the human user approve the new plan.")
+	}
+
+	// The approved plan stands in for the review and confirmation of each step
+	ctx = withConfirmedBatch(ctx)
+
+	response := types.BatchResponse{Success: true}
+	for i, step := range plan.steps {
+		step.Flags = append(append([]types.Flag{}, step.Flags...), types.Flag{Name: "user-reviewed"})
+		result := ExecuteCommandContext(ctx, step)
+		response.Results = append(response.Results, result)
+		response.Executed++
+
+		if !result.Success {
+			response.Success = false
+			response.Skipped = len(plan.steps) - i - 1
+			response.Error = fmt.Sprintf("step %d failed; %d later steps were not run", i, response.Skipped)
+			response.ErrorCode = "batch_step_failed"
+			response.Instructions = "The batch stopped at the failed step. Steps before it were applied."
+			response.NextSteps = []string{
+				"Check results for the error of the failed step",
+				"Plan the remaining steps again once the failure is resolved",
+			}
+			break
+		}
+	}
+
+	return response
+}
+
+// resolveBatchStep splits a command given with spaces into command and args,
+// forwards renamed commands and drops the MCP-only confirmation flags, which
+// the plan approval replaces.
+func resolveBatchStep(step types.CommandRequest) types.CommandRequest {
+	if parts := strings.Fields(step.Command); len(parts) > 1 {
+		step.Command = parts[0]
+		step.Args = append(parts[1:], step.Args...)
+	}
+	step.Command, _ = resolveCommandAlias(step.Command)
+	step.Flags = withoutConfirmationFlags(step.Flags)
+	return step
+}
+
+// validateBatchStep checks a resolved step before it is planned. The executor
+// repeats all of its checks when the step is run.
+func validateBatchStep(validator *validation.Validator, req types.CommandRequest) error {
+	if err := validator.ValidateCommand(req.Command); err != nil {
+		return err
+	}
+	if isSelfManagementBlocked(req.Command) {
+		return fmt.Errorf("the '%s' command modifies the Fastly CLI installation and is disabled", req.Command)
+	}
+	if err := validator.ValidateArgs(req.Args); err != nil {
+		return err
+	}
+	if isReadOnlyBlocked(req.Command, req.Args) {
+		return fmt.Errorf("the '%s' command may modify resources and the server runs in read-only mode", strings.TrimSpace(req.Command+" "+strings.Join(req.Args, " ")))
+	}
+	if validator.IsDenied(req.Command, req.Args) {
+		deniedCommand := validator.GetDeniedCommand(req.Command, req.Args)
+		if reason := validator.DeniedReason(req.Command, req.Args); reason != "" {
+			return fmt.Errorf("the '%s' command is not available (%s)", deniedCommand, reason)
+		}
+		return fmt.Errorf("the '%s' command is not available", deniedCommand)
+	}
+	for _, flag := range req.Flags {
+		if err := validator.ValidateFlagName(flag.Name); err != nil {
+			return err
+		}
+		if err := validator.ValidateFlagValue(flag.Value); err != nil {
+			return fmt.Errorf("flag '%s': %w", flag.Name, err)
+		}
+	}
+	return nil
+}
+
+// batchError creates a response for a batch that was refused.
+func batchError(err error, code, instructions string) types.BatchResponse {
+	return types.BatchResponse{
+		Success:      false,
+		Error:        err.Error(),
+		ErrorCode:    code,
+		Instructions: instructions,
+	}
+}
@@ -0,0 +1,121 @@
+package fastly
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestPlanBatchResolvesSteps(t *testing.T) {
+	callsFile := installMockFastly(t, `echo '[]'`)
+
+	result := PlanBatch([]types.CommandRequest{
+		{Command: "service list", Flags: []types.Flag{{Name: "json"}}},
+		{Command: "backend", Args: []string{"delete"}, Flags: []types.Flag{{Name: "service-id", Value: "abc123"}, {Name: "user-reviewed"}}},
+	}, "signature")
+	if !result.Success || result.PlanToken == "" {
+		t.Fatalf("Expected a plan with a token, got %+v", result)
+	}
+	if len(result.Plan) != 2 {
+		t.Fatalf("Expected 2 steps, got %+v", result.Plan)
+	}
+
+	first, second := result.Plan[0], result.Plan[1]
+	if first.CommandLine != "fastly service list --json" || first.OperationType != "read" || first.Dangerous {
+		t.Errorf("Unexpected first step: %+v", first)
+	}
+	// MCP-only flags are not part of the plan
+	if second.CommandLine != "fastly backend delete --service-id abc123" || !second.Dangerous || !second.Destructive || second.Warning == "" {
+		t.Errorf("Unexpected second step: %+v", second)
+	}
+
+	if _, err := os.ReadFile(callsFile); err == nil {
+		t.Error("Expected planning not to invoke the CLI")
+	}
+}
+
+func TestPlanBatchRejectsInvalidSteps(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []types.CommandRequest
+	}{
+		{"empty", nil},
+		{"too many steps", make([]types.CommandRequest, MaxBatchSteps+1)},
+		{"invalid argument", []types.CommandRequest{{Command: "service", Args: []string{"list;rm"}}}},
+		{"self-management", []types.CommandRequest{{Command: "update"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := PlanBatch(tt.steps, "signature")
+			if result.Success || result.ErrorCode != "invalid_batch" || result.PlanToken != "" {
+				t.Errorf("Expected invalid_batch without a token, got %+v", result)
+			}
+		})
+	}
+}
+
+func TestExecutePlannedBatchRequiresMatchingToken(t *testing.T) {
+	callsFile := installMockFastly(t, `echo "ok"`)
+
+	steps := []types.CommandRequest{
+		{Command: "service-version", Args: []string{"clone"}, Flags: []types.Flag{{Name: "service-id", Value: "abc123"}, {Name: "version", Value: "1"}}},
+		{Command: "backend", Args: []string{"delete"}, Flags: []types.Flag{{Name: "service-id", Value: "abc123"}, {Name: "name", Value: "old"}}},
+	}
+
+	plan := PlanBatch(steps, "signature")
+	if result := ExecutePlannedBatch(context.Background(), "plan-unknown", "signature"); result.ErrorCode != "plan_token_invalid" {
+		t.Errorf("Expected an unknown token to be rejected, got %+v", result)
+	}
+	if result := ExecutePlannedBatch(context.Background(), plan.PlanToken, "other steps"); result.ErrorCode != "plan_token_invalid" {
+		t.Errorf("Expected a token for other steps to be rejected, got %+v", result)
+	}
+	if _, err := os.ReadFile(callsFile); err == nil {
+		t.Fatal("Expected the CLI not to be invoked without a matching token")
+	}
+
+	// The rejected attempt used the token up
+	if result := ExecutePlannedBatch(context.Background(), plan.PlanToken, "signature"); result.ErrorCode != "plan_token_invalid" {
+		t.Fatalf("Expected a used token to be rejected, got %+v", result)
+	}
+
+	// Safe mode's review and confirmation token are covered by the plan
+	SetSafeMode(true)
+	defer SetSafeMode(false)
+
+	plan = PlanBatch(steps, "signature")
+	result := ExecutePlannedBatch(context.Background(), plan.PlanToken, "signature")
+	if !result.Success || result.Executed != 2 || len(result.Results) != 2 {
+		t.Fatalf("Expected both steps to run, got %+v", result)
+	}
+
+	calls, _ := os.ReadFile(callsFile)
+	lines := strings.Split(strings.TrimSpace(string(calls)), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "service-version clone") || !strings.HasPrefix(lines[1], "backend delete") {
+		t.Errorf("Expected the steps to run in order, got %q", calls)
+	}
+	if strings.Contains(string(calls), "user-reviewed") {
+		t.Errorf("Expected MCP-only flags not to reach the CLI, got %q", calls)
+	}
+}
+
+func TestExecutePlannedBatchStopsAtFailedStep(t *testing.T) {
+	installMockFastly(t, `case "$1" in backend) echo "not found" >&2; exit 1;; esac; echo "ok"`)
+
+	plan := PlanBatch([]types.CommandRequest{
+		{Command: "service", Args: []string{"list"}},
+		{Command: "backend", Args: []string{"create"}, Flags: []types.Flag{{Name: "service-id", Value: "abc123"}}},
+		{Command: "service-version", Args: []string{"activate"}, Flags: []types.Flag{{Name: "service-id", Value: "abc123"}}},
+	}, "signature")
+
+	result := ExecutePlannedBatch(context.Background(), plan.PlanToken, "signature")
+	if result.Success || result.ErrorCode != "batch_step_failed" {
+		t.Fatalf("Expected batch_step_failed, got %+v", result)
+	}
+	if result.Executed != 2 || result.Skipped != 1 {
+		t.Errorf("Expected 2 executed and 1 skipped step, got %d and %d", result.Executed, result.Skipped)
+	}
+}
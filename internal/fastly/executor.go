@@ -3,10 +3,13 @@
 package fastly
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/fastly/mcp/internal/cache"
 	"github.com/fastly/mcp/internal/types"
@@ -25,6 +28,31 @@ var globalValidator *validation.Validator
 // It can be configured via SetTokenEncryptionEnabled().
 var globalTokenEncryptionEnabled = false
 
+// globalStripFlags holds additional MCP-only flag names that are removed before
+// the command line is built, in the same way as --user-reviewed.
+// It can be configured via SetStripFlags().
+var globalStripFlags = map[string]bool{}
+
+// globalFirstOutputTimeout is how long an executed command may stay silent before
+// it is stopped as stalled. It can be configured via SetFirstOutputTimeout().
+var globalFirstOutputTimeout = FirstOutputTimeout
+
+// globalCommandTimeout is the timeout of a command that does not request one.
+// It can be configured via SetCommandTimeout().
+var globalCommandTimeout = CommandTimeout
+
+// globalMaxCommandTimeout bounds the per-call timeout an agent may request.
+// It can be configured via SetMaxCommandTimeout().
+var globalMaxCommandTimeout = MaxCommandTimeout
+
+// globalMaxOutputSize is the size above which text output and JSON objects are
+// truncated. It can be configured via SetMaxOutputSize().
+var globalMaxOutputSize = MaxOutputSize
+
+// globalMaxJSONArrayItems is the number of items above which JSON arrays are
+// truncated. It can be configured via SetMaxJSONArrayItems().
+var globalMaxJSONArrayItems = MaxJSONArrayItems
+
 // SetSanitizationEnabled enables or disables output sanitization globally.
 // When enabled, sensitive information like API tokens, secrets, and personal data
 // will be redacted from command outputs before being returned to the caller.
@@ -32,6 +60,109 @@ func SetSanitizationEnabled(enabled bool) {
 	globalSanitizeOpts.Enabled = enabled
 }
 
+// SetMaskPaths configures JSON field paths (e.g. "customer.email") whose values
+// are always redacted from command output, even when sanitization is disabled.
+func SetMaskPaths(paths []string) {
+	var maskPaths []string
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if path != "" {
+			maskPaths = append(maskPaths, path)
+		}
+	}
+	globalSanitizeOpts.MaskPaths = maskPaths
+}
+
+// SetFirstOutputTimeout configures how long an executed command may run without
+// producing any output before it is stopped and reported as stalled. A value of
+// zero, the default, disables the check, leaving only the total command timeout.
+// Commands that run with a timeout above the command timeout are never stopped
+// as stalled.
+func SetFirstOutputTimeout(timeout time.Duration) {
+	globalFirstOutputTimeout = timeout
+}
+
+// SetCommandTimeout configures the timeout of commands that do not request one
+// with timeout_seconds, replacing the 30 second default.
+func SetCommandTimeout(timeout time.Duration) {
+	globalCommandTimeout = timeout
+}
+
+// DefaultCommandTimeout returns the configured timeout of commands that do not
+// request one.
+func DefaultCommandTimeout() time.Duration {
+	return globalCommandTimeout
+}
+
+// MaxRequestTimeout returns the configured upper bound for per-call timeouts.
+func MaxRequestTimeout() time.Duration {
+	return globalMaxCommandTimeout
+}
+
+// SetMaxOutputSize configures the size in bytes above which output is
+// truncated, replacing MaxOutputSize. Non-positive values restore the default.
+func SetMaxOutputSize(size int) {
+	if size <= 0 {
+		size = MaxOutputSize
+	}
+	globalMaxOutputSize = size
+}
+
+// SetMaxJSONArrayItems configures the number of items above which JSON arrays
+// are truncated, replacing MaxJSONArrayItems. Non-positive values restore the
+// default.
+func SetMaxJSONArrayItems(items int) {
+	if items <= 0 {
+		items = MaxJSONArrayItems
+	}
+	globalMaxJSONArrayItems = items
+}
+
+// ResponseLimits returns the configured maximum output size in bytes and
+// maximum number of JSON array items of a response.
+func ResponseLimits() (maxOutputSize int, maxJSONArrayItems int) {
+	return globalMaxOutputSize, globalMaxJSONArrayItems
+}
+
+// SetMaxCommandTimeout configures the upper bound for per-call timeouts requested
+// with timeout_seconds. Requests above it are clamped to this value.
+func SetMaxCommandTimeout(timeout time.Duration) {
+	globalMaxCommandTimeout = timeout
+}
+
+// requestTimeout returns the timeout for a request: the per-call timeout when one
+// is given, clamped to the configured maximum, then the per-command timeout of the
+// most specific matching command path, and the command timeout otherwise. Command
+// families with a longer default timeout get that instead of a shorter command timeout.
+func requestTimeout(req types.CommandRequest) time.Duration {
+	if req.TimeoutSeconds <= 0 {
+		if timeout, ok := commandTimeoutOverride(req.Command, req.Args); ok {
+			return timeout
+		}
+		if timeout, ok := familyTimeout(req.Command); ok && timeout > globalCommandTimeout {
+			return timeout
+		}
+		return globalCommandTimeout
+	}
+
+	timeout := time.Duration(req.TimeoutSeconds) * time.Second
+	if timeout > globalMaxCommandTimeout {
+		return globalMaxCommandTimeout
+	}
+	return timeout
+}
+
+// stallTimeout returns the first output timeout of a command that runs with
+// timeout. A timeout raised above the command timeout, by timeout_seconds, a
+// per-command timeout or a family default, marks a command known to be slow,
+// which often prints nothing until it finishes, so the check is skipped.
+func stallTimeout(timeout time.Duration) time.Duration {
+	if timeout > globalCommandTimeout {
+		return 0
+	}
+	return globalFirstOutputTimeout
+}
+
 // SetCustomValidator sets a custom validator instance to use for command validation.
 // This allows callers to provide their own validation rules and security policies.
 // If not set, a default validator with standard security rules will be used.
@@ -52,6 +183,21 @@ func GetTokenEncryptionEnabled() bool {
 	return globalTokenEncryptionEnabled
 }
 
+// SetStripFlags configures additional flag names that are always stripped before
+// executing a command. This lets operators introduce their own MCP-only flags
+// without them reaching the Fastly CLI. Stripped flags are reported in the
+// response metadata.
+func SetStripFlags(names []string) {
+	stripFlags := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimLeft(strings.TrimSpace(name), "-")
+		if name != "" {
+			stripFlags[name] = true
+		}
+	}
+	globalStripFlags = stripFlags
+}
+
 // GetValidator returns the current validator instance.
 // Returns the custom validator if set, otherwise a new default validator.
 func GetValidator() *validation.Validator {
@@ -61,6 +207,42 @@ func GetValidator() *validation.Validator {
 	return validation.NewValidator()
 }
 
+// validatorContextKey carries the validator of a request in its context.
+type validatorContextKey struct{}
+
+// WithValidator returns a context whose commands are validated by v instead of
+// the global validator, such as the policy an HTTP client selected.
+func WithValidator(ctx context.Context, v *validation.Validator) context.Context {
+	return context.WithValue(ctx, validatorContextKey{}, v)
+}
+
+// ContextValidator returns the validator set on ctx with WithValidator, or
+// GetValidator() if there is none.
+func ContextValidator(ctx context.Context) *validation.Validator {
+	if v, ok := ctx.Value(validatorContextKey{}).(*validation.Validator); ok && v != nil {
+		return v
+	}
+	return GetValidator()
+}
+
+// callerContextKey carries the identity of the client making a request.
+type callerContextKey struct{}
+
+// WithCaller returns a context whose commands run on behalf of the client
+// identified by caller, such as the verified bearer token or the session of an
+// HTTP client. State kept between calls, such as idempotent replays, is scoped
+// to it.
+func WithCaller(ctx context.Context, caller string) context.Context {
+	return context.WithValue(ctx, callerContextKey{}, caller)
+}
+
+// ContextCaller returns the caller set on ctx with WithCaller, or an empty
+// string for the single client of stdio and CLI mode.
+func ContextCaller(ctx context.Context) string {
+	caller, _ := ctx.Value(callerContextKey{}).(string)
+	return caller
+}
+
 // convertFlagsToInterface converts []types.Flag to []interface{} for cache compatibility.
 func convertFlagsToInterface(flags []types.Flag) []interface{} {
 	result := make([]interface{}, len(flags))
@@ -75,7 +257,7 @@ func convertFlagsToInterface(flags []types.Flag) []interface{} {
 //  1. Validates the command, arguments, and flags for security
 //  2. Checks if the operation is dangerous (delete, purge, etc.)
 //  3. Enforces --user-reviewed flag requirement for dangerous operations
-//  4. Executes the command with timeout protection (30 seconds)
+//  4. Executes the command with timeout protection (30 seconds by default)
 //  5. Processes output, including JSON parsing and truncation
 //  6. Returns structured response with appropriate error codes and guidance
 //
@@ -83,10 +265,14 @@ func convertFlagsToInterface(flags []types.Flag) []interface{} {
 // operations. It is stripped before passing to the actual Fastly CLI, serving as a
 // confirmation mechanism to prevent accidental destructive operations by AI agents.
 func ExecuteCommand(req types.CommandRequest) types.CommandResponse {
-	validator := globalValidator
-	if validator == nil {
-		validator = validation.NewValidator()
-	}
+	return ExecuteCommandContext(context.Background(), req)
+}
+
+// ExecuteCommandContext is like ExecuteCommand but stops the Fastly CLI process
+// when ctx is cancelled, for example when an MCP client cancels the request.
+// A cancelled command returns a response with the "cancelled" error code.
+func ExecuteCommandContext(ctx context.Context, req types.CommandRequest) types.CommandResponse {
+	validator := ContextValidator(ctx)
 
 	// Split command into parts if it contains spaces
 	// This supports both syntaxes:
@@ -100,38 +286,96 @@ func ExecuteCommand(req types.CommandRequest) types.CommandResponse {
 		req.Args = append(commandParts[1:], req.Args...)
 	}
 
+	// Forward renamed commands to their current names
+	var aliasNote string
+	req.Command, aliasNote = resolveCommandAlias(req.Command)
+
 	if err := validator.ValidateCommand(req.Command); err != nil {
 		return ValidationError(req.Command, err)
 	}
 
+	if isSelfManagementBlocked(req.Command) {
+		return SelfManagementError(req.Command, req.Args, req.Flags)
+	}
+
+	if isLoggingProviderBlocked(req.Command, req.Args) {
+		return LoggingProviderError(req.Command, req.Args, req.Flags)
+	}
+
 	if err := validator.ValidateArgs(req.Args); err != nil {
 		return ArgValidationError(req.Command, req.Args, err)
 	}
 
+	// Catch copy-paste errors in service IDs before the CLI rejects them
+	if globalNormalizeServiceIDs {
+		flags, err := normalizeServiceIDFlags(req.Flags)
+		if err != nil {
+			return ServiceIDValidationError(req.Command, req.Args, req.Flags, err)
+		}
+		req.Flags = flags
+	}
+
 	for _, flag := range req.Flags {
 		if err := validator.ValidateFlagName(flag.Name); err != nil {
 			return FlagNameValidationError(req.Command, req.Args, req.Flags, flag.Name, err)
 		}
+
+		// Multi-line values are validated line by line and passed via a temp file
+		if len(flag.Lines) > 0 {
+			if err := validateMultiLineFlag(flag); err != nil {
+				return FlagValueValidationError(req.Command, req.Args, req.Flags, flag.Name, err)
+			}
+			continue
+		}
+
 		if err := validator.ValidateFlagValue(flag.Value); err != nil {
 			return FlagValueValidationError(req.Command, req.Args, req.Flags, flag.Name, err)
 		}
 
 		// Additional path validation for file-related flags
-		if isPathFlag(flag.Name) && flag.Value != "" {
+		if (isPathFlag(flag.Name) || isOutputFileFlag(flag.Name)) && flag.Value != "" {
 			if err := validator.ValidatePath(flag.Value); err != nil {
 				return PathValidationError(req.Command, req.Args, req.Flags, flag.Name, err)
 			}
 		}
 	}
 
+	// Raw arguments are an opt-in escape hatch and are still validated token by token
+	if len(req.RawArgs) > 0 {
+		if !globalRawArgsEnabled {
+			return RawArgsDisabledError(req.Command, req.Args, req.Flags)
+		}
+		if err := validateRawArgs(validator, req.RawArgs); err != nil {
+			return RawArgValidationError(req.Command, req.Args, req.Flags, err)
+		}
+	}
+
+	// The CLI still resolves subcommands from raw arguments, so the deny,
+	// read-only and review checks see them as arguments too
+	gateArgs := rawGateArgs(req)
+
 	// Check if the command-args combination is denied
-	if validator.IsDenied(req.Command, req.Args) {
-		deniedCommand := validator.GetDeniedCommand(req.Command, req.Args)
-		return types.CommandResponse{
+	if validator.IsDenied(req.Command, gateArgs) {
+		deniedCommand := validator.GetDeniedCommand(req.Command, gateArgs)
+		response := types.CommandResponse{
 			Success:   false,
 			Error:     fmt.Sprintf("The '%s' command is not available", deniedCommand),
 			ErrorCode: "COMMAND_NOT_AVAILABLE",
 		}
+		if reason := validator.DeniedReason(req.Command, gateArgs); reason != "" {
+			response.Instructions = fmt.Sprintf("The '%s' command path is %s.", deniedCommand, reason)
+		}
+		return response
+	}
+
+	// Read-only mode cannot be bypassed by review or the danger policy
+	if isReadOnlyBlocked(req.Command, gateArgs) {
+		return ReadOnlyModeError(req.Command, gateArgs, req.Flags)
+	}
+
+	// A batch of surrogate keys is purged key by key
+	if len(req.Keys) > 0 {
+		return executePurgeBatch(ctx, validator, req, gateArgs)
 	}
 
 	cmdStr := req.Command
@@ -139,14 +383,21 @@ func ExecuteCommand(req types.CommandRequest) types.CommandResponse {
 		cmdStr += " " + strings.Join(req.Args, " ")
 	}
 
-	isDangerous, warningText := IsDangerousOperation(cmdStr)
+	isDangerous, warningText := reviewRequirement(req.Command, gateArgs)
 
-	// The --user-reviewed flag is MCP-specific and not passed to the Fastly CLI
+	// The --user-reviewed and --confirmation-token flags are MCP-specific and
+	// not passed to the Fastly CLI. Operator-configured strip flags are removed
+	// the same way.
 	hasUserReviewed := false
 	var filteredFlags []types.Flag
+	var strippedFlags []string
 	for _, flag := range req.Flags {
 		if flag.Name == "user-reviewed" {
 			hasUserReviewed = true
+		} else if flag.Name == confirmationTokenFlag {
+			continue
+		} else if globalStripFlags[flag.Name] {
+			strippedFlags = append(strippedFlags, flag.Name)
 		} else {
 			filteredFlags = append(filteredFlags, flag)
 		}
@@ -162,9 +413,74 @@ func ExecuteCommand(req types.CommandRequest) types.CommandResponse {
 			"Do NOT proceed without explicit human approval",
 		}
 		response.Metadata = GetOperationMetadata(req.Command, req.Args)
+		if globalExplainMode {
+			response.Explanation = explainCommand(req.Command, req.Args, filteredFlags)
+			if response.Explanation != nil {
+				response.Instructions += "\n\nThe 'explanation' field describes the command and each of its flags. Show it to the human user along with the command."
+			}
+		}
+		if globalPurgeAllPreflight && isPurgeAll(req.Command, filteredFlags) {
+			response.PurgeImpact = purgeAllImpact(ctx, filteredFlags)
+			if summary := purgeImpactSummary(response.PurgeImpact); summary != "" {
+				response.Instructions += "\n\n" + summary + " Show the 'purge_impact' field to the human user along with the command."
+			}
+		}
+		// Issue the token up front so an approved command needs one retry only
+		if requiresConfirmationToken(ctx, req.Command, gateArgs) {
+			response.ConfirmationToken = issueConfirmationToken(confirmationSubject(req, filteredFlags))
+			response.NextSteps[2] = fmt.Sprintf("Only after receiving human confirmation, retry with {\"name\":\"user-reviewed\"} and {\"name\":\"%s\",\"value\":\"%s\"} in the flags array", confirmationTokenFlag, response.ConfirmationToken)
+		}
 		return response
 	}
 
+	// The server-level profile cannot be overridden per call
+	if err := profileFlagConflict(filteredFlags); err != nil {
+		return ValidationError(req.Command, err)
+	}
+
+	// Safe mode requires a token issued for this exact command line. It is
+	// used up only right before the command runs, so a failure before that
+	// does not force another confirmation.
+	var confirmationToken, confirmedSubject string
+	reviewedFlags := filteredFlags
+	if requiresConfirmationToken(ctx, req.Command, gateArgs) {
+		confirmedSubject = confirmationSubject(req, filteredFlags)
+		confirmationToken = flagValue(req.Flags, confirmationTokenFlag)
+		if !checkConfirmationToken(confirmationToken, confirmedSubject) {
+			return ConfirmationTokenError(req.Command, req.Args, filteredFlags, issueConfirmationToken(confirmedSubject))
+		}
+	}
+
+	// Tag create operations with the operator-configured flags
+	filteredFlags, injectedFlags, skippedFlags := applyCreateFlags(ctx, req.Command, req.Args, filteredFlags)
+
+	// Fill in the operator-configured compute project defaults
+	filteredFlags, presetFlags, err := applyComputePreset(req.Command, req.Args, filteredFlags)
+	if err != nil {
+		return ComputeManifestError(req.Command, req.Args, filteredFlags, err)
+	}
+	injectedFlags = append(injectedFlags, presetFlags...)
+
+	// Drop --json for commands that have no JSON output mode, or pass it as --format json
+	filteredFlags, formatNote := resolveOutputFormat(req.Command, req.Args, filteredFlags)
+
+	// Pass stats time flags in the format the subcommand expects
+	filteredFlags = normalizeStatsTimeFlags(req.Command, req.Args, filteredFlags)
+
+	// Write multi-line flag values to temporary files passed by path
+	filteredFlags, cleanupFlagFiles, err := materializeMultiLineFlags(filteredFlags)
+	if err != nil {
+		return NewResponseBuilder().
+			WithCommand(req.Command, req.Args, nil).
+			WithError(fmt.Errorf("failed to prepare multi-line flag value: %w", err), "system_execution_error").
+			WithInstructions("A temporary file for a multi-line flag value could not be written.", []string{
+				"Check that the system temporary directory is writable",
+				"Retry the command",
+			}).
+			Build()
+	}
+	defer cleanupFlagFiles()
+
 	args := []string{req.Command}
 	args = append(args, req.Args...)
 
@@ -176,21 +492,60 @@ func ExecuteCommand(req types.CommandRequest) types.CommandResponse {
 		}
 	}
 
-	args = append(args, "--non-interactive")
+	if shouldInjectNonInteractive(req.Command, req.Args) {
+		args = append(args, "--non-interactive")
+	}
+	args = append(args, ProfileArgs()...)
+
+	// Raw arguments go after "--" so the CLI never parses them as flags
+	if len(req.RawArgs) > 0 {
+		args = append(args, "--")
+		args = append(args, req.RawArgs...)
+	}
 
 	fullCmdLine := "fastly " + strings.Join(args, " ")
 
+	// Return the recorded result for an identical create retry
+	idempotencySig, idempotent := idempotencySignature(req, fullCmdLine, ContextCaller(ctx))
+	if idempotent {
+		if previous, ok := lookupIdempotentResponse(idempotencySig); ok {
+			return replayIdempotentResponse(previous)
+		}
+	}
+
 	// Validate binary security before execution
 	if err := ValidateBinarySecurity(); err != nil {
 		return BinarySecurityValidationError(req.Command, req.Args, filteredFlags, err)
 	}
 
+	// Fail at once while authentication keeps failing
+	if open, failures, lastError := authBreakerOpen(filteredFlags); open {
+		return AuthBreakerError(req.Command, req.Args, filteredFlags, failures, lastError)
+	}
+
+	// Refuse to activate a version that fails validation, when enabled
+	preflightFailure, preflightNote := runActivationPreflight(ctx, req, filteredFlags)
+	if preflightFailure != nil {
+		return *preflightFailure
+	}
+
+	// Nothing else can stop the command from running now
+	if confirmedSubject != "" && !consumeConfirmationToken(confirmationToken, confirmedSubject) {
+		return ConfirmationTokenError(req.Command, req.Args, reviewedFlags, issueConfirmationToken(confirmedSubject))
+	}
+
 	// Execute the command using the shared runner
-	result := RunFastlyCommand(CommandRunConfig{
-		Command: "fastly",
-		Args:    args,
-		Timeout: CommandTimeout,
+	timeout := requestTimeout(req)
+	started := time.Now()
+	_, readOnly := GetOperationType(req.Command, req.Args)
+	result, attempts := runWithRetries(ctx, readOnly, CommandRunConfig{
+		Context:            ctx,
+		Command:            "fastly",
+		Args:               args,
+		Timeout:            timeout,
+		FirstOutputTimeout: stallTimeout(timeout),
 	})
+	elapsed := time.Since(started)
 
 	cleanedOutput := CleanANSI(result.Stdout)
 
@@ -199,22 +554,59 @@ func ExecuteCommand(req types.CommandRequest) types.CommandResponse {
 		cleanedOutput = SanitizeOutput(cleanedOutput, globalSanitizeOpts)
 	}
 
+	// Separate warning lines printed before JSON output so the JSON still parses
+	var outputWarnings []string
+	if result.Error == nil {
+		cleanedOutput, outputWarnings = splitLeadingWarnings(cleanedOutput)
+	}
+
 	// Strip heavy fields (e.g., versions array from service list) before
 	// caching or truncation so the output stays manageable.
 	cleanedOutput = StripHeavyFields(cleanedOutput, req.Command, req.Args)
 
+	// Mask configured JSON field paths, including in output that gets cached
+	cleanedOutput = MaskJSONOutput(cleanedOutput, globalSanitizeOpts)
+
+	// Normalize stats samples into typed metric points for easier aggregation.
+	cleanedOutput = NormalizeStatsOutput(cleanedOutput, req.Command, req.Args)
+
+	// Group POPs by region while keeping the raw list.
+	cleanedOutput = NormalizePopsOutput(cleanedOutput, req.Command, req.Args)
+
+	// Treat a single-object list result as a one-element array.
+	cleanedOutput = NormalizeListOutput(cleanedOutput, req.Command, req.Args)
+
+	// Keep the echoed command line short for commands with many flags
+	echoedCmdLine, elided := echoCommandLine(fullCmdLine, req.Command, req.Args, filteredFlags)
+
 	response := types.CommandResponse{
 		Command:     cmdStr,
-		CommandLine: fullCmdLine,
+		CommandLine: echoedCmdLine,
 		Metadata:    GetOperationMetadata(req.Command, req.Args),
 	}
+	response.Metadata.StrippedFlags = strippedFlags
+	response.Metadata.InjectedFlags = injectedFlags
+	response.Metadata.SkippedFlags = skippedFlags
+	response.Metadata.TimeoutSeconds = int(math.Ceil(timeout.Seconds()))
+	response.Metadata.Attempts = attempts
+	if elided {
+		response.Metadata.Flags = filteredFlags
+	}
 
 	if result.Error != nil {
 		response.Success = false
 
+		if result.Cancelled {
+			return CancelledError(req.Command, req.Args, filteredFlags)
+		}
+
+		if result.Stalled {
+			return StalledError(req.Command, req.Args, filteredFlags, globalFirstOutputTimeout)
+		}
+
 		if result.TimedOut {
 			// For timeout errors, include any partial output that was captured
-			timeoutResp := TimeoutError(req.Command, req.Args, filteredFlags)
+			timeoutResp := TimeoutErrorAfter(req.Command, req.Args, filteredFlags, timeout)
 			if result.Stdout != "" || result.Stderr != "" {
 				partialOutput := ""
 				if result.Stdout != "" {
@@ -231,7 +623,7 @@ func ExecuteCommand(req types.CommandRequest) types.CommandResponse {
 					partialOutput = SanitizeOutput(partialOutput, globalSanitizeOpts)
 				}
 				timeoutResp.Output = partialOutput
-				timeoutResp.Instructions = "The command timed out after 30 seconds. Partial output is included above."
+				timeoutResp.Instructions = fmt.Sprintf("The command timed out after %s. Partial output is included above. %s", timeout, timeoutLimitNote(timeout))
 			}
 			return timeoutResp
 		} else {
@@ -276,6 +668,7 @@ func ExecuteCommand(req types.CommandRequest) types.CommandResponse {
 			}
 
 			response.ErrorCode = DetectErrorCode(response.Error)
+			recordAuthResult(filteredFlags, response.ErrorCode, response.Error)
 
 			// Provide more specific instructions based on error type
 			switch response.ErrorCode {
@@ -319,6 +712,17 @@ func ExecuteCommand(req types.CommandRequest) types.CommandResponse {
 					"Check that the token has the necessary permissions for this operation",
 					"Note: FASTLY_API_TOKEN environment variable is not recommended for MCP clients",
 				}
+			case "insufficient_permission":
+				scope := requiredTokenScope(req.Command, req.Args, filteredFlags)
+				response.Instructions = fmt.Sprintf("The API token is valid but lacks the permission for this operation, which needs the '%s' scope.", scope)
+				response.NextSteps = []string{
+					fmt.Sprintf("Ask the human user to use a token with the '%s' scope", scope),
+					"Check the scopes of the current token at https://manage.fastly.com/account/personal/tokens",
+					"Create a token with the needed scope and set it up with 'fastly profile create'",
+					"Check that the user's role permits this operation on the service",
+				}
+			case "tls_error":
+				response.Instructions, response.NextSteps = tlsErrorGuidance(response.Error, time.Now())
 			case "not_found":
 				response.Instructions = "The requested resource was not found."
 				response.NextSteps = []string{
@@ -335,15 +739,25 @@ func ExecuteCommand(req types.CommandRequest) types.CommandResponse {
 					"Use the fastly_describe tool to see the correct command syntax",
 				}
 			}
+
+			// Point to the salient failure of a compute build instead of its whole log
+			applyComputeBuildFailure(&response, req, ContextCaller(ctx))
 		}
 	} else {
 		response.Success = true
-
-		// Check if output should be cached (>25KB by default, configurable)
-		if cache.ShouldCache(cleanedOutput) {
+		response.OutputWarnings = outputWarnings
+		recordAuthResult(filteredFlags, "", "")
+
+		// Check if output should be cached (>25KB by default, configurable).
+		// Large list results are always cached when they are summarized.
+		listSummary := summarizeList(cleanedOutput, req.Args)
+		if needsBinarySafeEncoding(req, result.Stdout) {
+			// Binary output is encoded as is rather than cached as text
+			applyBinarySafeOutput(&response, result.Stdout)
+		} else if listSummary != nil || cache.ShouldCacheCommand(cleanedOutput, req.Command, req.Args) {
 			// Store the output in cache
 			store := cache.GetStore()
-			resultID := store.Store(cleanedOutput, req.Command, req.Args, req.Flags)
+			resultID := store.StoreFor(ContextCaller(ctx), cleanedOutput, req.Command, req.Args, req.Flags)
 
 			// Create a cached response with preview
 			cachedResp := cache.CreateCachedResponse(resultID, cleanedOutput, req.Command, req.Args, convertFlagsToInterface(req.Flags))
@@ -360,8 +774,12 @@ func ExecuteCommand(req types.CommandRequest) types.CommandResponse {
 				TotalLines: cachedResp.Metadata.TotalLines,
 			}
 			response.Preview = cachedResp.Preview
+			response.Truncation = truncationFromCache(cachedResp.ResultID, cachedResp.Metadata)
 			response.Instructions = cachedResp.Instructions
 			response.NextSteps = cachedResp.NextSteps
+			if listSummary != nil {
+				applyListSummary(&response, listSummary)
+			}
 		} else {
 			// Normal processing for small outputs
 			trimmedOutput := strings.TrimSpace(cleanedOutput)
@@ -369,22 +787,33 @@ func ExecuteCommand(req types.CommandRequest) types.CommandResponse {
 				var jsonData interface{}
 				if err := json.Unmarshal([]byte(trimmedOutput), &jsonData); err == nil {
 					// Apply sanitization to JSON data if enabled
-					if globalSanitizeOpts.Enabled {
+					if globalSanitizeOpts.Enabled || len(globalSanitizeOpts.MaskPaths) > 0 {
 						jsonData = SanitizeJSON(jsonData, globalSanitizeOpts)
 					}
 					truncatedJSON, paginationInfo := TruncateJSONArray(jsonData)
 					response.OutputJSON = truncatedJSON
 					response.Pagination = paginationInfo
+					if _, isArray := jsonData.([]interface{}); isArray {
+						response.Truncation = truncationFromPagination(TruncationKindArray, paginationInfo)
+					} else {
+						response.Truncation = truncationFromPagination(TruncationKindObject, paginationInfo)
+					}
 
 					if paginationInfo != nil {
 						response.Instructions = "Command executed successfully. The JSON output has been truncated due to size."
 					} else {
 						response.Instructions = "Command executed successfully. The output has been parsed as JSON."
 					}
+					if items, ok := truncatedJSON.([]interface{}); ok {
+						if warning := ItemSoftLimitWarning(len(items)); warning != "" {
+							response.Instructions = warning + " " + response.Instructions
+						}
+					}
 				} else {
-					truncatedOutput, paginationInfo := TruncateOutput(cleanedOutput, MaxOutputSize)
+					truncatedOutput, paginationInfo := TruncateOutput(cleanedOutput, globalMaxOutputSize)
 					response.Output = truncatedOutput
 					response.Pagination = paginationInfo
+					response.Truncation = truncationFromPagination(TruncationKindText, paginationInfo)
 
 					if paginationInfo != nil {
 						response.Instructions = "Command executed successfully. The output has been truncated due to size."
@@ -393,9 +822,10 @@ func ExecuteCommand(req types.CommandRequest) types.CommandResponse {
 					}
 				}
 			} else {
-				truncatedOutput, paginationInfo := TruncateOutput(cleanedOutput, MaxOutputSize)
+				truncatedOutput, paginationInfo := TruncateOutput(cleanedOutput, globalMaxOutputSize)
 				response.Output = truncatedOutput
 				response.Pagination = paginationInfo
+				response.Truncation = truncationFromPagination(TruncationKindText, paginationInfo)
 
 				if paginationInfo != nil {
 					response.Instructions = "Command executed successfully. The output has been truncated due to size."
@@ -462,6 +892,37 @@ func ExecuteCommand(req types.CommandRequest) types.CommandResponse {
 				"Tip: Use current_time tool to record when this purge was initiated",
 			}, response.NextSteps...)
 		}
+
+		if response.ListSummary != nil {
+			response.NextSteps = listSummaryNextSteps(response.ResultID)
+		}
+
+		applyOutputFile(&response, filteredFlags)
+	}
+
+	// Name the created resource so agents can chain operations on it
+	if response.Success {
+		response.CreatedResource = extractCreatedResource(req.Command, req.Args, filteredFlags, cleanedOutput)
+	}
+
+	if aliasNote != "" {
+		response.Instructions = strings.TrimSpace(response.Instructions + " " + aliasNote)
+	}
+	if formatNote != "" {
+		response.Instructions = strings.TrimSpace(response.Instructions + " " + formatNote)
+	}
+	if preflightNote != "" {
+		response.Instructions = strings.TrimSpace(response.Instructions + " " + preflightNote)
+	}
+	if slowNote := slowCommandNote(elapsed, timeout); slowNote != "" && response.Success {
+		response.Instructions = strings.TrimSpace(response.Instructions + " " + slowNote)
+	}
+
+	if response.Success && idempotent {
+		recordIdempotentResponse(idempotencySig, req.Command, response)
+	}
+	if response.Success {
+		forgetIdempotentResponses(req)
 	}
 
 	return response
@@ -1,10 +1,17 @@
 package fastly
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 
+	"github.com/fastly/mcp/internal/cache"
 	"github.com/fastly/mcp/internal/types"
+	"github.com/fastly/mcp/internal/validation"
 )
 
 func TestExecuteCommandWithUserReview(t *testing.T) {
@@ -51,19 +58,6 @@ func TestExecuteCommandWithUserReview(t *testing.T) {
 			expectError:   "",
 			expectCode:    "",
 		},
-		{
-			name: "create command without user-reviewed",
-			req: types.CommandRequest{
-				Command: "backend",
-				Args:    []string{"create"},
-				Flags: []types.Flag{
-					{Name: "name", Value: "test-backend"},
-				},
-			},
-			expectSuccess: false,
-			expectError:   "this is a dangerous operation that requires explicit human user confirmation",
-			expectCode:    "user_confirmation_required",
-		},
 		{
 			name: "purge command without user-reviewed",
 			req: types.CommandRequest{
@@ -353,6 +347,28 @@ func TestDeniedCommands(t *testing.T) {
 	}
 }
 
+func TestDeniedCommandSource(t *testing.T) {
+	originalValidator := globalValidator
+	defer func() {
+		globalValidator = originalValidator
+	}()
+
+	globalValidator = validation.NewValidator()
+	result := ExecuteCommand(types.CommandRequest{Command: "stats", Args: []string{"realtime"}})
+	if result.ErrorCode != "COMMAND_NOT_AVAILABLE" || !strings.Contains(result.Instructions, "denied by the default denylist") {
+		t.Errorf("Expected the default denylist to be named, got %+v", result)
+	}
+
+	denied := map[string]bool{"service delete": true}
+	validator := validation.NewValidatorWithCommandsAndDenied(validation.DefaultAllowedCommands(), denied)
+	validator.SetCommandSources(nil, validation.RecordSources(nil, denied, validation.SourceInline))
+	SetCustomValidator(validator)
+	result = ExecuteCommand(types.CommandRequest{Command: "service", Args: []string{"delete"}})
+	if result.ErrorCode != "COMMAND_NOT_AVAILABLE" || !strings.Contains(result.Instructions, "denied by the inline denylist") {
+		t.Errorf("Expected the inline denylist to be named, got %+v", result)
+	}
+}
+
 func TestImprovedErrorHandling(t *testing.T) {
 	tests := []struct {
 		name                   string
@@ -476,3 +492,200 @@ func TestTimeoutErrorHandling(t *testing.T) {
 		t.Errorf("Expected next steps in timeout response")
 	}
 }
+
+// installMockFastly writes a fake fastly binary that runs the given shell body
+// and points FASTLY_CLI_PATH at it. The arguments of every invocation are
+// appended to the returned calls file, one line per call.
+func installMockFastly(t *testing.T, body string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	callsFile := filepath.Join(dir, "calls")
+	script := "#!/bin/sh\necho \"$@\" >> \"" + callsFile + "\"\n" + body + "\n"
+	mockPath := filepath.Join(dir, "fastly")
+	if err := os.WriteFile(mockPath, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("FASTLY_CLI_PATH", mockPath)
+
+	return callsFile
+}
+
+func TestConfiguredStripFlags(t *testing.T) {
+	installMockFastly(t, `echo '[]'`)
+
+	SetStripFlags([]string{"trace-id", "--agent-note"})
+	defer SetStripFlags(nil)
+
+	result := ExecuteCommand(types.CommandRequest{
+		Command: "service",
+		Args:    []string{"list"},
+		Flags: []types.Flag{
+			{Name: "json"},
+			{Name: "trace-id", Value: "abc123"},
+			{Name: "agent-note", Value: "checking"},
+		},
+	})
+
+	if !result.Success {
+		t.Fatalf("Expected success, got error: %s", result.Error)
+	}
+
+	for _, stripped := range []string{"--trace-id", "abc123", "--agent-note"} {
+		if strings.Contains(result.CommandLine, stripped) {
+			t.Errorf("Command line should not contain %q: %s", stripped, result.CommandLine)
+		}
+	}
+	if !strings.Contains(result.CommandLine, "--json") {
+		t.Errorf("Command line should contain --json: %s", result.CommandLine)
+	}
+
+	if result.Metadata == nil {
+		t.Fatal("Expected metadata in response")
+	}
+	got := strings.Join(result.Metadata.StrippedFlags, ",")
+	if got != "trace-id,agent-note" {
+		t.Errorf("Expected stripped flags trace-id,agent-note, got %q", got)
+	}
+}
+
+func TestStripFlagsNotConfigured(t *testing.T) {
+	callsFile := installMockFastly(t, `echo '[]'`)
+
+	result := ExecuteCommand(types.CommandRequest{
+		Command: "service",
+		Args:    []string{"list"},
+		Flags: []types.Flag{
+			{Name: "json"},
+			{Name: "trace-id", Value: "abc123"},
+		},
+	})
+
+	if !result.Success {
+		t.Fatalf("Expected success, got error: %s", result.Error)
+	}
+
+	calls, err := os.ReadFile(callsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(calls), "--trace-id abc123") {
+		t.Errorf("Expected unconfigured flag to reach the CLI, got: %s", calls)
+	}
+	if len(result.Metadata.StrippedFlags) != 0 {
+		t.Errorf("Expected no stripped flags, got %v", result.Metadata.StrippedFlags)
+	}
+}
+
+func TestCommandCachePolicyOverridesThreshold(t *testing.T) {
+	installMockFastly(t, `i=1; while [ $i -le 50 ]; do echo "line $i"; i=$((i+1)); done`)
+
+	cache.SetOutputCacheThreshold(100)
+	defer cache.SetOutputCacheThreshold(cache.DefaultOutputCacheThreshold)
+	cache.SetCommandCachePolicies(map[string]cache.CachePolicy{
+		"service describe": cache.CachePolicyNever,
+		"backend list":     cache.CachePolicyAlways,
+	})
+	defer cache.SetCommandCachePolicies(nil)
+
+	// Output is above the threshold, but the command is configured to never cache
+	result := ExecuteCommand(types.CommandRequest{Command: "service", Args: []string{"describe"}})
+	if !result.Success || result.Cached {
+		t.Fatalf("Expected inline success for never-cache command, got %+v", result)
+	}
+	if !strings.Contains(result.Output, "line 50") {
+		t.Errorf("Expected full output inline, got %q", result.Output)
+	}
+
+	// Output is below the raised threshold, but the command is configured to always cache
+	cache.SetOutputCacheThreshold(cache.DefaultOutputCacheThreshold)
+	result = ExecuteCommand(types.CommandRequest{Command: "backend", Args: []string{"list"}})
+	if !result.Success || !result.Cached || result.ResultID == "" {
+		t.Fatalf("Expected cached success for always-cache command, got %+v", result)
+	}
+}
+
+func TestPerCallTimeoutHonored(t *testing.T) {
+	installMockFastly(t, `echo "deploying"; exec sleep 5`)
+
+	start := time.Now()
+	result := ExecuteCommand(types.CommandRequest{
+		Command:        "service",
+		Args:           []string{"list"},
+		TimeoutSeconds: 1,
+	})
+
+	if elapsed := time.Since(start); elapsed > 4*time.Second {
+		t.Errorf("Expected the per-call timeout to stop the command early, took %v", elapsed)
+	}
+	if result.Success || result.ErrorCode != "timeout" {
+		t.Fatalf("Expected timeout error, got %+v", result)
+	}
+	if !strings.Contains(result.Error, "after 1 seconds") {
+		t.Errorf("Expected error to mention the per-call timeout, got %q", result.Error)
+	}
+}
+
+func TestRequestTimeoutClamped(t *testing.T) {
+	SetMaxCommandTimeout(2 * time.Minute)
+	defer SetMaxCommandTimeout(MaxCommandTimeout)
+
+	tests := []struct {
+		name     string
+		seconds  int
+		expected time.Duration
+	}{
+		{"family default clamped when unset", 0, 2 * time.Minute},
+		{"family default clamped when negative", -5, 2 * time.Minute},
+		{"per-call within maximum", 90, 90 * time.Second},
+		{"clamped to maximum", 3600, 2 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := requestTimeout(types.CommandRequest{Command: "compute", Args: []string{"deploy"}, TimeoutSeconds: tt.seconds})
+			if got != tt.expected {
+				t.Errorf("requestTimeout() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExecuteRepairsInvalidUTF8(t *testing.T) {
+	installMockFastly(t, `printf 'Service: edge \377\376app\n'`)
+
+	result := ExecuteCommand(types.CommandRequest{Command: "service", Args: []string{"list"}})
+	if !result.Success {
+		t.Fatalf("Expected success, got %+v", result)
+	}
+	if !utf8.ValidString(result.Output) || !strings.Contains(result.Output, "edge \uFFFDapp") {
+		t.Errorf("Expected invalid bytes to be replaced, got %q", result.Output)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Expected the response to encode, got %v", err)
+	}
+	if !utf8.Valid(data) {
+		t.Error("Expected the encoded response to be valid UTF-8")
+	}
+}
+
+func TestConfiguredCommandTimeout(t *testing.T) {
+	installMockFastly(t, `echo "partial"; exec sleep 5`)
+
+	SetCommandTimeout(time.Second)
+	defer SetCommandTimeout(CommandTimeout)
+
+	if got := requestTimeout(types.CommandRequest{Command: "stats", Args: []string{"historical"}}); got != time.Second {
+		t.Errorf("requestTimeout() = %v, want the configured timeout", got)
+	}
+
+	result := ExecuteCommand(types.CommandRequest{Command: "stats", Args: []string{"historical"}})
+	if result.ErrorCode != "timeout" {
+		t.Fatalf("Expected a timeout, got %+v", result)
+	}
+	if !strings.Contains(result.Instructions, "timed out after 1s") || !strings.Contains(result.Instructions, "command timeout is 1s") {
+		t.Errorf("Expected the instructions to name the configured timeout, got %q", result.Instructions)
+	}
+}
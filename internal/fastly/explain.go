@@ -0,0 +1,66 @@
+package fastly
+
+import "github.com/fastly/mcp/internal/types"
+
+// globalExplainMode controls whether dangerous operations awaiting review are
+// annotated with the meaning of each flag. It can be configured via SetExplainMode().
+var globalExplainMode = false
+
+// maxExplainDepth bounds how many arguments are tried as part of the command
+// path when looking up help, like the denylist depth.
+const maxExplainDepth = 3
+
+// undocumentedFlag describes a flag that the command's help does not list.
+const undocumentedFlag = "Not documented in the command's help"
+
+// SetExplainMode enables or disables explain mode. When enabled, the response
+// asking for review of a dangerous operation explains each of its flags, so
+// the reviewing human knows what they approve.
+func SetExplainMode(enabled bool) {
+	globalExplainMode = enabled
+}
+
+// explainCommand annotates a command line with the descriptions of the command
+// and its flags from the parsed help. Positional arguments are dropped from
+// the end of the path until the help lookup succeeds. It returns nil when no
+// help is available.
+func explainCommand(command string, args []string, flags []types.Flag) *types.CommandExplanation {
+	pathArgs := args
+	if len(pathArgs) > maxExplainDepth {
+		pathArgs = pathArgs[:maxExplainDepth]
+	}
+
+	var info types.HelpInfo
+	found := false
+	for n := len(pathArgs); n >= 0 && !found; n-- {
+		info = DescribeCommand(append([]string{command}, pathArgs[:n]...))
+		found = info.Description != "Invalid operation" && info.Description != "Command not available"
+	}
+	if !found {
+		return nil
+	}
+
+	descriptions := make(map[string]string)
+	for _, list := range [][]types.FlagInfo{info.RequiredFlags, info.Flags} {
+		for _, flag := range list {
+			descriptions[flag.Name] = flag.Description
+		}
+	}
+
+	explanation := &types.CommandExplanation{
+		CommandLine: BuildCommandLine(command, args, flags),
+		Description: info.Description,
+	}
+	for _, flag := range flags {
+		description, ok := descriptions[flag.Name]
+		if !ok || description == "" {
+			description = undocumentedFlag
+		}
+		explanation.Flags = append(explanation.Flags, types.FlagExplanation{
+			Name:        flag.Name,
+			Value:       flag.Value,
+			Description: description,
+		})
+	}
+	return explanation
+}
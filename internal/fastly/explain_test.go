@@ -0,0 +1,94 @@
+package fastly
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestExplainModeDangerousCommand(t *testing.T) {
+	mockHelp := map[string]string{
+		"backend delete --help": `USAGE
+  fastly backend delete --name=NAME --version=VERSION [<flags>]
+
+Delete a backend on a Fastly service version
+
+REQUIRED FLAGS
+  -n, --name=NAME          Backend name
+      --version=VERSION    'latest', 'active', or the number of a specific Fastly service version
+
+OPTIONAL FLAGS
+      --autoclone          If the selected service version is not editable, clone it and use the clone.
+  -s, --service-id=SERVICE-ID  Service ID (falls back to FASTLY_SERVICE_ID, then fastly.toml)`,
+	}
+	originalExecutor := testCommandExecutor
+	testCommandExecutor = func(ctx context.Context, name string, args ...string) (string, error) {
+		return mockHelp[strings.Join(args, " ")], nil
+	}
+	defer func() {
+		testCommandExecutor = originalExecutor
+	}()
+
+	SetExplainMode(true)
+	defer SetExplainMode(false)
+
+	result := ExecuteCommand(types.CommandRequest{
+		Command: "backend",
+		Args:    []string{"delete"},
+		Flags: []types.Flag{
+			{Name: "service-id", Value: "SVC123"},
+			{Name: "version", Value: "latest"},
+			{Name: "name", Value: "origin-eu"},
+			{Name: "autoclone"},
+		},
+	})
+
+	if result.ErrorCode != "user_confirmation_required" {
+		t.Fatalf("Expected a confirmation request, got %+v", result)
+	}
+	explanation := result.Explanation
+	if explanation == nil {
+		t.Fatal("Expected an explanation in explain mode")
+	}
+	if !strings.Contains(explanation.Description, "Delete a backend on a Fastly service version") {
+		t.Errorf("Expected the command description, got %q", explanation.Description)
+	}
+
+	want := map[string]string{
+		"service-id": "Service ID (falls back to FASTLY_SERVICE_ID, then fastly.toml)",
+		"version":    "'latest', 'active', or the number of a specific Fastly service version",
+		"name":       "Backend name",
+		"autoclone":  "If the selected service version is not editable, clone it and use the clone.",
+	}
+	if len(explanation.Flags) != len(want) {
+		t.Fatalf("Expected %d flag explanations, got %+v", len(want), explanation.Flags)
+	}
+	for _, flag := range explanation.Flags {
+		if flag.Description != want[flag.Name] {
+			t.Errorf("Expected --%s to be explained as %q, got %q", flag.Name, want[flag.Name], flag.Description)
+		}
+	}
+}
+
+func TestExplainModeDisabled(t *testing.T) {
+	invoked := false
+	originalExecutor := testCommandExecutor
+	testCommandExecutor = func(ctx context.Context, name string, args ...string) (string, error) {
+		invoked = true
+		return "", nil
+	}
+	defer func() {
+		testCommandExecutor = originalExecutor
+	}()
+
+	result := ExecuteCommand(types.CommandRequest{
+		Command: "backend",
+		Args:    []string{"delete"},
+		Flags:   []types.Flag{{Name: "name", Value: "origin-eu"}},
+	})
+	if result.Explanation != nil || invoked {
+		t.Errorf("Expected no explanation without explain mode, got %+v", result.Explanation)
+	}
+}
@@ -17,31 +17,53 @@ var CustomHelpText = map[string]string{
 	"vcl": "To update the main VCL code of a service, use fastly_execute vcl custom update --name=main --autoclone --service-id=service_id --version=latest --content=file_to_upload.vcl",
 }
 
+// DescribeOptions controls optional parts of describe output.
+type DescribeOptions struct {
+	// IncludeHiddenFlags surfaces flags normally filtered by ShouldIncludeFlag,
+	// except security-sensitive ones, in the HiddenFlags field.
+	IncludeHiddenFlags bool
+	// Recursive also describes the subcommands, assembling them into SubcommandTree.
+	Recursive bool
+	// Depth is how many levels of subcommands a recursive describe walks.
+	// Zero means DefaultDescribeDepth; larger values are capped at MaxDescribeDepth.
+	Depth int
+
+	// validator filters the subcommands; DescribeCommandContext sets it from
+	// the context and nil means GetValidator().
+	validator *validation.Validator
+}
+
 // DescribeCommand returns detailed help information for a Fastly command.
 // It validates the command is allowed, executes 'fastly [command] --help',
 // and parses the output into structured help information suitable for AI consumption.
 // The function detects invalid commands and provides helpful error messages.
 func DescribeCommand(cmdPath []string) types.HelpInfo {
-	// Check if the command is allowed
+	return DescribeCommandWithOptions(cmdPath, DescribeOptions{})
+}
+
+// DescribeCommandWithOptions is like DescribeCommand but accepts DescribeOptions.
+func DescribeCommandWithOptions(cmdPath []string, opts DescribeOptions) types.HelpInfo {
+	return DescribeCommandContext(context.Background(), cmdPath, opts)
+}
+
+// DescribeCommandContext is like DescribeCommandWithOptions but describes the
+// command as allowed by the validator of ctx, see WithValidator.
+func DescribeCommandContext(ctx context.Context, cmdPath []string, opts DescribeOptions) types.HelpInfo {
+	validator := ContextValidator(ctx)
+	opts.validator = validator
+
+	// Check if the command is allowed, and not denied when denied commands are hidden
 	if len(cmdPath) > 0 {
-		validator := globalValidator
-		if validator == nil {
-			validator = validation.NewValidator()
-		}
 		if err := validator.ValidateCommand(cmdPath[0]); err != nil {
-			return types.HelpInfo{
-				Command:      strings.Join(cmdPath, " "),
-				Description:  "Command not available",
-				Instructions: fmt.Sprintf("The command '%s' is not available.", cmdPath[0]),
-				NextSteps: []string{
-					"Use the fastly_list_commands tool to see available commands",
-				},
-			}
+			return commandNotAvailable(cmdPath, cmdPath[0])
+		}
+		if isHiddenFromDescribe(validator, cmdPath) {
+			return commandNotAvailable(cmdPath, strings.Join(cmdPath, " "))
 		}
 	}
 
 	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), CommandTimeout)
+	ctx, cancel := context.WithTimeout(ctx, globalCommandTimeout)
 	defer cancel()
 
 	// Use test executor if available, otherwise use default
@@ -87,7 +109,24 @@ func DescribeCommand(cmdPath []string) types.HelpInfo {
 		return invalidHelp
 	}
 
-	return parseHelpOutput(strings.Join(cmdPath, " "), output)
+	info := parseHelpOutputWithOptions(strings.Join(cmdPath, " "), output, opts)
+	if opts.Recursive && len(info.Subcommands) > 0 {
+		info.SubcommandTree, info.SubcommandTreeTruncated = describeSubcommandTree(ctx, cmdPath, info.Subcommands, opts.Depth)
+	}
+	return info
+}
+
+// commandNotAvailable describes a command that cannot be used, without saying
+// whether it is unknown or blocked.
+func commandNotAvailable(cmdPath []string, name string) types.HelpInfo {
+	return types.HelpInfo{
+		Command:      strings.Join(cmdPath, " "),
+		Description:  "Command not available",
+		Instructions: fmt.Sprintf("The command '%s' is not available.", name),
+		NextSteps: []string{
+			"Use the fastly_list_commands tool to see available commands",
+		},
+	}
 }
 
 // parseHelpOutput parses Fastly CLI help text into a structured format.
@@ -101,6 +140,11 @@ func DescribeCommand(cmdPath []string) types.HelpInfo {
 // The parser handles various help text formats and adds MCP-specific
 // instructions and warnings for dangerous operations.
 func parseHelpOutput(command string, helpText string) types.HelpInfo {
+	return parseHelpOutputWithOptions(command, helpText, DescribeOptions{})
+}
+
+// parseHelpOutputWithOptions is like parseHelpOutput but accepts DescribeOptions.
+func parseHelpOutputWithOptions(command string, helpText string, opts DescribeOptions) types.HelpInfo {
 	// Clean the help text first
 	helpText = CleanANSI(helpText)
 
@@ -112,6 +156,9 @@ func parseHelpOutput(command string, helpText string) types.HelpInfo {
 	var currentSection string
 	var usageLines []string
 	var inRequiredFlags bool
+	// lastFlags points at the list that received the most recent flag, so that
+	// description continuation lines of a filtered flag are not misattributed
+	var lastFlags *[]types.FlagInfo
 
 	for _, line := range lines {
 		trimmed := strings.TrimSpace(line)
@@ -151,21 +198,24 @@ func parseHelpOutput(command string, helpText string) types.HelpInfo {
 		case "FLAGS":
 			if strings.HasPrefix(trimmed, "-") || strings.HasPrefix(trimmed, "--") {
 				flag := parseFlagLine(line)
+				lastFlags = nil
 				if flag.Name != "" && ShouldIncludeFlag(flag.Name) { // Filter out global/internal flags
 					if inRequiredFlags {
-						info.RequiredFlags = append(info.RequiredFlags, flag)
+						lastFlags = &info.RequiredFlags
 					} else {
-						info.Flags = append(info.Flags, flag)
+						lastFlags = &info.Flags
 					}
+				} else if flag.Name != "" && opts.IncludeHiddenFlags && !IsSensitiveFlag(flag.Name) {
+					lastFlags = &info.HiddenFlags
+				}
+				if lastFlags != nil {
+					*lastFlags = append(*lastFlags, flag)
 				}
 			} else if len(trimmed) > 0 && len(line) > 20 && strings.Count(line[:20], " ") >= 15 {
 				// This is a continuation of the previous flag's description (lots of leading spaces)
-				if inRequiredFlags && len(info.RequiredFlags) > 0 {
-					lastIdx := len(info.RequiredFlags) - 1
-					info.RequiredFlags[lastIdx].Description += " " + trimmed
-				} else if !inRequiredFlags && len(info.Flags) > 0 {
-					lastIdx := len(info.Flags) - 1
-					info.Flags[lastIdx].Description += " " + trimmed
+				if lastFlags != nil && len(*lastFlags) > 0 {
+					lastIdx := len(*lastFlags) - 1
+					(*lastFlags)[lastIdx].Description += " " + trimmed
 				}
 			}
 		case "COMMANDS":
@@ -186,9 +236,9 @@ func parseHelpOutput(command string, helpText string) types.HelpInfo {
 					parts := strings.Fields(trimmed)
 					if len(parts) >= 2 {
 						// Check if this subcommand is denied
-						validator := globalValidator
+						validator := opts.validator
 						if validator == nil {
-							validator = validation.NewValidator()
+							validator = GetValidator()
 						}
 
 						// Build args to check against denylist
@@ -227,21 +277,37 @@ func parseHelpOutput(command string, helpText string) types.HelpInfo {
 	// Post-process to improve clarity for AI
 	info = improveUsageClarity(info)
 
-	// Add warning to description if dangerous
-	if isDangerous, warningText := IsDangerousOperation(info.Command); isDangerous {
+	// Add a warning to the description if the executor requires review
+	info.Severity, _ = IsDangerousOperation(info.Command)
+	if needsReview, warningText := pathReviewRequirement(info.Command, len(info.Subcommands) == 0); needsReview {
 		if info.Description != "" {
-			info.Description = fmt.Sprintf("⚠️ %s - %s", info.Description, warningText)
+			info.Description = fmt.Sprintf("⚠️ %s - %s (%s)", info.Description, warningText, info.Severity)
 		} else {
-			info.Description = fmt.Sprintf("⚠️ %s", warningText)
+			info.Description = fmt.Sprintf("⚠️ %s (%s)", warningText, info.Severity)
 		}
 	}
 
 	// Add AI-friendly instructions
 	info = addMCPInstructions(info)
 
+	// Mark commands rejected by read-only mode as unavailable
+	if parts := strings.Fields(info.Command); len(parts) > 0 && len(info.Subcommands) == 0 && isReadOnlyBlocked(parts[0], parts[1:]) {
+		info.Description = "⛔ Unavailable in read-only mode - " + info.Description
+		info.Instructions = "This command may modify resources and the server runs in read-only mode, so fastly_execute rejects it even with --user-reviewed."
+		info.NextSteps = []string{
+			"Use list, describe or get commands to inspect resources instead",
+			"Ask the human user to run this command directly in a terminal if the change is needed",
+		}
+	}
+
 	// Add MCP metadata
 	info = addMCPMetadata(info)
 
+	if len(info.HiddenFlags) > 0 {
+		info.NextSteps = append(info.NextSteps,
+			"⚠️ 'hidden_flags' lists flags that are normally hidden because they affect output format or CLI behavior in ways the MCP wrapper already manages. Use them only when specifically needed.")
+	}
+
 	return info
 }
 
@@ -352,8 +418,9 @@ func improveUsageClarity(info types.HelpInfo) types.HelpInfo {
 // For dangerous operations, it emphasizes the need for human confirmation
 // and includes the --user-reviewed flag requirement.
 func addMCPInstructions(info types.HelpInfo) types.HelpInfo {
-	// Check if this is a dangerous operation
-	isDangerous, warningText := IsDangerousOperation(info.Command)
+	// Check if this is a dangerous operation that requires review, with the
+	// rules of the executor, including safe mode and the danger policy
+	isDangerous, warningText := pathReviewRequirement(info.Command, len(info.Subcommands) == 0)
 
 	// Add instructions based on what the command structure looks like
 	if len(info.Subcommands) > 0 {
@@ -472,7 +539,7 @@ func addMCPInstructions(info types.HelpInfo) types.HelpInfo {
 	return info
 }
 
-// addMCPMetadata adds category and resource type metadata to help information.
+// addMCPMetadata adds category, resource type and required token scope metadata to help information.
 // This categorization helps AI agents understand the context and impact of commands:
 //   - configuration: Service configuration commands
 //   - edge-logic: ACL and dictionary management
@@ -496,5 +563,11 @@ func addMCPMetadata(info types.HelpInfo) types.HelpInfo {
 	info.Category = cmdMetadata.Category
 	info.ResourceType = cmdMetadata.ResourceType
 
+	// Tell callers which token permission to have before trying the command
+	if len(cmdParts) > 0 {
+		info.RequiredScope = requiredTokenScope(baseCommand, cmdParts[1:], nil)
+		info.ScopeNote = scopeNotes[baseCommand]
+	}
+
 	return info
 }
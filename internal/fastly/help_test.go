@@ -1,6 +1,7 @@
 package fastly
 
 import (
+	"context"
 	"strings"
 	"testing"
 
@@ -126,18 +127,21 @@ func TestAddMCPMetadata(t *testing.T) {
 		command          string
 		expectedCategory string
 		expectedResource string
+		expectedScope    string
 	}{
 		{
 			name:             "service command",
 			command:          "service list",
 			expectedCategory: "configuration",
 			expectedResource: "service",
+			expectedScope:    "global:read",
 		},
 		{
 			name:             "auth command",
 			command:          "auth",
 			expectedCategory: "security",
 			expectedResource: "auth",
+			expectedScope:    "global",
 		},
 		{
 			name:             "version command",
@@ -150,6 +154,7 @@ func TestAddMCPMetadata(t *testing.T) {
 			command:          "unknown-command",
 			expectedCategory: "general",
 			expectedResource: "unknown",
+			expectedScope:    "global",
 		},
 	}
 
@@ -164,6 +169,9 @@ func TestAddMCPMetadata(t *testing.T) {
 			if result.ResourceType != tt.expectedResource {
 				t.Errorf("Expected resource type %s, got %s", tt.expectedResource, result.ResourceType)
 			}
+			if result.RequiredScope != tt.expectedScope {
+				t.Errorf("Expected required scope %q, got %q", tt.expectedScope, result.RequiredScope)
+			}
 		})
 	}
 }
@@ -219,12 +227,12 @@ func TestAddMCPInstructions(t *testing.T) {
 		{
 			name: "command with required flags",
 			info: types.HelpInfo{
-				Command: "service create",
+				Command: "service delete",
 				RequiredFlags: []types.FlagInfo{
-					{Name: "name", Description: "Service name"},
+					{Name: "service-id", Description: "Service ID"},
 				},
 			},
-			checkDanger: true, // create is dangerous
+			checkDanger: true, // delete is destructive
 			checkSteps:  6,    // includes 3 danger warnings + 3 regular steps
 		},
 		{
@@ -255,3 +263,116 @@ func TestAddMCPInstructions(t *testing.T) {
 		})
 	}
 }
+
+func TestDescribeIncludeHiddenFlags(t *testing.T) {
+	mockHelp := `USAGE
+  fastly service list [<flags>]
+
+List Fastly services
+
+OPTIONAL FLAGS
+      --direction=ascend  Direction in which to sort results
+  -j, --json              Render output as JSON
+
+GLOBAL FLAGS
+      --accept-defaults   Accept default options for all interactive prompts
+                          apart from Yes/No confirmations
+  -t, --token=TOKEN       Fastly API token
+  -v, --verbose           Verbose logging
+`
+
+	originalExecutor := testCommandExecutor
+	testCommandExecutor = func(ctx context.Context, name string, args ...string) (string, error) {
+		return mockHelp, nil
+	}
+	defer func() {
+		testCommandExecutor = originalExecutor
+	}()
+
+	flagNames := func(flags []types.FlagInfo) []string {
+		names := []string{}
+		for _, flag := range flags {
+			names = append(names, flag.Name)
+		}
+		return names
+	}
+
+	t.Run("hidden flags omitted by default", func(t *testing.T) {
+		info := DescribeCommand([]string{"service", "list"})
+
+		if len(info.HiddenFlags) != 0 {
+			t.Errorf("Expected no hidden flags, got %v", flagNames(info.HiddenFlags))
+		}
+		for _, name := range flagNames(info.Flags) {
+			if name == "verbose" || name == "accept-defaults" {
+				t.Errorf("Flag %q should be hidden", name)
+			}
+		}
+	})
+
+	t.Run("hidden flags included on request", func(t *testing.T) {
+		info := DescribeCommandWithOptions([]string{"service", "list"}, DescribeOptions{IncludeHiddenFlags: true})
+
+		hidden := strings.Join(flagNames(info.HiddenFlags), ",")
+		if hidden != "accept-defaults,verbose" {
+			t.Errorf("Expected hidden flags accept-defaults,verbose, got %q", hidden)
+		}
+		if strings.Contains(strings.Join(flagNames(info.Flags), ","), "verbose") {
+			t.Error("Hidden flags should not be mixed into the regular flags")
+		}
+		if !strings.Contains(info.HiddenFlags[0].Description, "apart from Yes/No confirmations") {
+			t.Errorf("Expected continuation line on hidden flag, got %q", info.HiddenFlags[0].Description)
+		}
+
+		warned := false
+		for _, step := range info.NextSteps {
+			if strings.Contains(step, "hidden_flags") {
+				warned = true
+			}
+		}
+		if !warned {
+			t.Error("Expected a warning about hidden flags in next steps")
+		}
+	})
+
+	t.Run("sensitive flags never surfaced", func(t *testing.T) {
+		info := DescribeCommandWithOptions([]string{"service", "list"}, DescribeOptions{IncludeHiddenFlags: true})
+
+		for _, flags := range [][]types.FlagInfo{info.Flags, info.HiddenFlags} {
+			for _, name := range flagNames(flags) {
+				if name == "token" {
+					t.Error("The token flag must never be surfaced")
+				}
+			}
+		}
+	})
+}
+
+func TestDescribeIncludesRequiredScope(t *testing.T) {
+	mockHelp := `USAGE
+  fastly purge [<flags>]
+
+Invalidate objects in the Fastly cache
+
+OPTIONAL FLAGS
+      --all                Purge everything from a service
+      --key=KEY            Purge a service of a key-tagged set of objects
+  -s, --service-id=SERVICE-ID  Service ID
+`
+
+	originalExecutor := testCommandExecutor
+	testCommandExecutor = func(ctx context.Context, name string, args ...string) (string, error) {
+		return mockHelp, nil
+	}
+	defer func() {
+		testCommandExecutor = originalExecutor
+	}()
+
+	info := DescribeCommand([]string{"purge"})
+	if info.RequiredScope != "purge_select" {
+		t.Errorf("Expected required scope purge_select, got %q", info.RequiredScope)
+	}
+	if !strings.Contains(info.ScopeNote, "purge_all") {
+		t.Errorf("Expected a scope note about purge_all, got %q", info.ScopeNote)
+	}
+}
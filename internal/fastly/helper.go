@@ -3,8 +3,6 @@ package fastly
 import (
 	"encoding/json"
 	"fmt"
-	"os"
-	"os/exec"
 	"regexp"
 	"strings"
 
@@ -15,29 +13,18 @@ import (
 var ansiRegex = regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]`)
 
 // CheckSetup verifies that the Fastly CLI is properly installed and configured.
-// It performs two critical checks:
+// It performs three critical checks:
 //  1. Verifies the 'fastly' executable exists in the system PATH
-//  2. Tests authentication by attempting to list services (requires valid credentials)
+//  2. Verifies the CLI is at least MinimumCLIVersion, returning a *CLIVersionError otherwise
+//  3. Tests authentication by attempting to list services (requires valid credentials)
 //
 // The function returns specific error messages to help diagnose setup issues:
 //   - CLI not found errors direct users to installation instructions
 //   - Authentication errors indicate missing or invalid API tokens
 //   - Timeout errors suggest connectivity or CLI responsiveness issues
 func CheckSetup() error {
-	// Check if FASTLY_CLI_PATH is set
-	customPath := os.Getenv("FASTLY_CLI_PATH")
-	if customPath != "" {
-		// Validate the custom path exists and is executable
-		if _, err := os.Stat(customPath); err != nil {
-			return fmt.Errorf("FASTLY_CLI_PATH is set to '%s' but file does not exist: %w", customPath, err)
-		}
-	} else {
-		// Try to find fastly in PATH
-		if _, err := exec.LookPath("fastly"); err != nil {
-			currentPath := os.Getenv("PATH")
-			pathDirs := strings.Split(currentPath, string(os.PathListSeparator))
-			return fmt.Errorf("fastly CLI not found in PATH. Searched directories: %v. Please install it from https://developer.fastly.com/reference/cli/ or set FASTLY_CLI_PATH environment variable to the binary location", pathDirs)
-		}
+	if err := locateFastlyCLI(); err != nil {
+		return err
 	}
 
 	// Validate binary security
@@ -45,12 +32,24 @@ func CheckSetup() error {
 		return fmt.Errorf("fastly CLI binary security check failed: %w", err)
 	}
 
+	// Reject CLI releases older than the minimum supported version
+	versionResult := RunFastlyCommand(CommandRunConfig{
+		Command: "fastly",
+		Args:    []string{"version"},
+		Timeout: SetupCheckTimeout,
+	})
+	if versionResult.Error == nil {
+		if err := checkCLIVersion(versionResult.Stdout); err != nil {
+			return err
+		}
+	}
+
 	// Try to run 'fastly whoami' to check both CLI availability and authentication
 	// This command requires authentication to work and is faster than listing services
 	result := RunFastlyCommand(CommandRunConfig{
 		Command: "fastly",
-		Args:    []string{"whoami"},
-		Timeout: CommandTimeout,
+		Args:    append([]string{"whoami"}, ProfileArgs()...),
+		Timeout: SetupCheckTimeout,
 	})
 
 	if result.Error != nil {
@@ -75,11 +74,13 @@ func CheckSetup() error {
 	}
 
 	// If we got here, the command succeeded and user is authenticated
+	ResetAuthBreaker()
 	return nil
 }
 
 // CleanANSI removes ANSI escape sequences and transforms CLI output for AI consumption.
 // It performs several transformations:
+//   - Replaces invalid UTF-8 byte sequences with U+FFFD so responses always encode
 //   - Strips ANSI color codes and formatting sequences
 //   - Converts Unicode escapes to their actual characters
 //   - Replaces terminal-specific formatting with AI-friendly alternatives
@@ -88,6 +89,7 @@ func CheckSetup() error {
 //
 // This ensures that CLI output is clean and actionable for AI agents.
 func CleanANSI(text string) string {
+	text = strings.ToValidUTF8(text, "\uFFFD")
 	text = ansiRegex.ReplaceAllString(text, "")
 
 	text = strings.ReplaceAll(text, "\u003c", "<")
@@ -180,29 +182,30 @@ func TruncateOutput(output string, maxSize int) (string, *types.PaginationInfo)
 }
 
 // TruncateJSONArray truncates JSON data to manage response sizes.
-// For arrays, it limits the number of items to MaxJSONArrayItems (100).
-// For other JSON structures, it checks the serialized size and returns
-// an error object if the data exceeds MaxOutputSize.
+// For arrays, it limits the number of items to the configured maximum
+// (MaxJSONArrayItems, 100, by default). For other JSON structures, it checks
+// the serialized size and returns an error object if the data exceeds the
+// configured maximum output size (MaxOutputSize by default).
 //
 // This prevents overwhelming AI agents with excessive data while providing
 // clear feedback about truncation and how to access additional items.
 func TruncateJSONArray(data interface{}) (interface{}, *types.PaginationInfo) {
 	switch v := data.(type) {
 	case []interface{}:
-		if len(v) <= MaxJSONArrayItems {
+		if len(v) <= globalMaxJSONArrayItems {
 			return data, nil
 		}
 
-		truncated := v[:MaxJSONArrayItems]
+		truncated := v[:globalMaxJSONArrayItems]
 		return truncated, &types.PaginationInfo{
 			TotalSize:      len(v),
-			ReturnedSize:   MaxJSONArrayItems,
+			ReturnedSize:   globalMaxJSONArrayItems,
 			Truncated:      true,
-			TruncationNote: fmt.Sprintf("Array truncated. Showing first %d of %d items. Use pagination flags (--page, --per-page) to access more items.", MaxJSONArrayItems, len(v)),
+			TruncationNote: fmt.Sprintf("Array truncated. Showing first %d of %d items. Use pagination flags (--page, --per-page) to access more items.", globalMaxJSONArrayItems, len(v)),
 		}
 	default:
 		jsonBytes, err := json.Marshal(data)
-		if err != nil || len(jsonBytes) <= MaxOutputSize {
+		if err != nil || len(jsonBytes) <= globalMaxOutputSize {
 			return data, nil
 		}
 
@@ -257,6 +260,19 @@ func ShouldIncludeFlag(flagName string) bool {
 	return !excludedFlags[flagName]
 }
 
+// IsSensitiveFlag reports whether a flag is security sensitive. These flags
+// control authentication and are never surfaced, even when hidden flags are
+// requested in describe output.
+func IsSensitiveFlag(flagName string) bool {
+	sensitiveFlags := map[string]bool{
+		"token":      true,
+		"enable-sso": true,
+		"profile":    true,
+	}
+
+	return sensitiveFlags[flagName]
+}
+
 // BuildCommandLine constructs the full command line string from command, args, and flags.
 // It builds a properly formatted command line that can be shown to users for transparency
 // and confirmation. The resulting string represents the exact command that will be executed,
@@ -277,42 +293,49 @@ func BuildCommandLine(command string, args []string, flags []types.Flag) string
 	return strings.Join(parts, " ")
 }
 
-// IsDangerousOperation checks if a command is potentially dangerous or destructive.
-// It examines the command string for keywords that indicate operations that:
-//   - Delete or remove resources
-//   - Modify existing configurations
-//   - Manage authentication or secrets
-//   - Could have significant side effects
-//
-// Returns true with a warning message if the operation is dangerous.
-// This is used to enforce the --user-reviewed flag requirement for safety.
-func IsDangerousOperation(command string) (bool, string) {
-	dangerousPatterns := map[string]string{
-		"delete":    "This operation permanently deletes resources",
-		"purge":     "This operation invalidates cached content",
-		"update":    "This operation modifies existing resources",
-		"create":    "This operation creates new resources",
-		"upload":    "This operation uploads files to the system",
-		"write":     "This operation writes data",
-		"remove":    "This operation removes resources",
-		"destroy":   "This operation destroys resources",
-		"terminate": "This operation terminates resources",
-		"install":   "This operation installs software",
-		"uninstall": "This operation uninstalls software",
-		"auth":      "This operation manages authentication",
-		"token":     "This operation manages API tokens",
-		"secret":    "This operation manages secrets",
-		"key":       "This operation manages keys",
-		"cert":      "This operation manages certificates",
-		"tls":       "This operation manages TLS/SSL settings",
-	}
+// dangerousPatterns maps keywords in command paths to the severity of matching
+// operations and a warning explaining it. Destructive patterns come first, so a
+// command matching several patterns gets the highest severity.
+var dangerousPatterns = []struct {
+	pattern  string
+	severity string
+	warning  string
+}{
+	{"delete", SeverityDestructive, "This operation permanently deletes resources"},
+	{"purge", SeverityDestructive, "This operation invalidates cached content"},
+	{"remove", SeverityDestructive, "This operation removes resources"},
+	{"destroy", SeverityDestructive, "This operation destroys resources"},
+	{"terminate", SeverityDestructive, "This operation terminates resources"},
+	{"uninstall", SeverityDestructive, "This operation uninstalls software"},
+	{"auth", SeverityDestructive, "This operation manages authentication"},
+	{"token", SeverityDestructive, "This operation manages API tokens"},
+	{"secret", SeverityDestructive, "This operation manages secrets"},
+	{"key", SeverityDestructive, "This operation manages keys"},
+	{"cert", SeverityDestructive, "This operation manages certificates"},
+	{"tls", SeverityDestructive, "This operation manages TLS/SSL settings"},
+	{"update", SeverityMutating, "This operation modifies existing resources"},
+	{"create", SeverityMutating, "This operation creates new resources"},
+	{"upload", SeverityMutating, "This operation uploads files to the system"},
+	{"write", SeverityMutating, "This operation writes data"},
+	{"install", SeverityMutating, "This operation installs software"},
+}
 
+// IsDangerousOperation classifies how risky a command is by the keywords in it:
+//   - SeverityDestructive for operations that delete or remove resources, or
+//     manage authentication, secrets, keys and certificates, whose mistakes are
+//     hard to undo
+//   - SeverityMutating for operations that create, modify or upload resources
+//   - SeverityInfo for everything else
+//
+// It returns the severity and, unless the severity is SeverityInfo, a warning
+// message. requiresReview decides which severities need the --user-reviewed flag.
+func IsDangerousOperation(command string) (severity string, warning string) {
 	lowerCommand := strings.ToLower(command)
-	for pattern, warning := range dangerousPatterns {
-		if strings.Contains(lowerCommand, pattern) {
-			return true, warning
+	for _, p := range dangerousPatterns {
+		if strings.Contains(lowerCommand, p.pattern) {
+			return p.severity, p.warning
 		}
 	}
 
-	return false, ""
+	return SeverityInfo, ""
 }
@@ -31,6 +31,11 @@ func TestCleanANSI(t *testing.T) {
 			input:    "Run 'fastly service list' to see services",
 			expected: "Use the fastly_execute tool with service list' to see services",
 		},
+		{
+			name:     "repair invalid UTF-8",
+			input:    "name: caf\xc3\xa9 \xff\xfe\x1b[1mbold\x1b[0m",
+			expected: "name: caf\u00e9 \uFFFDbold",
+		},
 	}
 
 	for _, tt := range tests {
@@ -101,40 +106,44 @@ func TestShouldIncludeFlag(t *testing.T) {
 
 func TestIsDangerousOperation(t *testing.T) {
 	tests := []struct {
-		name          string
-		command       string
-		expectDanger  bool
-		expectWarning string
+		name           string
+		command        string
+		expectSeverity string
 	}{
 		{
-			name:         "delete is dangerous",
-			command:      "service delete",
-			expectDanger: true,
+			name:           "delete is destructive",
+			command:        "service delete",
+			expectSeverity: SeverityDestructive,
+		},
+		{
+			name:           "create is mutating",
+			command:        "backend create",
+			expectSeverity: SeverityMutating,
 		},
 		{
-			name:         "create is dangerous",
-			command:      "backend create",
-			expectDanger: true,
+			name:           "highest severity wins",
+			command:        "secret-store create",
+			expectSeverity: SeverityDestructive,
 		},
 		{
-			name:         "list is safe",
-			command:      "service list",
-			expectDanger: false,
+			name:           "list is safe",
+			command:        "service list",
+			expectSeverity: SeverityInfo,
 		},
 		{
-			name:         "describe is safe",
-			command:      "service describe",
-			expectDanger: false,
+			name:           "describe is safe",
+			command:        "service describe",
+			expectSeverity: SeverityInfo,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			isDanger, warning := IsDangerousOperation(tt.command)
-			if isDanger != tt.expectDanger {
-				t.Errorf("Expected dangerous=%v, got %v", tt.expectDanger, isDanger)
+			severity, warning := IsDangerousOperation(tt.command)
+			if severity != tt.expectSeverity {
+				t.Errorf("Expected severity=%s, got %s", tt.expectSeverity, severity)
 			}
-			if isDanger && warning == "" {
+			if severity != SeverityInfo && warning == "" {
 				t.Error("Expected warning text for dangerous operation")
 			}
 		})
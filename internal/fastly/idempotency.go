@@ -0,0 +1,107 @@
+package fastly
+
+import (
+	"sync"
+	"time"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+// IdempotencyWindow is how long a successful create is remembered. An identical
+// retry within this window returns the recorded response instead of creating
+// the resource again.
+const IdempotencyWindow = 5 * time.Minute
+
+// idempotencyEntry is a recorded successful create response.
+type idempotencyEntry struct {
+	response   types.CommandResponse
+	command    string // Command whose delete invalidates the entry, e.g. backend
+	recordedAt time.Time
+}
+
+// idempotencyStore holds recently succeeded create commands keyed by signature.
+var idempotencyStore = struct {
+	mu      sync.Mutex
+	entries map[string]idempotencyEntry
+}{entries: make(map[string]idempotencyEntry)}
+
+// idempotencySignature returns the replay signature for a request, or false if
+// the request is not eligible. Only create operations with an explicit
+// idempotency key qualify, so an intentionally repeated create always runs.
+// The signature includes the caller, so one HTTP client never receives the
+// replay of another's create.
+func idempotencySignature(req types.CommandRequest, commandLine, caller string) (string, bool) {
+	operationType, _ := GetOperationType(req.Command, req.Args)
+	if operationType != "create" || req.IdempotencyKey == "" {
+		return "", false
+	}
+
+	return caller + "\x00" + req.IdempotencyKey + "\x00" + commandLine, true
+}
+
+// forgetIdempotentResponses drops the recorded creates of a command after one
+// of its resources was deleted, so that creating it again is not answered with
+// the replay of a resource that no longer exists.
+func forgetIdempotentResponses(req types.CommandRequest) {
+	if operationType, _ := GetOperationType(req.Command, req.Args); operationType != "delete" {
+		return
+	}
+
+	idempotencyStore.mu.Lock()
+	defer idempotencyStore.mu.Unlock()
+	for key, entry := range idempotencyStore.entries {
+		if entry.command == req.Command {
+			delete(idempotencyStore.entries, key)
+		}
+	}
+}
+
+// lookupIdempotentResponse returns the recorded response for a signature if it
+// was recorded within IdempotencyWindow. Expired entries are dropped.
+func lookupIdempotentResponse(signature string) (types.CommandResponse, bool) {
+	idempotencyStore.mu.Lock()
+	defer idempotencyStore.mu.Unlock()
+
+	entry, ok := idempotencyStore.entries[signature]
+	if !ok {
+		return types.CommandResponse{}, false
+	}
+	if time.Since(entry.recordedAt) > IdempotencyWindow {
+		delete(idempotencyStore.entries, signature)
+		return types.CommandResponse{}, false
+	}
+
+	return entry.response, true
+}
+
+// recordIdempotentResponse remembers a successful create response of command
+// and prunes entries that have fallen out of the window.
+func recordIdempotentResponse(signature, command string, response types.CommandResponse) {
+	idempotencyStore.mu.Lock()
+	defer idempotencyStore.mu.Unlock()
+
+	now := time.Now()
+	for key, entry := range idempotencyStore.entries {
+		if now.Sub(entry.recordedAt) > IdempotencyWindow {
+			delete(idempotencyStore.entries, key)
+		}
+	}
+
+	idempotencyStore.entries[signature] = idempotencyEntry{
+		response:   response,
+		command:    command,
+		recordedAt: now,
+	}
+}
+
+// replayIdempotentResponse marks a recorded response as a replay so the agent
+// knows the resource was not created a second time.
+func replayIdempotentResponse(response types.CommandResponse) types.CommandResponse {
+	if response.Metadata != nil {
+		metadata := *response.Metadata
+		metadata.IdempotentReplay = true
+		response.Metadata = &metadata
+	}
+	response.Instructions = "This identical create command already succeeded recently, so it was not executed again. The original result is returned. " + response.Instructions
+	return response
+}
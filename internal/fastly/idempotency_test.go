@@ -0,0 +1,169 @@
+package fastly
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestIdempotentCreateRetry(t *testing.T) {
+	callsFile := installMockFastly(t, `echo "Created backend origin"`)
+
+	req := types.CommandRequest{
+		Command: "backend",
+		Args:    []string{"create"},
+		Flags: []types.Flag{
+			{Name: "user-reviewed"},
+			{Name: "service-id", Value: "svc-idempotency-test"},
+			{Name: "version", Value: "1"},
+			{Name: "name", Value: "origin"},
+		},
+		IdempotencyKey: "create-origin",
+	}
+
+	first := ExecuteCommand(req)
+	if !first.Success {
+		t.Fatalf("Expected first create to succeed, got: %s", first.Error)
+	}
+	if first.Metadata.IdempotentReplay {
+		t.Error("First execution should not be marked as a replay")
+	}
+
+	retry := ExecuteCommand(req)
+	if !retry.Success {
+		t.Fatalf("Expected retry to succeed, got: %s", retry.Error)
+	}
+	if retry.Metadata == nil || !retry.Metadata.IdempotentReplay {
+		t.Error("Expected retry to be marked as an idempotent replay")
+	}
+	if retry.Output != first.Output {
+		t.Errorf("Expected replayed output %q, got %q", first.Output, retry.Output)
+	}
+	if first.Metadata.IdempotentReplay {
+		t.Error("Replaying must not modify the recorded response")
+	}
+
+	calls, err := os.ReadFile(callsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count := strings.Count(string(calls), "backend create"); count != 1 {
+		t.Errorf("Expected the CLI to be invoked once, got %d invocations", count)
+	}
+}
+
+func TestIdempotencyEligibility(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      types.CommandRequest
+		eligible bool
+	}{
+		{
+			name: "create with name but no key",
+			req: types.CommandRequest{
+				Command: "backend", Args: []string{"create"},
+				Flags: []types.Flag{{Name: "name", Value: "origin"}},
+			},
+			eligible: false,
+		},
+		{
+			name: "create with idempotency key",
+			req: types.CommandRequest{
+				Command: "service", Args: []string{"create"}, IdempotencyKey: "retry-1",
+			},
+			eligible: true,
+		},
+		{
+			name: "anonymous create",
+			req: types.CommandRequest{
+				Command: "service", Args: []string{"create"},
+			},
+			eligible: false,
+		},
+		{
+			name: "update with idempotency key",
+			req: types.CommandRequest{
+				Command: "backend", Args: []string{"update"}, IdempotencyKey: "retry-1",
+				Flags: []types.Flag{{Name: "name", Value: "origin"}},
+			},
+			eligible: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, eligible := idempotencySignature(tt.req, "fastly ...", ""); eligible != tt.eligible {
+				t.Errorf("Expected eligible=%v, got %v", tt.eligible, eligible)
+			}
+		})
+	}
+}
+
+func TestIdempotencyKeyDistinguishesRetries(t *testing.T) {
+	callsFile := installMockFastly(t, `echo "Created service"`)
+
+	req := types.CommandRequest{
+		Command:        "service",
+		Args:           []string{"create"},
+		Flags:          []types.Flag{{Name: "user-reviewed"}, {Name: "type", Value: "vcl"}},
+		IdempotencyKey: "attempt-a",
+	}
+
+	ExecuteCommand(req)
+	req.IdempotencyKey = "attempt-b"
+	second := ExecuteCommand(req)
+
+	if second.Metadata.IdempotentReplay {
+		t.Error("A different idempotency key should not replay the earlier result")
+	}
+
+	calls, err := os.ReadFile(callsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count := strings.Count(string(calls), "service create"); count != 2 {
+		t.Errorf("Expected two CLI invocations, got %d", count)
+	}
+}
+
+func TestIdempotencyReplayScope(t *testing.T) {
+	callsFile := installMockFastly(t, `echo "Created backend origin"`)
+
+	create := types.CommandRequest{
+		Command:        "backend",
+		Args:           []string{"create"},
+		Flags:          []types.Flag{{Name: "user-reviewed"}, {Name: "service-id", Value: "svc-scope-test"}, {Name: "version", Value: "1"}, {Name: "name", Value: "origin"}},
+		IdempotencyKey: "scope-test",
+	}
+	alice := WithCaller(context.Background(), "client:alice")
+	bob := WithCaller(context.Background(), "client:bob")
+
+	ExecuteCommandContext(alice, create)
+	if replay := ExecuteCommandContext(alice, create); !replay.Metadata.IdempotentReplay {
+		t.Error("Expected the retry of the same caller to be replayed")
+	}
+	if other := ExecuteCommandContext(bob, create); other.Metadata.IdempotentReplay {
+		t.Error("Expected another caller not to receive the replay")
+	}
+
+	// Deleting the resource forgets the recorded create
+	ExecuteCommandContext(alice, types.CommandRequest{
+		Command: "backend",
+		Args:    []string{"delete"},
+		Flags:   []types.Flag{{Name: "user-reviewed"}, {Name: "service-id", Value: "svc-scope-test"}, {Name: "version", Value: "1"}, {Name: "name", Value: "origin"}},
+	})
+	if recreate := ExecuteCommandContext(alice, create); recreate.Metadata.IdempotentReplay {
+		t.Error("Expected a create after a delete to run again")
+	}
+
+	calls, err := os.ReadFile(callsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count := strings.Count(string(calls), "backend create"); count != 3 {
+		t.Errorf("Expected three CLI invocations of backend create, got %d", count)
+	}
+}
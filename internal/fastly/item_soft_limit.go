@@ -0,0 +1,28 @@
+package fastly
+
+import "fmt"
+
+// globalItemSoftLimit is the number of items a response may return before it
+// carries a warning; 0 disables the warning. It can be configured via
+// SetItemSoftLimit().
+var globalItemSoftLimit = 0
+
+// SetItemSoftLimit sets the number of returned items above which responses
+// warn the agent. Use 0 to disable the warning.
+func SetItemSoftLimit(limit int) {
+	if limit < 0 {
+		limit = 0
+	}
+	globalItemSoftLimit = limit
+}
+
+// ItemSoftLimitWarning returns a warning for a response returning count items,
+// or an empty string while count is within the soft limit. Even a truncated
+// array can overflow the context of a small model, so the warning points the
+// agent to narrower commands and the cache query tools.
+func ItemSoftLimitWarning(count int) string {
+	if globalItemSoftLimit == 0 || count <= globalItemSoftLimit {
+		return ""
+	}
+	return fmt.Sprintf("⚠️ LARGE RESULT: %d items returned, above the soft limit of %d. Reading all of them may overflow your context: narrow the command with filter or --per-page flags, and for cached results use fastly_result_query or fastly_result_summary instead of reading everything.", count, globalItemSoftLimit)
+}
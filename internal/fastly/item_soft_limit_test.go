@@ -0,0 +1,45 @@
+package fastly
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestItemSoftLimitWarning(t *testing.T) {
+	if warning := ItemSoftLimitWarning(500); warning != "" {
+		t.Errorf("Expected no warning while the soft limit is disabled, got %q", warning)
+	}
+
+	SetItemSoftLimit(10)
+	defer SetItemSoftLimit(0)
+
+	if warning := ItemSoftLimitWarning(10); warning != "" {
+		t.Errorf("Expected no warning at the soft limit, got %q", warning)
+	}
+	if warning := ItemSoftLimitWarning(11); !strings.Contains(warning, "11 items") || !strings.Contains(warning, "fastly_result_query") {
+		t.Errorf("Expected a warning above the soft limit, got %q", warning)
+	}
+}
+
+func TestExecuteWarnsAboveItemSoftLimit(t *testing.T) {
+	installMockFastly(t, `echo '[{"id":"svc1"},{"id":"svc2"},{"id":"svc3"}]'`)
+
+	SetItemSoftLimit(2)
+	defer SetItemSoftLimit(0)
+
+	result := ExecuteCommand(types.CommandRequest{Command: "service", Args: []string{"list"}})
+	if !result.Success {
+		t.Fatalf("Expected success, got %+v", result)
+	}
+	if !strings.HasPrefix(result.Instructions, "⚠️ LARGE RESULT: 3 items returned") {
+		t.Errorf("Expected the instructions to start with the warning, got %q", result.Instructions)
+	}
+
+	SetItemSoftLimit(3)
+	result = ExecuteCommand(types.CommandRequest{Command: "service", Args: []string{"list"}})
+	if strings.Contains(result.Instructions, "LARGE RESULT") {
+		t.Errorf("Expected no warning within the soft limit, got %q", result.Instructions)
+	}
+}
@@ -0,0 +1,40 @@
+package fastly
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// isListCommand reports whether a command lists resources, e.g. "service list"
+// or "service-version list".
+func isListCommand(args []string) bool {
+	return len(args) > 0 && args[len(args)-1] == "list"
+}
+
+// NormalizeListOutput wraps the JSON output of a list command in a one-element
+// array when the CLI returns a single object instead of an array, which it does
+// for some commands when only one item exists. Consistent arrays keep
+// fastly_result_query and array truncation working the same regardless of the
+// number of items. Any other output is returned unchanged.
+func NormalizeListOutput(output string, command string, args []string) string {
+	if !isListCommand(args) {
+		return output
+	}
+
+	trimmedBytes := []byte(strings.TrimSpace(output))
+	if len(trimmedBytes) == 0 || trimmedBytes[0] != '{' {
+		return output
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(trimmedBytes, &obj); err != nil || len(obj) == 0 {
+		return output
+	}
+
+	result, err := json.Marshal([]interface{}{obj})
+	if err != nil {
+		return output
+	}
+
+	return string(result)
+}
@@ -0,0 +1,100 @@
+package fastly
+
+import (
+	"testing"
+
+	"github.com/fastly/mcp/internal/cache"
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestNormalizeListOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		command  string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "single object from list command",
+			output:   `{"Name":"origin","Address":"example.com"}` + "\n",
+			command:  "backend",
+			args:     []string{"list"},
+			expected: `[{"Address":"example.com","Name":"origin"}]`,
+		},
+		{
+			name:     "array unchanged",
+			output:   `[{"Name":"origin"}]`,
+			command:  "backend",
+			args:     []string{"list"},
+			expected: `[{"Name":"origin"}]`,
+		},
+		{
+			name:     "empty object unchanged",
+			output:   `{}`,
+			command:  "backend",
+			args:     []string{"list"},
+			expected: `{}`,
+		},
+		{
+			name:     "non-list command unchanged",
+			output:   `{"Name":"origin"}`,
+			command:  "backend",
+			args:     []string{"describe"},
+			expected: `{"Name":"origin"}`,
+		},
+		{
+			name:     "text unchanged",
+			output:   "NAME  ADDRESS\norigin  example.com\n",
+			command:  "backend",
+			args:     []string{"list"},
+			expected: "NAME  ADDRESS\norigin  example.com\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeListOutput(tt.output, tt.command, tt.args); got != tt.expected {
+				t.Errorf("NormalizeListOutput() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExecuteCommandSingleObjectListIsArray(t *testing.T) {
+	installMockFastly(t, `echo '{"Name":"origin","Address":"example.com"}'`)
+
+	result := ExecuteCommand(types.CommandRequest{
+		Command: "backend",
+		Args:    []string{"list"},
+		Flags:   []types.Flag{{Name: "service-id", Value: "abc123"}, {Name: "version", Value: "1"}, {Name: "json"}},
+	})
+	if !result.Success {
+		t.Fatalf("Expected success, got %+v", result)
+	}
+
+	items, ok := result.OutputJSON.([]interface{})
+	if !ok || len(items) != 1 {
+		t.Fatalf("Expected a one-element array, got %#v", result.OutputJSON)
+	}
+
+	// Cached results are queried as arrays too
+	cache.SetOutputCacheThreshold(10)
+	defer cache.SetOutputCacheThreshold(cache.DefaultOutputCacheThreshold)
+
+	result = ExecuteCommand(types.CommandRequest{
+		Command: "backend",
+		Args:    []string{"list"},
+		Flags:   []types.Flag{{Name: "service-id", Value: "abc123"}, {Name: "version", Value: "1"}, {Name: "json"}},
+	})
+	if !result.Cached {
+		t.Fatalf("Expected cached result, got %+v", result)
+	}
+	matches, err := cache.GetStore().Query(result.ResultID, "Name=origin")
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if arr, ok := matches.([]interface{}); !ok || len(arr) != 1 {
+		t.Errorf("Expected one match from query, got %#v", matches)
+	}
+}
@@ -0,0 +1,95 @@
+package fastly
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+// ListSummaryItems is the number of leading items included in a list summary.
+const ListSummaryItems = 5
+
+// globalListSummaryThreshold is the number of items above which the JSON array
+// output of a list command is summarized instead of returned; 0 disables
+// summaries. It can be configured via SetListSummaryThreshold().
+var globalListSummaryThreshold = 0
+
+// SetListSummaryThreshold sets the number of items above which list results
+// are returned as a summary with a result_id. Use 0 to disable summaries.
+func SetListSummaryThreshold(items int) {
+	if items < 0 {
+		items = 0
+	}
+	globalListSummaryThreshold = items
+}
+
+// summarizeList returns a compact summary of a list command's JSON array
+// output, or nil when summaries are disabled, the command is not a list
+// command, or the array is within the threshold.
+func summarizeList(output string, args []string) *types.ListSummary {
+	if globalListSummaryThreshold == 0 || !isListCommand(args) {
+		return nil
+	}
+
+	trimmed := strings.TrimSpace(output)
+	if !strings.HasPrefix(trimmed, "[") {
+		return nil
+	}
+	var items []interface{}
+	if err := json.Unmarshal([]byte(trimmed), &items); err != nil || len(items) <= globalListSummaryThreshold {
+		return nil
+	}
+
+	fieldSet := map[string]bool{}
+	for _, item := range items {
+		if obj, ok := item.(map[string]interface{}); ok {
+			for field := range obj {
+				fieldSet[field] = true
+			}
+		}
+	}
+	fields := make([]string, 0, len(fieldSet))
+	for field := range fieldSet {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	first := items
+	if len(first) > ListSummaryItems {
+		first = first[:ListSummaryItems]
+	}
+	if globalSanitizeOpts.Enabled || len(globalSanitizeOpts.MaskPaths) > 0 {
+		if sanitized, ok := SanitizeJSON(first, globalSanitizeOpts).([]interface{}); ok {
+			first = sanitized
+		}
+	}
+
+	return &types.ListSummary{
+		TotalItems: len(items),
+		Fields:     fields,
+		FirstItems: first,
+	}
+}
+
+// applyListSummary replaces the preview of a cached list result with its
+// summary.
+func applyListSummary(response *types.CommandResponse, summary *types.ListSummary) {
+	response.ListSummary = summary
+	response.Preview = nil
+	if response.Truncation != nil {
+		response.Truncation.Returned = len(summary.FirstItems)
+	}
+	response.Instructions = fmt.Sprintf("Command executed successfully. The list has %d items, more than the summary threshold of %d, so only a summary is returned: the item count, the fields of the items and the first %d items. The full list is cached under result_id %s.", summary.TotalItems, globalListSummaryThreshold, len(summary.FirstItems), response.ResultID)
+}
+
+// listSummaryNextSteps points the agent to the cached full list.
+func listSummaryNextSteps(resultID string) []string {
+	return []string{
+		fmt.Sprintf("Use fastly_result_read with result_id=%s, offset=0 and limit=20 to page through the items", resultID),
+		fmt.Sprintf("Use fastly_result_query with result_id=%s to find specific items, e.g. filter='name=production'", resultID),
+		"Pick fields from 'list_summary.fields' to filter on instead of reading every item",
+	}
+}
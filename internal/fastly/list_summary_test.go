@@ -0,0 +1,83 @@
+package fastly
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/cache"
+	"github.com/fastly/mcp/internal/types"
+)
+
+// serviceListJSON returns a service list with count items.
+func serviceListJSON(count int) string {
+	items := make([]string, count)
+	for i := range items {
+		items[i] = fmt.Sprintf(`{"ID":"svc%d","Name":"service-%d","Type":"vcl"}`, i, i)
+	}
+	return "[" + strings.Join(items, ",") + "]"
+}
+
+func TestListSummaryForLargeList(t *testing.T) {
+	installMockFastly(t, "echo '"+serviceListJSON(12)+"'")
+
+	SetListSummaryThreshold(10)
+	defer SetListSummaryThreshold(0)
+
+	result := ExecuteCommand(types.CommandRequest{Command: "service", Args: []string{"list"}, Flags: []types.Flag{{Name: "json"}}})
+	if !result.Success || result.ListSummary == nil {
+		t.Fatalf("Expected a list summary, got %+v", result)
+	}
+
+	summary := result.ListSummary
+	if summary.TotalItems != 12 || !reflect.DeepEqual(summary.Fields, []string{"ID", "Name", "Type"}) {
+		t.Errorf("Unexpected summary: %+v", summary)
+	}
+	if len(summary.FirstItems) != ListSummaryItems {
+		t.Errorf("Expected the first %d items, got %d", ListSummaryItems, len(summary.FirstItems))
+	}
+	if result.OutputJSON != nil || result.Preview != nil {
+		t.Errorf("Expected no items besides the summary, got %v and %v", result.OutputJSON, result.Preview)
+	}
+	if result.ResultID == "" || !strings.Contains(result.NextSteps[0], "fastly_result_read with result_id="+result.ResultID) {
+		t.Errorf("Expected a result_id and a fastly_result_read next step, got %q and %v", result.ResultID, result.NextSteps)
+	}
+
+	cached, err := cache.GetStore().Get(result.ResultID)
+	if err != nil {
+		t.Fatalf("Expected the full list to be cached: %v", err)
+	}
+	if !strings.Contains(cached.RawOutput, "service-11") {
+		t.Errorf("Expected the cached result to hold every item")
+	}
+}
+
+func TestListSummarySkipped(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold int
+		items     int
+		args      []string
+	}{
+		{"disabled", 0, 12, []string{"list"}},
+		{"within threshold", 20, 12, []string{"list"}},
+		{"not a list command", 10, 12, []string{"describe"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installMockFastly(t, "echo '"+serviceListJSON(tt.items)+"'")
+			SetListSummaryThreshold(tt.threshold)
+			defer SetListSummaryThreshold(0)
+
+			result := ExecuteCommand(types.CommandRequest{Command: "service", Args: tt.args})
+			if result.ListSummary != nil {
+				t.Errorf("Expected no list summary, got %+v", result.ListSummary)
+			}
+			if items, ok := result.OutputJSON.([]interface{}); !ok || len(items) != tt.items {
+				t.Errorf("Expected the items inline, got %v", result.OutputJSON)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package fastly
+
+import (
+	"sort"
+	"strings"
+)
+
+// globalAllowedLoggingProviders restricts the logging provider subcommands that
+// can be run, e.g. "s3" for "logging s3 create". A nil map allows every
+// provider. It can be configured via SetAllowedLoggingProviders().
+var globalAllowedLoggingProviders map[string]bool
+
+// SetAllowedLoggingProviders restricts the logging command to the given
+// provider subcommands, so deployments can permit only approved log sinks.
+// An empty list allows every provider again.
+func SetAllowedLoggingProviders(providers []string) {
+	var allowed map[string]bool
+	for _, provider := range providers {
+		provider = strings.ToLower(strings.TrimSpace(provider))
+		if provider == "" {
+			continue
+		}
+		if allowed == nil {
+			allowed = make(map[string]bool)
+		}
+		allowed[provider] = true
+	}
+	globalAllowedLoggingProviders = allowed
+}
+
+// allowedLoggingProviders returns the permitted providers in sorted order.
+func allowedLoggingProviders() []string {
+	providers := make([]string, 0, len(globalAllowedLoggingProviders))
+	for provider := range globalAllowedLoggingProviders {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+	return providers
+}
+
+// isLoggingProviderBlocked reports whether a request runs a logging provider
+// subcommand outside the configured allowlist.
+func isLoggingProviderBlocked(command string, args []string) bool {
+	if command != "logging" || len(args) == 0 || globalAllowedLoggingProviders == nil {
+		return false
+	}
+	return !globalAllowedLoggingProviders[strings.ToLower(args[0])]
+}
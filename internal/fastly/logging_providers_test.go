@@ -0,0 +1,56 @@
+package fastly
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestLoggingProviderAllowlist(t *testing.T) {
+	callsFile := installMockFastly(t, `echo "Created S3 logging endpoint"`)
+
+	SetAllowedLoggingProviders([]string{"s3", " BigQuery "})
+	defer SetAllowedLoggingProviders(nil)
+
+	flags := []types.Flag{
+		{Name: "service-id", Value: "SVC123"},
+		{Name: "version", Value: "3"},
+		{Name: "name", Value: "logs"},
+		{Name: "user-reviewed"},
+	}
+
+	allowed := ExecuteCommand(types.CommandRequest{Command: "logging", Args: []string{"s3", "create"}, Flags: flags})
+	if !allowed.Success {
+		t.Errorf("Expected logging s3 create to pass, got %+v", allowed)
+	}
+
+	blocked := ExecuteCommand(types.CommandRequest{Command: "logging", Args: []string{"kafka", "create"}, Flags: flags})
+	if blocked.Success || blocked.ErrorCode != "logging_provider_not_allowed" {
+		t.Errorf("Expected logging kafka create to be blocked, got %+v", blocked)
+	}
+	if !strings.Contains(blocked.Instructions, "bigquery, s3") {
+		t.Errorf("Expected the allowed providers in the instructions, got %q", blocked.Instructions)
+	}
+
+	calls, err := os.ReadFile(callsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(calls), "kafka") {
+		t.Errorf("Expected the blocked provider not to run, got calls:\n%s", calls)
+	}
+}
+
+func TestLoggingProvidersUnrestrictedByDefault(t *testing.T) {
+	if isLoggingProviderBlocked("logging", []string{"kafka", "create"}) {
+		t.Error("Expected every provider to be allowed without an allowlist")
+	}
+
+	SetAllowedLoggingProviders([]string{"s3"})
+	defer SetAllowedLoggingProviders(nil)
+	if isLoggingProviderBlocked("service", []string{"list"}) {
+		t.Error("Expected other commands not to be affected")
+	}
+}
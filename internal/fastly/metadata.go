@@ -1,6 +1,8 @@
 package fastly
 
 import (
+	"strings"
+
 	"github.com/fastly/mcp/internal/types"
 )
 
@@ -9,6 +11,7 @@ import (
 //   - ResourceType: What kind of Fastly resource is being operated on (e.g., "service", "acl", "dictionary")
 //   - OperationType: The action being performed ("read", "create", "update", "delete", "purge", or "unknown")
 //   - IsSafe: Whether the operation is non-destructive (true for read-only operations)
+//   - Severity: How risky the operation is ("info", "mutating" or "destructive")
 //   - RequiresAuth: Whether authentication is needed (true for most operations except version and some utilities)
 //
 // This metadata helps AI agents understand the impact and requirements of operations,
@@ -24,11 +27,13 @@ func GetOperationMetadata(command string, args []string) *types.OperationMetadat
 
 	// Determine operation type and safety
 	operationType, isSafe := GetOperationType(command, args)
+	severity, _ := IsDangerousOperation(strings.Join(append([]string{command}, args...), " "))
 
 	return &types.OperationMetadata{
 		ResourceType:  cmdMetadata.ResourceType,
 		OperationType: operationType,
 		IsSafe:        isSafe,
+		Severity:      severity,
 		RequiresAuth:  cmdMetadata.RequiresAuth,
 	}
 }
@@ -0,0 +1,76 @@
+package fastly
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+// multiLineFlags lists the flags whose value may be supplied as an array of lines.
+// The Fastly CLI accepts a file path for these flags, so the joined lines are
+// written to a private temporary file and its path is passed instead. This keeps
+// newlines out of the command line while still allowing multi-line content.
+var multiLineFlags = map[string]bool{
+	"content": true,
+}
+
+// IsMultiLineFlag reports whether a flag accepts a multi-line value via Lines.
+func IsMultiLineFlag(flagName string) bool {
+	return multiLineFlags[flagName]
+}
+
+// validateMultiLineFlag checks that a flag with Lines is designated for
+// multi-line values and that every line is safe.
+func validateMultiLineFlag(flag types.Flag) error {
+	if !IsMultiLineFlag(flag.Name) {
+		return fmt.Errorf("flag does not accept multi-line values")
+	}
+	if flag.Value != "" {
+		return fmt.Errorf("specify either value or lines, not both")
+	}
+	return GetValidator().ValidateFlagLines(flag.Lines)
+}
+
+// materializeMultiLineFlags writes the lines of each multi-line flag to a
+// temporary file and replaces the flag value with the file path. The returned
+// cleanup function removes the files and must be called after execution.
+func materializeMultiLineFlags(flags []types.Flag) ([]types.Flag, func(), error) {
+	var tempFiles []string
+	cleanup := func() {
+		for _, path := range tempFiles {
+			_ = os.Remove(path)
+		}
+	}
+
+	result := make([]types.Flag, len(flags))
+	for i, flag := range flags {
+		result[i] = flag
+		if len(flag.Lines) == 0 {
+			continue
+		}
+
+		file, err := os.CreateTemp("", "fastly-mcp-"+flag.Name+"-*")
+		if err != nil {
+			cleanup()
+			return nil, func() {}, err
+		}
+		tempFiles = append(tempFiles, file.Name())
+
+		_, writeErr := file.WriteString(strings.Join(flag.Lines, "\n") + "\n")
+		closeErr := file.Close()
+		if writeErr != nil || closeErr != nil {
+			cleanup()
+			if writeErr != nil {
+				return nil, func() {}, writeErr
+			}
+			return nil, func() {}, closeErr
+		}
+
+		result[i].Value = file.Name()
+		result[i].Lines = nil
+	}
+
+	return result, cleanup, nil
+}
@@ -0,0 +1,108 @@
+package fastly
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+	"github.com/fastly/mcp/internal/validation"
+)
+
+func TestMultiLineFlagAccepted(t *testing.T) {
+	installMockFastly(t, `while [ $# -gt 0 ]; do
+  if [ "$1" = "--content" ]; then cat "$2"; echo "path=$2"; fi
+  shift
+done`)
+
+	// Snippet uploads are denied by default; allow them for this test
+	SetCustomValidator(validation.NewValidatorWithCommandsAndDenied(validation.DefaultAllowedCommands(), map[string]bool{}))
+	defer SetCustomValidator(nil)
+
+	lines := []string{
+		"if (req.url ~ \"^/admin\") {",
+		"  error 403;",
+		"}",
+	}
+	result := ExecuteCommand(types.CommandRequest{
+		Command: "vcl",
+		Args:    []string{"snippet", "create"},
+		Flags: []types.Flag{
+			{Name: "user-reviewed"},
+			{Name: "name", Value: "block-admin"},
+			{Name: "content", Lines: lines},
+		},
+	})
+
+	if !result.Success {
+		t.Fatalf("Expected multi-line flag to be accepted, got: %s", result.Error)
+	}
+	if !strings.Contains(result.Output, strings.Join(lines, "\n")) {
+		t.Errorf("Expected CLI to receive the joined lines, got: %s", result.Output)
+	}
+
+	// The temporary file must be removed after execution
+	idx := strings.Index(result.Output, "path=")
+	if idx == -1 {
+		t.Fatalf("Expected mock to report the content path, got: %s", result.Output)
+	}
+	path := strings.TrimSpace(result.Output[idx+len("path="):])
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected temporary file %s to be removed, stat error: %v", path, err)
+	}
+}
+
+func TestMultiLineFlagRejected(t *testing.T) {
+	callsFile := installMockFastly(t, `echo ok`)
+
+	tests := []struct {
+		name        string
+		flag        types.Flag
+		expectError string
+	}{
+		{
+			name:        "newline in normal flag value",
+			flag:        types.Flag{Name: "comment", Value: "line one\nline two"},
+			expectError: "forbidden character",
+		},
+		{
+			name:        "lines on a flag that is not designated",
+			flag:        types.Flag{Name: "comment", Lines: []string{"line one", "line two"}},
+			expectError: "does not accept multi-line values",
+		},
+		{
+			name:        "embedded line break in a line",
+			flag:        types.Flag{Name: "content", Lines: []string{"line one\nline two"}},
+			expectError: "line break",
+		},
+		{
+			name:        "value and lines together",
+			flag:        types.Flag{Name: "content", Value: "x", Lines: []string{"line one"}},
+			expectError: "either value or lines",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ExecuteCommand(types.CommandRequest{
+				Command: "service",
+				Args:    []string{"list"},
+				Flags:   []types.Flag{tt.flag},
+			})
+
+			if result.Success {
+				t.Fatal("Expected validation failure")
+			}
+			if result.ErrorCode != "validation_error" {
+				t.Errorf("Expected validation_error, got %s", result.ErrorCode)
+			}
+			if !strings.Contains(result.Error, tt.expectError) {
+				t.Errorf("Expected error containing %q, got %q", tt.expectError, result.Error)
+			}
+		})
+	}
+
+	if calls, _ := os.ReadFile(callsFile); len(calls) != 0 {
+		t.Errorf("Expected no CLI invocations, got: %s", calls)
+	}
+}
@@ -0,0 +1,63 @@
+package fastly
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+// NonInteractiveMode controls when --non-interactive is appended to the
+// command line of an executed command.
+type NonInteractiveMode string
+
+const (
+	// NonInteractiveAuto appends --non-interactive unless the command is known
+	// not to accept it.
+	NonInteractiveAuto NonInteractiveMode = "auto"
+
+	// NonInteractiveAlways appends --non-interactive to every command.
+	NonInteractiveAlways NonInteractiveMode = "always"
+
+	// NonInteractiveNever never appends --non-interactive.
+	NonInteractiveNever NonInteractiveMode = "never"
+)
+
+// globalNonInteractiveMode controls the injection of --non-interactive.
+// It can be configured via SetNonInteractiveMode().
+var globalNonInteractiveMode = NonInteractiveAuto
+
+// nonInteractiveUnsupported lists command paths that reject --non-interactive.
+// They never prompt, so nothing is lost by leaving the flag off. A path covers
+// the commands below it, and a longer path can set false to take one back.
+var nonInteractiveUnsupported = map[string]bool{
+	"version": true,
+}
+
+// SetNonInteractiveMode sets when --non-interactive is appended to commands.
+func SetNonInteractiveMode(mode NonInteractiveMode) {
+	globalNonInteractiveMode = mode
+}
+
+// ParseNonInteractiveMode parses a --non-interactive injection mode name.
+func ParseNonInteractiveMode(value string) (NonInteractiveMode, error) {
+	switch mode := NonInteractiveMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case NonInteractiveAuto, NonInteractiveAlways, NonInteractiveNever:
+		return mode, nil
+	}
+	return "", fmt.Errorf("invalid mode %q: expected 'auto', 'always' or 'never'", value)
+}
+
+// shouldInjectNonInteractive reports whether --non-interactive should be
+// appended when running the given command and arguments.
+func shouldInjectNonInteractive(command string, args []string) bool {
+	switch globalNonInteractiveMode {
+	case NonInteractiveAlways:
+		return true
+	case NonInteractiveNever:
+		return false
+	}
+
+	unsupported, _ := types.LongestCommandPathMatch(nonInteractiveUnsupported, command, args)
+	return !unsupported
+}
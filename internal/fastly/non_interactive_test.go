@@ -0,0 +1,67 @@
+package fastly
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestNonInteractiveInjection(t *testing.T) {
+	tests := []struct {
+		name    string
+		request types.CommandRequest
+		want    bool
+	}{
+		{"regular command", types.CommandRequest{Command: "service", Args: []string{"list"}}, true},
+		{"command rejecting the flag", types.CommandRequest{Command: "version"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callsFile := installMockFastly(t, `echo "ok"`)
+
+			result := ExecuteCommand(tt.request)
+			if !result.Success {
+				t.Fatalf("Expected success, got %+v", result)
+			}
+
+			calls, err := os.ReadFile(callsFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Contains(string(calls), "--non-interactive"); got != tt.want {
+				t.Errorf("Expected --non-interactive appended: %v, got call %q", tt.want, calls)
+			}
+			if got := strings.Contains(result.CommandLine, "--non-interactive"); got != tt.want {
+				t.Errorf("Expected --non-interactive in command_line: %v, got %q", tt.want, result.CommandLine)
+			}
+		})
+	}
+}
+
+func TestNonInteractiveModeOverride(t *testing.T) {
+	defer SetNonInteractiveMode(NonInteractiveAuto)
+
+	SetNonInteractiveMode(NonInteractiveAlways)
+	if !shouldInjectNonInteractive("version", nil) {
+		t.Error("Expected 'always' to inject --non-interactive for every command")
+	}
+
+	SetNonInteractiveMode(NonInteractiveNever)
+	if shouldInjectNonInteractive("service", []string{"list"}) {
+		t.Error("Expected 'never' not to inject --non-interactive")
+	}
+}
+
+func TestParseNonInteractiveMode(t *testing.T) {
+	mode, err := ParseNonInteractiveMode(" Never ")
+	if err != nil || mode != NonInteractiveNever {
+		t.Errorf("Expected never, got %q (%v)", mode, err)
+	}
+
+	if _, err := ParseNonInteractiveMode("sometimes"); err == nil {
+		t.Error("Expected an error for an unknown mode")
+	}
+}
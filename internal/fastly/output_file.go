@@ -0,0 +1,92 @@
+package fastly
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+// DefaultOutputFileFlags lists the flags that name a file a command writes its
+// output to instead of stdout.
+var DefaultOutputFileFlags = []string{"output", "output-file"}
+
+// globalOutputFileFlags holds the flags recognized as output file flags. It
+// can be extended via SetOutputFileFlags().
+var globalOutputFileFlags = outputFileFlagSet(nil)
+
+// SetOutputFileFlags recognizes the given flag names as output file flags in
+// addition to DefaultOutputFileFlags. Their values are validated as paths.
+func SetOutputFileFlags(names []string) {
+	globalOutputFileFlags = outputFileFlagSet(names)
+}
+
+func outputFileFlagSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(DefaultOutputFileFlags)+len(names))
+	for _, name := range DefaultOutputFileFlags {
+		set[name] = true
+	}
+	for _, name := range names {
+		if name = strings.TrimLeft(strings.TrimSpace(name), "-"); name != "" {
+			set[name] = true
+		}
+	}
+	return set
+}
+
+// isOutputFileFlag reports whether a flag names a file the command writes to.
+func isOutputFileFlag(flagName string) bool {
+	return globalOutputFileFlags[flagName]
+}
+
+// describeOutputFile reports the file written by a successful command given an
+// output file flag. When the flag is set but no regular file exists at the
+// path, it returns a note instead so the agent does not assume the export
+// worked.
+func describeOutputFile(flags []types.Flag) (*types.OutputFile, string) {
+	for _, flag := range flags {
+		if !isOutputFileFlag(flag.Name) || flag.Value == "" {
+			continue
+		}
+
+		info, err := os.Stat(flag.Value)
+		if err != nil {
+			return nil, fmt.Sprintf("The command was given --%s %s, but the file could not be found afterwards.", flag.Name, flag.Value)
+		}
+		if !info.Mode().IsRegular() {
+			return nil, fmt.Sprintf("The command was given --%s %s, but the path is not a regular file.", flag.Name, flag.Value)
+		}
+		return &types.OutputFile{
+			Flag:      flag.Name,
+			Path:      flag.Value,
+			SizeBytes: info.Size(),
+		}, ""
+	}
+	return nil, ""
+}
+
+// applyOutputFile reports the output file of a successful command in the
+// response. A command that printed nothing is described by the file it wrote.
+func applyOutputFile(response *types.CommandResponse, flags []types.Flag) {
+	outputFile, note := describeOutputFile(flags)
+	if note != "" {
+		response.Instructions = strings.TrimSpace(response.Instructions + " " + note)
+		return
+	}
+	if outputFile == nil {
+		return
+	}
+
+	response.OutputFile = outputFile
+	written := fmt.Sprintf("The output was written to %s (%d bytes).", outputFile.Path, outputFile.SizeBytes)
+	if strings.TrimSpace(response.Output) == "" && response.OutputJSON == nil && response.ResultID == "" {
+		response.Instructions = "Command executed successfully. " + written
+		response.NextSteps = []string{
+			fmt.Sprintf("Tell the user the output is in %s", outputFile.Path),
+			"The file contents are not included in this response",
+		}
+		return
+	}
+	response.Instructions = strings.TrimSpace(response.Instructions + " " + written)
+}
@@ -0,0 +1,68 @@
+package fastly
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestOutputFileReported(t *testing.T) {
+	installMockFastly(t, `while [ $# -gt 0 ]; do
+  case "$1" in
+    --output|--export-to) printf 'package-bytes' > "$2"; shift ;;
+  esac
+  shift
+done`)
+
+	path := filepath.Join(t.TempDir(), "package.tar.gz")
+	result := ExecuteCommand(types.CommandRequest{
+		Command: "compute",
+		Args:    []string{"pack"},
+		Flags:   []types.Flag{{Name: "output", Value: path}},
+	})
+	if !result.Success {
+		t.Fatalf("Expected success, got %+v", result)
+	}
+	if result.OutputFile == nil || result.OutputFile.Path != path || result.OutputFile.SizeBytes != int64(len("package-bytes")) {
+		t.Fatalf("Expected the output file and its size, got %+v", result.OutputFile)
+	}
+	if !strings.Contains(result.Instructions, "written to "+path+" (13 bytes)") {
+		t.Errorf("Expected the instructions to name the file, got %q", result.Instructions)
+	}
+
+	// A flag that writes nothing is reported rather than assumed to have worked
+	missing := filepath.Join(t.TempDir(), "missing.pem")
+	result = ExecuteCommand(types.CommandRequest{
+		Command: "compute",
+		Args:    []string{"pack"},
+		Flags:   []types.Flag{{Name: "output-file", Value: missing}},
+	})
+	if result.OutputFile != nil || !strings.Contains(result.Instructions, "could not be found") {
+		t.Errorf("Expected a note about the missing file, got %+v", result)
+	}
+
+	// Additional flags are recognized once configured
+	SetOutputFileFlags([]string{"--export-to"})
+	defer SetOutputFileFlags(nil)
+
+	exported := filepath.Join(t.TempDir(), "cert.pem")
+	result = ExecuteCommand(types.CommandRequest{
+		Command: "compute",
+		Args:    []string{"pack"},
+		Flags:   []types.Flag{{Name: "export-to", Value: exported}},
+	})
+	if result.OutputFile == nil || result.OutputFile.Flag != "export-to" {
+		t.Errorf("Expected the configured flag to be reported, got %+v", result.OutputFile)
+	}
+
+	result = ExecuteCommand(types.CommandRequest{
+		Command: "compute",
+		Args:    []string{"pack"},
+		Flags:   []types.Flag{{Name: "export-to", Value: "../escape.pem"}},
+	})
+	if result.Success {
+		t.Error("Expected a traversal path in a configured output flag to be rejected")
+	}
+}
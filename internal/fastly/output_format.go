@@ -0,0 +1,94 @@
+package fastly
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+// commandsWithoutJSON lists command paths that have no JSON output mode.
+// Passing --json to them makes the Fastly CLI fail with an unknown flag error,
+// so the flag is dropped and the text output is returned instead.
+var commandsWithoutJSON = map[string]bool{
+	"version":                    true,
+	"compute build":              true,
+	"compute deploy":             true,
+	"compute init":               true,
+	"compute pack":               true,
+	"compute publish":            true,
+	"compute serve":              true,
+	"compute validate":           true,
+	"service-version activate":   true,
+	"service-version deactivate": true,
+	"service-version lock":       true,
+}
+
+// commandsWithFormatFlag lists command paths that select JSON output with
+// --format json rather than the --json boolean flag, which they reject.
+var commandsWithFormatFlag = map[string]bool{
+	"stats historical": true,
+	"stats realtime":   true,
+}
+
+// matchCommandPath reports whether the longest of the given command paths
+// that prefixes the command and its arguments is set.
+func matchCommandPath(paths map[string]bool, command string, args []string) bool {
+	matched, _ := types.LongestCommandPathMatch(paths, command, args)
+	return matched
+}
+
+// SupportsJSONOutput reports whether a command has a JSON output mode.
+func SupportsJSONOutput(command string, args []string) bool {
+	return !matchCommandPath(commandsWithoutJSON, command, args)
+}
+
+// resolveOutputFormat removes a requested --json flag from commands that have
+// no JSON mode and rewrites it to --format json for commands that select JSON
+// that way. It returns the adjusted flags and a note for the agent, which is
+// empty when nothing was changed.
+func resolveOutputFormat(command string, args []string, flags []types.Flag) ([]types.Flag, string) {
+	if matchCommandPath(commandsWithFormatFlag, command, args) {
+		return rewriteJSONToFormat(command, args, flags)
+	}
+	if SupportsJSONOutput(command, args) {
+		return flags, ""
+	}
+
+	var result []types.Flag
+	removed := false
+	for _, flag := range flags {
+		if flag.Name == "json" {
+			removed = true
+			continue
+		}
+		result = append(result, flag)
+	}
+	if !removed {
+		return flags, ""
+	}
+
+	cmdPath := strings.TrimSpace(command + " " + strings.Join(args, " "))
+	return result, fmt.Sprintf("Note: '%s' has no JSON output mode, so --json was not passed and the text output is returned instead.", cmdPath)
+}
+
+// rewriteJSONToFormat replaces a requested --json flag with --format json. If
+// the agent also gave --format, that value is kept and --json is dropped.
+func rewriteJSONToFormat(command string, args []string, flags []types.Flag) ([]types.Flag, string) {
+	if !hasFlag(flags, "json") {
+		return flags, ""
+	}
+
+	var result []types.Flag
+	for _, flag := range flags {
+		if flag.Name != "json" {
+			result = append(result, flag)
+		}
+	}
+	if !hasFlag(result, "format") {
+		result = append(result, types.Flag{Name: "format", Value: "json"})
+	}
+
+	cmdPath := strings.TrimSpace(command + " " + strings.Join(args, " "))
+	return result, fmt.Sprintf("Note: '%s' selects JSON output with --format json, so --json was passed as --format json.", cmdPath)
+}
@@ -0,0 +1,124 @@
+package fastly
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestSupportsJSONOutput(t *testing.T) {
+	tests := []struct {
+		command  string
+		args     []string
+		expected bool
+	}{
+		{"version", nil, false},
+		{"compute", []string{"build"}, false},
+		{"service-version", []string{"activate"}, false},
+		{"service-version", []string{"list"}, true},
+		{"compute", []string{"service", "list"}, true},
+		{"service", []string{"list"}, true},
+	}
+
+	for _, tt := range tests {
+		name := strings.TrimSpace(tt.command + " " + strings.Join(tt.args, " "))
+		t.Run(name, func(t *testing.T) {
+			if got := SupportsJSONOutput(tt.command, tt.args); got != tt.expected {
+				t.Errorf("SupportsJSONOutput(%s) = %v, want %v", name, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestJSONFlagDroppedForTextOnlyCommand(t *testing.T) {
+	callsFile := installMockFastly(t, `echo "Fastly CLI version v10.0.0"`)
+
+	result := ExecuteCommand(types.CommandRequest{
+		Command: "version",
+		Flags:   []types.Flag{{Name: "json"}},
+	})
+
+	if !result.Success {
+		t.Fatalf("Expected success, got: %s", result.Error)
+	}
+	if strings.Contains(result.CommandLine, "--json") {
+		t.Errorf("Command line should not contain --json: %s", result.CommandLine)
+	}
+	calls, err := os.ReadFile(callsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(calls), "--json") {
+		t.Errorf("CLI should not receive --json, got: %s", calls)
+	}
+	if !strings.Contains(result.Output, "v10.0.0") {
+		t.Errorf("Expected text output, got: %q", result.Output)
+	}
+	if !strings.Contains(result.Instructions, "no JSON output mode") {
+		t.Errorf("Expected note about missing JSON mode, got: %s", result.Instructions)
+	}
+}
+
+func TestJSONFlagKeptForJSONCommand(t *testing.T) {
+	installMockFastly(t, `echo '[]'`)
+
+	result := ExecuteCommand(types.CommandRequest{
+		Command: "service",
+		Args:    []string{"list"},
+		Flags:   []types.Flag{{Name: "json"}},
+	})
+
+	if !strings.Contains(result.CommandLine, "--json") {
+		t.Errorf("Command line should contain --json: %s", result.CommandLine)
+	}
+	if strings.Contains(result.Instructions, "no JSON output mode") {
+		t.Errorf("Unexpected note for a JSON-capable command: %s", result.Instructions)
+	}
+}
+
+func TestJSONFlagRewrittenToFormat(t *testing.T) {
+	callsFile := installMockFastly(t, `echo '{"Data":[]}'`)
+
+	result := ExecuteCommand(types.CommandRequest{
+		Command: "stats",
+		Args:    []string{"historical"},
+		Flags:   []types.Flag{{Name: "json"}},
+	})
+
+	if !strings.Contains(result.CommandLine, "--format json") || strings.Contains(result.CommandLine, "--json") {
+		t.Errorf("Expected --format json instead of --json, got: %s", result.CommandLine)
+	}
+	calls, err := os.ReadFile(callsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(calls), "--format json") || strings.Contains(string(calls), "--json") {
+		t.Errorf("CLI should receive --format json, got: %s", calls)
+	}
+	if !strings.Contains(result.Instructions, "passed as --format json") {
+		t.Errorf("Expected a note about the rewrite, got: %s", result.Instructions)
+	}
+}
+
+func TestResolveOutputFormatForFormatCommands(t *testing.T) {
+	tests := []struct {
+		name     string
+		flags    []types.Flag
+		expected []types.Flag
+	}{
+		{"without json", []types.Flag{{Name: "by", Value: "day"}}, []types.Flag{{Name: "by", Value: "day"}}},
+		{"explicit format kept", []types.Flag{{Name: "json"}, {Name: "format", Value: "csv"}}, []types.Flag{{Name: "format", Value: "csv"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := resolveOutputFormat("stats", []string{"realtime"}, tt.flags)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("resolveOutputFormat() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
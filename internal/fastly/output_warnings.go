@@ -0,0 +1,58 @@
+package fastly
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// globalJSONWarningLines is the number of leading non-JSON lines, such as a
+// "Warning: ..." notice, that may precede JSON output and still have it parsed
+// as JSON; 0 disables the grace. It can be configured via
+// SetJSONWarningLines().
+var globalJSONWarningLines = 3
+
+// SetJSONWarningLines sets how many leading warning lines are stripped from
+// output to parse the remainder as JSON. Use 0 to disable stripping.
+func SetJSONWarningLines(lines int) {
+	if lines < 0 {
+		lines = 0
+	}
+	globalJSONWarningLines = lines
+}
+
+// splitLeadingWarnings separates up to globalJSONWarningLines leading lines
+// from output when the remainder is a JSON object or array, so that a CLI
+// warning printed before the JSON does not turn the output into text. It
+// returns the remaining output and the stripped lines, or the output unchanged
+// and no lines when it is already valid JSON or no remainder parses.
+func splitLeadingWarnings(output string) (string, []string) {
+	trimmed := strings.TrimSpace(output)
+	if globalJSONWarningLines == 0 || trimmed == "" || json.Valid([]byte(trimmed)) {
+		return output, nil
+	}
+
+	lines := strings.Split(trimmed, "\n")
+	for n := 1; n <= globalJSONWarningLines && n < len(lines); n++ {
+		// A line opening JSON means the JSON itself is broken, not prefixed
+		if line := strings.TrimSpace(lines[n-1]); strings.HasPrefix(line, "{") || strings.HasPrefix(line, "[") {
+			break
+		}
+
+		rest := strings.TrimSpace(strings.Join(lines[n:], "\n"))
+		if !strings.HasPrefix(rest, "{") && !strings.HasPrefix(rest, "[") {
+			continue
+		}
+		if !json.Valid([]byte(rest)) {
+			continue
+		}
+
+		warnings := make([]string, 0, n)
+		for _, line := range lines[:n] {
+			if line = strings.TrimSpace(line); line != "" {
+				warnings = append(warnings, line)
+			}
+		}
+		return rest, warnings
+	}
+	return output, nil
+}
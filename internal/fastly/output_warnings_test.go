@@ -0,0 +1,64 @@
+package fastly
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestOutputWarningsBeforeJSON(t *testing.T) {
+	installMockFastly(t, `printf 'Warning: a newer version is available\n[{"ID": "abc123", "Name": "www"}]\n'`)
+
+	result := ExecuteCommand(types.CommandRequest{
+		Command: "service",
+		Args:    []string{"list"},
+		Flags:   []types.Flag{{Name: "json"}},
+	})
+	if !result.Success {
+		t.Fatalf("Expected success, got %+v", result)
+	}
+	items, ok := result.OutputJSON.([]interface{})
+	if !ok || len(items) != 1 {
+		t.Fatalf("Expected the JSON after the warning to be parsed, got %+v", result)
+	}
+	if result.Output != "" {
+		t.Errorf("Expected no text output, got %q", result.Output)
+	}
+	expected := []string{"Warning: a newer version is available"}
+	if !reflect.DeepEqual(result.OutputWarnings, expected) {
+		t.Errorf("Expected warnings %v, got %v", expected, result.OutputWarnings)
+	}
+}
+
+func TestSplitLeadingWarnings(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		rest     string
+		warnings []string
+	}{
+		{"valid JSON", `{"ok": true}`, `{"ok": true}`, nil},
+		{"one warning", "Warning: deprecated flag\n{\"ok\": true}", `{"ok": true}`, []string{"Warning: deprecated flag"}},
+		{"warnings and blank line", "Warning: one\nWarning: two\n\n[1, 2]\n", "[1, 2]", []string{"Warning: one", "Warning: two"}},
+		{"plain text", "Service created\nID: abc123", "Service created\nID: abc123", nil},
+		{"broken JSON", "[1,\n2", "[1,\n2", nil},
+		{"scalar remainder", "Warning: one\n42", "Warning: one\n42", nil},
+		{"too many lines", "a\nb\nc\nd\n[]", "a\nb\nc\nd\n[]", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rest, warnings := splitLeadingWarnings(tt.output)
+			if rest != tt.rest || !reflect.DeepEqual(warnings, tt.warnings) {
+				t.Errorf("Expected %q %v, got %q %v", tt.rest, tt.warnings, rest, warnings)
+			}
+		})
+	}
+
+	SetJSONWarningLines(0)
+	defer SetJSONWarningLines(3)
+	if rest, warnings := splitLeadingWarnings("Warning: one\n[]"); rest != "Warning: one\n[]" || warnings != nil {
+		t.Errorf("Expected no stripping when disabled, got %q %v", rest, warnings)
+	}
+}
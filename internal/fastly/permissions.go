@@ -0,0 +1,43 @@
+package fastly
+
+import "github.com/fastly/mcp/internal/types"
+
+// commandScopes is a curated map of command paths to the API token scope they
+// need when it cannot be derived from the operation type. The longest matching
+// path wins.
+var commandScopes = map[string]string{
+	"purge":    "purge_select",
+	"stats":    "global:read",
+	"log-tail": "global:read",
+	"pops":     "global:read",
+	"ip-list":  "global:read",
+	"whoami":   "global:read",
+}
+
+// scopeNotes explain, per base command, when a different scope is needed than
+// the one reported by requiredTokenScope.
+var scopeNotes = map[string]string{
+	"purge": "Purging a URL or surrogate key needs purge_select; purging everything with --all needs purge_all. A global token allows both.",
+}
+
+// requiredTokenScope returns the API token scope needed to run a command:
+// purge_select or purge_all for purges, global:read for read operations and
+// global for everything else. Commands that need no authentication, such as
+// version, return an empty scope.
+func requiredTokenScope(command string, args []string, flags []types.Flag) string {
+	if !GetCommandMetadata(command).RequiresAuth {
+		return ""
+	}
+	if command == "purge" && hasFlag(flags, "all") {
+		return "purge_all"
+	}
+
+	if scope, ok := types.LongestCommandPathMatch(commandScopes, command, args); ok {
+		return scope
+	}
+
+	if operationType, _ := GetOperationType(command, args); operationType == "read" {
+		return "global:read"
+	}
+	return "global"
+}
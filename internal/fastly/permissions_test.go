@@ -0,0 +1,71 @@
+package fastly
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestDetectErrorCodeInsufficientPermission(t *testing.T) {
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{"Error: 403 - Forbidden", "insufficient_permission"},
+		{"the token has insufficient scope for this request", "insufficient_permission"},
+		{"401 Unauthorized", "auth_required"},
+		{"missing permission to read the file", "permission_denied"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			if got := DetectErrorCode(tt.message); got != tt.want {
+				t.Errorf("DetectErrorCode(%q) = %q, want %q", tt.message, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequiredTokenScope(t *testing.T) {
+	tests := []struct {
+		command string
+		args    []string
+		flags   []types.Flag
+		want    string
+	}{
+		{"purge", nil, []types.Flag{{Name: "key", Value: "product-1"}}, "purge_select"},
+		{"purge", nil, []types.Flag{{Name: "all"}}, "purge_all"},
+		{"service", []string{"list"}, nil, "global:read"},
+		{"stats", []string{"historical"}, nil, "global:read"},
+		{"backend", []string{"create"}, nil, "global"},
+	}
+
+	for _, tt := range tests {
+		if got := requiredTokenScope(tt.command, tt.args, tt.flags); got != tt.want {
+			t.Errorf("requiredTokenScope(%q, %v) = %q, want %q", tt.command, tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestInsufficientPermissionGuidance(t *testing.T) {
+	installMockFastly(t, `echo "Error: 403 - Forbidden" >&2; exit 1`)
+
+	result := ExecuteCommand(types.CommandRequest{
+		Command: "purge",
+		Flags: []types.Flag{
+			{Name: "service-id", Value: "SU1Z0isxPaozGVKXdv0eY"},
+			{Name: "key", Value: "product-1"},
+			{Name: "user-reviewed"},
+		},
+	})
+	if result.Success || result.ErrorCode != "insufficient_permission" {
+		t.Fatalf("Expected insufficient_permission, got %+v", result)
+	}
+	if !strings.Contains(result.Instructions, "'purge_select' scope") {
+		t.Errorf("Expected the needed scope in the instructions, got %q", result.Instructions)
+	}
+	if !strings.Contains(strings.Join(result.NextSteps, "\n"), "token with the 'purge_select' scope") {
+		t.Errorf("Expected scope guidance in the next steps, got %v", result.NextSteps)
+	}
+}
@@ -0,0 +1,81 @@
+package fastly
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+// popRegionFields are the POP fields that name its region, in order of preference.
+var popRegionFields = []string{"region", "group", "continent"}
+
+// popUnknownRegion is the group for POPs that carry no region information.
+const popUnknownRegion = "Unknown"
+
+// NormalizePopsOutput adds a grouping by region to the JSON output of the pops
+// command. The CLI returns a flat list, which makes questions such as "which
+// POPs are in Asia" hard to answer; the grouped view maps each region to the
+// codes of its POPs. The raw list is kept unchanged under "pops".
+// Output that is not a JSON array of POPs is returned unchanged.
+func NormalizePopsOutput(output string, command string, args []string) string {
+	if command != "pops" || len(args) > 0 {
+		return output
+	}
+
+	trimmedBytes := []byte(strings.TrimSpace(output))
+	if len(trimmedBytes) == 0 || trimmedBytes[0] != '[' {
+		return output
+	}
+
+	var pops []interface{}
+	if err := json.Unmarshal(trimmedBytes, &pops); err != nil {
+		return output
+	}
+
+	byRegion, ok := groupPopsByRegion(pops)
+	if !ok {
+		return output
+	}
+
+	result, err := json.Marshal(types.PopsByRegion{
+		Pops:     pops,
+		ByRegion: byRegion,
+	})
+	if err != nil {
+		return output
+	}
+
+	return string(result)
+}
+
+// groupPopsByRegion maps each region to the codes of its POPs, in input order.
+// It returns false if any entry is not a POP object with a code.
+func groupPopsByRegion(pops []interface{}) (map[string][]string, bool) {
+	byRegion := make(map[string][]string)
+	for _, item := range pops {
+		pop, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		code, _ := lookupField(pop, "code")
+		codeStr, ok := code.(string)
+		if !ok || codeStr == "" {
+			return nil, false
+		}
+
+		region := popUnknownRegion
+		for _, field := range popRegionFields {
+			if value, ok := lookupField(pop, field); ok {
+				if name, ok := value.(string); ok && name != "" {
+					region = name
+					break
+				}
+			}
+		}
+
+		byRegion[region] = append(byRegion[region], codeStr)
+	}
+	return byRegion, true
+}
@@ -0,0 +1,75 @@
+package fastly
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestNormalizePopsOutput(t *testing.T) {
+	input := `[
+		{"code":"AMS","name":"Amsterdam","group":"Europe","shield":"amsterdam-nl"},
+		{"code":"IAD","name":"Ashburn","group":"North America"},
+		{"code":"LHR","name":"London","group":"Europe"},
+		{"code":"XYZ","name":"Nowhere"}
+	]`
+
+	var normalized types.PopsByRegion
+	if err := json.Unmarshal([]byte(NormalizePopsOutput(input, "pops", nil)), &normalized); err != nil {
+		t.Fatalf("Normalized output is not valid JSON: %v", err)
+	}
+
+	expected := map[string][]string{
+		"Europe":        {"AMS", "LHR"},
+		"North America": {"IAD"},
+		"Unknown":       {"XYZ"},
+	}
+	if !reflect.DeepEqual(normalized.ByRegion, expected) {
+		t.Errorf("Expected grouping %v, got %v", expected, normalized.ByRegion)
+	}
+
+	// The raw list is preserved and every POP appears in exactly one group
+	if len(normalized.Pops) != 4 {
+		t.Fatalf("Expected 4 raw POPs, got %d", len(normalized.Pops))
+	}
+	seen := make(map[string]int)
+	for _, codes := range normalized.ByRegion {
+		for _, code := range codes {
+			seen[code]++
+		}
+	}
+	for _, item := range normalized.Pops {
+		pop := item.(map[string]interface{})
+		code := pop["code"].(string)
+		if seen[code] != 1 {
+			t.Errorf("Expected POP %s in exactly one group, found %d", code, seen[code])
+		}
+		if pop["name"] == nil {
+			t.Errorf("Expected raw POP %s to keep its fields, got %v", code, pop)
+		}
+	}
+}
+
+func TestNormalizePopsOutputUnchanged(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		input   string
+	}{
+		{"other command", "service", []string{"list"}, `[{"code":"AMS","group":"Europe"}]`},
+		{"text output", "pops", nil, "AMS Amsterdam Europe\n"},
+		{"entry without code", "pops", nil, `[{"name":"Amsterdam"}]`},
+		{"object output", "pops", nil, `{"code":"AMS"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizePopsOutput(tt.input, tt.command, tt.args); got != tt.input {
+				t.Errorf("Expected output unchanged, got %q", got)
+			}
+		})
+	}
+}
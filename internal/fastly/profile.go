@@ -0,0 +1,53 @@
+package fastly
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+// profileNameRegex matches the names accepted for a server-level profile
+var profileNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,63}$`)
+
+// globalProfile is the Fastly CLI profile every command runs with; empty uses
+// the CLI's default profile or FASTLY_API_TOKEN. It can be configured via
+// SetProfile().
+var globalProfile = ""
+
+// SetProfile makes every Fastly CLI invocation use the named profile, passed
+// as --profile. The profile takes precedence over the default profile, and
+// FASTLY_API_TOKEN is removed from the environment of the CLI so it cannot
+// override the profile's token. Use an empty name to go back to the CLI's
+// default.
+func SetProfile(name string) error {
+	if name != "" && !profileNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use up to 64 letters, digits, '.', '_' or '-', starting with a letter or digit", name)
+	}
+	globalProfile = name
+	return nil
+}
+
+// ProfileArgs returns the arguments selecting the configured profile, to append
+// to a Fastly CLI command line, or nil when no profile is configured.
+func ProfileArgs() []string {
+	if globalProfile == "" {
+		return nil
+	}
+	return []string{"--profile", globalProfile}
+}
+
+// profileFlagConflict returns an error when a request passes its own profile
+// flag while the server is pinned to a profile. The profile is an operator
+// decision, so agents cannot switch accounts per call.
+func profileFlagConflict(flags []types.Flag) error {
+	if globalProfile == "" {
+		return nil
+	}
+	for _, flag := range flags {
+		if flag.Name == "profile" {
+			return fmt.Errorf("this server runs every command with the '%s' profile; the profile flag cannot be set per call", globalProfile)
+		}
+	}
+	return nil
+}
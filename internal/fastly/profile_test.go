@@ -0,0 +1,91 @@
+package fastly
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestProfileAppended(t *testing.T) {
+	callsFile := installMockFastly(t, `echo '[]'`)
+
+	if err := SetProfile("work"); err != nil {
+		t.Fatalf("SetProfile failed: %v", err)
+	}
+	defer func() { _ = SetProfile("") }()
+
+	result := ExecuteCommand(types.CommandRequest{Command: "service", Args: []string{"list"}, Flags: []types.Flag{{Name: "json"}}})
+	if !result.Success {
+		t.Fatalf("Expected success, got %+v", result)
+	}
+	if !strings.HasSuffix(result.CommandLine, "--profile work") {
+		t.Errorf("Expected the command line to end with the profile, got %q", result.CommandLine)
+	}
+
+	if err := CheckSetup(); err != nil {
+		t.Fatalf("CheckSetup failed: %v", err)
+	}
+
+	calls, err := os.ReadFile(callsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(calls)), "\n")
+	if !slices.Contains(lines, "service list --json --non-interactive --profile work") {
+		t.Errorf("Expected the command to run with the profile, got %q", lines)
+	}
+	if !slices.Contains(lines, "whoami --profile work") {
+		t.Errorf("Expected the setup check to run whoami with the profile, got %q", lines)
+	}
+
+	// Agents cannot switch the profile per call
+	result = ExecuteCommand(types.CommandRequest{Command: "service", Args: []string{"list"}, Flags: []types.Flag{{Name: "profile", Value: "other"}}})
+	if result.Success || result.ErrorCode != "validation_error" || !strings.Contains(result.Error, "'work' profile") {
+		t.Errorf("Expected a per-call profile to be rejected, got %+v", result)
+	}
+}
+
+func TestSetProfile(t *testing.T) {
+	defer func() { _ = SetProfile("") }()
+
+	for _, name := range []string{"work", "acct-1", "team_a.prod"} {
+		if err := SetProfile(name); err != nil {
+			t.Errorf("Expected %q to be accepted, got %v", name, err)
+		}
+	}
+	for _, name := range []string{"-work", "my profile", "a;b", strings.Repeat("a", 65)} {
+		if err := SetProfile(name); err == nil {
+			t.Errorf("Expected %q to be rejected", name)
+		}
+	}
+	if err := SetProfile(""); err != nil || ProfileArgs() != nil {
+		t.Errorf("Expected an empty name to clear the profile, got %v %v", ProfileArgs(), err)
+	}
+}
+
+func TestProfileOverridesTokenEnvironment(t *testing.T) {
+	t.Setenv("FASTLY_API_TOKEN", "secret")
+
+	hasToken := func() bool {
+		for _, entry := range ChildEnvironment() {
+			if strings.HasPrefix(entry, "FASTLY_API_TOKEN=") {
+				return true
+			}
+		}
+		return false
+	}
+
+	if !hasToken() {
+		t.Error("Expected FASTLY_API_TOKEN to be passed without a profile")
+	}
+	if err := SetProfile("work"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = SetProfile("") }()
+	if hasToken() {
+		t.Error("Expected FASTLY_API_TOKEN to be dropped with a profile")
+	}
+}
@@ -0,0 +1,263 @@
+package fastly
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fastly/mcp/internal/types"
+	"github.com/fastly/mcp/internal/validation"
+)
+
+// globalStrictPurgeBatch controls whether a batch of surrogate keys containing
+// an invalid key is rejected as a whole instead of purging the valid keys.
+// It can be configured via SetStrictPurgeBatch().
+var globalStrictPurgeBatch = false
+
+// purgeBatchConflictingFlags select what to purge and cannot be combined with
+// a batch of keys.
+var purgeBatchConflictingFlags = map[string]bool{
+	"key":  true,
+	"file": true,
+	"url":  true,
+	"all":  true,
+}
+
+// SetStrictPurgeBatch enables or disables strict batches. When enabled, a batch
+// with any invalid surrogate key is rejected and no key is purged. By default
+// invalid keys are reported and the remaining keys are still purged.
+func SetStrictPurgeBatch(enabled bool) {
+	globalStrictPurgeBatch = enabled
+}
+
+// validateSurrogateKey checks a single surrogate key of a batch. Keys are sent
+// space-separated to the API, so they cannot contain whitespace.
+func validateSurrogateKey(validator *validation.Validator, key string) error {
+	if key == "" {
+		return fmt.Errorf("surrogate key is empty")
+	}
+	if len(key) > MaxSurrogateKeyLength {
+		return fmt.Errorf("surrogate key exceeds %d bytes", MaxSurrogateKeyLength)
+	}
+	if strings.ContainsAny(key, " \t\r\n") {
+		return fmt.Errorf("surrogate key contains whitespace")
+	}
+	return validator.ValidateFlagValue(key)
+}
+
+// executePurgeBatch purges a batch of surrogate keys given in req.Keys. Each key
+// is validated on its own, so one bad key does not fail the others, and the
+// valid keys are purged together with a single 'fastly purge --file' call,
+// whose output gives the purge ID of each key. The review requirement of a
+// single purge with gateArgs applies, and is satisfied once for the whole batch.
+func executePurgeBatch(ctx context.Context, validator *validation.Validator, req types.CommandRequest, gateArgs []string) types.CommandResponse {
+	if req.Command != "purge" || len(req.Args) > 0 {
+		return PurgeBatchError(req.Command, req.Args, req.Flags, fmt.Errorf("keys is only supported by the purge command"))
+	}
+	if len(req.RawArgs) > 0 {
+		return PurgeBatchError(req.Command, req.Args, req.Flags, fmt.Errorf("keys cannot be combined with raw_args"))
+	}
+	if len(req.Keys) > MaxPurgeBatchKeys {
+		return PurgeBatchError(req.Command, req.Args, req.Flags, fmt.Errorf("batch of %d keys exceeds the maximum of %d", len(req.Keys), MaxPurgeBatchKeys))
+	}
+	for _, flag := range req.Flags {
+		if purgeBatchConflictingFlags[flag.Name] {
+			return PurgeBatchError(req.Command, req.Args, req.Flags, fmt.Errorf("keys cannot be combined with --%s", flag.Name))
+		}
+	}
+
+	results := make([]types.PurgeKeyResult, len(req.Keys))
+	var valid []int
+	for i, key := range req.Keys {
+		results[i].Key = key
+		if err := validateSurrogateKey(validator, key); err != nil {
+			results[i].Error = err.Error()
+			results[i].ErrorCode = "invalid_surrogate_key"
+			continue
+		}
+		valid = append(valid, i)
+	}
+	invalid := len(req.Keys) - len(valid)
+
+	if invalid > 0 && (globalStrictPurgeBatch || len(valid) == 0) {
+		for _, i := range valid {
+			results[i].Error = "not purged because the batch contains invalid keys"
+			results[i].ErrorCode = "batch_rejected"
+		}
+		response := NewResponseBuilder().
+			WithCommand(req.Command, req.Args, req.Flags).
+			WithError(fmt.Errorf("%d of %d surrogate keys are invalid; no keys were purged", invalid, len(req.Keys)), "invalid_surrogate_key").
+			WithInstructions("The batch was not purged. Check output_json.results for the invalid keys.", []string{
+				"Fix or remove the keys with error_code 'invalid_surrogate_key'",
+				"Retry the purge with the corrected batch",
+			}).
+			Build()
+		response.OutputJSON = types.PurgeBatchResult{Failed: len(req.Keys), Results: results}
+		return response
+	}
+
+	// One confirmation covers every key of the batch
+	if isDangerous, _ := reviewRequirement(req.Command, gateArgs); isDangerous && !hasFlag(req.Flags, "user-reviewed") {
+		keys := make([]string, len(valid))
+		for n, i := range valid {
+			keys[n] = req.Keys[i]
+		}
+		response := UserConfirmationError(req.Command, req.Args, req.Flags)
+		response.Instructions = fmt.Sprintf("⚠️ DANGEROUS OPERATION: This operation invalidates cached content for %d surrogate keys\n\nThis command modifies or deletes resources and requires explicit confirmation from the human user. You must ask the human user to review and approve this command before proceeding.", len(keys))
+		response.NextSteps = []string{
+			"Ask the human user to review this purge of surrogate keys: " + strings.Join(keys, ", "),
+			"Wait for the human user to explicitly confirm they want to proceed",
+			"Only after receiving human confirmation, retry with {\"name\":\"user-reviewed\"} in the flags array",
+			"Do NOT proceed without explicit human approval",
+		}
+		response.Metadata = GetOperationMetadata(req.Command, req.Args)
+		if requiresConfirmationToken(ctx, req.Command, req.Args) {
+			response.ConfirmationToken = issueConfirmationToken(purgeBatchSubject(req))
+			response.NextSteps[2] = fmt.Sprintf("Only after receiving human confirmation, retry with {\"name\":\"user-reviewed\"} and {\"name\":\"%s\",\"value\":\"%s\"} in the flags array", confirmationTokenFlag, response.ConfirmationToken)
+		}
+		return response
+	}
+
+	// In safe mode one token covers every key of the batch. It is used up
+	// once the keys file is written and the purge is about to run.
+	var confirmedSubject string
+	token := flagValue(req.Flags, confirmationTokenFlag)
+	if requiresConfirmationToken(ctx, req.Command, req.Args) {
+		confirmedSubject = purgeBatchSubject(req)
+		if !checkConfirmationToken(token, confirmedSubject) {
+			return ConfirmationTokenError(req.Command, req.Args, withoutConfirmationFlags(req.Flags), issueConfirmationToken(confirmedSubject))
+		}
+		ctx = withConfirmedBatch(ctx)
+	}
+
+	keys := make([]string, len(valid))
+	for n, i := range valid {
+		keys[n] = req.Keys[i]
+	}
+	keysFile, err := writePurgeKeysFile(keys)
+	if err != nil {
+		return NewResponseBuilder().
+			WithCommand(req.Command, req.Args, req.Flags).
+			WithError(fmt.Errorf("failed to write the surrogate keys file: %w", err), "system_execution_error").
+			WithInstructions("A temporary file for the surrogate keys could not be written.", []string{
+				"Check that the system temporary directory is writable",
+				"Retry the purge",
+			}).
+			Build()
+	}
+	defer func() { _ = os.Remove(keysFile) }()
+
+	if confirmedSubject != "" && !consumeConfirmationToken(token, confirmedSubject) {
+		return ConfirmationTokenError(req.Command, req.Args, withoutConfirmationFlags(req.Flags), issueConfirmationToken(confirmedSubject))
+	}
+
+	// One CLI call purges every valid key with a single bulk purge request
+	flags := append(append([]types.Flag{}, req.Flags...), types.Flag{Name: "file", Value: keysFile})
+	if !hasFlag(flags, "json") {
+		flags = append(flags, types.Flag{Name: "json"})
+	}
+	batchResponse := ExecuteCommandContext(ctx, types.CommandRequest{
+		Command:        req.Command,
+		Flags:          flags,
+		TimeoutSeconds: req.TimeoutSeconds,
+	})
+	purgeIDs := parsePurgeKeysOutput(batchResponse)
+	for _, i := range valid {
+		switch {
+		case !batchResponse.Success:
+			results[i].Error = batchResponse.Error
+			results[i].ErrorCode = batchResponse.ErrorCode
+		case purgeIDs == nil:
+			// The purge succeeded but its output has no per-key IDs
+			results[i].Success = true
+		case purgeIDs[req.Keys[i]] != "":
+			results[i].Success = true
+			results[i].Output = purgeIDs[req.Keys[i]]
+		default:
+			results[i].Error = "the key is missing from the purge output"
+			results[i].ErrorCode = "purge_key_missing"
+		}
+	}
+
+	batch := types.PurgeBatchResult{Results: results}
+	for _, result := range results {
+		if result.Success {
+			batch.Purged++
+		} else {
+			batch.Failed++
+		}
+	}
+
+	response := types.CommandResponse{
+		Success:     batch.Failed == 0,
+		Command:     req.Command,
+		CommandLine: fmt.Sprintf("%s --file <file of %d keys>", BuildCommandLine(req.Command, nil, withoutFlag(req.Flags, "user-reviewed")), len(valid)),
+		OutputJSON:  batch,
+		Metadata:    GetOperationMetadata(req.Command, req.Args),
+	}
+	if batch.Failed == 0 {
+		response.Instructions = fmt.Sprintf("Purged %d surrogate keys. Per-key results are in output_json.results.", batch.Purged)
+		response.NextSteps = []string{
+			"Check output_json.results for the purge ID of each key",
+			"Verify the content is refreshed by requesting it again",
+		}
+		return response
+	}
+
+	response.Error = fmt.Sprintf("%d of %d surrogate keys were not purged", batch.Failed, len(req.Keys))
+	response.ErrorCode = "purge_batch_incomplete"
+	response.Instructions = fmt.Sprintf("Purged %d of %d surrogate keys. Check output_json.results for the keys that failed.", batch.Purged, len(req.Keys))
+	response.NextSteps = []string{
+		"Fix or remove the keys with error_code 'invalid_surrogate_key'",
+		"Retry only the keys that failed; purging a key again is harmless",
+	}
+	return response
+}
+
+// writePurgeKeysFile writes surrogate keys, one per line, to a private
+// temporary file for 'fastly purge --file' and returns its path.
+func writePurgeKeysFile(keys []string) (string, error) {
+	file, err := os.CreateTemp("", "fastly-mcp-purge-keys-*")
+	if err != nil {
+		return "", err
+	}
+	_, writeErr := file.WriteString(strings.Join(keys, "\n") + "\n")
+	closeErr := file.Close()
+	if writeErr != nil || closeErr != nil {
+		_ = os.Remove(file.Name())
+		if writeErr != nil {
+			return "", writeErr
+		}
+		return "", closeErr
+	}
+	return file.Name(), nil
+}
+
+// parsePurgeKeysOutput returns the purge ID of each key from the output of
+// 'fastly purge --file --json', an object mapping every purged key to its
+// purge ID, or nil when the output is not such an object.
+func parsePurgeKeysOutput(response types.CommandResponse) map[string]string {
+	var purgeIDs map[string]string
+	if data, err := json.Marshal(response.OutputJSON); err == nil && response.OutputJSON != nil {
+		if json.Unmarshal(data, &purgeIDs) == nil {
+			return purgeIDs
+		}
+	}
+	if json.Unmarshal([]byte(strings.TrimSpace(response.Output)), &purgeIDs) == nil {
+		return purgeIDs
+	}
+	return nil
+}
+
+// withoutFlag returns flags with every flag of the given name removed.
+func withoutFlag(flags []types.Flag, name string) []types.Flag {
+	var filtered []types.Flag
+	for _, flag := range flags {
+		if flag.Name != name {
+			filtered = append(filtered, flag)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,206 @@
+package fastly
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func purgeBatchRequest(keys ...string) types.CommandRequest {
+	return types.CommandRequest{
+		Command: "purge",
+		Flags: []types.Flag{
+			{Name: "service-id", Value: "SU1Z0isxPaozGVKXdv0eY"},
+			{Name: "user-reviewed"},
+		},
+		Keys: keys,
+	}
+}
+
+// purgeKeysScript answers 'fastly purge --file --json' like the Fastly CLI,
+// with the purge ID of every key in the file, except keys named skip-me.
+const purgeKeysScript = `file=
+prev=
+for arg in "$@"; do
+	[ "$prev" = "--file" ] && file="$arg"
+	prev="$arg"
+done
+printf '{'
+sep=
+while read -r key; do
+	[ "$key" = skip-me ] && continue
+	printf '%s"%s":"purge-%s"' "$sep" "$key" "$key"
+	sep=,
+done < "$file"
+echo '}'`
+
+func TestPurgeBatchValidKeys(t *testing.T) {
+	callsFile := installMockFastly(t, purgeKeysScript)
+
+	result := ExecuteCommand(purgeBatchRequest("product-1", "product-2", "category/shoes"))
+	if !result.Success {
+		t.Fatalf("Expected the batch to succeed, got %+v", result)
+	}
+
+	batch, ok := result.OutputJSON.(types.PurgeBatchResult)
+	if !ok {
+		t.Fatalf("Expected a PurgeBatchResult, got %T", result.OutputJSON)
+	}
+	if batch.Purged != 3 || batch.Failed != 0 || len(batch.Results) != 3 {
+		t.Errorf("Expected 3 purged keys, got %+v", batch)
+	}
+	if batch.Results[2].Key != "category/shoes" || !batch.Results[2].Success || batch.Results[2].Output != "purge-category/shoes" {
+		t.Errorf("Expected results with purge IDs in request order, got %+v", batch.Results)
+	}
+
+	calls, err := os.ReadFile(callsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(calls)), "\n")
+	if len(lines) != 1 || !strings.HasPrefix(lines[0], "purge --service-id SU1Z0isxPaozGVKXdv0eY --file ") || !strings.Contains(lines[0], "--json") {
+		t.Fatalf("Expected a single purge --file call, got %q", calls)
+	}
+	if strings.Contains(string(calls), "user-reviewed") {
+		t.Errorf("Expected --user-reviewed to be stripped, got %q", calls)
+	}
+	keysFile := strings.Fields(lines[0])[4]
+	if _, err := os.Stat(keysFile); !os.IsNotExist(err) {
+		t.Errorf("Expected the keys file to be removed, got %v", err)
+	}
+}
+
+func TestPurgeBatchKeyMissingFromOutput(t *testing.T) {
+	installMockFastly(t, purgeKeysScript)
+
+	result := ExecuteCommand(purgeBatchRequest("product-1", "skip-me"))
+	if result.Success || result.ErrorCode != "purge_batch_incomplete" {
+		t.Fatalf("Expected purge_batch_incomplete, got %+v", result)
+	}
+	batch := result.OutputJSON.(types.PurgeBatchResult)
+	if !batch.Results[0].Success || batch.Results[1].Success || batch.Results[1].ErrorCode != "purge_key_missing" {
+		t.Errorf("Expected only the key missing from the output to fail, got %+v", batch.Results)
+	}
+}
+
+func TestPurgeBatchCommandFailure(t *testing.T) {
+	installMockFastly(t, `echo "Error: service not found" >&2; exit 1`)
+
+	result := ExecuteCommand(purgeBatchRequest("product-1", "product-2"))
+	batch := result.OutputJSON.(types.PurgeBatchResult)
+	if result.Success || batch.Failed != 2 {
+		t.Fatalf("Expected every key to fail, got %+v", result)
+	}
+	if !strings.Contains(batch.Results[1].Error, "service not found") {
+		t.Errorf("Expected the error of the purge for each key, got %+v", batch.Results[1])
+	}
+}
+
+func TestPurgeBatchInvalidKeyDoesNotAbort(t *testing.T) {
+	callsFile := installMockFastly(t, purgeKeysScript)
+
+	result := ExecuteCommand(purgeBatchRequest("product-1", "bad key", "product-2"))
+	if result.Success || result.ErrorCode != "purge_batch_incomplete" {
+		t.Fatalf("Expected purge_batch_incomplete, got %+v", result)
+	}
+
+	batch := result.OutputJSON.(types.PurgeBatchResult)
+	if batch.Purged != 2 || batch.Failed != 1 {
+		t.Errorf("Expected 2 purged and 1 failed, got %+v", batch)
+	}
+	if batch.Results[1].Success || batch.Results[1].ErrorCode != "invalid_surrogate_key" {
+		t.Errorf("Expected the invalid key to be reported, got %+v", batch.Results[1])
+	}
+
+	calls, err := os.ReadFile(callsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(calls), "purge "); n != 1 {
+		t.Errorf("Expected one CLI call for the valid keys, got %d: %q", n, calls)
+	}
+}
+
+func TestPurgeBatchStrictRejectsWholeBatch(t *testing.T) {
+	callsFile := installMockFastly(t, `echo '{"status":"ok"}'`)
+
+	SetStrictPurgeBatch(true)
+	defer SetStrictPurgeBatch(false)
+
+	result := ExecuteCommand(purgeBatchRequest("product-1", "bad;key"))
+	if result.Success || result.ErrorCode != "invalid_surrogate_key" {
+		t.Fatalf("Expected invalid_surrogate_key, got %+v", result)
+	}
+
+	batch := result.OutputJSON.(types.PurgeBatchResult)
+	if batch.Purged != 0 || batch.Results[0].ErrorCode != "batch_rejected" {
+		t.Errorf("Expected no key to be purged, got %+v", batch)
+	}
+	if _, err := os.ReadFile(callsFile); err == nil {
+		t.Error("Expected the CLI not to be invoked for a rejected batch")
+	}
+}
+
+func TestPurgeBatchConfirmationRequiredOnce(t *testing.T) {
+	callsFile := installMockFastly(t, `echo '{"status":"ok"}'`)
+
+	req := purgeBatchRequest("product-1", "product-2")
+	req.Flags = req.Flags[:1]
+
+	result := ExecuteCommand(req)
+	if result.Success || result.ErrorCode != "user_confirmation_required" {
+		t.Fatalf("Expected user_confirmation_required, got %+v", result)
+	}
+	if !strings.Contains(strings.Join(result.NextSteps, "\n"), "product-1, product-2") {
+		t.Errorf("Expected every key in the review step, got %v", result.NextSteps)
+	}
+	if _, err := os.ReadFile(callsFile); err == nil {
+		t.Error("Expected the CLI not to be invoked before confirmation")
+	}
+}
+
+func TestPurgeBatchFollowsDangerPolicy(t *testing.T) {
+	SetDangerPolicy(map[string]string{"purge": DangerPolicyAllow})
+	defer SetDangerPolicy(nil)
+	callsFile := installMockFastly(t, purgeKeysScript)
+
+	req := purgeBatchRequest("product-1", "product-2")
+	req.Flags = req.Flags[:1]
+
+	// A batch needs no review when a single purge needs none
+	single := ExecuteCommand(types.CommandRequest{Command: "purge", Flags: []types.Flag{{Name: "service-id", Value: "SU1Z0isxPaozGVKXdv0eY"}, {Name: "key", Value: "product-1"}}})
+	result := ExecuteCommand(req)
+	if !single.Success || !result.Success {
+		t.Fatalf("Expected the allowed purges to run without review, got %+v and %+v", single, result)
+	}
+	if batch, ok := result.OutputJSON.(types.PurgeBatchResult); !ok || batch.Purged != 2 {
+		t.Errorf("Expected 2 purged keys, got %+v", result.OutputJSON)
+	}
+	if calls := countCalls(t, callsFile); calls != 2 {
+		t.Errorf("Expected one CLI call for each purge, got %d", calls)
+	}
+}
+
+func TestPurgeBatchRejectsInvalidRequests(t *testing.T) {
+	installMockFastly(t, `echo '{"status":"ok"}'`)
+
+	tests := []struct {
+		name string
+		req  types.CommandRequest
+	}{
+		{"other command", types.CommandRequest{Command: "service", Args: []string{"list"}, Keys: []string{"a"}}},
+		{"conflicting flag", types.CommandRequest{Command: "purge", Flags: []types.Flag{{Name: "all"}}, Keys: []string{"a"}}},
+		{"too many keys", types.CommandRequest{Command: "purge", Keys: make([]string, MaxPurgeBatchKeys+1)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ExecuteCommand(tt.req)
+			if result.Success || result.ErrorCode != "invalid_purge_batch" {
+				t.Errorf("Expected invalid_purge_batch, got %+v", result)
+			}
+		})
+	}
+}
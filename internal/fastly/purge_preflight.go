@@ -0,0 +1,161 @@
+package fastly
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+// purgeImpactWindow is how far back the traffic of a service is summed for a
+// purge-all preflight.
+const purgeImpactWindow = 24 * time.Hour
+
+// maxPurgeImpactDomains bounds the domains named in the review instructions.
+// The full list is in the purge_impact field.
+const maxPurgeImpactDomains = 10
+
+// globalPurgeAllPreflight controls whether the confirmation request of a purge
+// of all cached content reports the affected domains and traffic. It can be
+// configured via SetPurgeAllPreflight().
+var globalPurgeAllPreflight = false
+
+// SetPurgeAllPreflight enables or disables the purge-all preflight. When
+// enabled, asking for review of 'purge --all' first looks up the service's
+// active domains and recent traffic.
+func SetPurgeAllPreflight(enabled bool) {
+	globalPurgeAllPreflight = enabled
+}
+
+// isPurgeAll reports whether a request purges all cached content of a service.
+func isPurgeAll(command string, flags []types.Flag) bool {
+	return command == "purge" && hasFlag(flags, "all")
+}
+
+// purgeAllImpact looks up what a purge of all content of the service in flags
+// affects. Lookups that fail are recorded as notes and never block the review.
+func purgeAllImpact(ctx context.Context, flags []types.Flag) *types.PurgeImpact {
+	serviceID := flagValue(flags, "service-id")
+	if serviceID == "" {
+		return &types.PurgeImpact{Notes: []string{"No --service-id was given, so the affected service could not be looked up."}}
+	}
+
+	impact := &types.PurgeImpact{ServiceID: serviceID, Domains: []string{}}
+
+	output, err := runPurgeImpactCommand(ctx, "domain", []string{"list"}, []string{"--service-id", serviceID, "--version", "active", "--json"})
+	if err == nil {
+		impact.Domains, err = parseDomainNames(output)
+	}
+	if err != nil {
+		impact.Notes = append(impact.Notes, "The domains could not be listed: "+err.Error())
+	}
+
+	now := time.Now()
+	output, err = runPurgeImpactCommand(ctx, "stats", []string{"historical"}, []string{
+		"--service-id", serviceID,
+		"--from", strconv.FormatInt(now.Add(-purgeImpactWindow).Unix(), 10),
+		"--to", strconv.FormatInt(now.Unix(), 10),
+		"--by", "hour",
+		"--json",
+	})
+	if err == nil {
+		impact.Traffic, err = sumPurgeImpactTraffic(output)
+	}
+	if err != nil {
+		impact.Notes = append(impact.Notes, "The recent traffic could not be looked up: "+err.Error())
+	}
+
+	return impact
+}
+
+// runPurgeImpactCommand runs one lookup of the purge-all preflight and returns
+// its output.
+func runPurgeImpactCommand(ctx context.Context, command string, args []string, flags []string) (string, error) {
+	cmdArgs := append(append([]string{command}, args...), flags...)
+	if shouldInjectNonInteractive(command, args) {
+		cmdArgs = append(cmdArgs, "--non-interactive")
+	}
+	cmdArgs = append(cmdArgs, ProfileArgs()...)
+
+	result := RunFastlyCommand(CommandRunConfig{
+		Context:            ctx,
+		Command:            "fastly",
+		Args:               cmdArgs,
+		Timeout:            globalCommandTimeout,
+		FirstOutputTimeout: globalFirstOutputTimeout,
+	})
+	if result.Error != nil {
+		return "", fmt.Errorf("%s", GetErrorMessage(result))
+	}
+	return result.Stdout, nil
+}
+
+// parseDomainNames extracts the domain names from 'domain list --json' output.
+func parseDomainNames(output string) ([]string, error) {
+	var domains []map[string]interface{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(output)), &domains); err != nil {
+		return nil, fmt.Errorf("unexpected output: %w", err)
+	}
+
+	names := []string{}
+	for _, domain := range domains {
+		for _, key := range []string{"Name", "name"} {
+			if name, ok := domain[key].(string); ok && name != "" {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	return names, nil
+}
+
+// sumPurgeImpactTraffic totals the requests and bandwidth of 'stats historical
+// --json' output.
+func sumPurgeImpactTraffic(output string) (*types.PurgeTraffic, error) {
+	var data interface{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(output)), &data); err != nil {
+		return nil, fmt.Errorf("unexpected output: %w", err)
+	}
+	metrics, ok := normalizeStatsData(data)
+	if !ok {
+		return nil, fmt.Errorf("unexpected stats output")
+	}
+
+	traffic := &types.PurgeTraffic{Period: purgeImpactWindow.String()}
+	for _, metric := range metrics {
+		switch metric.Metric {
+		case "requests":
+			traffic.Requests += metric.Value
+		case "bandwidth":
+			traffic.BandwidthBytes += metric.Value
+		}
+	}
+	return traffic, nil
+}
+
+// purgeImpactSummary describes the impact for the human reviewer.
+func purgeImpactSummary(impact *types.PurgeImpact) string {
+	if impact.ServiceID == "" {
+		return ""
+	}
+
+	var parts []string
+	switch {
+	case len(impact.Domains) > maxPurgeImpactDomains:
+		parts = append(parts, fmt.Sprintf("%d domains (%s and %d more)", len(impact.Domains), strings.Join(impact.Domains[:maxPurgeImpactDomains], ", "), len(impact.Domains)-maxPurgeImpactDomains))
+	case len(impact.Domains) > 0:
+		parts = append(parts, fmt.Sprintf("%d domains (%s)", len(impact.Domains), strings.Join(impact.Domains, ", ")))
+	}
+	if impact.Traffic != nil {
+		parts = append(parts, fmt.Sprintf("%.0f requests and %.0f bytes in the last %s", impact.Traffic.Requests, impact.Traffic.BandwidthBytes, impact.Traffic.Period))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("Blast radius: purging all content of service %s affects %s. Until the cache refills, the origins receive this traffic directly.", impact.ServiceID, strings.Join(parts, " serving "))
+}
@@ -0,0 +1,91 @@
+package fastly
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestPurgeAllPreflightReportsImpact(t *testing.T) {
+	callsFile := installMockFastly(t, `case "$1" in
+domain) echo '[{"Name":"www.example.com"},{"Name":"api.example.com"}]';;
+stats) echo '{"Data":[{"start_time":1700000000,"requests":1200,"bandwidth":5000},{"start_time":1700003600,"requests":800,"bandwidth":3000}]}';;
+*) echo "ok";;
+esac`)
+
+	SetPurgeAllPreflight(true)
+	defer SetPurgeAllPreflight(false)
+
+	result := ExecuteCommand(types.CommandRequest{
+		Command: "purge",
+		Flags:   []types.Flag{{Name: "service-id", Value: "abc123"}, {Name: "all"}},
+	})
+	if result.Success || result.PurgeImpact == nil {
+		t.Fatalf("Expected a review request with the purge impact, got %+v", result)
+	}
+
+	impact := result.PurgeImpact
+	if impact.ServiceID != "abc123" || strings.Join(impact.Domains, ",") != "www.example.com,api.example.com" {
+		t.Errorf("Expected the service's domains, got %+v", impact)
+	}
+	if impact.Traffic == nil || impact.Traffic.Requests != 2000 || impact.Traffic.BandwidthBytes != 8000 {
+		t.Errorf("Expected the summed traffic, got %+v", impact.Traffic)
+	}
+	if !strings.Contains(result.Instructions, "www.example.com, api.example.com") || !strings.Contains(result.Instructions, "2000 requests") {
+		t.Errorf("Expected the instructions to name the blast radius, got %q", result.Instructions)
+	}
+
+	calls, _ := os.ReadFile(callsFile)
+	if strings.Contains(string(calls), "purge") {
+		t.Errorf("Expected the purge itself not to run before review, got %q", calls)
+	}
+}
+
+func TestPurgeAllPreflightFailureIsNoted(t *testing.T) {
+	installMockFastly(t, `echo "service not found" >&2; exit 1`)
+
+	SetPurgeAllPreflight(true)
+	defer SetPurgeAllPreflight(false)
+
+	result := ExecuteCommand(types.CommandRequest{
+		Command: "purge",
+		Flags:   []types.Flag{{Name: "service-id", Value: "abc123"}, {Name: "all"}},
+	})
+	if result.PurgeImpact == nil || len(result.PurgeImpact.Notes) != 2 {
+		t.Fatalf("Expected both failed lookups to be noted, got %+v", result.PurgeImpact)
+	}
+	if result.ErrorCode != "user_confirmation_required" {
+		t.Errorf("Expected the review request to be returned regardless, got %q", result.ErrorCode)
+	}
+}
+
+func TestPurgeAllPreflightSkipped(t *testing.T) {
+	callsFile := installMockFastly(t, `echo "ok"`)
+
+	tests := []struct {
+		name    string
+		enabled bool
+		flags   []types.Flag
+	}{
+		{"disabled", false, []types.Flag{{Name: "service-id", Value: "abc123"}, {Name: "all"}}},
+		{"single url", true, []types.Flag{{Name: "service-id", Value: "abc123"}, {Name: "url", Value: "https://www.example.com/"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetPurgeAllPreflight(tt.enabled)
+			defer SetPurgeAllPreflight(false)
+
+			result := ExecuteCommand(types.CommandRequest{Command: "purge", Flags: tt.flags})
+			if result.PurgeImpact != nil {
+				t.Errorf("Expected no purge impact, got %+v", result.PurgeImpact)
+			}
+		})
+	}
+
+	if _, err := os.ReadFile(callsFile); err == nil {
+		t.Error("Expected no lookup to invoke the CLI")
+	}
+}
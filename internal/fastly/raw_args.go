@@ -0,0 +1,33 @@
+package fastly
+
+import (
+	"github.com/fastly/mcp/internal/types"
+	"github.com/fastly/mcp/internal/validation"
+)
+
+// globalRawArgsEnabled controls whether requests may pass raw extra arguments
+// after a "--" separator. It can be configured via SetRawArgsEnabled().
+var globalRawArgsEnabled = false
+
+// SetRawArgsEnabled enables or disables raw_args. When enabled, the raw
+// arguments of a request are appended to the command line after "--", so the
+// CLI receives them untouched by the wrapper's flag handling.
+func SetRawArgsEnabled(enabled bool) {
+	globalRawArgsEnabled = enabled
+}
+
+// validateRawArgs checks each raw argument with the same length and shell
+// metacharacter rules as regular arguments.
+func validateRawArgs(validator *validation.Validator, rawArgs []string) error {
+	return validator.ValidateAllInputs(rawArgs, validation.MaxArgLength, "raw argument", true)
+}
+
+// rawGateArgs returns the arguments of a request followed by its raw
+// arguments. The CLI resolves subcommands from positional tokens even after
+// "--", so checks that decide by command path must see the raw arguments too.
+func rawGateArgs(req types.CommandRequest) []string {
+	if len(req.RawArgs) == 0 {
+		return req.Args
+	}
+	return append(append([]string{}, req.Args...), req.RawArgs...)
+}
@@ -0,0 +1,101 @@
+package fastly
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+	"github.com/fastly/mcp/internal/validation"
+)
+
+func TestRawArgsAfterSeparator(t *testing.T) {
+	callsFile := installMockFastly(t, `echo '[]'`)
+
+	SetRawArgsEnabled(true)
+	defer SetRawArgsEnabled(false)
+
+	result := ExecuteCommand(types.CommandRequest{
+		Command: "service",
+		Args:    []string{"list"},
+		Flags:   []types.Flag{{Name: "json"}},
+		RawArgs: []string{"--experimental-filter", "name=prod"},
+	})
+	if !result.Success {
+		t.Fatalf("Expected success, got %+v", result)
+	}
+
+	calls, err := os.ReadFile(callsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(strings.TrimSpace(string(calls)), "-- --experimental-filter name=prod") {
+		t.Errorf("Expected raw args after '--' at the end of the call, got %q", calls)
+	}
+}
+
+func TestRawArgsRejectedWhenDisabled(t *testing.T) {
+	callsFile := installMockFastly(t, `echo '[]'`)
+
+	result := ExecuteCommand(types.CommandRequest{
+		Command: "service",
+		Args:    []string{"list"},
+		RawArgs: []string{"--experimental-filter"},
+	})
+	if result.Success || result.ErrorCode != "raw_args_disabled" {
+		t.Errorf("Expected error code 'raw_args_disabled', got %+v", result)
+	}
+	if _, err := os.Stat(callsFile); !os.IsNotExist(err) {
+		t.Error("Expected the CLI not to be run")
+	}
+}
+
+func TestRawArgsValidated(t *testing.T) {
+	callsFile := installMockFastly(t, `echo '[]'`)
+
+	SetRawArgsEnabled(true)
+	defer SetRawArgsEnabled(false)
+
+	result := ExecuteCommand(types.CommandRequest{
+		Command: "service",
+		Args:    []string{"list"},
+		RawArgs: []string{"ok", "$(whoami)"},
+	})
+	if result.Success || result.ErrorCode != "validation_error" {
+		t.Errorf("Expected error code 'validation_error', got %+v", result)
+	}
+	if _, err := os.Stat(callsFile); !os.IsNotExist(err) {
+		t.Error("Expected the CLI not to be run")
+	}
+}
+
+func TestRawArgsGated(t *testing.T) {
+	callsFile := installMockFastly(t, `echo '[]'`)
+
+	SetRawArgsEnabled(true)
+	defer SetRawArgsEnabled(false)
+
+	// A subcommand passed as a raw argument still needs review
+	req := types.CommandRequest{Command: "service", RawArgs: []string{"delete"}, Flags: []types.Flag{{Name: "service-id", Value: "abc123"}}}
+	if result := ExecuteCommand(req); result.ErrorCode != "user_confirmation_required" {
+		t.Errorf("Expected raw 'delete' to require review, got %+v", result)
+	}
+
+	// and is denied like a regular argument
+	denied := validation.NewValidatorWithCommandsAndDenied(validation.DefaultAllowedCommands(), map[string]bool{"service delete": true})
+	req.Flags = append(req.Flags, types.Flag{Name: "user-reviewed"})
+	if result := ExecuteCommandContext(WithValidator(context.Background(), denied), req); result.ErrorCode != "COMMAND_NOT_AVAILABLE" {
+		t.Errorf("Expected raw 'delete' to be denied, got %+v", result)
+	}
+
+	SetReadOnly(true)
+	defer SetReadOnly(false)
+	if result := ExecuteCommand(req); result.ErrorCode != "read_only_mode" {
+		t.Errorf("Expected raw 'delete' to be blocked in read-only mode, got %+v", result)
+	}
+
+	if n := countCalls(t, callsFile); n != 0 {
+		t.Errorf("Expected no command to reach the CLI, ran %d times", n)
+	}
+}
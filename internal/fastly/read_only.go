@@ -0,0 +1,54 @@
+package fastly
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+// globalReadOnly blocks every command that is not known to only read data,
+// however it is reviewed. It can be configured via SetReadOnly().
+var globalReadOnly = false
+
+// SetReadOnly enables or disables read-only mode, in which mutating commands
+// are rejected before they run.
+func SetReadOnly(enabled bool) {
+	globalReadOnly = enabled
+}
+
+// isReadOnlyBlocked reports whether read-only mode rejects a command. Only
+// operations known to be reads, and the commands whose subcommands only read
+// data, are allowed; anything else, including every operation
+// IsDangerousOperation classifies as mutating or destructive, is treated as
+// mutating, so a command missing from the classification is blocked rather
+// than run. A read such as 'secret-store list' stays available even though its
+// keywords give it a severity.
+func isReadOnlyBlocked(command string, args []string) bool {
+	if !globalReadOnly || safeModeReadCommands[command] {
+		return false
+	}
+	_, isSafe := GetOperationType(command, args)
+	return !isSafe
+}
+
+// ReadOnlyModeError creates the response for a command rejected because the
+// server runs in read-only mode
+func ReadOnlyModeError(command string, args []string, flags []types.Flag) types.CommandResponse {
+	cmdStr := command
+	if len(args) > 0 {
+		cmdStr += " " + strings.Join(args, " ")
+	}
+
+	response := NewResponseBuilder().
+		WithCommand(command, args, flags).
+		WithError(fmt.Errorf("the '%s' command may modify resources and the server runs in read-only mode", cmdStr), "read_only_mode").
+		WithInstructions("This server only runs commands that read data. Mutating commands are rejected even when the user has reviewed them.", []string{
+			"Use list, describe or get commands to inspect resources instead",
+			"Ask the human user to run this command directly in a terminal if the change is needed",
+			"Do NOT retry this command with --user-reviewed; read-only mode cannot be bypassed",
+		}).
+		Build()
+	response.Metadata = GetOperationMetadata(command, args)
+	return response
+}
@@ -0,0 +1,101 @@
+package fastly
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestReadOnlyMode(t *testing.T) {
+	callsFile := installMockFastly(t, `echo '[]'`)
+
+	SetReadOnly(true)
+	defer SetReadOnly(false)
+
+	if result := ExecuteCommand(types.CommandRequest{Command: "service", Args: []string{"list"}}); !result.Success {
+		t.Fatalf("Expected service list to run in read-only mode, got %+v", result)
+	}
+
+	// Review does not bypass read-only mode
+	result := ExecuteCommand(types.CommandRequest{
+		Command: "service",
+		Args:    []string{"delete"},
+		Flags:   []types.Flag{{Name: "service-id", Value: "abc123"}, {Name: "user-reviewed"}},
+	})
+	if result.Success || result.ErrorCode != "read_only_mode" {
+		t.Errorf("Expected service delete to fail with read_only_mode, got %+v", result)
+	}
+	if n := countCalls(t, callsFile); n != 1 {
+		t.Errorf("Expected only service list to reach the CLI, ran %d times", n)
+	}
+}
+
+func TestIsReadOnlyBlocked(t *testing.T) {
+	SetReadOnly(true)
+	defer SetReadOnly(false)
+
+	tests := []struct {
+		command string
+		args    []string
+		blocked bool
+	}{
+		{"service", []string{"list"}, false},
+		{"backend", []string{"describe"}, false},
+		{"secret-store", []string{"list"}, false},
+		{"stats", []string{"historical"}, false},
+		{"whoami", nil, false},
+		{"service", []string{"delete"}, true},
+		{"backend", []string{"create"}, true},
+		{"purge", nil, true},
+		{"service-version", []string{"activate"}, true},
+		{"compute", []string{"deploy"}, true},
+	}
+
+	for _, tt := range tests {
+		if got := isReadOnlyBlocked(tt.command, tt.args); got != tt.blocked {
+			t.Errorf("isReadOnlyBlocked(%q, %v) = %v, want %v", tt.command, tt.args, got, tt.blocked)
+		}
+	}
+
+	SetReadOnly(false)
+	if isReadOnlyBlocked("service", []string{"delete"}) {
+		t.Error("Expected nothing to be blocked outside read-only mode")
+	}
+}
+
+func TestReadOnlyModeDescribe(t *testing.T) {
+	SetReadOnly(true)
+	defer SetReadOnly(false)
+
+	helpText := `USAGE
+  fastly service delete [FLAGS]
+
+Delete a Fastly service
+
+OPTIONAL FLAGS
+  --service-id  Service ID`
+
+	info := parseHelpOutput("service delete", helpText)
+	if !strings.Contains(info.Description, "Unavailable in read-only mode") || !strings.Contains(info.Instructions, "read-only mode") {
+		t.Errorf("Expected service delete to be marked unavailable, got %q / %q", info.Description, info.Instructions)
+	}
+
+	info = parseHelpOutput("service list", strings.ReplaceAll(helpText, "delete", "list"))
+	if strings.Contains(info.Description, "read-only mode") {
+		t.Errorf("Expected service list to stay available, got %q", info.Description)
+	}
+}
+
+func TestReadOnlyModeBatch(t *testing.T) {
+	SetReadOnly(true)
+	defer SetReadOnly(false)
+
+	result := PlanBatch([]types.CommandRequest{
+		{Command: "service", Args: []string{"list"}},
+		{Command: "service", Args: []string{"delete"}, Flags: []types.Flag{{Name: "service-id", Value: "abc123"}}},
+	}, "signature")
+	if result.Success || !strings.Contains(result.Error, "step 1") || !strings.Contains(result.Error, "read-only mode") {
+		t.Errorf("Expected the batch to be refused at step 1, got %+v", result)
+	}
+}
@@ -4,6 +4,8 @@ package fastly
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/fastly/mcp/internal/types"
 )
@@ -90,15 +92,141 @@ func UserConfirmationError(command string, args []string, flags []types.Flag) ty
 		Build()
 }
 
+// ConfirmationTokenError creates an error response for a destructive command
+// run in safe mode without a valid confirmation token. It carries a new token
+// for the same command line.
+func ConfirmationTokenError(command string, args []string, flags []types.Flag, token string) types.CommandResponse {
+	response := NewResponseBuilder().
+		WithCommand(command, args, flags).
+		WithError(fmt.Errorf("safe mode requires a valid confirmation token for destructive operations"), "confirmation_token_required").
+		WithInstructions(fmt.Sprintf("The confirmation token is missing, expired, already used or was issued for a different command. Tokens are valid once, for %s, and only for the exact command line they were issued for.", ConfirmationTokenTTL), []string{
+			"Ask the human user to review this command again: " + BuildCommandLine(command, args, flags),
+			fmt.Sprintf("Only after receiving human confirmation, retry with {\"name\":\"user-reviewed\"} and {\"name\":\"%s\",\"value\":\"%s\"} in the flags array", confirmationTokenFlag, token),
+			"Do NOT change the command when retrying; the token is bound to it",
+		}).
+		Build()
+	response.ConfirmationToken = token
+	return response
+}
+
+// CancelledError creates an error response for a command that was stopped
+// because the caller cancelled the request
+func CancelledError(command string, args []string, flags []types.Flag) types.CommandResponse {
+	return NewResponseBuilder().
+		WithCommand(command, args, flags).
+		WithError(fmt.Errorf("command was cancelled before it completed"), "cancelled").
+		WithInstructions("The request was cancelled and the command was stopped. Changes made before it was stopped may have been applied.", []string{
+			"Check the current state of the affected resources before retrying",
+			"Run the command again if it is still needed",
+		}).
+		Build()
+}
+
+// SelfManagementError creates an error response for a blocked self-management
+// command (install, update)
+func SelfManagementError(command string, args []string, flags []types.Flag) types.CommandResponse {
+	return NewResponseBuilder().
+		WithCommand(command, args, flags).
+		WithError(fmt.Errorf("the '%s' command modifies the Fastly CLI installation and is disabled", command), "self_management_disabled").
+		WithInstructions("Self-management commands replace or modify the Fastly CLI binary and are not permitted through MCP by default.", []string{
+			"Ask the human user to run this command directly in a terminal",
+			"Or restart the MCP server with --allow-self-management to permit it (with --unsafe, as safe mode denies these commands)",
+		}).
+		Build()
+}
+
+// LoggingProviderError creates an error response for a logging provider that
+// is not in the configured allowlist
+func LoggingProviderError(command string, args []string, flags []types.Flag) types.CommandResponse {
+	return NewResponseBuilder().
+		WithCommand(command, args, flags).
+		WithError(fmt.Errorf("the '%s' logging provider is not allowed on this server", args[0]), "logging_provider_not_allowed").
+		WithInstructions("This server only permits approved logging providers: "+strings.Join(allowedLoggingProviders(), ", ")+".", []string{
+			"Use one of the allowed logging providers instead",
+			"Or ask the human user to add the provider to the server's --logging-providers setting",
+		}).
+		Build()
+}
+
+// StalledError creates an error response for a command that produced no output
+// within the first-output window
+func StalledError(command string, args []string, flags []types.Flag, window time.Duration) types.CommandResponse {
+	return NewResponseBuilder().
+		WithCommand(command, args, flags).
+		WithError(fmt.Errorf("command produced no output within %s and was stopped", window), "stalled").
+		WithInstructions("The command never started responding. It is most likely waiting for interactive input, a browser-based login, or a network connection that cannot be established.", []string{
+			"Check that the active profile is authenticated (run the whoami command)",
+			"Check your network connection to the Fastly API",
+			"If the command legitimately takes a long time before printing anything, run it directly in the CLI",
+		}).
+		Build()
+}
+
 // TimeoutError creates a timeout error response
 func TimeoutError(command string, args []string, flags []types.Flag) types.CommandResponse {
+	return TimeoutErrorAfter(command, args, flags, globalCommandTimeout)
+}
+
+// TimeoutErrorAfter creates a timeout error response for a command that ran
+// into the given timeout
+func TimeoutErrorAfter(command string, args []string, flags []types.Flag, timeout time.Duration) types.CommandResponse {
 	return NewResponseBuilder().
 		WithCommand(command, args, flags).
-		WithError(fmt.Errorf("command execution timed out after 30 seconds"), "timeout").
-		WithInstructions("The command took too long to execute.", []string{
+		WithError(fmt.Errorf("command execution timed out after %d seconds", int(timeout.Seconds())), "timeout").
+		WithInstructions("The command took too long to execute. "+timeoutLimitNote(timeout), []string{
 			"Try running the command with fewer results or a more specific filter",
 			"Check your network connection",
 			"If the problem persists, run the command directly in the CLI",
 		}).
 		Build()
 }
+
+// timeoutLimitNote tells the agent the configured timeouts, so it knows whether
+// a retry with a longer timeout_seconds can succeed.
+func timeoutLimitNote(timeout time.Duration) string {
+	if timeout >= globalMaxCommandTimeout {
+		return fmt.Sprintf("This is the server's maximum timeout of %s; run the command directly in the CLI or narrow it down.", globalMaxCommandTimeout)
+	}
+	return fmt.Sprintf("The server's command timeout is %s; a single call may request up to %s with timeout_seconds.", globalCommandTimeout, globalMaxCommandTimeout)
+}
+
+// RawArgsDisabledError creates an error response for a request with raw_args
+// when the server does not allow them
+func RawArgsDisabledError(command string, args []string, flags []types.Flag) types.CommandResponse {
+	return NewResponseBuilder().
+		WithCommand(command, args, flags).
+		WithError(fmt.Errorf("raw_args are disabled on this server"), "raw_args_disabled").
+		WithInstructions("This server does not accept raw arguments after a '--' separator.", []string{
+			"Express the arguments with args and flags instead",
+			"Or ask the human user to restart the server with --allow-raw-args",
+		}).
+		Build()
+}
+
+// PurgeBatchError creates an error response for a batched surrogate-key purge
+// that cannot be run as requested
+func PurgeBatchError(command string, args []string, flags []types.Flag, err error) types.CommandResponse {
+	return NewResponseBuilder().
+		WithCommand(command, args, flags).
+		WithError(err, "invalid_purge_batch").
+		WithInstructions("The batch of surrogate keys could not be purged as requested.", []string{
+			"Use 'keys' only with the purge command and no subcommands",
+			"Do not combine 'keys' with the key, file, url or all flags",
+			fmt.Sprintf("Split batches larger than %d keys into several calls", MaxPurgeBatchKeys),
+		}).
+		Build()
+}
+
+// ComputeManifestError creates an error response for a compute command whose
+// project directory has no manifest
+func ComputeManifestError(command string, args []string, flags []types.Flag, err error) types.CommandResponse {
+	return NewResponseBuilder().
+		WithCommand(command, args, flags).
+		WithError(err, "compute_manifest_not_found").
+		WithInstructions("The Compute project directory does not contain a "+ComputeManifestFile+" manifest.", []string{
+			"Pass the project directory with {\"name\":\"dir\",\"value\":\"path/to/project\"}",
+			"Or ask the human user to adjust the server's --compute-preset dir setting",
+			"Use fastly_execute with 'compute init' to create a new project",
+		}).
+		Build()
+}
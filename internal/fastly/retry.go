@@ -0,0 +1,117 @@
+package fastly
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// globalMaxRetries is the number of times a read-only command that failed with
+// a transient error is retried; 0 disables retries. It can be configured via
+// SetMaxRetries().
+var globalMaxRetries = 0
+
+// globalRetryBaseDelay is the delay before the first retry. Each further retry
+// waits twice as long as the previous one. It can be configured via
+// SetRetryBaseDelay().
+var globalRetryBaseDelay = 500 * time.Millisecond
+
+// transientStatusPattern matches the HTTP status codes of server errors the
+// Fastly API may recover from.
+var transientStatusPattern = regexp.MustCompile(`\b(?:500|502|503|504)\b`)
+
+// transientErrorPatterns are lowercase fragments of network and server errors
+// that are worth retrying.
+var transientErrorPatterns = []string{
+	"connection reset",
+	"connection refused",
+	"i/o timeout",
+	"tls handshake timeout",
+	"timeout awaiting response headers",
+	"unexpected eof",
+	"too many requests",
+	"internal server error",
+	"bad gateway",
+	"service unavailable",
+	"gateway timeout",
+}
+
+// SetMaxRetries sets how many times a read-only command that failed with a
+// transient error, such as a 5xx response or a connection reset, is retried.
+// Use 0 to disable retries.
+func SetMaxRetries(retries int) {
+	if retries < 0 {
+		retries = 0
+	}
+	globalMaxRetries = retries
+}
+
+// SetRetryBaseDelay sets the delay before the first retry; the delay doubles
+// for each further retry.
+func SetRetryBaseDelay(delay time.Duration) {
+	if delay < 0 {
+		delay = 0
+	}
+	globalRetryBaseDelay = delay
+}
+
+// isRetryableFailure reports whether a failed command hit a transient error.
+// Errors with a specific cause, such as authentication, validation or a
+// missing resource, fail the same way when repeated and are not retried. A
+// command stopped by its own timeout, for stalling or by cancellation is not
+// retried either.
+func isRetryableFailure(result CommandRunResult) bool {
+	if result.Error == nil || result.TimedOut || result.Stalled || result.Cancelled {
+		return false
+	}
+
+	message := result.Stderr
+	if message == "" {
+		message = result.Stdout
+	}
+	message = message + " " + result.Error.Error()
+
+	switch DetectErrorCode(message) {
+	case "rate_limit":
+		return true
+	case "operation_failed":
+		// Unclassified errors are checked for transient causes below
+	default:
+		return false
+	}
+
+	lower := strings.ToLower(message)
+	for _, pattern := range transientErrorPatterns {
+		if strings.Contains(lower, pattern) {
+			return true
+		}
+	}
+	return transientStatusPattern.MatchString(message)
+}
+
+// runWithRetries runs a command, retrying transient failures with exponential
+// backoff up to globalMaxRetries times. Only read-only commands are retried,
+// as a create or delete that failed with a server error may still have been
+// applied. It returns the last result and the number of attempts made.
+func runWithRetries(ctx context.Context, readOnly bool, config CommandRunConfig) (CommandRunResult, int) {
+	result := RunFastlyCommand(config)
+	attempts := 1
+	if !readOnly {
+		return result, attempts
+	}
+
+	delay := globalRetryBaseDelay
+	for attempts <= globalMaxRetries && isRetryableFailure(result) {
+		select {
+		case <-ctx.Done():
+			return result, attempts
+		case <-time.After(delay):
+		}
+		delay *= 2
+
+		result = RunFastlyCommand(config)
+		attempts++
+	}
+	return result, attempts
+}
@@ -0,0 +1,106 @@
+package fastly
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+// failTwiceScript fails the first two calls with the given error and then
+// prints an empty JSON array.
+func failTwiceScript(message string) string {
+	return `if [ "$(wc -l < "$(dirname "$0")/calls")" -le 2 ]; then echo '` + message + `' >&2; exit 1; fi
+echo '[]'`
+}
+
+func TestRetryTransientFailures(t *testing.T) {
+	SetMaxRetries(3)
+	SetRetryBaseDelay(time.Millisecond)
+	defer SetMaxRetries(0)
+	defer SetRetryBaseDelay(500 * time.Millisecond)
+
+	tests := []struct {
+		name     string
+		message  string
+		req      types.CommandRequest
+		success  bool
+		attempts int
+	}{
+		{"server error", "Error: 503 Service Unavailable", types.CommandRequest{Command: "service", Args: []string{"list"}}, true, 3},
+		{"connection reset", "Error: read tcp: connection reset by peer", types.CommandRequest{Command: "service", Args: []string{"list"}}, true, 3},
+		{"rate limit", "Error: 429 Too Many Requests", types.CommandRequest{Command: "service", Args: []string{"list"}}, true, 3},
+		{"not found", "Error: 404 Not Found", types.CommandRequest{Command: "service", Args: []string{"list"}}, false, 1},
+		{"auth", "Error: unauthorized", types.CommandRequest{Command: "service", Args: []string{"list"}}, false, 1},
+		{"mutating command", "Error: 503 Service Unavailable", types.CommandRequest{Command: "backend", Args: []string{"create"}, Flags: []types.Flag{{Name: "service-id", Value: "abc123"}, {Name: "version", Value: "1"}, {Name: "name", Value: "origin"}, {Name: "address", Value: "example.com"}}}, false, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callsFile := installMockFastly(t, failTwiceScript(tt.message))
+
+			result := ExecuteCommand(tt.req)
+			if result.Success != tt.success {
+				t.Fatalf("Expected success = %v, got %+v", tt.success, result)
+			}
+			if result.Metadata == nil || result.Metadata.Attempts != tt.attempts {
+				t.Errorf("Expected %d attempts in the metadata, got %+v", tt.attempts, result.Metadata)
+			}
+
+			calls, err := os.ReadFile(callsFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n := strings.Count(string(calls), "\n"); n != tt.attempts {
+				t.Errorf("Expected the CLI to run %d times, ran %d times", tt.attempts, n)
+			}
+		})
+	}
+}
+
+func TestRetriesExhausted(t *testing.T) {
+	callsFile := installMockFastly(t, `echo 'Error: 502 Bad Gateway' >&2; exit 1`)
+
+	SetMaxRetries(1)
+	SetRetryBaseDelay(time.Millisecond)
+	defer SetMaxRetries(0)
+	defer SetRetryBaseDelay(500 * time.Millisecond)
+
+	result := ExecuteCommand(types.CommandRequest{Command: "service", Args: []string{"list"}})
+	if result.Success || result.Metadata.Attempts != 2 {
+		t.Errorf("Expected a failure after 2 attempts, got %+v", result)
+	}
+	calls, _ := os.ReadFile(callsFile)
+	if n := strings.Count(string(calls), "\n"); n != 2 {
+		t.Errorf("Expected the CLI to run twice, ran %d times", n)
+	}
+}
+
+func TestIsRetryableFailure(t *testing.T) {
+	failed := errors.New("exit status 1")
+
+	tests := []struct {
+		name      string
+		result    CommandRunResult
+		retryable bool
+	}{
+		{"success", CommandRunResult{}, false},
+		{"gateway timeout", CommandRunResult{Stderr: "Error: 504 Gateway Timeout", Error: failed}, true},
+		{"network timeout", CommandRunResult{Stderr: "dial tcp: i/o timeout", Error: failed}, true},
+		{"validation", CommandRunResult{Stderr: "Error: invalid value for --version", Error: failed}, false},
+		{"command timeout", CommandRunResult{Stderr: "Error: 503", Error: failed, TimedOut: true}, false},
+		{"cancelled", CommandRunResult{Stderr: "Error: 503", Error: failed, Cancelled: true}, false},
+		{"unclassified", CommandRunResult{Stderr: "Error: something went wrong", Error: failed}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryableFailure(tt.result); got != tt.retryable {
+				t.Errorf("Expected retryable = %v, got %v", tt.retryable, got)
+			}
+		})
+	}
+}
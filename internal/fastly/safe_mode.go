@@ -0,0 +1,235 @@
+package fastly
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+// ConfirmationTokenTTL is how long a confirmation token issued in safe mode
+// stays valid.
+const ConfirmationTokenTTL = 5 * time.Minute
+
+// confirmationTokenFlag is the MCP-only flag carrying a confirmation token. It
+// is stripped before the command reaches the Fastly CLI.
+const confirmationTokenFlag = "confirmation-token"
+
+// globalSafeMode enables the safe-mode preset: every mutating command requires
+// review, destructive commands also require a confirmation token, and the
+// self-management commands are denied. It can be configured via SetSafeMode().
+var globalSafeMode = false
+
+// SetSafeMode enables or disables the safe-mode preset.
+func SetSafeMode(enabled bool) {
+	globalSafeMode = enabled
+}
+
+// safeModeReadCommands are top-level commands whose subcommands only read data,
+// so they need no review even though their operation type is not recognized.
+var safeModeReadCommands = map[string]bool{
+	"stats": true,
+}
+
+// destructiveOperations are the subcommands that delete resources or data.
+var destructiveOperations = map[string]bool{
+	"delete":    true,
+	"remove":    true,
+	"destroy":   true,
+	"terminate": true,
+	"purge":     true,
+}
+
+// requiresSafeModeReview reports whether safe mode requires review of a
+// command. Anything not known to be a read operation is treated as mutating.
+func requiresSafeModeReview(command string, args []string) bool {
+	if !globalSafeMode || safeModeReadCommands[command] {
+		return false
+	}
+	_, isSafe := GetOperationType(command, args)
+	return !isSafe
+}
+
+// reviewRequirement reports whether a command requires human review and why:
+// either its keywords give it a severity that requires review, or safe mode
+// treats it as mutating.
+// A danger policy level configured for the command path takes precedence over
+// both.
+func reviewRequirement(command string, args []string) (bool, string) {
+	if level, ok := dangerPolicyOverride(command, args); ok {
+		if level == DangerPolicyDangerous {
+			return true, "The operator's danger policy requires review of this operation"
+		}
+		return false, ""
+	}
+
+	cmdStr := command
+	if len(args) > 0 {
+		cmdStr += " " + strings.Join(args, " ")
+	}
+	if severity, warningText := IsDangerousOperation(cmdStr); requiresReview(severity) {
+		return true, warningText
+	}
+	if requiresSafeModeReview(command, args) {
+		return true, "Safe mode requires review of every operation that may modify resources"
+	}
+	return false, ""
+}
+
+// pathReviewRequirement reports the review requirement that describe, help and
+// the catalog show for a command path, the same one the executor enforces.
+// Parent commands run nothing by themselves, so only leaf commands can
+// require review.
+func pathReviewRequirement(cmdPath string, leaf bool) (bool, string) {
+	parts := strings.Fields(cmdPath)
+	if !leaf || len(parts) == 0 {
+		return false, ""
+	}
+	return reviewRequirement(parts[0], parts[1:])
+}
+
+// isDestructiveOperation reports whether a command deletes resources or data.
+func isDestructiveOperation(command string, args []string) bool {
+	if destructiveOperations[command] {
+		return true
+	}
+	for _, arg := range args {
+		if destructiveOperations[arg] {
+			return true
+		}
+	}
+	return false
+}
+
+// requiresConfirmationToken reports whether safe mode requires a confirmation
+// token for a command. Commands run on behalf of an already confirmed batch are
+// exempt.
+func requiresConfirmationToken(ctx context.Context, command string, args []string) bool {
+	if !globalSafeMode || !isDestructiveOperation(command, args) {
+		return false
+	}
+	confirmed, _ := ctx.Value(confirmedBatchKey{}).(bool)
+	return !confirmed
+}
+
+// confirmedBatchKey marks the context of commands run for a batch whose
+// confirmation token was already checked.
+type confirmedBatchKey struct{}
+
+// withConfirmedBatch returns a context whose commands skip the confirmation
+// token check.
+func withConfirmedBatch(ctx context.Context) context.Context {
+	return context.WithValue(ctx, confirmedBatchKey{}, true)
+}
+
+// confirmationSubject returns what a confirmation token is bound to: the
+// command line without MCP-only flags, followed by any raw arguments.
+func confirmationSubject(req types.CommandRequest, flags []types.Flag) string {
+	subject := BuildCommandLine(req.Command, req.Args, flags)
+	if len(req.RawArgs) > 0 {
+		subject += " -- " + strings.Join(req.RawArgs, " ")
+	}
+	return subject
+}
+
+// purgeBatchSubject returns what the confirmation token of a purge batch is
+// bound to: the command line followed by the surrogate keys.
+func purgeBatchSubject(req types.CommandRequest) string {
+	return BuildCommandLine(req.Command, req.Args, withoutConfirmationFlags(req.Flags)) + " keys: " + strings.Join(req.Keys, " ")
+}
+
+// withoutConfirmationFlags returns flags without the MCP-only confirmation flags.
+func withoutConfirmationFlags(flags []types.Flag) []types.Flag {
+	var result []types.Flag
+	for _, flag := range flags {
+		if flag.Name != "user-reviewed" && flag.Name != confirmationTokenFlag {
+			result = append(result, flag)
+		}
+	}
+	return result
+}
+
+// confirmationTokenEntry is an issued confirmation token.
+type confirmationTokenEntry struct {
+	subject  string
+	issuedAt time.Time
+}
+
+// confirmationTokens holds the confirmation tokens issued in safe mode.
+var confirmationTokens = struct {
+	mu      sync.Mutex
+	entries map[string]confirmationTokenEntry
+}{entries: make(map[string]confirmationTokenEntry)}
+
+// issueConfirmationToken returns a new token confirming one run of subject.
+// Expired tokens are dropped.
+func issueConfirmationToken(subject string) string {
+	token := randomToken("ct-")
+
+	confirmationTokens.mu.Lock()
+	defer confirmationTokens.mu.Unlock()
+
+	now := time.Now()
+	for t, entry := range confirmationTokens.entries {
+		if now.Sub(entry.issuedAt) > ConfirmationTokenTTL {
+			delete(confirmationTokens.entries, t)
+		}
+	}
+	confirmationTokens.entries[token] = confirmationTokenEntry{subject: subject, issuedAt: now}
+	return token
+}
+
+// checkConfirmationToken reports whether token was issued for subject within
+// ConfirmationTokenTTL. A valid token stays usable until the command runs and
+// consumeConfirmationToken uses it up; any other attempt to redeem it drops it.
+func checkConfirmationToken(token, subject string) bool {
+	confirmationTokens.mu.Lock()
+	defer confirmationTokens.mu.Unlock()
+
+	entry, ok := confirmationTokens.entries[token]
+	if !ok {
+		return false
+	}
+	if entry.subject != subject || time.Since(entry.issuedAt) > ConfirmationTokenTTL {
+		delete(confirmationTokens.entries, token)
+		return false
+	}
+	return true
+}
+
+// consumeConfirmationToken reports whether token was issued for subject within
+// ConfirmationTokenTTL and uses it up. It is called right before the confirmed
+// command runs, so a checked token that a concurrent request already used is
+// refused.
+func consumeConfirmationToken(token, subject string) bool {
+	confirmationTokens.mu.Lock()
+	defer confirmationTokens.mu.Unlock()
+
+	entry, ok := confirmationTokens.entries[token]
+	if !ok {
+		return false
+	}
+	delete(confirmationTokens.entries, token)
+	return entry.subject == subject && time.Since(entry.issuedAt) <= ConfirmationTokenTTL
+}
+
+// randomToken returns a random token with the given prefix.
+func randomToken(prefix string) string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return prefix + hex.EncodeToString(b)
+}
+
+// flagValue returns the value of the first flag with the given name.
+func flagValue(flags []types.Flag, name string) string {
+	for _, flag := range flags {
+		if flag.Name == name {
+			return flag.Value
+		}
+	}
+	return ""
+}
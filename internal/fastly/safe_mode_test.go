@@ -0,0 +1,219 @@
+package fastly
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestSafeModeRequiresReviewOfMutatingCommands(t *testing.T) {
+	callsFile := installMockFastly(t, `echo "cloned"`)
+
+	SetSafeMode(true)
+	defer SetSafeMode(false)
+
+	// clone matches no dangerous keyword but still modifies the service
+	req := types.CommandRequest{
+		Command: "service-version",
+		Args:    []string{"clone"},
+		Flags:   []types.Flag{{Name: "service-id", Value: "abc123"}, {Name: "version", Value: "1"}},
+	}
+	result := ExecuteCommand(req)
+	if result.Success || result.ErrorCode != "user_confirmation_required" {
+		t.Fatalf("Expected user_confirmation_required, got %+v", result)
+	}
+	if result.ConfirmationToken != "" {
+		t.Errorf("Expected no confirmation token for a non-destructive command, got %q", result.ConfirmationToken)
+	}
+	if _, err := os.ReadFile(callsFile); err == nil {
+		t.Error("Expected the CLI not to be invoked before review")
+	}
+
+	req.Flags = append(req.Flags, types.Flag{Name: "user-reviewed"})
+	if result := ExecuteCommand(req); !result.Success {
+		t.Fatalf("Expected the reviewed command to run, got %+v", result)
+	}
+
+	// Read operations need no review
+	if result := ExecuteCommand(types.CommandRequest{Command: "service", Args: []string{"list"}}); !result.Success {
+		t.Errorf("Expected a read operation to run without review, got %+v", result)
+	}
+}
+
+func TestSafeModeRequiresConfirmationToken(t *testing.T) {
+	callsFile := installMockFastly(t, `echo "deleted"`)
+
+	SetSafeMode(true)
+	defer SetSafeMode(false)
+
+	request := func(serviceID string, flags ...types.Flag) types.CommandRequest {
+		return types.CommandRequest{
+			Command: "service",
+			Args:    []string{"delete"},
+			Flags:   append([]types.Flag{{Name: "service-id", Value: serviceID}}, flags...),
+		}
+	}
+	reviewed := types.Flag{Name: "user-reviewed"}
+
+	// The review request carries the token
+	result := ExecuteCommand(request("abc123"))
+	if result.ErrorCode != "user_confirmation_required" || result.ConfirmationToken == "" {
+		t.Fatalf("Expected a confirmation request with a token, got %+v", result)
+	}
+	if !strings.Contains(strings.Join(result.NextSteps, "\n"), result.ConfirmationToken) {
+		t.Errorf("Expected the token in the next steps, got %v", result.NextSteps)
+	}
+	token := result.ConfirmationToken
+
+	// user-reviewed alone is not enough
+	result = ExecuteCommand(request("abc123", reviewed))
+	if result.Success || result.ErrorCode != "confirmation_token_required" || result.ConfirmationToken == "" {
+		t.Fatalf("Expected confirmation_token_required with a new token, got %+v", result)
+	}
+
+	// A token only confirms the command it was issued for
+	result = ExecuteCommand(request("other", reviewed, types.Flag{Name: "confirmation-token", Value: token}))
+	if result.ErrorCode != "confirmation_token_required" {
+		t.Fatalf("Expected a token for another command to be rejected, got %+v", result)
+	}
+	if _, err := os.ReadFile(callsFile); err == nil {
+		t.Fatal("Expected the CLI not to be invoked without a valid token")
+	}
+
+	result = ExecuteCommand(request("abc123"))
+	token = result.ConfirmationToken
+	result = ExecuteCommand(request("abc123", reviewed, types.Flag{Name: "confirmation-token", Value: token}))
+	if !result.Success {
+		t.Fatalf("Expected the confirmed command to run, got %+v", result)
+	}
+	calls, _ := os.ReadFile(callsFile)
+	if strings.Contains(string(calls), "confirmation-token") || strings.Contains(string(calls), token) {
+		t.Errorf("Expected the token not to be passed to the CLI, got %q", calls)
+	}
+
+	// Tokens are single-use
+	result = ExecuteCommand(request("abc123", reviewed, types.Flag{Name: "confirmation-token", Value: token}))
+	if result.ErrorCode != "confirmation_token_required" {
+		t.Errorf("Expected a used token to be rejected, got %+v", result)
+	}
+}
+
+func TestSafeModeTokenSurvivesFailureBeforeRun(t *testing.T) {
+	callsFile := installMockFastly(t, `echo "deleted"`)
+
+	SetSafeMode(true)
+	defer SetSafeMode(false)
+	SetAuthBreakerThreshold(1)
+	defer SetAuthBreakerThreshold(3)
+	defer ResetAuthBreaker()
+
+	req := types.CommandRequest{Command: "service", Args: []string{"delete"}, Flags: []types.Flag{{Name: "service-id", Value: "abc123"}}}
+	token := ExecuteCommand(req).ConfirmationToken
+	req.Flags = append(req.Flags, types.Flag{Name: "user-reviewed"}, types.Flag{Name: "confirmation-token", Value: token})
+
+	// The open auth breaker stops the command before it runs
+	recordAuthResult(nil, "auth_required", "Error: unauthorized")
+	if result := ExecuteCommand(req); result.ErrorCode != "auth_required" {
+		t.Fatalf("Expected the auth breaker to stop the command, got %+v", result)
+	}
+	if countCalls(t, callsFile) != 0 {
+		t.Fatal("Expected the CLI not to be invoked while the breaker is open")
+	}
+
+	// The command never ran, so the same token still confirms it
+	ResetAuthBreaker()
+	if result := ExecuteCommand(req); !result.Success {
+		t.Fatalf("Expected the token to still confirm the command, got %+v", result)
+	}
+	if result := ExecuteCommand(req); result.ErrorCode != "confirmation_token_required" {
+		t.Errorf("Expected the token to be used up once the command ran, got %+v", result)
+	}
+}
+
+func TestSafeModeConfirmsPurgeBatchOnce(t *testing.T) {
+	callsFile := installMockFastly(t, purgeKeysScript)
+
+	SetSafeMode(true)
+	defer SetSafeMode(false)
+
+	req := purgeBatchRequest("product-1", "product-2")
+	result := ExecuteCommand(req)
+	if result.ErrorCode != "confirmation_token_required" {
+		t.Fatalf("Expected confirmation_token_required, got %+v", result)
+	}
+
+	req.Flags = append(req.Flags, types.Flag{Name: "confirmation-token", Value: result.ConfirmationToken})
+	result = ExecuteCommand(req)
+	if !result.Success {
+		t.Fatalf("Expected the confirmed batch to be purged, got %+v", result)
+	}
+	calls, _ := os.ReadFile(callsFile)
+	if n := strings.Count(string(calls), "\n"); n != 1 {
+		t.Errorf("Expected 1 purge of both keys, got %d: %q", n, calls)
+	}
+}
+
+func TestSafeModeDeniesSelfManagement(t *testing.T) {
+	callsFile := installMockFastly(t, `echo "updated"`)
+
+	SetSafeMode(true)
+	defer SetSafeMode(false)
+	SetAllowSelfManagement(true)
+	defer SetAllowSelfManagement(false)
+
+	result := ExecuteCommand(types.CommandRequest{Command: "update", Flags: []types.Flag{{Name: "user-reviewed"}}})
+	if result.Success || result.ErrorCode != "self_management_disabled" {
+		t.Errorf("Expected update to be denied in safe mode, got %+v", result)
+	}
+	if _, err := os.ReadFile(callsFile); err == nil {
+		t.Error("Expected the CLI not to be invoked")
+	}
+}
+
+func TestUnsafeRestoresDefaultChecks(t *testing.T) {
+	callsFile := installMockFastly(t, `echo "done"`)
+
+	SetSafeMode(false)
+	SetAllowSelfManagement(true)
+	defer SetAllowSelfManagement(false)
+
+	tests := []types.CommandRequest{
+		{Command: "service-version", Args: []string{"clone"}, Flags: []types.Flag{{Name: "service-id", Value: "abc123"}, {Name: "version", Value: "1"}}},
+		{Command: "service", Args: []string{"delete"}, Flags: []types.Flag{{Name: "service-id", Value: "abc123"}, {Name: "user-reviewed"}}},
+		{Command: "update", Flags: []types.Flag{{Name: "user-reviewed"}}},
+	}
+	for _, req := range tests {
+		if result := ExecuteCommand(req); !result.Success {
+			t.Errorf("Expected %s %v to run without safe mode, got %+v", req.Command, req.Args, result)
+		}
+	}
+
+	calls, _ := os.ReadFile(callsFile)
+	if n := strings.Count(string(calls), "\n"); n != len(tests) {
+		t.Errorf("Expected %d CLI invocations, got %d: %q", len(tests), n, calls)
+	}
+}
+
+func TestSafeModeDescribeShowsReview(t *testing.T) {
+	SetSafeMode(true)
+	defer SetSafeMode(false)
+
+	info := parseHelpOutput("backend create", "Create a backend\n\nREQUIRED FLAGS\n  -s, --service-id=SERVICE-ID  Service ID\n")
+	if !strings.HasPrefix(info.Description, "⚠️") {
+		t.Errorf("Expected describe to flag a mutating command in safe mode, got %q", info.Description)
+	}
+	if !strings.Contains(info.Instructions, "user-reviewed") {
+		t.Errorf("Expected the instructions to ask for --user-reviewed, got %q", info.Instructions)
+	}
+	if entry := newCatalogEntry([]string{"backend", "create"}, info, ""); !entry.Dangerous {
+		t.Errorf("Expected the catalog entry to require review in safe mode, got %+v", entry)
+	}
+
+	// Parent commands run nothing by themselves
+	parent := parseHelpOutput("backend", "Manipulate Fastly service version backends\n\nCOMMANDS\n  create  Create a backend\n")
+	if strings.HasPrefix(parent.Description, "⚠️") {
+		t.Errorf("Expected a parent command not to be flagged, got %q", parent.Description)
+	}
+}
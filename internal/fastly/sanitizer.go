@@ -3,6 +3,7 @@
 package fastly
 
 import (
+	"encoding/json"
 	"regexp"
 	"strings"
 )
@@ -11,7 +12,10 @@ import (
 // When Enabled is true, sensitive data patterns will be detected and redacted.
 type SanitizeOptions struct {
 	Enabled bool
-	// Reserved for future options: custom patterns, exclusions, etc.
+	// MaskPaths lists dot-separated JSON field paths (e.g. "customer.email")
+	// whose values are always redacted, regardless of content and of Enabled.
+	// Arrays are traversed transparently, so a path applies to every element.
+	MaskPaths []string
 }
 
 // Sensitive data detection patterns.
@@ -108,19 +112,41 @@ func SanitizeOutput(output string, opts SanitizeOptions) string {
 // SanitizeJSON recursively sanitizes JSON data by detecting and redacting values
 // in fields with sensitive names (e.g., password, token, secret).
 // It preserves the structure while replacing sensitive string values with "[REDACTED]".
-// Returns the original data unchanged if sanitization is disabled.
+// Fields listed in opts.MaskPaths are redacted whether or not sanitization is enabled.
+// Returns the original data unchanged if sanitization is disabled and no paths are masked.
 func SanitizeJSON(jsonData interface{}, opts SanitizeOptions) interface{} {
-	if !opts.Enabled {
+	if !opts.Enabled && len(opts.MaskPaths) == 0 {
 		return jsonData
 	}
 
+	maskPaths := make(map[string]bool, len(opts.MaskPaths))
+	for _, path := range opts.MaskPaths {
+		maskPaths[path] = true
+	}
+
+	return sanitizeJSONValue(jsonData, opts, maskPaths, "")
+}
+
+// sanitizeJSONValue sanitizes a single JSON value located at path.
+func sanitizeJSONValue(jsonData interface{}, opts SanitizeOptions, maskPaths map[string]bool, path string) interface{} {
 	switch v := jsonData.(type) {
 	case map[string]interface{}:
 		sanitized := make(map[string]interface{})
 		for key, value := range v {
+			fieldPath := key
+			if path != "" {
+				fieldPath = path + "." + key
+			}
+
+			// Redact configured paths, whatever their content
+			if maskPaths[fieldPath] {
+				sanitized[key] = "[REDACTED]"
+				continue
+			}
+
 			lowerKey := strings.ToLower(key)
 			// Redact values for keys that likely contain sensitive data
-			if containsSensitiveKey(lowerKey) {
+			if opts.Enabled && containsSensitiveKey(lowerKey) {
 				if str, ok := value.(string); ok && str != "" {
 					sanitized[key] = "[REDACTED]"
 				} else {
@@ -128,7 +154,7 @@ func SanitizeJSON(jsonData interface{}, opts SanitizeOptions) interface{} {
 				}
 			} else {
 				// Recursively sanitize nested structures
-				sanitized[key] = SanitizeJSON(value, opts)
+				sanitized[key] = sanitizeJSONValue(value, opts, maskPaths, fieldPath)
 			}
 		}
 		return sanitized
@@ -136,7 +162,7 @@ func SanitizeJSON(jsonData interface{}, opts SanitizeOptions) interface{} {
 	case []interface{}:
 		sanitized := make([]interface{}, len(v))
 		for i, item := range v {
-			sanitized[i] = SanitizeJSON(item, opts)
+			sanitized[i] = sanitizeJSONValue(item, opts, maskPaths, path)
 		}
 		return sanitized
 
@@ -149,6 +175,43 @@ func SanitizeJSON(jsonData interface{}, opts SanitizeOptions) interface{} {
 	}
 }
 
+// MaskJSONOutput redacts the fields listed in opts.MaskPaths from JSON text
+// output. It is applied before caching so cached results are masked too.
+// Non-JSON output, or output when no paths are configured, is returned unchanged.
+func MaskJSONOutput(output string, opts SanitizeOptions) string {
+	if len(opts.MaskPaths) == 0 {
+		return output
+	}
+
+	trimmedBytes := []byte(strings.TrimSpace(output))
+	if len(trimmedBytes) == 0 {
+		return output
+	}
+
+	var jsonData interface{}
+	if err := json.Unmarshal(trimmedBytes, &jsonData); err != nil {
+		return output
+	}
+
+	masked := SanitizeJSON(jsonData, SanitizeOptions{MaskPaths: opts.MaskPaths})
+	result, err := json.Marshal(masked)
+	if err != nil {
+		return output
+	}
+
+	return string(result)
+}
+
+// IsSecretLike reports whether a flag looks like it carries a secret, either
+// because its name suggests sensitive data or because its value matches one of
+// the sanitization patterns.
+func IsSecretLike(name, value string) bool {
+	if IsSensitiveFlag(name) || containsSensitiveKey(strings.ToLower(name)) {
+		return true
+	}
+	return SanitizeOutput(value, SanitizeOptions{Enabled: true}) != value
+}
+
 // containsSensitiveKey checks if a key name suggests sensitive data
 func containsSensitiveKey(key string) bool {
 	sensitiveTerms := []string{
@@ -2,6 +2,7 @@ package fastly
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 )
 
@@ -222,6 +223,71 @@ func TestSanitizeJSON(t *testing.T) {
 	}
 }
 
+func TestSanitizeJSONMaskPaths(t *testing.T) {
+	input := map[string]interface{}{
+		"customer": map[string]interface{}{
+			"email": "owner@example.com",
+			"name":  "Example Corp",
+		},
+		"tls": map[string]interface{}{
+			"private_key": map[string]interface{}{"pem": "-----BEGIN-----"},
+			"domain":      "www.example.com",
+		},
+		"backends": []interface{}{
+			map[string]interface{}{"address": "origin-1.example.com", "name": "primary"},
+			map[string]interface{}{"address": "origin-2.example.com", "name": "secondary"},
+		},
+		"email": "top-level@example.com",
+	}
+
+	expected := map[string]interface{}{
+		"customer": map[string]interface{}{
+			"email": "[REDACTED]",
+			"name":  "Example Corp",
+		},
+		"tls": map[string]interface{}{
+			"private_key": "[REDACTED]",
+			"domain":      "www.example.com",
+		},
+		"backends": []interface{}{
+			map[string]interface{}{"address": "[REDACTED]", "name": "primary"},
+			map[string]interface{}{"address": "[REDACTED]", "name": "secondary"},
+		},
+		"email": "top-level@example.com",
+	}
+
+	// Paths are masked even with pattern-based sanitization disabled
+	opts := SanitizeOptions{MaskPaths: []string{"customer.email", "tls.private_key", "backends.address"}}
+	result := SanitizeJSON(input, opts)
+
+	expectedJSON, _ := json.Marshal(expected)
+	resultJSON, _ := json.Marshal(result)
+	if string(resultJSON) != string(expectedJSON) {
+		t.Errorf("SanitizeJSON() = %s, want %s", resultJSON, expectedJSON)
+	}
+}
+
+func TestMaskJSONOutput(t *testing.T) {
+	opts := SanitizeOptions{MaskPaths: []string{"customer.email"}}
+
+	masked := MaskJSONOutput(`{"customer":{"email":"owner@example.com","id":"c1"}}`, opts)
+	if strings.Contains(masked, "owner@example.com") {
+		t.Errorf("Expected customer.email to be masked, got %s", masked)
+	}
+	if !strings.Contains(masked, `"id":"c1"`) {
+		t.Errorf("Expected other fields to remain, got %s", masked)
+	}
+
+	if got := MaskJSONOutput("plain text owner@example.com", opts); got != "plain text owner@example.com" {
+		t.Errorf("Expected non-JSON output unchanged, got %s", got)
+	}
+
+	raw := `{"customer":{"email":"owner@example.com"}}`
+	if got := MaskJSONOutput(raw, SanitizeOptions{}); got != raw {
+		t.Errorf("Expected output unchanged without mask paths, got %s", got)
+	}
+}
+
 func TestIsHexString(t *testing.T) {
 	tests := []struct {
 		input    string
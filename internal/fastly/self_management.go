@@ -0,0 +1,26 @@
+package fastly
+
+// globalAllowSelfManagement controls whether the install and update commands,
+// which modify the Fastly CLI installation itself, can be run. It can be
+// configured via SetAllowSelfManagement().
+var globalAllowSelfManagement = false
+
+// selfManagementCommands are top-level commands that replace or modify the
+// Fastly CLI binary. Running them through MCP would undermine the binary
+// security checks, so they are blocked unless explicitly allowed.
+var selfManagementCommands = map[string]bool{
+	"install": true,
+	"update":  true,
+}
+
+// SetAllowSelfManagement enables or disables the self-management commands
+// (install, update). They are blocked by default.
+func SetAllowSelfManagement(enabled bool) {
+	globalAllowSelfManagement = enabled
+}
+
+// isSelfManagementBlocked reports whether a command is a self-management
+// command that is currently not permitted. Safe mode always denies them.
+func isSelfManagementBlocked(command string) bool {
+	return selfManagementCommands[command] && (globalSafeMode || !globalAllowSelfManagement)
+}
@@ -0,0 +1,46 @@
+package fastly
+
+import (
+	"os"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestSelfManagementBlockedByDefault(t *testing.T) {
+	callsFile := installMockFastly(t, `echo "updated"`)
+
+	for _, command := range []string{"update", "install"} {
+		result := ExecuteCommand(types.CommandRequest{
+			Command: command,
+			Flags:   []types.Flag{{Name: "user-reviewed"}},
+		})
+		if result.Success || result.ErrorCode != "self_management_disabled" {
+			t.Errorf("Expected %s to be blocked with self_management_disabled, got %+v", command, result)
+		}
+	}
+
+	if _, err := os.ReadFile(callsFile); err == nil {
+		t.Error("Expected the CLI not to be invoked for blocked self-management commands")
+	}
+}
+
+func TestSelfManagementAllowedWhenEnabled(t *testing.T) {
+	callsFile := installMockFastly(t, `echo "updated"`)
+
+	SetAllowSelfManagement(true)
+	defer SetAllowSelfManagement(false)
+
+	result := ExecuteCommand(types.CommandRequest{
+		Command: "update",
+		Flags:   []types.Flag{{Name: "user-reviewed"}},
+	})
+	if !result.Success {
+		t.Fatalf("Expected update to run when self-management is allowed, got %+v", result)
+	}
+
+	calls, err := os.ReadFile(callsFile)
+	if err != nil || len(calls) == 0 {
+		t.Errorf("Expected the CLI to be invoked, got %q (%v)", calls, err)
+	}
+}
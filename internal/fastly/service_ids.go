@@ -0,0 +1,83 @@
+package fastly
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+const (
+	// MinServiceIDLength and MaxServiceIDLength bound the length of a
+	// well-formed service ID.
+	MinServiceIDLength = 16
+	MaxServiceIDLength = 32
+)
+
+// globalNormalizeServiceIDs controls whether service ID flag values are trimmed
+// and checked for common copy-paste errors. It can be configured via
+// SetServiceIDNormalization().
+var globalNormalizeServiceIDs = false
+
+// serviceIDFlags are the flags whose value is a service ID.
+var serviceIDFlags = map[string]bool{
+	"service-id": true,
+}
+
+// serviceURLRegex extracts the service ID from a pasted Fastly control panel
+// or API URL.
+var serviceURLRegex = regexp.MustCompile(`/services?/([A-Za-z0-9]{16,32})\b`)
+
+// SetServiceIDNormalization enables or disables the trimming and checking of
+// service ID flag values.
+func SetServiceIDNormalization(enabled bool) {
+	globalNormalizeServiceIDs = enabled
+}
+
+// ServiceIDNormalization reports whether service ID normalization is enabled.
+func ServiceIDNormalization() bool {
+	return globalNormalizeServiceIDs
+}
+
+// normalizeServiceIDFlags trims surrounding whitespace from service ID flag
+// values and rejects values that cannot be a service ID, naming the likely
+// copy-paste error. Other flags are returned unchanged.
+func normalizeServiceIDFlags(flags []types.Flag) ([]types.Flag, error) {
+	result := make([]types.Flag, len(flags))
+	copy(result, flags)
+	for i, flag := range result {
+		if !serviceIDFlags[flag.Name] {
+			continue
+		}
+		value := strings.TrimSpace(flag.Value)
+		if err := checkServiceID(value); err != nil {
+			return nil, fmt.Errorf("flag '%s' value %q %w", flag.Name, flag.Value, err)
+		}
+		result[i].Value = value
+	}
+	return result, nil
+}
+
+// checkServiceID explains why a trimmed value is not a well-formed service ID.
+func checkServiceID(value string) error {
+	if value == "" {
+		return fmt.Errorf("is empty")
+	}
+	if match := serviceURLRegex.FindStringSubmatch(value); match != nil {
+		return fmt.Errorf("looks like a URL; pass only the service ID %s", match[1])
+	}
+	if strings.IndexFunc(value, unicode.IsSpace) >= 0 {
+		return fmt.Errorf("contains whitespace; it may hold more than one value pasted together")
+	}
+	for _, r := range value {
+		if !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')) {
+			return fmt.Errorf("contains %q, which is not part of a service ID; remove quotes or punctuation copied with it", r)
+		}
+	}
+	if len(value) < MinServiceIDLength || len(value) > MaxServiceIDLength {
+		return fmt.Errorf("is %d characters long, but service IDs have %d to %d letters and digits; check that it was not truncated or joined with other text", len(value), MinServiceIDLength, MaxServiceIDLength)
+	}
+	return nil
+}
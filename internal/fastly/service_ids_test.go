@@ -0,0 +1,86 @@
+package fastly
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestServiceIDTrimmed(t *testing.T) {
+	callsFile := installMockFastly(t, `echo '{}'`)
+
+	SetServiceIDNormalization(true)
+	defer SetServiceIDNormalization(false)
+
+	result := ExecuteCommand(types.CommandRequest{
+		Command: "service",
+		Args:    []string{"describe"},
+		Flags:   []types.Flag{{Name: "service-id", Value: "  SU1Z0isxPaozGVKXdv0eY\n"}},
+	})
+	if !result.Success {
+		t.Fatalf("Expected the trimmed service ID to be accepted, got %+v", result)
+	}
+
+	calls, err := os.ReadFile(callsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(calls), "--service-id SU1Z0isxPaozGVKXdv0eY ") {
+		t.Errorf("Expected the trimmed service ID to reach the CLI, got %q", calls)
+	}
+}
+
+func TestMalformedServiceIDRejected(t *testing.T) {
+	callsFile := installMockFastly(t, `echo '{}'`)
+
+	SetServiceIDNormalization(true)
+	defer SetServiceIDNormalization(false)
+
+	tests := []struct {
+		name   string
+		value  string
+		expect string
+	}{
+		{"empty", "   ", "is empty"},
+		{"pasted URL", "https://manage.fastly.com/configure/services/SU1Z0isxPaozGVKXdv0eY", "pass only the service ID SU1Z0isxPaozGVKXdv0eY"},
+		{"two values", "SU1Z0isxPaozGVKXdv0eY SU1Z0isxPaozGVKXdv0eZ", "contains whitespace"},
+		{"quoted", `"SU1Z0isxPaozGVKXdv0eY"`, `contains '"'`},
+		{"trailing comma", "SU1Z0isxPaozGVKXdv0eY,", "contains ','"},
+		{"truncated", "SU1Z0isx", "is 8 characters long"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ExecuteCommand(types.CommandRequest{
+				Command: "service",
+				Args:    []string{"describe"},
+				Flags:   []types.Flag{{Name: "service-id", Value: tt.value}},
+			})
+			if result.Success || result.ErrorCode != "invalid_service_id" {
+				t.Fatalf("Expected invalid_service_id, got %+v", result)
+			}
+			if !strings.Contains(result.Error, tt.expect) {
+				t.Errorf("Expected error to contain %q, got %q", tt.expect, result.Error)
+			}
+		})
+	}
+
+	if _, err := os.ReadFile(callsFile); err == nil {
+		t.Error("Expected the CLI not to be invoked for malformed service IDs")
+	}
+}
+
+func TestServiceIDUnchangedWhenDisabled(t *testing.T) {
+	installMockFastly(t, `echo '{}'`)
+
+	result := ExecuteCommand(types.CommandRequest{
+		Command: "service",
+		Args:    []string{"describe"},
+		Flags:   []types.Flag{{Name: "service-id", Value: "abc123"}},
+	})
+	if !result.Success {
+		t.Errorf("Expected short service IDs to pass without normalization, got %+v", result)
+	}
+}
@@ -0,0 +1,37 @@
+package fastly
+
+// Operation severities returned by IsDangerousOperation and reported in the
+// operation metadata.
+const (
+	// SeverityInfo marks operations that only read data
+	SeverityInfo = "info"
+	// SeverityMutating marks operations that create or modify resources
+	SeverityMutating = "mutating"
+	// SeverityDestructive marks operations that delete data or manage credentials
+	SeverityDestructive = "destructive"
+)
+
+// globalReviewMutating controls whether mutating operations require the
+// --user-reviewed flag like destructive ones. It can be configured via
+// SetReviewMutating().
+var globalReviewMutating = false
+
+// SetReviewMutating makes mutating operations, such as creating a backend,
+// require human review in addition to destructive ones.
+func SetReviewMutating(enabled bool) {
+	globalReviewMutating = enabled
+}
+
+// requiresReview reports whether operations of a severity require human
+// review by keyword: destructive operations always, mutating operations only
+// when configured with SetReviewMutating.
+func requiresReview(severity string) bool {
+	switch severity {
+	case SeverityDestructive:
+		return true
+	case SeverityMutating:
+		return globalReviewMutating
+	default:
+		return false
+	}
+}
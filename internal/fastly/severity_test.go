@@ -0,0 +1,60 @@
+package fastly
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestMutatingOperationReview(t *testing.T) {
+	installMockFastly(t, `echo '{"name": "test-backend"}'`)
+
+	create := types.CommandRequest{
+		Command: "backend",
+		Args:    []string{"create"},
+		Flags:   []types.Flag{{Name: "service-id", Value: "abc123"}, {Name: "name", Value: "test-backend"}},
+	}
+	result := ExecuteCommand(create)
+	if !result.Success {
+		t.Fatalf("Expected a mutating operation to run without review by default, got %+v", result)
+	}
+	if result.Metadata == nil || result.Metadata.Severity != SeverityMutating {
+		t.Errorf("Expected the mutating severity in the metadata, got %+v", result.Metadata)
+	}
+
+	result = ExecuteCommand(types.CommandRequest{Command: "service", Args: []string{"delete"}, Flags: []types.Flag{{Name: "service-id", Value: "abc123"}}})
+	if result.ErrorCode != "user_confirmation_required" || result.Metadata.Severity != SeverityDestructive {
+		t.Errorf("Expected a destructive operation to require review, got %+v", result)
+	}
+
+	SetReviewMutating(true)
+	defer SetReviewMutating(false)
+	result = ExecuteCommand(create)
+	if result.ErrorCode != "user_confirmation_required" {
+		t.Errorf("Expected a mutating operation to require review when configured, got %+v", result)
+	}
+	if !strings.Contains(result.Instructions, "creates new resources") {
+		t.Errorf("Expected the warning in the instructions, got %q", result.Instructions)
+	}
+}
+
+func TestDescribeReportsSeverity(t *testing.T) {
+	info := addMCPInstructions(types.HelpInfo{
+		Command:       "backend create",
+		RequiredFlags: []types.FlagInfo{{Name: "name", Description: "Backend name"}},
+	})
+	if strings.Contains(info.Instructions, "user-reviewed") {
+		t.Errorf("Expected no review instructions for a mutating operation by default, got %q", info.Instructions)
+	}
+
+	SetReviewMutating(true)
+	defer SetReviewMutating(false)
+	info = addMCPInstructions(types.HelpInfo{
+		Command:       "backend create",
+		RequiredFlags: []types.FlagInfo{{Name: "name", Description: "Backend name"}},
+	})
+	if !strings.Contains(info.Instructions, "user-reviewed") {
+		t.Errorf("Expected review instructions when mutating operations require review, got %q", info.Instructions)
+	}
+}
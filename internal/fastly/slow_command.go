@@ -0,0 +1,33 @@
+package fastly
+
+import (
+	"fmt"
+	"time"
+)
+
+// globalSlowCommandThreshold is how long a command may run before its response
+// notes that it was slow; 0 disables the note. It can be configured via
+// SetSlowCommandThreshold().
+var globalSlowCommandThreshold time.Duration
+
+// SetSlowCommandThreshold configures the soft threshold after which a command
+// that still completes within its timeout is reported as slow. A value of zero
+// disables the note.
+func SetSlowCommandThreshold(threshold time.Duration) {
+	if threshold < 0 {
+		threshold = 0
+	}
+	globalSlowCommandThreshold = threshold
+}
+
+// slowCommandNote returns a note for a command that took elapsed to complete
+// under the given timeout, or an empty string while it stayed within the soft
+// threshold. A run of slow responses usually points at the network or the API
+// rather than the command itself.
+func slowCommandNote(elapsed, timeout time.Duration) string {
+	if globalSlowCommandThreshold == 0 || elapsed < globalSlowCommandThreshold {
+		return ""
+	}
+	return fmt.Sprintf("Note: the command took %s, longer than the expected %s (timeout %s). If commands are often this slow, check network connectivity and the Fastly status page.",
+		elapsed.Round(100*time.Millisecond), globalSlowCommandThreshold, timeout)
+}
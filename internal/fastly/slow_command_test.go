@@ -0,0 +1,49 @@
+package fastly
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestSlowCommandNote(t *testing.T) {
+	installMockFastly(t, `sleep 0.3; echo '[]'`)
+
+	SetSlowCommandThreshold(100 * time.Millisecond)
+	defer SetSlowCommandThreshold(0)
+
+	result := ExecuteCommand(types.CommandRequest{
+		Command: "service",
+		Args:    []string{"list"},
+		Flags:   []types.Flag{{Name: "json"}},
+	})
+	if !result.Success {
+		t.Fatalf("Expected success, got %+v", result)
+	}
+	if !strings.Contains(result.Instructions, "longer than the expected 100ms") {
+		t.Errorf("Expected a slow-execution note, got %q", result.Instructions)
+	}
+}
+
+func TestNoSlowCommandNoteWithinThreshold(t *testing.T) {
+	installMockFastly(t, `echo '[]'`)
+
+	SetSlowCommandThreshold(10 * time.Second)
+	defer SetSlowCommandThreshold(0)
+
+	result := ExecuteCommand(types.CommandRequest{
+		Command: "service",
+		Args:    []string{"list"},
+		Flags:   []types.Flag{{Name: "json"}},
+	})
+	if strings.Contains(result.Instructions, "longer than the expected") {
+		t.Errorf("Expected no slow-execution note, got %q", result.Instructions)
+	}
+
+	SetSlowCommandThreshold(0)
+	if note := slowCommandNote(time.Minute, CommandTimeout); note != "" {
+		t.Errorf("Expected no note with the threshold disabled, got %q", note)
+	}
+}
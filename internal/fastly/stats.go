@@ -0,0 +1,150 @@
+package fastly
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+// statsNonMetricFields are record fields that describe a stats sample rather
+// than being a metric themselves.
+var statsNonMetricFields = map[string]bool{
+	"start_time": true,
+	"service_id": true,
+}
+
+// NormalizeStatsOutput converts historical stats JSON into a flat array of typed
+// metric points (timestamp, metric name, value, unit). The raw API shape nests
+// samples under "Data", either as a list or keyed by service ID, and mixes every
+// metric into one object per sample, which is hard to filter and aggregate.
+// A flat array works directly with fastly_result_query (e.g. "metric=requests").
+// Output that is not recognizable stats JSON is returned unchanged.
+func NormalizeStatsOutput(output string, command string, args []string) string {
+	if command != "stats" || (len(args) > 0 && args[0] != "historical") {
+		return output
+	}
+
+	trimmedBytes := []byte(strings.TrimSpace(output))
+	if len(trimmedBytes) == 0 {
+		return output
+	}
+
+	var jsonData interface{}
+	if err := json.Unmarshal(trimmedBytes, &jsonData); err != nil {
+		return output
+	}
+
+	metrics, ok := normalizeStatsData(jsonData)
+	if !ok {
+		return output
+	}
+
+	result, err := json.Marshal(metrics)
+	if err != nil {
+		return output
+	}
+
+	return string(result)
+}
+
+// normalizeStatsData extracts metric points from the supported stats shapes:
+// a bare list of samples, or an object whose "Data" field holds either a list
+// of samples or a map of service ID to samples.
+func normalizeStatsData(data interface{}) ([]types.MetricPoint, bool) {
+	if obj, ok := data.(map[string]interface{}); ok {
+		inner, found := obj["Data"]
+		if !found {
+			inner, found = obj["data"]
+		}
+		if !found {
+			return nil, false
+		}
+		data = inner
+	}
+
+	metrics := []types.MetricPoint{}
+	switch v := data.(type) {
+	case []interface{}:
+		for _, sample := range v {
+			if !appendSampleMetrics(&metrics, sample, "") {
+				return nil, false
+			}
+		}
+	case map[string]interface{}:
+		serviceIDs := make([]string, 0, len(v))
+		for serviceID := range v {
+			serviceIDs = append(serviceIDs, serviceID)
+		}
+		sort.Strings(serviceIDs)
+
+		for _, serviceID := range serviceIDs {
+			samples, ok := v[serviceID].([]interface{})
+			if !ok {
+				return nil, false
+			}
+			for _, sample := range samples {
+				if !appendSampleMetrics(&metrics, sample, serviceID) {
+					return nil, false
+				}
+			}
+		}
+	default:
+		return nil, false
+	}
+
+	return metrics, true
+}
+
+// appendSampleMetrics adds one metric point per numeric field of a sample.
+// It returns false if the sample is not a stats object with a start_time.
+func appendSampleMetrics(metrics *[]types.MetricPoint, sample interface{}, serviceID string) bool {
+	obj, ok := sample.(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	timestamp, ok := obj["start_time"].(float64)
+	if !ok {
+		return false
+	}
+
+	if id, ok := obj["service_id"].(string); ok && id != "" {
+		serviceID = id
+	}
+
+	names := make([]string, 0, len(obj))
+	for name, value := range obj {
+		if _, isNumber := value.(float64); isNumber && !statsNonMetricFields[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		*metrics = append(*metrics, types.MetricPoint{
+			Timestamp: int64(timestamp),
+			ServiceID: serviceID,
+			Metric:    name,
+			Value:     obj[name].(float64),
+			Unit:      statsMetricUnit(name),
+		})
+	}
+
+	return true
+}
+
+// statsMetricUnit infers the unit of a stats metric from its field name.
+func statsMetricUnit(name string) string {
+	switch {
+	case strings.Contains(name, "ratio"):
+		return "ratio"
+	case strings.Contains(name, "bandwidth") || strings.Contains(name, "bytes") || strings.HasSuffix(name, "_size"):
+		return "bytes"
+	case strings.HasSuffix(name, "_time"):
+		return "seconds"
+	default:
+		return "count"
+	}
+}
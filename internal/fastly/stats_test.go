@@ -0,0 +1,96 @@
+package fastly
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestNormalizeStatsOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  string
+		args     []string
+		input    string
+		expected []types.MetricPoint
+	}{
+		{
+			name:    "data list with service_id per sample",
+			command: "stats",
+			args:    []string{"historical"},
+			input: `{"Status":"success","Meta":{"by":"day"},"Data":[
+				{"service_id":"svc1","start_time":1700000000,"requests":120,"hit_ratio":0.95,"bandwidth":2048,"miss_time":1.5}
+			]}`,
+			expected: []types.MetricPoint{
+				{Timestamp: 1700000000, ServiceID: "svc1", Metric: "bandwidth", Value: 2048, Unit: "bytes"},
+				{Timestamp: 1700000000, ServiceID: "svc1", Metric: "hit_ratio", Value: 0.95, Unit: "ratio"},
+				{Timestamp: 1700000000, ServiceID: "svc1", Metric: "miss_time", Value: 1.5, Unit: "seconds"},
+				{Timestamp: 1700000000, ServiceID: "svc1", Metric: "requests", Value: 120, Unit: "count"},
+			},
+		},
+		{
+			name:    "data keyed by service ID",
+			command: "stats",
+			args:    []string{"historical"},
+			input: `{"Data":{
+				"svcB":[{"start_time":1700000060,"requests":7}],
+				"svcA":[{"start_time":1700000000,"requests":5,"region":"europe"}]
+			}}`,
+			expected: []types.MetricPoint{
+				{Timestamp: 1700000000, ServiceID: "svcA", Metric: "requests", Value: 5, Unit: "count"},
+				{Timestamp: 1700000060, ServiceID: "svcB", Metric: "requests", Value: 7, Unit: "count"},
+			},
+		},
+		{
+			name:    "bare sample list",
+			command: "stats",
+			args:    []string{"historical"},
+			input:   `[{"start_time":1700000000,"resp_body_bytes":512}]`,
+			expected: []types.MetricPoint{
+				{Timestamp: 1700000000, Metric: "resp_body_bytes", Value: 512, Unit: "bytes"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := NormalizeStatsOutput(tt.input, tt.command, tt.args)
+
+			var got []types.MetricPoint
+			if err := json.Unmarshal([]byte(output), &got); err != nil {
+				t.Fatalf("Expected normalized JSON array, got %q: %v", output, err)
+			}
+			if len(got) != len(tt.expected) {
+				t.Fatalf("Expected %d metric points, got %d: %s", len(tt.expected), len(got), output)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("Point %d: expected %+v, got %+v", i, tt.expected[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNormalizeStatsOutputUnchanged(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		args    []string
+		input   string
+	}{
+		{"other command", "service", []string{"list"}, `[{"start_time":1,"requests":2}]`},
+		{"other stats subcommand", "stats", []string{"regions"}, `{"Data":["europe","usa"]}`},
+		{"not JSON", "stats", []string{"historical"}, "Requests: 100"},
+		{"samples without start_time", "stats", []string{"historical"}, `{"Data":[{"requests":2}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if output := NormalizeStatsOutput(tt.input, tt.command, tt.args); output != tt.input {
+				t.Errorf("Expected output unchanged, got %q", output)
+			}
+		})
+	}
+}
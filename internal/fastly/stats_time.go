@@ -0,0 +1,91 @@
+package fastly
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+// statsTimeFormat is the format a stats subcommand expects for its time flags.
+type statsTimeFormat string
+
+const (
+	// statsTimeUnix is a Unix timestamp in seconds
+	statsTimeUnix statsTimeFormat = "unix"
+	// statsTimeRFC3339 is an RFC 3339 timestamp in UTC
+	statsTimeRFC3339 statsTimeFormat = "rfc3339"
+)
+
+// statsTimeFlags are the flags that carry a point in time on stats commands.
+var statsTimeFlags = map[string]bool{
+	"from": true,
+	"to":   true,
+}
+
+// statsTimeFormats maps stats command paths to the time format they expect.
+// The historical stats API rejects ISO timestamps, while the inspector APIs
+// take RFC 3339.
+var statsTimeFormats = map[string]statsTimeFormat{
+	"stats historical":       statsTimeUnix,
+	"stats usage":            statsTimeUnix,
+	"stats domain-inspector": statsTimeRFC3339,
+	"stats origin-inspector": statsTimeRFC3339,
+}
+
+// statsTimeLayouts are the layouts recognized when reformatting a time value.
+var statsTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// normalizeStatsTimeFlags rewrites the time flags of a stats command into the
+// format its subcommand expects, accepting Unix and ISO input alike. Values that
+// cannot be parsed, such as the API's own relative expressions, are passed on
+// unchanged.
+func normalizeStatsTimeFlags(command string, args []string, flags []types.Flag) []types.Flag {
+	if command != "stats" || len(args) == 0 {
+		return flags
+	}
+	format, ok := statsTimeFormats[command+" "+args[0]]
+	if !ok {
+		return flags
+	}
+
+	result := make([]types.Flag, len(flags))
+	copy(result, flags)
+	for i, flag := range result {
+		if !statsTimeFlags[flag.Name] {
+			continue
+		}
+		t, ok := parseStatsTime(flag.Value)
+		if !ok {
+			continue
+		}
+		switch format {
+		case statsTimeUnix:
+			result[i].Value = strconv.FormatInt(t.Unix(), 10)
+		case statsTimeRFC3339:
+			result[i].Value = t.UTC().Format(time.RFC3339)
+		}
+	}
+	return result
+}
+
+// parseStatsTime parses a Unix timestamp or one of statsTimeLayouts. Values
+// without a zone are taken as UTC.
+func parseStatsTime(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if unix, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(unix, 0), true
+	}
+	for _, layout := range statsTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
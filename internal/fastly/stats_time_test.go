@@ -0,0 +1,84 @@
+package fastly
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestNormalizeStatsTimeFlags(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		from     string
+		to       string
+		wantTo   string
+		wantFrom string
+	}{
+		{
+			name:     "historical takes unix timestamps",
+			args:     []string{"historical"},
+			from:     "2026-10-01T00:00:00Z",
+			to:       "2026-10-02",
+			wantFrom: "1790812800",
+			wantTo:   "1790899200",
+		},
+		{
+			name:     "origin-inspector takes RFC 3339",
+			args:     []string{"origin-inspector"},
+			from:     "1790812800",
+			to:       "2026-10-01T02:00:00+02:00",
+			wantFrom: "2026-10-01T00:00:00Z",
+			wantTo:   "2026-10-01T00:00:00Z",
+		},
+		{
+			name:     "unparseable values pass through",
+			args:     []string{"historical"},
+			from:     "1 day ago",
+			to:       "now",
+			wantFrom: "1 day ago",
+			wantTo:   "now",
+		},
+		{
+			name:     "unknown subcommands are untouched",
+			args:     []string{"realtime"},
+			from:     "2026-10-01T00:00:00Z",
+			to:       "1790812800",
+			wantFrom: "2026-10-01T00:00:00Z",
+			wantTo:   "1790812800",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags := []types.Flag{{Name: "service-id", Value: "abc"}, {Name: "from", Value: tt.from}, {Name: "to", Value: tt.to}}
+			got := normalizeStatsTimeFlags("stats", tt.args, flags)
+			if got[0].Value != "abc" || got[1].Value != tt.wantFrom || got[2].Value != tt.wantTo {
+				t.Errorf("Expected from=%q to=%q, got %+v", tt.wantFrom, tt.wantTo, got)
+			}
+			if flags[1].Value != tt.from {
+				t.Error("Expected the input flags to be left unmodified")
+			}
+		})
+	}
+}
+
+func TestExecuteReformatsStatsTime(t *testing.T) {
+	callsFile := installMockFastly(t, `echo '{}'`)
+
+	ExecuteCommand(types.CommandRequest{
+		Command: "stats",
+		Args:    []string{"historical"},
+		Flags:   []types.Flag{{Name: "from", Value: "2026-10-01T00:00:00Z"}},
+	})
+
+	calls, err := os.ReadFile(callsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(calls), "stats historical --from 1790812800") {
+		t.Errorf("Expected --from as a Unix timestamp, got %q", calls)
+	}
+}
@@ -0,0 +1,36 @@
+package fastly
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// clockSkewPatterns indicate a certificate that is outside its validity period,
+// which for the Fastly API almost always means the local clock is wrong.
+var clockSkewPatterns = []string{
+	"certificate has expired",
+	"not yet valid",
+}
+
+// tlsErrorGuidance explains a TLS certificate verification failure. When the
+// certificate appears expired or not yet valid, the guidance names the server's
+// clock so the human user can compare it with the real time.
+func tlsErrorGuidance(message string, now time.Time) (string, []string) {
+	lower := strings.ToLower(message)
+	for _, pattern := range clockSkewPatterns {
+		if strings.Contains(lower, pattern) {
+			return fmt.Sprintf("The TLS certificate of the Fastly API was rejected as expired or not yet valid. This usually means the system clock is wrong: this machine reports the time as %s.", now.UTC().Format(time.RFC3339)), []string{
+				"Use the current_time tool and ask the human user to compare it with the actual time",
+				"If the clock is off, enable automatic time synchronization (NTP) and retry the command",
+				"Do not retry until the clock is corrected; the command will keep failing",
+			}
+		}
+	}
+
+	return "The TLS certificate of the Fastly API could not be verified. A proxy or security product may be intercepting the connection, or the system's CA certificates may be missing or outdated.", []string{
+		"Check whether a proxy, VPN or TLS-inspecting security product is in use",
+		"Update the system's CA certificates",
+		"Use the current_time tool to rule out a wrong system clock",
+	}
+}
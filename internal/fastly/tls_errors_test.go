@@ -0,0 +1,57 @@
+package fastly
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestDetectErrorCodeTLS(t *testing.T) {
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{`Get "https://api.fastly.com/service": tls: failed to verify certificate: x509: certificate has expired or is not yet valid: current time 2019-01-01T00:00:00Z is before 2024-05-01T00:00:00Z`, "tls_error"},
+		{"x509: certificate signed by unknown authority", "tls_error"},
+		{"invalid service ID", "validation_error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			if got := DetectErrorCode(tt.message); got != tt.want {
+				t.Errorf("DetectErrorCode(%q) = %q, want %q", tt.message, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTLSErrorGuidance(t *testing.T) {
+	now := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	instructions, steps := tlsErrorGuidance("x509: certificate has expired or is not yet valid", now)
+	if !strings.Contains(instructions, "system clock") || !strings.Contains(instructions, "2019-01-01T00:00:00Z") {
+		t.Errorf("Expected clock guidance naming the local time, got %q", instructions)
+	}
+	if !strings.Contains(strings.Join(steps, "\n"), "current_time") {
+		t.Errorf("Expected the next steps to point to current_time, got %v", steps)
+	}
+
+	instructions, _ = tlsErrorGuidance("x509: certificate signed by unknown authority", now)
+	if strings.Contains(instructions, "system clock") || !strings.Contains(instructions, "CA certificates") {
+		t.Errorf("Expected CA guidance for an unknown authority, got %q", instructions)
+	}
+}
+
+func TestTLSErrorResponse(t *testing.T) {
+	installMockFastly(t, `echo "Error: Get \"https://api.fastly.com/service\": tls: failed to verify certificate: x509: certificate has expired or is not yet valid" >&2; exit 1`)
+
+	result := ExecuteCommand(types.CommandRequest{Command: "service", Args: []string{"list"}})
+	if result.Success || result.ErrorCode != "tls_error" {
+		t.Fatalf("Expected tls_error, got %+v", result)
+	}
+	if !strings.Contains(result.Instructions, "system clock") {
+		t.Errorf("Expected clock guidance in the instructions, got %q", result.Instructions)
+	}
+}
@@ -0,0 +1,71 @@
+package fastly
+
+import (
+	"fmt"
+
+	"github.com/fastly/mcp/internal/cache"
+	"github.com/fastly/mcp/internal/types"
+)
+
+// Truncation kinds reported in types.TruncationInfo.
+const (
+	TruncationKindArray  = "array"
+	TruncationKindObject = "object"
+	TruncationKindText   = "text"
+)
+
+// paginationContinuation is the advice given when more data can be fetched by
+// re-running the command with pagination flags.
+const paginationContinuation = "Re-run the command with --page and --per-page flags to fetch the remaining results"
+
+// truncationFromPagination builds the standardized truncation object for inline
+// output truncated by TruncateOutput or TruncateJSONArray. It returns nil when
+// the output was not truncated.
+func truncationFromPagination(kind string, pagination *types.PaginationInfo) *types.TruncationInfo {
+	if pagination == nil || !pagination.Truncated {
+		return nil
+	}
+
+	unit := "bytes"
+	if kind == TruncationKindArray {
+		unit = "items"
+	}
+
+	return &types.TruncationInfo{
+		Truncated:    true,
+		Kind:         kind,
+		Total:        pagination.TotalSize,
+		Returned:     pagination.ReturnedSize,
+		Unit:         unit,
+		ContinueWith: paginationContinuation,
+	}
+}
+
+// truncationFromCache builds the standardized truncation object for output that
+// was cached, where only a preview is returned inline.
+func truncationFromCache(resultID string, metadata cache.ResultMetadata) *types.TruncationInfo {
+	info := &types.TruncationInfo{
+		Truncated:    true,
+		ResultID:     resultID,
+		ContinueWith: fmt.Sprintf("Use fastly_result_read with result_id=%s to page through the full output", resultID),
+	}
+
+	switch metadata.DataType {
+	case "json_array":
+		info.Kind = TruncationKindArray
+		info.Total = metadata.TotalItems
+		info.Returned = metadata.PreviewItems
+		info.Unit = "items"
+	case "json_object":
+		info.Kind = TruncationKindObject
+		info.Total = metadata.TotalSize
+		info.Unit = "bytes"
+	default:
+		info.Kind = TruncationKindText
+		info.Total = metadata.TotalLines
+		info.Returned = metadata.PreviewLines
+		info.Unit = "lines"
+	}
+
+	return info
+}
@@ -0,0 +1,161 @@
+package fastly
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/cache"
+	"github.com/fastly/mcp/internal/types"
+)
+
+func TestTruncationFromPagination(t *testing.T) {
+	if info := truncationFromPagination(TruncationKindText, nil); info != nil {
+		t.Errorf("Expected nil truncation for untruncated output, got %+v", info)
+	}
+
+	_, pagination := TruncateJSONArray(make([]interface{}, MaxJSONArrayItems+20))
+	info := truncationFromPagination(TruncationKindArray, pagination)
+	if info == nil {
+		t.Fatal("Expected truncation info for truncated array")
+	}
+	if !info.Truncated || info.Kind != TruncationKindArray || info.Unit != "items" {
+		t.Errorf("Unexpected truncation info: %+v", info)
+	}
+	if info.Total != MaxJSONArrayItems+20 || info.Returned != MaxJSONArrayItems {
+		t.Errorf("Expected total=%d returned=%d, got %+v", MaxJSONArrayItems+20, MaxJSONArrayItems, info)
+	}
+	if info.ContinueWith == "" {
+		t.Error("Expected continue_with guidance")
+	}
+
+	_, pagination = TruncateOutput(strings.Repeat("line\n", 200), 100)
+	info = truncationFromPagination(TruncationKindText, pagination)
+	if info == nil || info.Kind != TruncationKindText || info.Unit != "bytes" || info.Total != 1000 {
+		t.Errorf("Unexpected text truncation info: %+v", info)
+	}
+}
+
+func TestTruncationFromCache(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata cache.ResultMetadata
+		kind     string
+		total    int
+		returned int
+		unit     string
+	}{
+		{
+			name:     "array",
+			metadata: cache.ResultMetadata{DataType: "json_array", TotalItems: 300, PreviewItems: 5},
+			kind:     TruncationKindArray, total: 300, returned: 5, unit: "items",
+		},
+		{
+			name:     "object",
+			metadata: cache.ResultMetadata{DataType: "json_object", TotalSize: 40000},
+			kind:     TruncationKindObject, total: 40000, returned: 0, unit: "bytes",
+		},
+		{
+			name:     "text",
+			metadata: cache.ResultMetadata{DataType: "text", TotalLines: 900, PreviewLines: 20},
+			kind:     TruncationKindText, total: 900, returned: 20, unit: "lines",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := truncationFromCache("abc123", tt.metadata)
+			if !info.Truncated || info.Kind != tt.kind || info.Total != tt.total || info.Returned != tt.returned || info.Unit != tt.unit {
+				t.Errorf("Unexpected truncation info: %+v", info)
+			}
+			if info.ResultID != "abc123" || !strings.Contains(info.ContinueWith, "result_id=abc123") {
+				t.Errorf("Expected continuation referencing result abc123, got %+v", info)
+			}
+		})
+	}
+}
+
+func TestExecuteCommandReportsTruncation(t *testing.T) {
+	installMockFastly(t, `printf '['; i=1; while [ $i -lt 150 ]; do printf '%d,' $i; i=$((i+1)); done; printf '150]'`)
+
+	result := ExecuteCommand(types.CommandRequest{
+		Command: "service",
+		Args:    []string{"list"},
+		Flags:   []types.Flag{{Name: "json"}},
+	})
+
+	if !result.Success {
+		t.Fatalf("Expected success, got error: %s", result.Error)
+	}
+	if result.Truncation == nil {
+		t.Fatal("Expected truncation info for truncated array output")
+	}
+	if result.Truncation.Kind != TruncationKindArray || result.Truncation.Total != 150 || result.Truncation.Returned != MaxJSONArrayItems {
+		t.Errorf("Unexpected truncation info: %+v", result.Truncation)
+	}
+}
+
+func TestExecuteCommandReportsCachedTruncation(t *testing.T) {
+	installMockFastly(t, `i=1; while [ $i -le 50 ]; do echo "line $i"; i=$((i+1)); done`)
+
+	cache.SetOutputCacheThreshold(100)
+	defer cache.SetOutputCacheThreshold(cache.DefaultOutputCacheThreshold)
+
+	result := ExecuteCommand(types.CommandRequest{
+		Command: "service",
+		Args:    []string{"list"},
+	})
+
+	if !result.Success || !result.Cached {
+		t.Fatalf("Expected cached success, got %+v", result)
+	}
+	if result.Truncation == nil {
+		t.Fatal("Expected truncation info for cached output")
+	}
+	if result.Truncation.Kind != TruncationKindText || result.Truncation.Unit != "lines" || result.Truncation.ResultID != result.ResultID {
+		t.Errorf("Unexpected truncation info: %+v", result.Truncation)
+	}
+	if result.Truncation.Total <= result.Truncation.Returned {
+		t.Errorf("Expected total > returned, got %+v", result.Truncation)
+	}
+}
+
+func TestExecuteCommandNoTruncation(t *testing.T) {
+	installMockFastly(t, `echo '[]'`)
+
+	result := ExecuteCommand(types.CommandRequest{
+		Command: "service",
+		Args:    []string{"list"},
+		Flags:   []types.Flag{{Name: "json"}},
+	})
+
+	if result.Truncation != nil {
+		t.Errorf("Expected no truncation info, got %+v", result.Truncation)
+	}
+}
+
+func TestConfiguredResponseLimits(t *testing.T) {
+	SetMaxJSONArrayItems(10)
+	SetMaxOutputSize(200)
+	defer SetMaxJSONArrayItems(0)
+	defer SetMaxOutputSize(0)
+
+	if size, items := ResponseLimits(); size != 200 || items != 10 {
+		t.Errorf("ResponseLimits() = %d, %d, want 200, 10", size, items)
+	}
+
+	truncated, pagination := TruncateJSONArray(make([]interface{}, 25))
+	if items, ok := truncated.([]interface{}); !ok || len(items) != 10 || pagination == nil || pagination.ReturnedSize != 10 {
+		t.Errorf("Expected the array to be truncated to 10 items, got %v", pagination)
+	}
+
+	object := map[string]interface{}{"data": strings.Repeat("x", 300)}
+	if _, pagination := TruncateJSONArray(object); pagination == nil || !pagination.Truncated {
+		t.Error("Expected an object above the configured output size to be truncated")
+	}
+
+	installMockFastly(t, `i=1; while [ $i -le 60 ]; do echo "line $i"; i=$((i+1)); done`)
+	result := ExecuteCommand(types.CommandRequest{Command: "service", Args: []string{"describe"}})
+	if !result.Success || result.Truncation == nil || result.Truncation.Returned > 200 {
+		t.Errorf("Expected text output truncated to 200 bytes, got %+v", result.Truncation)
+	}
+}
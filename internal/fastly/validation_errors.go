@@ -19,6 +19,19 @@ func ArgValidationError(command string, args []string, err error) types.CommandR
 		Build()
 }
 
+// RawArgValidationError creates a validation error response for invalid raw arguments
+func RawArgValidationError(command string, args []string, flags []types.Flag, err error) types.CommandResponse {
+	return NewResponseBuilder().
+		WithCommand(command, args, flags).
+		WithError(err, "validation_error").
+		WithInstructions("The raw arguments are not available.", []string{
+			"Check raw_args for forbidden characters or patterns",
+			"Ensure raw arguments don't contain shell metacharacters",
+			"Pass each token as a separate raw_args entry",
+		}).
+		Build()
+}
+
 // FlagNameValidationError creates a validation error response for invalid flag names
 func FlagNameValidationError(command string, args []string, flags []types.Flag, flagName string, err error) types.CommandResponse {
 	return NewResponseBuilder().
@@ -45,6 +58,19 @@ func FlagValueValidationError(command string, args []string, flags []types.Flag,
 		Build()
 }
 
+// ServiceIDValidationError creates a validation error response for a malformed
+// service ID
+func ServiceIDValidationError(command string, args []string, flags []types.Flag, err error) types.CommandResponse {
+	return NewResponseBuilder().
+		WithCommand(command, args, flags).
+		WithError(err, "invalid_service_id").
+		WithInstructions("The service ID is malformed. Service IDs consist of letters and digits only and are case-sensitive.", []string{
+			"Copy the service ID again without surrounding quotes, punctuation or URL parts",
+			"Use fastly_execute with 'service list' to look up the exact service ID",
+		}).
+		Build()
+}
+
 // PathValidationError creates a validation error response for invalid file paths
 func PathValidationError(command string, args []string, flags []types.Flag, flagName string, err error) types.CommandResponse {
 	return NewResponseBuilder().
@@ -0,0 +1,112 @@
+package fastly
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/fastly/mcp/internal/cache"
+	"github.com/fastly/mcp/internal/types"
+)
+
+// versionDiffSection describes one kind of versioned resource compared by
+// DiffServiceVersions, and the list command used to fetch it.
+type versionDiffSection struct {
+	Name    string
+	Command string
+	Key     string
+}
+
+// versionDiffSections lists the versioned resources that are compared. Service
+// level settings (such as default TTL) are not exposed by a list command and are
+// therefore not part of the diff.
+var versionDiffSections = []versionDiffSection{
+	{Name: "backends", Command: "backend", Key: "Name"},
+	{Name: "domains", Command: "domain", Key: "Name"},
+	{Name: "healthchecks", Command: "healthcheck", Key: "Name"},
+}
+
+// versionDiffIgnoredFields are bookkeeping fields that differ between versions
+// without reflecting a configuration change.
+var versionDiffIgnoredFields = []string{"ServiceID", "ServiceVersion", "Version", "CreatedAt", "UpdatedAt", "DeletedAt"}
+
+// versionRegex matches a version number or one of the CLI's version aliases.
+var versionRegex = regexp.MustCompile(`^([0-9]+|active|latest)$`)
+
+// DiffServiceVersions compares the backends, domains and health checks of two
+// versions of a service. Each list is fetched through ExecuteCommandContext, so
+// the usual validation, allowlist and sanitization rules apply.
+func DiffServiceVersions(ctx context.Context, serviceID, fromVersion, toVersion string) (types.VersionDiff, error) {
+	if serviceID == "" {
+		return types.VersionDiff{}, fmt.Errorf("service_id is required")
+	}
+	for _, version := range []string{fromVersion, toVersion} {
+		if !versionRegex.MatchString(version) {
+			return types.VersionDiff{}, fmt.Errorf("invalid version %q: use a version number, 'active' or 'latest'", version)
+		}
+	}
+
+	diff := types.VersionDiff{
+		ServiceID:   serviceID,
+		FromVersion: fromVersion,
+		ToVersion:   toVersion,
+		Sections:    map[string]types.ResourceDiff{},
+		Identical:   true,
+	}
+
+	for _, section := range versionDiffSections {
+		fromItems, err := listVersionResources(ctx, section, serviceID, fromVersion)
+		if err != nil {
+			return types.VersionDiff{}, err
+		}
+		toItems, err := listVersionResources(ctx, section, serviceID, toVersion)
+		if err != nil {
+			return types.VersionDiff{}, err
+		}
+
+		sectionDiff := DiffByKey(fromItems, toItems, section.Key, versionDiffIgnoredFields)
+		if len(sectionDiff.Added) > 0 || len(sectionDiff.Removed) > 0 || len(sectionDiff.Changed) > 0 {
+			diff.Identical = false
+		}
+		diff.Sections[section.Name] = sectionDiff
+	}
+
+	return diff, nil
+}
+
+// listVersionResources runs the list command of a section for one version and
+// returns the parsed JSON array.
+func listVersionResources(ctx context.Context, section versionDiffSection, serviceID, version string) ([]interface{}, error) {
+	response := ExecuteCommandContext(ctx, types.CommandRequest{
+		Command: section.Command,
+		Args:    []string{"list"},
+		Flags: []types.Flag{
+			{Name: "service-id", Value: serviceID},
+			{Name: "version", Value: version},
+			{Name: "json"},
+		},
+	})
+	if !response.Success {
+		return nil, fmt.Errorf("failed to list %s for version %s: %s", section.Name, version, response.Error)
+	}
+
+	data := response.OutputJSON
+	if response.Cached {
+		cached, err := cache.GetStore().Get(response.ResultID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cached %s for version %s: %w", section.Name, version, err)
+		}
+		data = cached.Data
+	} else if response.Truncation != nil {
+		return nil, fmt.Errorf("%s for version %s were truncated and cannot be compared", section.Name, version)
+	}
+
+	if data == nil {
+		return []interface{}{}, nil
+	}
+	items, ok := data.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected output listing %s for version %s: expected a JSON array", section.Name, version)
+	}
+	return items, nil
+}
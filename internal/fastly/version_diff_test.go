@@ -0,0 +1,59 @@
+package fastly
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+func TestDiffServiceVersions(t *testing.T) {
+	installMockFastly(t, `case "$*" in
+*"backend list"*"--version 1"*)
+	echo '[{"Name":"origin_a","Address":"a.example.com","ServiceVersion":1},{"Name":"origin_b","Address":"b.example.com","ServiceVersion":1}]' ;;
+*"backend list"*"--version 2"*)
+	echo '[{"Name":"origin_a","Address":"a.example.com","ServiceVersion":2},{"Name":"origin_new","Address":"new.example.com","ServiceVersion":2}]' ;;
+*"domain list"*)
+	echo '[{"Name":"www.example.com","Comment":""}]' ;;
+*)
+	echo '[]' ;;
+esac`)
+
+	diff, err := DiffServiceVersions(context.Background(), "SVC123", "1", "2")
+	if err != nil {
+		t.Fatalf("DiffServiceVersions failed: %v", err)
+	}
+
+	if diff.Identical {
+		t.Error("Expected versions with backend changes not to be identical")
+	}
+
+	backends := diff.Sections["backends"]
+	if len(backends.Added) != 1 || backends.Added[0].(map[string]interface{})["Name"] != "origin_new" {
+		t.Errorf("Expected origin_new to be added, got %v", backends.Added)
+	}
+	if len(backends.Removed) != 1 || backends.Removed[0].(map[string]interface{})["Name"] != "origin_b" {
+		t.Errorf("Expected origin_b to be removed, got %v", backends.Removed)
+	}
+	if len(backends.Changed) != 0 || backends.Unchanged != 1 {
+		t.Errorf("Expected origin_a unchanged despite its version number, got %+v", backends)
+	}
+
+	domains := diff.Sections["domains"]
+	if len(domains.Added)+len(domains.Removed)+len(domains.Changed) != 0 || domains.Unchanged != 1 {
+		t.Errorf("Expected domains to be unchanged, got %+v", domains)
+	}
+}
+
+func TestDiffServiceVersionsInvalidVersion(t *testing.T) {
+	callsFile := installMockFastly(t, `echo '[]'`)
+
+	if _, err := DiffServiceVersions(context.Background(), "SVC123", "1; rm -rf /", "2"); err == nil {
+		t.Error("Expected an error for an invalid version")
+	}
+	if _, err := DiffServiceVersions(context.Background(), "", "1", "2"); err == nil {
+		t.Error("Expected an error for a missing service ID")
+	}
+	if _, err := os.Stat(callsFile); !os.IsNotExist(err) {
+		t.Error("Expected no CLI calls for invalid input")
+	}
+}
@@ -21,7 +21,7 @@ func (ft *FastlyTool) makeBackgroundStartHandler() mcp.ToolHandler {
 		command, ok := params["command"].(string)
 		if !ok || command == "" {
 			err := fmt.Errorf("command parameter is required")
-			LogCommand("fastly_background_start", params, nil, err, time.Since(start))
+			LogCommand(request, "fastly_background_start", params, nil, err, time.Since(start))
 			return nil, err
 		}
 
@@ -74,7 +74,7 @@ func (ft *FastlyTool) makeBackgroundStartHandler() mcp.ToolHandler {
 			}
 
 			// Validate the command is allowed
-			validator := fastly.GetValidator()
+			validator := fastly.ContextValidator(ctx)
 			if err := validator.ValidateCommand(command); err != nil {
 				return newErrorResult(background.StartResponse{
 					Success: false,
@@ -90,7 +90,8 @@ func (ft *FastlyTool) makeBackgroundStartHandler() mcp.ToolHandler {
 
 			// Start the background job
 			manager := background.GetManager()
-			resp, err := manager.Start(ctx, command, args, flags)
+			label, _ := params["label"].(string)
+			resp, err := manager.Start(ctx, command, args, flags, label)
 			if err != nil {
 				return newErrorResult(background.StartResponse{
 					Success: false,
@@ -104,7 +105,7 @@ func (ft *FastlyTool) makeBackgroundStartHandler() mcp.ToolHandler {
 			return newErrorResult(resp), nil
 		})
 
-		LogCommand("fastly_background_start", params, result, err, time.Since(start))
+		LogCommand(request, "fastly_background_start", params, result, err, time.Since(start))
 		return result, err
 	}
 }
@@ -118,7 +119,7 @@ func makeBackgroundStopHandler() mcp.ToolHandler {
 		jobID, ok := params["job_id"].(string)
 		if !ok || jobID == "" {
 			err := fmt.Errorf("job_id parameter is required")
-			LogCommand("fastly_background_stop", params, nil, err, time.Since(start))
+			LogCommand(request, "fastly_background_stop", params, nil, err, time.Since(start))
 			return nil, err
 		}
 
@@ -130,7 +131,7 @@ func makeBackgroundStopHandler() mcp.ToolHandler {
 				JobID:   jobID,
 				Error:   err.Error(),
 			})
-			LogCommand("fastly_background_stop", params, result, err, time.Since(start))
+			LogCommand(request, "fastly_background_stop", params, result, err, time.Since(start))
 			return result, nil
 		}
 
@@ -141,7 +142,28 @@ func makeBackgroundStopHandler() mcp.ToolHandler {
 			result = newErrorResult(resp)
 		}
 
-		LogCommand("fastly_background_stop", params, result, nil, time.Since(start))
+		LogCommand(request, "fastly_background_stop", params, result, nil, time.Since(start))
+		return result, nil
+	}
+}
+
+// makeBackgroundStopAllHandler creates a handler for stopping every running background job.
+func makeBackgroundStopAllHandler() mcp.ToolHandler {
+	return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		params := getArguments(request)
+
+		manager := background.GetManager()
+		resp := manager.StopAll()
+
+		var result *mcp.CallToolResult
+		if resp.Success {
+			result = newSuccessResult(resp)
+		} else {
+			result = newErrorResult(resp)
+		}
+
+		LogCommand(request, "fastly_background_stop_all", params, result, nil, time.Since(start))
 		return result, nil
 	}
 }
@@ -156,7 +178,7 @@ func makeBackgroundListHandler() mcp.ToolHandler {
 		resp := manager.List()
 
 		result := newSuccessResult(resp)
-		LogCommand("fastly_background_list", params, result, nil, time.Since(start))
+		LogCommand(request, "fastly_background_list", params, result, nil, time.Since(start))
 		return result, nil
 	}
 }
@@ -170,7 +192,7 @@ func makeBackgroundStatusHandler() mcp.ToolHandler {
 		jobID, ok := params["job_id"].(string)
 		if !ok || jobID == "" {
 			err := fmt.Errorf("job_id parameter is required")
-			LogCommand("fastly_background_status", params, nil, err, time.Since(start))
+			LogCommand(request, "fastly_background_status", params, nil, err, time.Since(start))
 			return nil, err
 		}
 
@@ -182,7 +204,7 @@ func makeBackgroundStatusHandler() mcp.ToolHandler {
 				JobID:   jobID,
 				Error:   err.Error(),
 			})
-			LogCommand("fastly_background_status", params, result, err, time.Since(start))
+			LogCommand(request, "fastly_background_status", params, result, err, time.Since(start))
 			return result, nil
 		}
 
@@ -193,7 +215,7 @@ func makeBackgroundStatusHandler() mcp.ToolHandler {
 			result = newErrorResult(resp)
 		}
 
-		LogCommand("fastly_background_status", params, result, nil, time.Since(start))
+		LogCommand(request, "fastly_background_status", params, result, nil, time.Since(start))
 		return result, nil
 	}
 }
@@ -207,7 +229,7 @@ func makeBackgroundReadHandler() mcp.ToolHandler {
 		jobID, ok := params["job_id"].(string)
 		if !ok || jobID == "" {
 			err := fmt.Errorf("job_id parameter is required")
-			LogCommand("fastly_background_read", params, nil, err, time.Since(start))
+			LogCommand(request, "fastly_background_read", params, nil, err, time.Since(start))
 			return nil, err
 		}
 
@@ -229,7 +251,7 @@ func makeBackgroundReadHandler() mcp.ToolHandler {
 				"job_id":  jobID,
 				"error":   err.Error(),
 			})
-			LogCommand("fastly_background_read", params, result, err, time.Since(start))
+			LogCommand(request, "fastly_background_read", params, result, err, time.Since(start))
 			return result, nil
 		}
 
@@ -243,7 +265,7 @@ func makeBackgroundReadHandler() mcp.ToolHandler {
 			"has_more":    output.HasMore,
 		})
 
-		LogCommand("fastly_background_read", params, result, nil, time.Since(start))
+		LogCommand(request, "fastly_background_read", params, result, nil, time.Since(start))
 		return result, nil
 	}
 }
@@ -257,14 +279,14 @@ func makeBackgroundQueryHandler() mcp.ToolHandler {
 		jobID, ok := params["job_id"].(string)
 		if !ok || jobID == "" {
 			err := fmt.Errorf("job_id parameter is required")
-			LogCommand("fastly_background_query", params, nil, err, time.Since(start))
+			LogCommand(request, "fastly_background_query", params, nil, err, time.Since(start))
 			return nil, err
 		}
 
 		pattern, ok := params["pattern"].(string)
 		if !ok || pattern == "" {
 			err := fmt.Errorf("pattern parameter is required")
-			LogCommand("fastly_background_query", params, nil, err, time.Since(start))
+			LogCommand(request, "fastly_background_query", params, nil, err, time.Since(start))
 			return nil, err
 		}
 
@@ -281,7 +303,7 @@ func makeBackgroundQueryHandler() mcp.ToolHandler {
 				"job_id":  jobID,
 				"error":   err.Error(),
 			})
-			LogCommand("fastly_background_query", params, result, err, time.Since(start))
+			LogCommand(request, "fastly_background_query", params, result, err, time.Since(start))
 			return result, nil
 		}
 
@@ -294,7 +316,7 @@ func makeBackgroundQueryHandler() mcp.ToolHandler {
 			"truncated":   queryResult.Truncated,
 		})
 
-		LogCommand("fastly_background_query", params, result, nil, time.Since(start))
+		LogCommand(request, "fastly_background_query", params, result, nil, time.Since(start))
 		return result, nil
 	}
 }
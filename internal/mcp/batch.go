@@ -0,0 +1,185 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	// MaxBatchRequests is the maximum number of messages in one JSON-RPC batch.
+	MaxBatchRequests = 32
+
+	// MaxRequestBodySize is the maximum size in bytes of a POST body, batch or
+	// not. Larger bodies are refused with 413 before they are buffered.
+	MaxRequestBodySize = 4 << 20
+
+	// batchConcurrency bounds how many batched messages are handled at once,
+	// across all batches of the server, so concurrent batches share the limit.
+	batchConcurrency = 4
+
+	// jsonrpcInternalError is the JSON-RPC error code for a message the MCP
+	// handler refused without a JSON-RPC response of its own.
+	jsonrpcInternalError = -32603
+)
+
+// batchSlots holds a slot for each batched message being handled.
+var batchSlots = make(chan struct{}, batchConcurrency)
+
+// batchMessage is the part of a JSON-RPC message the batch handler needs.
+type batchMessage struct {
+	ID json.RawMessage `json:"id,omitempty"`
+}
+
+// withBatching lets the MCP handler accept JSON-RPC batches on any protocol
+// version. A POST body holding an array is split into single messages, which
+// are handled at most batchConcurrency at a time across the server; the
+// responses to the calls are returned as an array in request order. Other
+// requests pass through. Every POST body is read up front, as the MCP handler
+// reads it whole too, so it is limited to MaxRequestBodySize.
+func withBatching(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxRequestBodySize))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				http.Error(w, fmt.Sprintf("request body exceeds the maximum of %d bytes", MaxRequestBodySize), http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+		if !bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")) {
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		var messages []json.RawMessage
+		if err := json.Unmarshal(body, &messages); err != nil {
+			http.Error(w, fmt.Sprintf("malformed batch: %v", err), http.StatusBadRequest)
+			return
+		}
+		if len(messages) == 0 {
+			http.Error(w, "batch is empty", http.StatusBadRequest)
+			return
+		}
+		if len(messages) > MaxBatchRequests {
+			http.Error(w, fmt.Sprintf("batch has %d messages, the maximum is %d", len(messages), MaxBatchRequests), http.StatusBadRequest)
+			return
+		}
+
+		results := make([]*bufferedResponse, len(messages))
+		var wg sync.WaitGroup
+		for i, message := range messages {
+			wg.Add(1)
+			go func(i int, message json.RawMessage) {
+				defer wg.Done()
+				batchSlots <- struct{}{}
+				defer func() { <-batchSlots }()
+
+				single := r.Clone(r.Context())
+				single.Body = io.NopCloser(bytes.NewReader(message))
+				single.ContentLength = int64(len(message))
+				results[i] = newBufferedResponse()
+				handler.ServeHTTP(results[i], single)
+			}(i, message)
+		}
+		wg.Wait()
+
+		var responses []json.RawMessage
+		for i, result := range results {
+			var request batchMessage
+			_ = json.Unmarshal(messages[i], &request)
+			if isJSONNull(request.ID) {
+				// Notifications get no response
+				continue
+			}
+			if sessionID := result.Header().Get("Mcp-Session-Id"); sessionID != "" {
+				w.Header().Set("Mcp-Session-Id", sessionID)
+			}
+			responses = append(responses, batchResponse(request.ID, result))
+		}
+
+		if len(responses) == 0 {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(responses)
+	})
+}
+
+// batchResponse extracts the JSON-RPC response to the call with the given id
+// from a recorded single-message response, which is either JSON or an event
+// stream. A response without one becomes a JSON-RPC error.
+func batchResponse(id json.RawMessage, result *bufferedResponse) json.RawMessage {
+	var candidates [][]byte
+	if strings.HasPrefix(result.Header().Get("Content-Type"), "text/event-stream") {
+		for _, event := range strings.Split(result.body.String(), "\n\n") {
+			var data []string
+			for _, line := range strings.Split(event, "\n") {
+				if value, ok := strings.CutPrefix(line, "data:"); ok {
+					data = append(data, strings.TrimPrefix(value, " "))
+				}
+			}
+			if len(data) > 0 {
+				candidates = append(candidates, []byte(strings.Join(data, "\n")))
+			}
+		}
+	} else {
+		candidates = append(candidates, result.body.Bytes())
+	}
+
+	for _, candidate := range candidates {
+		var message batchMessage
+		if json.Unmarshal(candidate, &message) == nil && bytes.Equal(message.ID, id) {
+			return candidate
+		}
+	}
+
+	errorResponse, _ := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"error": map[string]interface{}{
+			"code":    jsonrpcInternalError,
+			"message": fmt.Sprintf("HTTP %d: %s", result.status, strings.TrimSpace(result.body.String())),
+		},
+	})
+	return errorResponse
+}
+
+// isJSONNull reports whether a raw JSON value is absent or null.
+func isJSONNull(value json.RawMessage) bool {
+	return len(value) == 0 || string(value) == "null"
+}
+
+// bufferedResponse records the response to one message of a batch.
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newBufferedResponse() *bufferedResponse {
+	return &bufferedResponse{header: make(http.Header), status: http.StatusOK}
+}
+
+func (b *bufferedResponse) Header() http.Header { return b.header }
+
+func (b *bufferedResponse) Write(data []byte) (int, error) { return b.body.Write(data) }
+
+func (b *bufferedResponse) WriteHeader(status int) { b.status = status }
+
+// Flush satisfies http.Flusher for event streams; the response is buffered.
+func (b *bufferedResponse) Flush() {}
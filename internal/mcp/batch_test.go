@@ -0,0 +1,178 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestHTTPBatchRequests(t *testing.T) {
+	server, err := CreateServer()
+	if err != nil {
+		t.Fatalf("CreateServer failed: %v", err)
+	}
+	httpServer := httptest.NewServer(withBatching(mcp.NewStreamableHTTPHandler(func(r *http.Request) *mcp.Server {
+		return server
+	}, nil)))
+	defer httpServer.Close()
+
+	var sessionID string
+	post := func(body string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, httpServer.URL, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		req.Header.Set("Mcp-Protocol-Version", "2025-06-18")
+		if sessionID != "" {
+			req.Header.Set("Mcp-Session-Id", sessionID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		return resp
+	}
+
+	resp := post(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"batch-test","version":"1.0.0"}}}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("initialize failed with status %d", resp.StatusCode)
+	}
+	sessionID = resp.Header.Get("Mcp-Session-Id")
+	if resp := post(`[{"jsonrpc":"2.0","method":"notifications/initialized"}]`); resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("Expected a batch of notifications to be accepted, got status %d", resp.StatusCode)
+	}
+
+	resp = post(`[
+		{"jsonrpc":"2.0","id":"time","method":"tools/call","params":{"name":"current_time","arguments":{}}},
+		{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"fastly_result_stats","arguments":{}}}
+	]`)
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/json" {
+		t.Fatalf("Expected a JSON batch response, got status %d and %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	var responses []struct {
+		ID     json.RawMessage `json:"id"`
+		Result struct {
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+		} `json:"result"`
+		Error json.RawMessage `json:"error"`
+	}
+	var body bytes.Buffer
+	_, _ = body.ReadFrom(resp.Body)
+	if err := json.Unmarshal(body.Bytes(), &responses); err != nil {
+		t.Fatalf("Expected an array of responses, got %q: %v", body.String(), err)
+	}
+	if len(responses) != 2 {
+		t.Fatalf("Expected 2 responses, got %d: %s", len(responses), body.String())
+	}
+	if string(responses[0].ID) != `"time"` || string(responses[1].ID) != "7" {
+		t.Errorf("Expected responses in request order, got ids %s and %s", responses[0].ID, responses[1].ID)
+	}
+	for i, response := range responses {
+		if response.Error != nil || len(response.Result.Content) == 0 {
+			t.Errorf("Expected response %d to hold a tool result, got %s", i, body.String())
+		}
+	}
+	if !strings.Contains(responses[0].Result.Content[0].Text, "unix") || !strings.Contains(responses[1].Result.Content[0].Text, "entries") {
+		t.Errorf("Expected each result to come from its own tool, got %s", body.String())
+	}
+}
+
+func TestHTTPBatchLimits(t *testing.T) {
+	handler := withBatching(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "refused", http.StatusBadRequest)
+	}))
+
+	tooMany := "[" + strings.TrimSuffix(strings.Repeat(`{"jsonrpc":"2.0","id":1,"method":"ping"},`, MaxBatchRequests+1), ",") + "]"
+	for _, body := range []string{"[]", tooMany, "[{"} {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %.40q, got %d", body, recorder.Code)
+		}
+	}
+
+	// Oversized bodies are refused whether or not they are batches
+	for _, prefix := range []string{"[", "{"} {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(prefix+strings.Repeat(" ", MaxRequestBodySize))))
+		if recorder.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("Expected status 413 for an oversized body starting with %q, got %d", prefix, recorder.Code)
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`[{"jsonrpc":"2.0","id":3,"method":"ping"}]`)))
+	if !strings.Contains(recorder.Body.String(), `"id":3`) || !strings.Contains(recorder.Body.String(), "refused") {
+		t.Errorf("Expected a refused message to become a JSON-RPC error, got %s", recorder.Body.String())
+	}
+}
+
+func TestHTTPBatchConcurrency(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	handler := withBatching(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			previous := peak.Load()
+			if current <= previous || peak.CompareAndSwap(previous, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		var body bytes.Buffer
+		_, _ = body.ReadFrom(r.Body)
+		var message batchMessage
+		_ = json.Unmarshal(body.Bytes(), &message)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(message.ID) + `,"result":{}}`))
+	}))
+
+	var messages []string
+	for i := 1; i <= batchConcurrency*3; i++ {
+		messages = append(messages, fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"ping"}`, i))
+	}
+
+	// Concurrent batches share the limit
+	var wg sync.WaitGroup
+	for batch := 0; batch < 3; batch++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("["+strings.Join(messages, ",")+"]")))
+
+			var responses []batchMessage
+			if err := json.Unmarshal(recorder.Body.Bytes(), &responses); err != nil || len(responses) != len(messages) {
+				t.Errorf("Expected %d responses, got %s", len(messages), recorder.Body.String())
+				return
+			}
+			for i, response := range responses {
+				if string(response.ID) != strconv.Itoa(i+1) {
+					t.Errorf("Expected response %d to answer id %d, got %s", i, i+1, response.ID)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if peak.Load() > batchConcurrency {
+		t.Errorf("Expected at most %d messages in flight, got %d", batchConcurrency, peak.Load())
+	}
+}
@@ -0,0 +1,30 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/fastly/mcp/internal/fastly"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// callerScope returns the identity that state kept between calls is scoped
+// to: the client identity when the request has one, or else its HTTP session.
+// It returns an empty string over stdio, which serves a single client.
+func callerScope(req mcp.Request) string {
+	if identity := extraIdentity(req.GetExtra()); identity != "" {
+		return "client:" + identity
+	}
+	if session, ok := req.GetSession().(*mcp.ServerSession); ok && session != nil && session.ID() != "" {
+		return "session:" + session.ID()
+	}
+	return ""
+}
+
+// withCallerScope makes every request to s run on behalf of its callerScope.
+func withCallerScope(s *mcp.Server) {
+	s.AddReceivingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			return next(fastly.WithCaller(ctx, callerScope(req)), method, req)
+		}
+	})
+}
@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/fastly/mcp/internal/fastly"
 )
 
 // CommandContext stores reusable values from previous commands
@@ -42,6 +45,7 @@ type CommandRecord struct {
 type Flag struct {
 	Name  string
 	Value string
+	Lines []string
 }
 
 var globalContext = &CommandContext{
@@ -123,15 +127,48 @@ func resolveServiceReferences(flags []Flag) []Flag {
 	for _, flag := range flags {
 		if flag.Name == "service-id" || flag.Name == "service" {
 			// Check if it's a name that needs resolution
-			if id, exists := globalContext.ServiceNameToID[flag.Value]; exists {
-				flag.Value = id
-			}
+			flag.Value = lookupServiceID(flag.Value)
 		}
 		result = append(result, flag)
 	}
 	return result
 }
 
+// resolveServiceID returns the ID of a service name seen in an earlier service
+// list, or the value unchanged if it is not a known name. Every tool that takes
+// a service goes through it, so names resolve the same way everywhere.
+func resolveServiceID(value string) string {
+	globalContext.mu.RLock()
+	defer globalContext.mu.RUnlock()
+	return lookupServiceID(value)
+}
+
+// lookupServiceID is resolveServiceID for callers holding the context lock.
+// With service ID normalization, surrounding whitespace is trimmed and an ID
+// that differs from a known one only in case is corrected.
+func lookupServiceID(value string) string {
+	normalize := fastly.ServiceIDNormalization()
+	if normalize {
+		value = strings.TrimSpace(value)
+	}
+	if id, exists := globalContext.ServiceNameToID[value]; exists {
+		return id
+	}
+	if normalize {
+		corrected := value
+		for _, id := range globalContext.ServiceNameToID {
+			if id == value {
+				return value
+			}
+			if strings.EqualFold(id, value) {
+				corrected = id
+			}
+		}
+		return corrected
+	}
+	return value
+}
+
 func applySmartDefaults(cmd string, args []string, flags []Flag) []Flag {
 	// Add JSON output for list commands if not specified
 	if len(args) > 0 && args[0] == "list" && !hasFlag(flags, "json") {
@@ -140,6 +177,11 @@ func applySmartDefaults(cmd string, args []string, flags []Flag) []Flag {
 		}
 	}
 
+	// Add the operator-configured page size if the agent did not choose one
+	if perPage, ok := defaultPerPage(cmd, args); ok && !hasFlag(flags, "per-page") {
+		flags = append(flags, Flag{Name: "per-page", Value: strconv.Itoa(perPage)})
+	}
+
 	// Add service-id if missing but we have context AND no other service identification is present
 	if requiresServiceID(cmd, args) && !hasServiceIdentification(flags) && globalContext.LastServiceID != "" {
 		flags = append(flags, Flag{Name: "service-id", Value: globalContext.LastServiceID})
@@ -2,6 +2,8 @@ package mcp
 
 import (
 	"testing"
+
+	"github.com/fastly/mcp/internal/fastly"
 )
 
 func TestHasServiceIdentification(t *testing.T) {
@@ -622,3 +624,34 @@ func TestExtractContextUsesRawOutputString(t *testing.T) {
 		t.Fatalf("expected context extraction to set active version, got %q", got)
 	}
 }
+
+func TestResolveServiceReferencesNormalizesServiceIDs(t *testing.T) {
+	originalServiceNameToID := globalContext.ServiceNameToID
+	defer func() { globalContext.ServiceNameToID = originalServiceNameToID }()
+	globalContext.ServiceNameToID = map[string]string{"shop": "SU1Z0isxPaozGVKXdv0eY"}
+
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{" shop ", "SU1Z0isxPaozGVKXdv0eY"},
+		{"su1z0isxpaozgvkxdv0ey", "SU1Z0isxPaozGVKXdv0eY"},
+		{" SU1Z0isxPaozGVKXdv0eY\t", "SU1Z0isxPaozGVKXdv0eY"},
+		{"unknownServiceId0001", "unknownServiceId0001"},
+	}
+
+	fastly.SetServiceIDNormalization(true)
+	for _, tt := range tests {
+		flags := resolveServiceReferences([]Flag{{Name: "service-id", Value: tt.value}})
+		if flags[0].Value != tt.expected {
+			t.Errorf("resolveServiceReferences(%q) = %q, want %q", tt.value, flags[0].Value, tt.expected)
+		}
+	}
+	fastly.SetServiceIDNormalization(false)
+
+	// Without normalization values are only resolved as names
+	flags := resolveServiceReferences([]Flag{{Name: "service-id", Value: "su1z0isxpaozgvkxdv0ey"}})
+	if flags[0].Value != "su1z0isxpaozgvkxdv0ey" {
+		t.Errorf("Expected the value unchanged without normalization, got %q", flags[0].Value)
+	}
+}
@@ -0,0 +1,109 @@
+package mcp
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// CORSAnyOrigin is the --cors-origin entry allowing every origin.
+const CORSAnyOrigin = "*"
+
+// corsAllowedHeaders are the request headers browser clients may send: those
+// of the MCP transports plus the ones this server reads.
+var corsAllowedHeaders = []string{"Authorization", "Content-Type", "Last-Event-ID", "Mcp-Protocol-Version", "Mcp-Session-Id", PolicyHeader}
+
+// corsOrigins holds the origins browser clients may connect from, which an
+// empty list, the default, keeps from any. It can be configured via
+// SetCORSOrigins().
+var corsOrigins []string
+
+// SetCORSOrigins configures the origins allowed to reach the HTTP server from
+// a browser. Each entry is CORSAnyOrigin or an origin such as
+// https://app.example.com; other entries are rejected.
+func SetCORSOrigins(origins []string) error {
+	check := http.NewCrossOriginProtection()
+	for _, origin := range origins {
+		if origin == CORSAnyOrigin {
+			continue
+		}
+		if err := check.AddTrustedOrigin(origin); err != nil {
+			return err
+		}
+	}
+	corsOrigins = origins
+	return nil
+}
+
+// corsAllowsOrigin reports whether a browser origin may reach the server.
+func corsAllowsOrigin(origin string) bool {
+	return origin != "" && (slices.Contains(corsOrigins, CORSAnyOrigin) || slices.Contains(corsOrigins, origin))
+}
+
+// withCORS adds CORS headers to responses for the configured origins and
+// answers their preflight requests, before authentication, since browsers send
+// preflights without credentials. Without origins it returns handler
+// unchanged.
+func withCORS(handler http.Handler) http.Handler {
+	if len(corsOrigins) == 0 {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+		w.Header().Add("Vary", "Origin")
+
+		if !corsAllowsOrigin(origin) {
+			if preflight {
+				http.Error(w, "origin not allowed", http.StatusForbidden)
+				return
+			}
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		if slices.Contains(corsOrigins, CORSAnyOrigin) {
+			w.Header().Set("Access-Control-Allow-Origin", CORSAnyOrigin)
+		} else {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+		}
+		w.Header().Set("Access-Control-Expose-Headers", "Mcp-Session-Id")
+
+		if preflight {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(corsAllowedHeaders, ", "))
+			w.Header().Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// corsProtection returns the cross-origin protection of the StreamableHTTP
+// handler, which otherwise refuses the requests of the configured origins,
+// or nil for the default protection when none are configured.
+func corsProtection() *http.CrossOriginProtection {
+	if len(corsOrigins) == 0 {
+		return nil
+	}
+	protection := http.NewCrossOriginProtection()
+	for _, origin := range corsOrigins {
+		if origin == CORSAnyOrigin {
+			protection.AddInsecureBypassPattern("/")
+			continue
+		}
+		// Validated by SetCORSOrigins
+		_ = protection.AddTrustedOrigin(origin)
+	}
+	return protection
+}
+
+// printCORSInfo lists the configured origins, if any.
+func printCORSInfo() {
+	if len(corsOrigins) == 0 {
+		return
+	}
+	fmt.Printf("  CORS origins: %s\n", strings.Join(corsOrigins, ", "))
+}
@@ -0,0 +1,141 @@
+package mcp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// corsTestServer serves the StreamableHTTP handler the way RunHTTPServer does.
+func corsTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mcpServer, err := CreateServer()
+	if err != nil {
+		t.Fatalf("CreateServer failed: %v", err)
+	}
+	httpServer := httptest.NewServer(withCORS(withBearerAuth(mcp.NewStreamableHTTPHandler(func(r *http.Request) *mcp.Server {
+		return mcpServer
+	}, &mcp.StreamableHTTPOptions{CrossOriginProtection: corsProtection()}))))
+	t.Cleanup(httpServer.Close)
+	return httpServer
+}
+
+func preflight(t *testing.T, url, origin string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodOptions, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	req.Header.Set("Access-Control-Request-Headers", "authorization, content-type, mcp-session-id")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("OPTIONS failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	return resp
+}
+
+func TestCORSPreflight(t *testing.T) {
+	if err := SetCORSOrigins([]string{"https://app.example.com", "http://localhost:3000"}); err != nil {
+		t.Fatalf("SetCORSOrigins failed: %v", err)
+	}
+	defer func() { _ = SetCORSOrigins(nil) }()
+	// Preflights are answered even when bearer tokens are required
+	SetHTTPAuthTokens([]string{"secret"})
+	defer SetHTTPAuthTokens(nil)
+
+	httpServer := corsTestServer(t)
+
+	for _, path := range []string{"/", "/mcp", "/sse", "/message"} {
+		resp := preflight(t, httpServer.URL+path, "https://app.example.com")
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("%s: expected status 204, got %d", path, resp.StatusCode)
+		}
+		if got := resp.Header.Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+			t.Errorf("%s: expected the origin to be allowed, got %q", path, got)
+		}
+		if got := resp.Header.Get("Access-Control-Allow-Methods"); !strings.Contains(got, http.MethodPost) {
+			t.Errorf("%s: expected POST to be allowed, got %q", path, got)
+		}
+		allowedHeaders := resp.Header.Get("Access-Control-Allow-Headers")
+		for _, header := range []string{"Authorization", "Content-Type", "Mcp-Session-Id"} {
+			if !strings.Contains(allowedHeaders, header) {
+				t.Errorf("%s: expected %s in Access-Control-Allow-Headers, got %q", path, header, allowedHeaders)
+			}
+		}
+		if got := resp.Header.Get("Access-Control-Expose-Headers"); got != "Mcp-Session-Id" {
+			t.Errorf("%s: expected Mcp-Session-Id to be exposed, got %q", path, got)
+		}
+	}
+
+	resp := preflight(t, httpServer.URL, "https://evil.example.com")
+	if resp.StatusCode != http.StatusForbidden || resp.Header.Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("Expected a preflight from another origin to be refused, got %d with %q", resp.StatusCode, resp.Header.Get("Access-Control-Allow-Origin"))
+	}
+}
+
+func TestCORSAnyOrigin(t *testing.T) {
+	if err := SetCORSOrigins([]string{CORSAnyOrigin}); err != nil {
+		t.Fatalf("SetCORSOrigins failed: %v", err)
+	}
+	defer func() { _ = SetCORSOrigins(nil) }()
+
+	httpServer := corsTestServer(t)
+
+	resp := preflight(t, httpServer.URL, "https://anywhere.example.com")
+	if resp.StatusCode != http.StatusNoContent || resp.Header.Get("Access-Control-Allow-Origin") != CORSAnyOrigin {
+		t.Errorf("Expected every origin to be allowed, got %d with %q", resp.StatusCode, resp.Header.Get("Access-Control-Allow-Origin"))
+	}
+
+	// The cross-origin protection of the handler lets the request through
+	body := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"cors-test","version":"1.0.0"}}}`
+	req, err := http.NewRequest(http.MethodPost, httpServer.URL, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Origin", "https://anywhere.example.com")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	post, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST failed: %v", err)
+	}
+	_ = post.Body.Close()
+	if post.StatusCode != http.StatusOK || post.Header.Get("Access-Control-Allow-Origin") != CORSAnyOrigin {
+		t.Errorf("Expected the cross-origin initialize to succeed with CORS headers, got %d with %q", post.StatusCode, post.Header.Get("Access-Control-Allow-Origin"))
+	}
+}
+
+func TestCORSDisabled(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	})
+
+	req := httptest.NewRequest(http.MethodOptions, "/", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	rec := httptest.NewRecorder()
+	withCORS(handler).ServeHTTP(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("Expected no CORS handling without origins, got %d with %q", rec.Code, rec.Header().Get("Access-Control-Allow-Origin"))
+	}
+	if corsProtection() != nil {
+		t.Error("Expected the default cross-origin protection without origins")
+	}
+}
+
+func TestSetCORSOriginsInvalid(t *testing.T) {
+	for _, origin := range []string{"app.example.com", "https://app.example.com/path"} {
+		if err := SetCORSOrigins([]string{origin}); err == nil {
+			t.Errorf("Expected %q to be rejected", origin)
+		}
+	}
+	if len(corsOrigins) != 0 {
+		t.Errorf("Expected rejected origins not to be configured, got %v", corsOrigins)
+	}
+}
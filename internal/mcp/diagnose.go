@@ -0,0 +1,30 @@
+package mcp
+
+import (
+	"context"
+	"time"
+
+	"github.com/fastly/mcp/internal/fastly"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// makeDiagnoseHandler creates a handler that reports the Fastly CLI setup
+// step by step. It runs without the setup check, as it is the tool to use
+// when that check fails, and a healthy report replaces a cached setup error
+// so the other tools work once the setup is fixed.
+func (ft *FastlyTool) makeDiagnoseHandler() mcp.ToolHandler {
+	return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		params := getArguments(request)
+
+		diagnosis := fastly.DiagnoseSetup(ctx)
+		if diagnosis.Healthy {
+			ft.setupError = nil
+			ft.setupChecked = true
+		}
+
+		result := newSuccessResult(diagnosis)
+		LogCommand(request, "fastly_diagnose", params, result, nil, time.Since(start))
+		return result, nil
+	}
+}
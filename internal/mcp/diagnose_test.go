@@ -0,0 +1,29 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestDiagnoseTool(t *testing.T) {
+	t.Setenv("FASTLY_CLI_PATH", filepath.Join(t.TempDir(), "fastly"))
+
+	session := connectTestClient(t)
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "fastly_diagnose"})
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+
+	var diagnosis types.SetupDiagnosis
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &diagnosis); err != nil {
+		t.Fatalf("Failed to decode the diagnosis: %v", err)
+	}
+	if diagnosis.Healthy || diagnosis.FailedStep != "cli_installed" || len(diagnosis.Steps) != 6 {
+		t.Errorf("Expected a report failing at cli_installed, got %+v", diagnosis)
+	}
+}
@@ -0,0 +1,148 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/fastly/mcp/internal/fastly"
+	"github.com/fastly/mcp/internal/types"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// makeExecuteBatchHandler creates the handler for the fastly_execute_batch tool.
+// A batch is planned first: the response lists the resolved command line and
+// danger classification of each step together with a plan token. Once a human
+// approves the plan, the same steps are sent back with the token and executed
+// in order. Without a token nothing is executed.
+func (ft *FastlyTool) makeExecuteBatchHandler() mcp.ToolHandler {
+	return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		params := getArguments(request)
+
+		stepsParam, ok := params["steps"].([]interface{})
+		if !ok {
+			err := fmt.Errorf("steps parameter must be an array")
+			LogCommand(request, "fastly_execute_batch", params, nil, err, time.Since(start))
+			return nil, err
+		}
+
+		// Refuse oversized steps before any preprocessing iterates over them
+		for i, item := range stepsParam {
+			stepMap, _ := item.(map[string]interface{})
+			if err := checkRequestItems(stepMap); err != nil {
+				result := newErrorResult(types.BatchResponse{
+					Success:      false,
+					Error:        fmt.Sprintf("step %d: %v", i, err),
+					ErrorCode:    "request_too_large",
+					Instructions: "A step has too many args and flags to be processed.",
+					NextSteps: []string{
+						"Pass only the args and flags each command needs",
+						"Split the step into several smaller steps",
+					},
+				})
+				LogCommand(request, "fastly_execute_batch", params, result, nil, time.Since(start))
+				return result, nil
+			}
+		}
+
+		result, err := executeWithSetupCheck(ctx, ft, "execute_batch", func() (*mcp.CallToolResult, error) {
+			// The plan token is bound to the steps exactly as submitted
+			signature, err := json.Marshal(stepsParam)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode steps: %w", err)
+			}
+
+			planOnly, _ := params["plan_only"].(bool)
+			planToken, _ := params["plan_token"].(string)
+
+			var response types.BatchResponse
+			if planToken != "" && !planOnly {
+				response = fastly.ExecutePlannedBatch(ctx, planToken, string(signature))
+			} else {
+				steps, err := parseBatchSteps(stepsParam)
+				if err != nil {
+					return nil, err
+				}
+				response = fastly.PlanBatchContext(ctx, steps, string(signature))
+				if response.Success && !planOnly {
+					response.Success = false
+					response.Error = "executing a batch requires the plan token of an approved plan"
+					response.ErrorCode = "plan_token_required"
+				}
+			}
+
+			if response.Success {
+				return newSuccessResult(response), nil
+			}
+			return newErrorResult(response), nil
+		})
+
+		LogCommand(request, "fastly_execute_batch", params, result, err, time.Since(start))
+		return result, err
+	}
+}
+
+// parseBatchSteps converts the steps parameter into command requests, applying
+// the same token decryption and preprocessing as fastly_execute.
+func parseBatchSteps(stepsParam []interface{}) ([]types.CommandRequest, error) {
+	steps := make([]types.CommandRequest, 0, len(stepsParam))
+	for i, item := range stepsParam {
+		stepMap, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("step %d must be an object", i)
+		}
+		command, ok := stepMap["command"].(string)
+		if !ok || command == "" {
+			return nil, fmt.Errorf("step %d: command parameter must be a non-empty string", i)
+		}
+		if tokenCrypto != nil && tokenCrypto.Enabled {
+			command = tokenCrypto.DecryptTokensInString(command)
+		}
+
+		var args []string
+		if argsParam, ok := stepMap["args"].([]interface{}); ok {
+			for _, arg := range argsParam {
+				if argStr, ok := arg.(string); ok {
+					if tokenCrypto != nil && tokenCrypto.Enabled {
+						argStr = tokenCrypto.DecryptTokensInString(argStr)
+					}
+					args = append(args, argStr)
+				}
+			}
+		}
+
+		var flags []types.Flag
+		if flagsParam, ok := stepMap["flags"].([]interface{}); ok {
+			for _, flagItem := range flagsParam {
+				if flagMap, ok := flagItem.(map[string]interface{}); ok {
+					flag := types.Flag{}
+					if name, ok := flagMap["name"].(string); ok {
+						flag.Name = name
+					}
+					if value, ok := flagMap["value"].(string); ok {
+						if tokenCrypto != nil && tokenCrypto.Enabled {
+							value = tokenCrypto.DecryptTokensInString(value)
+						}
+						flag.Value = value
+					}
+					if flag.Name != "" {
+						flags = append(flags, flag)
+					}
+				}
+			}
+		}
+
+		processedCmd, processedArgs, processedFlags, err := IntelligentPreprocess(command, args, convertFlags(flags))
+		if err != nil {
+			return nil, fmt.Errorf("step %d: preprocessing failed: %w", i, err)
+		}
+		steps = append(steps, types.CommandRequest{
+			Command: processedCmd,
+			Args:    processedArgs,
+			Flags:   convertFlagsBack(processedFlags),
+		})
+	}
+	return steps, nil
+}
@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestExecuteBatchPlanThenExecute(t *testing.T) {
+	dir := t.TempDir()
+	callsFile := filepath.Join(dir, "calls")
+	script := "#!/bin/sh\necho \"$@\" >> \"" + callsFile + "\"\necho 'ok'\n"
+	mockPath := filepath.Join(dir, "fastly")
+	if err := os.WriteFile(mockPath, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("FASTLY_CLI_PATH", mockPath)
+
+	steps := []map[string]interface{}{
+		{"command": "service-version", "args": []string{"clone"}, "flags": []map[string]interface{}{{"name": "service-id", "value": "SU1Z0isxPaozGVKXdv0eY"}, {"name": "version", "value": "1"}}},
+		{"command": "backend delete", "flags": []map[string]interface{}{{"name": "service-id", "value": "SU1Z0isxPaozGVKXdv0eY"}, {"name": "version", "value": "2"}, {"name": "name", "value": "old"}}},
+	}
+
+	session := connectTestClient(t)
+	call := func(arguments map[string]interface{}) (types.BatchResponse, bool) {
+		t.Helper()
+		result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "fastly_execute_batch", Arguments: arguments})
+		if err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		var response types.BatchResponse
+		if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return response, result.IsError
+	}
+
+	plan, isError := call(map[string]interface{}{"steps": steps, "plan_only": true})
+	if isError || !plan.Success || plan.PlanToken == "" || len(plan.Plan) != 2 {
+		t.Fatalf("Expected a plan with a token, got %+v", plan)
+	}
+	if plan.Plan[1].CommandLine != "fastly backend delete --service-id SU1Z0isxPaozGVKXdv0eY --version 2 --name old" || !plan.Plan[1].Destructive {
+		t.Errorf("Expected the resolved destructive step, got %+v", plan.Plan[1])
+	}
+	if strings.Contains(readCalls(callsFile), "clone") {
+		t.Fatal("Expected plan_only not to execute any step")
+	}
+
+	// Executing without a token returns a fresh plan but runs nothing
+	if response, isError := call(map[string]interface{}{"steps": steps}); !isError || response.ErrorCode != "plan_token_required" || response.PlanToken == "" {
+		t.Errorf("Expected plan_token_required with a plan, got %+v", response)
+	}
+
+	// The token is bound to the steps it was issued for
+	changed := []map[string]interface{}{steps[0]}
+	if response, isError := call(map[string]interface{}{"steps": changed, "plan_token": plan.PlanToken}); !isError || response.ErrorCode != "plan_token_invalid" {
+		t.Fatalf("Expected plan_token_invalid for changed steps, got %+v", response)
+	}
+	if strings.Contains(readCalls(callsFile), "clone") {
+		t.Fatal("Expected no step to run without a matching plan token")
+	}
+
+	plan, _ = call(map[string]interface{}{"steps": steps, "plan_only": true})
+	response, isError := call(map[string]interface{}{"steps": steps, "plan_token": plan.PlanToken})
+	if isError || !response.Success || response.Executed != 2 {
+		t.Fatalf("Expected both steps to run, got %+v", response)
+	}
+	calls := readCalls(callsFile)
+	if !strings.Contains(calls, "service-version clone") || !strings.Contains(calls, "backend delete") {
+		t.Errorf("Expected both steps to reach the CLI, got %q", calls)
+	}
+}
+
+// readCalls returns the recorded CLI invocations, or "" if there were none.
+func readCalls(path string) string {
+	data, _ := os.ReadFile(path)
+	return string(data)
+}
@@ -0,0 +1,94 @@
+package mcp
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fastly/mcp/internal/fastly"
+)
+
+// HTTP paths of the liveness and readiness probes in HTTP mode.
+const (
+	// HealthzPath answers 200 while the process serves requests
+	HealthzPath = "/healthz"
+	// ReadyzPath answers 200 only while the Fastly CLI is installed and authenticated
+	ReadyzPath = "/readyz"
+)
+
+// ReadinessCacheTTL is how long a readiness result is reused, so frequent
+// probes run 'fastly whoami' at most once per period.
+const ReadinessCacheTTL = 10 * time.Second
+
+// maxReadinessReason caps the reason /readyz gives for not being ready.
+const maxReadinessReason = 200
+
+// setupCheck checks the Fastly CLI setup for /readyz; tests replace it.
+var setupCheck = fastly.CheckSetup
+
+// The last readiness result, reused for ReadinessCacheTTL. The mutex is held
+// during a check, so concurrent probes wait for one check instead of starting
+// their own.
+var (
+	readinessMu      sync.Mutex
+	readinessChecked time.Time
+	readinessErr     error
+)
+
+// withHealth serves HealthzPath and ReadyzPath and every other path from
+// handler. The probes come first so that load balancers and orchestrators can
+// reach them without bearer tokens or CORS headers; they reveal nothing but
+// whether the server is ready.
+func withHealth(handler http.Handler) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(HealthzPath, healthzHandler)
+	mux.HandleFunc(ReadyzPath, readyzHandler)
+	mux.Handle("/", handler)
+	return mux
+}
+
+// healthzHandler reports that the process is alive, without any checks.
+func healthzHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = fmt.Fprintln(w, "ok")
+}
+
+// readyzHandler reports whether commands can run: 200 if the cached setup
+// check passed, 503 with a short reason otherwise.
+func readyzHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := checkReadiness(); err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = fmt.Fprintf(w, "not ready: %s\n", readinessReason(err))
+		return
+	}
+	_, _ = fmt.Fprintln(w, "ok")
+}
+
+// checkReadiness runs setupCheck, or returns its result from the last
+// ReadinessCacheTTL.
+func checkReadiness() error {
+	readinessMu.Lock()
+	defer readinessMu.Unlock()
+
+	if !readinessChecked.IsZero() && time.Since(readinessChecked) < ReadinessCacheTTL {
+		return readinessErr
+	}
+	readinessErr = setupCheck()
+	readinessChecked = time.Now()
+	return readinessErr
+}
+
+// readinessReason shortens a setup error to its first sentence, which names
+// the failed check without details such as the searched PATH.
+func readinessReason(err error) string {
+	reason, _, _ := strings.Cut(err.Error(), "\n")
+	reason, _, _ = strings.Cut(reason, ". ")
+	if len(reason) > maxReadinessReason {
+		reason = reason[:maxReadinessReason] + "..."
+	}
+	return reason
+}
@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// mockSetupCheck replaces the readiness setup check with one returning err
+// and counts its calls.
+func mockSetupCheck(t *testing.T, err error) *int {
+	t.Helper()
+	calls := 0
+	original := setupCheck
+	setupCheck = func() error {
+		calls++
+		return err
+	}
+	readinessChecked = time.Time{}
+	t.Cleanup(func() {
+		setupCheck = original
+		readinessChecked = time.Time{}
+	})
+	return &calls
+}
+
+func probe(handler http.Handler, path string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+	return recorder
+}
+
+func TestHealthEndpoints(t *testing.T) {
+	// The probes need no bearer token
+	SetHTTPAuthTokens([]string{"secret"})
+	defer SetHTTPAuthTokens(nil)
+	mcpHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("mcp"))
+	})
+	handler := withHealth(withBearerAuth(mcpHandler))
+
+	t.Run("healthy", func(t *testing.T) {
+		calls := mockSetupCheck(t, nil)
+
+		if rec := probe(handler, HealthzPath); rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != "ok" {
+			t.Errorf("Expected /healthz to return 200 ok, got %d %q", rec.Code, rec.Body.String())
+		}
+		for i := 0; i < 3; i++ {
+			if rec := probe(handler, ReadyzPath); rec.Code != http.StatusOK {
+				t.Errorf("Expected /readyz to return 200, got %d %q", rec.Code, rec.Body.String())
+			}
+		}
+		if *calls != 1 {
+			t.Errorf("Expected the setup check to run once and be cached, ran %d times", *calls)
+		}
+
+		// The check runs again once the cached result expires
+		readinessChecked = time.Now().Add(-ReadinessCacheTTL)
+		probe(handler, ReadyzPath)
+		if *calls != 2 {
+			t.Errorf("Expected an expired result to be checked again, ran %d times", *calls)
+		}
+	})
+
+	t.Run("unhealthy", func(t *testing.T) {
+		calls := mockSetupCheck(t, errors.New("fastly CLI not found in PATH. Searched directories: [/usr/bin /secret/dir]"))
+
+		if rec := probe(handler, HealthzPath); rec.Code != http.StatusOK {
+			t.Errorf("Expected /healthz to stay 200 while not ready, got %d", rec.Code)
+		}
+		rec := probe(handler, ReadyzPath)
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("Expected /readyz to return 503, got %d", rec.Code)
+		}
+		body := rec.Body.String()
+		if !strings.Contains(body, "fastly CLI not found in PATH") || strings.Contains(body, "/secret/dir") {
+			t.Errorf("Expected a short reason without details, got %q", body)
+		}
+		probe(handler, ReadyzPath)
+		if *calls != 1 {
+			t.Errorf("Expected the failed check to be cached, ran %d times", *calls)
+		}
+	})
+
+	if rec := probe(handler, "/"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected other paths to keep requiring a token, got %d", rec.Code)
+	}
+}
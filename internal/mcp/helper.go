@@ -5,6 +5,8 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/fastly/mcp/internal/crypto"
@@ -13,17 +15,22 @@ import (
 )
 
 // handleSetupError creates a consistent error response for setup failures across all handlers.
-// It analyzes the error message to provide appropriate error codes (cli_not_found, auth_required)
+// It analyzes the error message to provide appropriate error codes (cli_version_unsupported, cli_not_found, auth_required)
 // and returns a properly formatted MCP CallToolResult with the error details and IsError set to true.
 func handleSetupError(err error, command string) *mcp.CallToolResult {
 	errorResponse := fastly.SetupError(command, err)
 
 	// Refine error code based on specific error
-	if strings.Contains(err.Error(), "not found") {
+	var versionErr *fastly.CLIVersionError
+	if errors.As(err, &versionErr) {
+		errorResponse.ErrorCode = "cli_version_unsupported"
+		errorResponse.Instructions = fmt.Sprintf("The installed Fastly CLI version %s is older than the minimum supported version %s. Ask the user to upgrade the CLI.", versionErr.Detected, versionErr.Required)
+	} else if strings.Contains(err.Error(), "not found") {
 		errorResponse.ErrorCode = "cli_not_found"
 	} else if strings.Contains(err.Error(), "not authenticated") || strings.Contains(err.Error(), "Not authenticated") {
 		errorResponse.ErrorCode = "auth_required"
 	}
+	errorResponse.NextSteps = append(errorResponse.NextSteps, "Call the fastly_diagnose tool to find out which setup step fails")
 
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
@@ -0,0 +1,71 @@
+package mcp
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// httpAuthTokens holds the bearer tokens HTTP clients must present, one of
+// which must match. It can be configured via SetHTTPAuthTokens().
+var httpAuthTokens []string
+
+// SetHTTPAuthTokens configures the bearer tokens accepted in HTTP mode. Giving
+// each client its own token lets a single one be revoked. With no tokens, the
+// default, requests are not authenticated.
+func SetHTTPAuthTokens(tokens []string) {
+	httpAuthTokens = tokens
+}
+
+// HTTPAuthEnabled reports whether HTTP clients must present a bearer token.
+func HTTPAuthEnabled() bool {
+	return len(httpAuthTokens) > 0
+}
+
+// withBearerAuth answers requests without an 'Authorization: Bearer' header
+// matching one of the configured tokens with 401 Unauthorized. Without tokens
+// it returns handler unchanged.
+func withBearerAuth(handler http.Handler) http.Handler {
+	if !HTTPAuthEnabled() {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if matchBearerToken(r.Header.Get("Authorization")) < 0 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="fastly-mcp"`)
+			http.Error(w, "missing or invalid bearer token", http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// matchBearerToken returns the index of the configured token an Authorization
+// header carries, or -1 if it carries none of them. Every token is compared in
+// constant time.
+func matchBearerToken(header string) int {
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return -1
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return -1
+	}
+
+	match := -1
+	for i, expected := range httpAuthTokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1 {
+			match = i
+		}
+	}
+	return match
+}
+
+// printAuthInfo tells how to authenticate, if tokens are configured.
+func printAuthInfo() {
+	if !HTTPAuthEnabled() {
+		return
+	}
+	fmt.Printf("  Authentication: send 'Authorization: Bearer <token>' with one of the %d configured tokens\n", len(httpAuthTokens))
+}
@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestHTTPBearerAuth(t *testing.T) {
+	SetHTTPAuthTokens([]string{"token-alice", "token-bob"})
+	defer SetHTTPAuthTokens(nil)
+
+	mcpServer, err := CreateServer()
+	if err != nil {
+		t.Fatalf("CreateServer failed: %v", err)
+	}
+	httpServer := httptest.NewServer(withBearerAuth(withMetrics(mcp.NewStreamableHTTPHandler(func(r *http.Request) *mcp.Server {
+		return mcpServer
+	}, nil))))
+	defer httpServer.Close()
+
+	get := func(authorization string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, httpServer.URL+MetricsPath, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("GET %s failed: %v", MetricsPath, err)
+		}
+		_ = resp.Body.Close()
+		return resp
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		status        int
+	}{
+		{"missing token", "", http.StatusUnauthorized},
+		{"invalid token", "Bearer token-eve", http.StatusUnauthorized},
+		{"token prefix", "Bearer token-al", http.StatusUnauthorized},
+		{"other scheme", "Basic token-alice", http.StatusUnauthorized},
+		{"first token", "Bearer token-alice", http.StatusOK},
+		{"second token", "bearer token-bob", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := get(tt.authorization)
+			if resp.StatusCode != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+			if tt.status == http.StatusUnauthorized && resp.Header.Get("WWW-Authenticate") == "" {
+				t.Error("Expected a WWW-Authenticate header on 401")
+			}
+		})
+	}
+
+	connect := func(token string) (*mcp.ClientSession, error) {
+		client := &http.Client{Transport: headerTransport{"Authorization", "Bearer " + token}}
+		transport := &mcp.StreamableClientTransport{Endpoint: httpServer.URL, HTTPClient: client, MaxRetries: -1, DisableStandaloneSSE: true}
+		return mcp.NewClient(&mcp.Implementation{Name: "auth-test", Version: "1.0.0"}, nil).Connect(context.Background(), transport, nil)
+	}
+
+	session, err := connect("token-bob")
+	if err != nil {
+		t.Fatalf("Expected a client with a valid token to connect, got %v", err)
+	}
+	if _, err := session.ListTools(context.Background(), nil); err != nil {
+		t.Errorf("Expected tools/list to succeed with a valid token, got %v", err)
+	}
+	_ = session.Close()
+
+	if session, err := connect("token-eve"); err == nil {
+		_ = session.Close()
+		t.Error("Expected a client with an invalid token to be refused")
+	}
+}
+
+func TestHTTPBearerAuthDisabled(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	rec := httptest.NewRecorder()
+	withBearerAuth(handler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("Expected requests without a token to pass when no tokens are configured, got %d", rec.Code)
+	}
+}
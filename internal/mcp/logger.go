@@ -2,15 +2,36 @@
 package mcp
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/fastly/mcp/internal/fastly"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
+// LogRedaction controls how much of the flag values of a request are written
+// to the command log. Tool, command and flag names are always logged, and so
+// are arguments unless they look like secrets.
+type LogRedaction string
+
+const (
+	// LogRedactionFull logs flag values and arguments as sent
+	LogRedactionFull LogRedaction = "full"
+	// LogRedactionSecrets replaces values of flags that look like secrets
+	LogRedactionSecrets LogRedaction = "redact-secrets"
+	// LogRedactionAllValues replaces every flag value, the default
+	LogRedactionAllValues LogRedaction = "redact-all-values"
+)
+
+// redactedLogValue replaces redacted flag values in the command log
+const redactedLogValue = "[REDACTED]"
+
 // CommandLogger handles logging of MCP commands to a file
 type CommandLogger struct {
 	file  *os.File
@@ -27,11 +48,34 @@ type LogEntry struct {
 	DurationMs int64                  `json:"duration_ms"`
 }
 
+// identityHashBytes is how many bytes of the token hash name a per-token log
+const identityHashBytes = 8
+
 var (
-	commandLogger *CommandLogger
-	loggerMutex   sync.Mutex
+	commandLogger  *CommandLogger
+	commandLogPath string
+	perTokenLogs   bool
+	tenantLoggers  = map[string]*CommandLogger{}
+	loggerMutex    sync.Mutex
+	logRedaction   = LogRedactionAllValues
 )
 
+// SetLogRedaction sets the redaction level for flag values in the command log.
+// Flag values are only logged when opted in with LogRedactionSecrets or
+// LogRedactionFull.
+func SetLogRedaction(level LogRedaction) {
+	logRedaction = level
+}
+
+// ParseLogRedaction parses a redaction level name
+func ParseLogRedaction(value string) (LogRedaction, error) {
+	switch level := LogRedaction(strings.ToLower(strings.TrimSpace(value))); level {
+	case LogRedactionFull, LogRedactionSecrets, LogRedactionAllValues:
+		return level, nil
+	}
+	return "", fmt.Errorf("invalid log redaction %q: expected 'full', 'redact-secrets' or 'redact-all-values'", value)
+}
+
 // InitializeCommandLogger initializes the command logger with the specified file path
 func InitializeCommandLogger(filePath string) error {
 	if filePath == "" {
@@ -41,18 +85,35 @@ func InitializeCommandLogger(filePath string) error {
 	loggerMutex.Lock()
 	defer loggerMutex.Unlock()
 
-	// Close existing logger if any
-	if commandLogger != nil {
-		_ = commandLogger.Close()
-	}
+	// Close existing loggers if any
+	closeCommandLoggers()
 
-	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	logger, err := openCommandLogger(filePath)
 	if err != nil {
-		return fmt.Errorf("failed to open log file: %w", err)
+		return err
 	}
+	commandLogger = logger
+	commandLogPath = filePath
+
+	return nil
+}
 
-	commandLogger = &CommandLogger{
-		file: file,
+// SetPerTokenCommandLogs enables or disables separate command logs per client
+// token. When enabled, calls made over HTTP with one of the --http-auth-token
+// tokens are written to a file next to the command log whose name carries a
+// hash of the token, so there is at most one file per configured token. Other
+// calls keep going to the command log itself.
+func SetPerTokenCommandLogs(enabled bool) {
+	loggerMutex.Lock()
+	defer loggerMutex.Unlock()
+	perTokenLogs = enabled
+}
+
+// openCommandLogger opens a log file for appending and writes the start marker
+func openCommandLogger(filePath string) (*CommandLogger, error) {
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
 	}
 
 	// Write initial log entry in human-readable format
@@ -61,16 +122,81 @@ func InitializeCommandLogger(filePath string) error {
 
 	if _, err := fmt.Fprintln(file, initialMsg); err != nil {
 		_ = file.Close()
-		commandLogger = nil
-		return fmt.Errorf("failed to write initial log entry: %w", err)
+		return nil, fmt.Errorf("failed to write initial log entry: %w", err)
 	}
 
-	return nil
+	return &CommandLogger{file: file}, nil
 }
 
-// LogCommand logs an MCP command request and response
-func LogCommand(tool string, request map[string]interface{}, response interface{}, err error, duration time.Duration) {
-	if commandLogger == nil {
+// callerIdentity returns a stable, non-reversible identity for the client
+// that made a tool call: the user of verified token info when an auth
+// middleware supplied it, or else a hash of the --http-auth-token the HTTP
+// request carries. The token itself is never written to disk. It returns an
+// empty string for calls without a configured token, such as over stdio, so
+// that made-up tokens cannot open log files of their own.
+func callerIdentity(call *mcp.CallToolRequest) string {
+	if call == nil {
+		return ""
+	}
+	return extraIdentity(call.Extra)
+}
+
+// extraIdentity returns the identity of callerIdentity from the HTTP details
+// of any request.
+func extraIdentity(extra *mcp.RequestExtra) string {
+	if extra == nil {
+		return ""
+	}
+
+	var subject string
+	if extra.TokenInfo != nil && extra.TokenInfo.UserID != "" {
+		subject = "user:" + extra.TokenInfo.UserID
+	} else if index := matchBearerToken(extra.Header.Get("Authorization")); index >= 0 {
+		subject = "token:" + httpAuthTokens[index]
+	}
+	if subject == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(subject))
+	return hex.EncodeToString(sum[:identityHashBytes])
+}
+
+// tenantLogPath returns the log file for an identity, placed next to the
+// command log: commands.log becomes commands.<identity>.log.
+func tenantLogPath(basePath, identity string) string {
+	ext := filepath.Ext(basePath)
+	return strings.TrimSuffix(basePath, ext) + "." + identity + ext
+}
+
+// commandLoggerFor returns the logger that receives entries for an identity,
+// opening its file on first use. It falls back to the command log when
+// per-token logs are disabled or the file cannot be opened.
+func commandLoggerFor(identity string) *CommandLogger {
+	loggerMutex.Lock()
+	defer loggerMutex.Unlock()
+
+	if identity == "" || !perTokenLogs || commandLogger == nil {
+		return commandLogger
+	}
+	if logger, ok := tenantLoggers[identity]; ok {
+		return logger
+	}
+
+	logger, err := openCommandLogger(tenantLogPath(commandLogPath, identity))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open command log for client %s: %v\n", identity, err)
+		return commandLogger
+	}
+	tenantLoggers[identity] = logger
+	return logger
+}
+
+// LogCommand logs an MCP command request and response. The call identifies the
+// client when per-token logs are enabled; it may be nil.
+func LogCommand(call *mcp.CallToolRequest, tool string, request map[string]interface{}, response interface{}, err error, duration time.Duration) {
+	logger := commandLoggerFor(callerIdentity(call))
+	if logger == nil {
 		return
 	}
 
@@ -101,7 +227,7 @@ func LogCommand(tool string, request map[string]interface{}, response interface{
 		entry.Error = err.Error()
 	}
 
-	if writeErr := commandLogger.writeLog(entry); writeErr != nil {
+	if writeErr := logger.writeLog(entry); writeErr != nil {
 		// Log to stderr if we can't write to the log file
 		fmt.Fprintf(os.Stderr, "Failed to write to command log: %v\n", writeErr)
 	}
@@ -121,7 +247,7 @@ func (cl *CommandLogger) writeLog(entry LogEntry) error {
 		if args, ok := entry.Request["args"].([]interface{}); ok && len(args) > 0 {
 			argStrs := make([]string, len(args))
 			for i, arg := range args {
-				argStrs[i] = fmt.Sprintf("%v", arg)
+				argStrs[i] = redactLogArg(fmt.Sprintf("%v", arg))
 			}
 			command = fmt.Sprintf("%s %s %s", entry.Tool, cmd, strings.Join(argStrs, " "))
 		} else {
@@ -129,6 +255,8 @@ func (cl *CommandLogger) writeLog(entry LogEntry) error {
 		}
 	}
 
+	command += formatLogFlags(entry.Request["flags"])
+
 	// Build status indicator
 	status := "✓"
 	if !entry.Success {
@@ -159,6 +287,58 @@ func (cl *CommandLogger) writeLog(entry LogEntry) error {
 	return cl.file.Sync()
 }
 
+// formatLogFlags renders request flags for the command log, redacting values
+// according to the configured redaction level
+func formatLogFlags(flags interface{}) string {
+	flagList, ok := flags.([]interface{})
+	if !ok {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, item := range flagList {
+		flag, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := flag["name"].(string)
+		if name == "" {
+			continue
+		}
+
+		b.WriteString(" --" + name)
+		if value := fmt.Sprintf("%v", flag["value"]); flag["value"] != nil && value != "" {
+			b.WriteString(" " + redactLogValue(name, value))
+		}
+	}
+	return b.String()
+}
+
+// redactLogValue returns a flag value as written to the command log
+func redactLogValue(name, value string) string {
+	switch logRedaction {
+	case LogRedactionFull:
+		return value
+	case LogRedactionAllValues:
+		return redactedLogValue
+	default:
+		if fastly.IsSecretLike(name, value) {
+			return redactedLogValue
+		}
+		return value
+	}
+}
+
+// redactLogArg returns a positional argument as written to the command log.
+// Arguments are mostly subcommand names, which are always logged, but one that
+// looks like a secret is redacted at every level but LogRedactionFull.
+func redactLogArg(arg string) string {
+	if logRedaction != LogRedactionFull && fastly.IsSecretLike("", arg) {
+		return redactedLogValue
+	}
+	return arg
+}
+
 // Close closes the command logger
 func (cl *CommandLogger) Close() error {
 	cl.mutex.Lock()
@@ -175,13 +355,22 @@ func (cl *CommandLogger) Close() error {
 	return nil
 }
 
-// CloseCommandLogger closes the global command logger
+// CloseCommandLogger closes the global command logger and any per-token logs
 func CloseCommandLogger() {
 	loggerMutex.Lock()
 	defer loggerMutex.Unlock()
 
+	closeCommandLoggers()
+}
+
+// closeCommandLoggers closes all open loggers. The caller holds loggerMutex.
+func closeCommandLoggers() {
 	if commandLogger != nil {
 		_ = commandLogger.Close()
 		commandLogger = nil
 	}
+	for identity, logger := range tenantLoggers {
+		_ = logger.Close()
+		delete(tenantLoggers, identity)
+	}
 }
@@ -0,0 +1,184 @@
+package mcp
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// logTestCommand logs a command with a secret-looking flag value and returns the log contents.
+func logTestCommand(t *testing.T, level LogRedaction) string {
+	t.Helper()
+
+	logFile := filepath.Join(t.TempDir(), "commands.log")
+	if err := InitializeCommandLogger(logFile); err != nil {
+		t.Fatalf("InitializeCommandLogger failed: %v", err)
+	}
+	defer CloseCommandLogger()
+
+	SetLogRedaction(level)
+	defer SetLogRedaction(LogRedactionAllValues)
+
+	LogCommand(nil, "fastly_execute", map[string]interface{}{
+		"command": "service",
+		"args":    []interface{}{"describe", "api_token=zyxwvutsrqponmlkjihgfedcba654321"},
+		"flags": []interface{}{
+			map[string]interface{}{"name": "service-id", "value": "SU1Z0isxPaozGVKXdv0eY"},
+			map[string]interface{}{"name": "api-key", "value": "plain-value"},
+			map[string]interface{}{"name": "comment", "value": "api_token=abcdefghijklmnopqrstuvwxyz123456"},
+			map[string]interface{}{"name": "json"},
+		},
+	}, nil, nil, time.Millisecond)
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("Failed to read log: %v", err)
+	}
+	return string(data)
+}
+
+func TestLogRedactionLevels(t *testing.T) {
+	tests := []struct {
+		level    LogRedaction
+		contains []string
+		excludes []string
+	}{
+		{
+			level:    LogRedactionFull,
+			contains: []string{"--service-id SU1Z0isxPaozGVKXdv0eY", "--api-key plain-value", "api_token=abcdefghijklmnopqrstuvwxyz123456", "describe api_token=zyxwvutsrqponmlkjihgfedcba654321"},
+		},
+		{
+			level:    LogRedactionSecrets,
+			contains: []string{"--service-id SU1Z0isxPaozGVKXdv0eY", "--api-key [REDACTED]", "--comment [REDACTED]", "describe [REDACTED]"},
+			excludes: []string{"plain-value", "abcdefghijklmnopqrstuvwxyz123456", "zyxwvutsrqponmlkjihgfedcba654321"},
+		},
+		{
+			level:    LogRedactionAllValues,
+			contains: []string{"--service-id [REDACTED]", "--api-key [REDACTED]", "--comment [REDACTED]", "describe [REDACTED]"},
+			excludes: []string{"SU1Z0isxPaozGVKXdv0eY", "plain-value", "abcdefghijklmnopqrstuvwxyz123456", "zyxwvutsrqponmlkjihgfedcba654321"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.level), func(t *testing.T) {
+			log := logTestCommand(t, tt.level)
+
+			// Command names are kept at every level
+			if !strings.Contains(log, "fastly_execute service describe") || !strings.Contains(log, "--json") {
+				t.Errorf("Expected command names in log, got %q", log)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(log, want) {
+					t.Errorf("Expected log to contain %q, got %q", want, log)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(log, unwanted) {
+					t.Errorf("Expected log not to contain %q, got %q", unwanted, log)
+				}
+			}
+		})
+	}
+}
+
+func TestLogRedactionDefault(t *testing.T) {
+	if logRedaction != LogRedactionAllValues {
+		t.Errorf("Expected flag values not to be logged by default, got %q", logRedaction)
+	}
+}
+
+func TestParseLogRedaction(t *testing.T) {
+	if level, err := ParseLogRedaction(" Redact-All-Values "); err != nil || level != LogRedactionAllValues {
+		t.Errorf("Expected redact-all-values, got %q (%v)", level, err)
+	}
+	if _, err := ParseLogRedaction("partial"); err == nil {
+		t.Error("Expected error for unknown level")
+	}
+}
+
+// bearerCall is a tool call made over HTTP with the given bearer token.
+func bearerCall(token string) *mcp.CallToolRequest {
+	return &mcp.CallToolRequest{Extra: &mcp.RequestExtra{Header: http.Header{"Authorization": {"Bearer " + token}}}}
+}
+
+func TestPerTokenCommandLogs(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "commands.log")
+	if err := InitializeCommandLogger(logFile); err != nil {
+		t.Fatalf("InitializeCommandLogger failed: %v", err)
+	}
+	SetPerTokenCommandLogs(true)
+	defer SetPerTokenCommandLogs(false)
+	SetHTTPAuthTokens([]string{"tenant-a-token", "tenant-b-token"})
+	defer SetHTTPAuthTokens(nil)
+
+	callA, callB := bearerCall("tenant-a-token"), bearerCall("tenant-b-token")
+	LogCommand(callA, "fastly_execute", map[string]interface{}{"command": "service", "args": []interface{}{"list"}}, nil, nil, time.Millisecond)
+	LogCommand(callB, "fastly_execute", map[string]interface{}{"command": "backend", "args": []interface{}{"list"}}, nil, nil, time.Millisecond)
+	LogCommand(nil, "current_time", map[string]interface{}{}, nil, nil, time.Millisecond)
+	// Unconfigured tokens open no files of their own
+	LogCommand(bearerCall("made-up-token"), "fastly_describe", map[string]interface{}{}, nil, nil, time.Millisecond)
+	if n := len(tenantLoggers); n != 2 {
+		t.Errorf("Expected one log per configured token, got %d", n)
+	}
+	CloseCommandLogger()
+	if n := len(tenantLoggers); n != 0 {
+		t.Errorf("Expected the per-token logs to be closed, got %d open", n)
+	}
+	CloseCommandLogger()
+
+	pathA := tenantLogPath(logFile, callerIdentity(callA))
+	pathB := tenantLogPath(logFile, callerIdentity(callB))
+	if pathA == pathB {
+		t.Fatalf("Expected distinct logs for distinct tokens, got %s for both", pathA)
+	}
+
+	read := func(path string) string {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read log: %v", err)
+		}
+		return string(data)
+	}
+	logA, logB, shared := read(pathA), read(pathB), read(logFile)
+
+	if !strings.Contains(logA, "fastly_execute service list") || strings.Contains(logA, "backend list") {
+		t.Errorf("Expected only tenant A's command in its log, got %q", logA)
+	}
+	if !strings.Contains(logB, "fastly_execute backend list") || strings.Contains(logB, "service list") {
+		t.Errorf("Expected only tenant B's command in its log, got %q", logB)
+	}
+	if !strings.Contains(shared, "current_time") || !strings.Contains(shared, "fastly_describe") || strings.Contains(shared, "fastly_execute") {
+		t.Errorf("Expected only calls without a configured token in the command log, got %q", shared)
+	}
+	for _, path := range []string{pathA, pathB} {
+		if strings.Contains(path, "tenant-") {
+			t.Errorf("Expected the token not to appear in the log path, got %s", path)
+		}
+	}
+}
+
+func TestCallerIdentity(t *testing.T) {
+	if id := callerIdentity(nil); id != "" {
+		t.Errorf("Expected no identity for a nil call, got %q", id)
+	}
+	if id := callerIdentity(&mcp.CallToolRequest{Extra: &mcp.RequestExtra{Header: http.Header{"Authorization": {"Basic abc"}}}}); id != "" {
+		t.Errorf("Expected no identity without a bearer token, got %q", id)
+	}
+	if id := callerIdentity(bearerCall("same")); id != "" {
+		t.Errorf("Expected no identity without configured tokens, got %q", id)
+	}
+
+	SetHTTPAuthTokens([]string{"same"})
+	defer SetHTTPAuthTokens(nil)
+	if id := callerIdentity(bearerCall("same")); id == "" || id != callerIdentity(bearerCall("same")) {
+		t.Errorf("Expected the identity of a configured token to be stable, got %q", id)
+	}
+	if id := callerIdentity(bearerCall("other")); id != "" {
+		t.Errorf("Expected no identity for an unconfigured token, got %q", id)
+	}
+}
@@ -0,0 +1,48 @@
+package mcp
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/fastly/mcp/internal/cache"
+)
+
+// MetricsPath is the HTTP path serving server metrics in HTTP mode.
+const MetricsPath = "/metrics"
+
+// withMetrics serves MetricsPath from metricsHandler and every other path
+// from the MCP handler.
+func withMetrics(handler http.Handler) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(MetricsPath, metricsHandler)
+	mux.Handle("/", handler)
+	return mux
+}
+
+// metricsHandler writes result cache metrics in the Prometheus text format, so
+// operators can watch the cache footprint and tune its limits.
+func metricsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	writeStoreMetrics(w, cache.GetStore().Stats())
+}
+
+// writeStoreMetrics writes the result cache gauges and eviction counter. Timestamps are omitted
+// while the cache is empty.
+func writeStoreMetrics(w io.Writer, stats cache.StoreStats) {
+	gauge := func(name, help string, value interface{}) {
+		_, _ = fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %v\n", name, help, name, name, value)
+	}
+	counter := func(name, help string, value interface{}) {
+		_, _ = fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n%s %v\n", name, help, name, name, value)
+	}
+
+	gauge("fastly_mcp_result_store_entries", "Number of cached command results.", stats.Entries)
+	gauge("fastly_mcp_result_store_unique_outputs", "Number of distinct outputs stored for the cached results.", stats.UniqueOutputs)
+	gauge("fastly_mcp_result_store_bytes", "Combined size of the distinct cached outputs in bytes.", stats.TotalBytes)
+	counter("fastly_mcp_result_store_evictions_total", "Number of cached results evicted to stay within the entry or byte limit.", stats.Evictions)
+	if !stats.Oldest.IsZero() {
+		gauge("fastly_mcp_result_store_oldest_timestamp_seconds", "Creation time of the oldest cached result.", stats.Oldest.Unix())
+		gauge("fastly_mcp_result_store_newest_timestamp_seconds", "Creation time of the newest cached result.", stats.Newest.Unix())
+	}
+}
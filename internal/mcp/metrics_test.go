@@ -0,0 +1,38 @@
+package mcp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/cache"
+)
+
+func TestMetricsEndpoint(t *testing.T) {
+	cache.GetStore().Store(`[{"id":"svc1"}]`, "service", []string{"list"}, nil)
+
+	mcpHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("mcp"))
+	})
+	recorder := httptest.NewRecorder()
+	withMetrics(mcpHandler).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, MetricsPath, nil))
+	body := recorder.Body.String()
+	for _, metric := range []string{
+		"fastly_mcp_result_store_entries ",
+		"fastly_mcp_result_store_unique_outputs ",
+		"fastly_mcp_result_store_bytes ",
+		"fastly_mcp_result_store_evictions_total ",
+		"fastly_mcp_result_store_oldest_timestamp_seconds ",
+	} {
+		if !strings.Contains(body, "\n"+metric) {
+			t.Errorf("Expected metric %q in:\n%s", strings.TrimSpace(metric), body)
+		}
+	}
+
+	recorder = httptest.NewRecorder()
+	withMetrics(mcpHandler).ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", nil))
+	if recorder.Body.String() != "mcp" {
+		t.Errorf("Expected other paths to reach the MCP handler, got %q", recorder.Body.String())
+	}
+}
@@ -0,0 +1,59 @@
+package mcp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// perPageDefaults maps command paths (e.g. "service list", "stats") to the
+// per-page value applied when the agent omits pagination.
+// It can be configured via SetPerPageDefaults().
+var perPageDefaults = map[string]int{}
+
+// SetPerPageDefaults configures per-command default page sizes. Keys are command
+// paths; the longest matching path wins, so "service list" overrides "service".
+func SetPerPageDefaults(defaults map[string]int) {
+	normalized := make(map[string]int, len(defaults))
+	for path, perPage := range defaults {
+		normalized[strings.Join(strings.Fields(path), " ")] = perPage
+	}
+	perPageDefaults = normalized
+}
+
+// ParsePerPageDefaults parses a comma-separated list of "command path=per-page"
+// entries, e.g. "service list=50,stats historical=100".
+func ParsePerPageDefaults(spec string) (map[string]int, error) {
+	defaults := make(map[string]int)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		path, value, found := strings.Cut(entry, "=")
+		path = strings.Join(strings.Fields(path), " ")
+		if !found || path == "" {
+			return nil, fmt.Errorf("invalid per-page default %q: expected 'command=per-page'", entry)
+		}
+
+		perPage, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || perPage <= 0 {
+			return nil, fmt.Errorf("invalid per-page default %q: per-page must be a positive integer", entry)
+		}
+		defaults[path] = perPage
+	}
+	return defaults, nil
+}
+
+// defaultPerPage returns the configured per-page value for the longest command
+// path that prefixes the given command and arguments.
+func defaultPerPage(cmd string, args []string) (int, bool) {
+	parts := append([]string{cmd}, args...)
+	for n := len(parts); n > 0; n-- {
+		if perPage, ok := perPageDefaults[strings.Join(parts[:n], " ")]; ok {
+			return perPage, true
+		}
+	}
+	return 0, false
+}
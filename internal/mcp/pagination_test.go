@@ -0,0 +1,62 @@
+package mcp
+
+import (
+	"testing"
+)
+
+func TestParsePerPageDefaults(t *testing.T) {
+	defaults, err := ParsePerPageDefaults("service list=50, stats  historical=100,")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if defaults["service list"] != 50 || defaults["stats historical"] != 100 {
+		t.Errorf("Unexpected defaults: %v", defaults)
+	}
+
+	for _, spec := range []string{"service list", "=10", "stats=0", "stats=abc"} {
+		if _, err := ParsePerPageDefaults(spec); err == nil {
+			t.Errorf("Expected error for %q", spec)
+		}
+	}
+}
+
+func TestApplyPerPageDefaults(t *testing.T) {
+	SetPerPageDefaults(map[string]int{
+		"service":          20,
+		"service list":     50,
+		"stats historical": 100,
+	})
+	defer SetPerPageDefaults(nil)
+
+	perPageValue := func(flags []Flag) string {
+		for _, flag := range flags {
+			if flag.Name == "per-page" {
+				return flag.Value
+			}
+		}
+		return ""
+	}
+
+	tests := []struct {
+		name     string
+		cmd      string
+		args     []string
+		flags    []Flag
+		expected string
+	}{
+		{"service list default", "service", []string{"list"}, nil, "50"},
+		{"stats default", "stats", []string{"historical"}, nil, "100"},
+		{"shorter path default", "service", []string{"search"}, nil, "20"},
+		{"no default configured", "backend", []string{"list"}, nil, ""},
+		{"explicit value wins", "service", []string{"list"}, []Flag{{Name: "per-page", Value: "5"}}, "5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags := applySmartDefaults(tt.cmd, tt.args, tt.flags)
+			if got := perPageValue(flags); got != tt.expected {
+				t.Errorf("Expected per-page %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/fastly/mcp/internal/fastly"
+	"github.com/fastly/mcp/internal/validation"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// PolicyHeader is the HTTP header a client selects a named policy with.
+const PolicyHeader = "X-Fastly-MCP-Policy"
+
+// PolicyPathPrefix is the URL path prefix a client selects a named policy with
+// instead of the header, e.g. http://127.0.0.1:8080/policy/readonly.
+const PolicyPathPrefix = "/policy/"
+
+// httpPolicies holds the validators of the named policies HTTP clients select
+// from, and httpDefaultPolicy the policy of clients that select none. They can
+// be configured via SetHTTPPolicies().
+var (
+	httpPolicies      map[string]*validation.Validator
+	httpDefaultPolicy string
+)
+
+// SetHTTPPolicies configures the named policies of HTTP mode. Each client
+// session runs with the policy selected by its initialize request, or with
+// defaultPolicy when it selects none; without a default such sessions are
+// refused. Stdio mode always uses the global validator.
+func SetHTTPPolicies(policies map[string]*validation.Validator, defaultPolicy string) {
+	httpPolicies = policies
+	httpDefaultPolicy = defaultPolicy
+}
+
+// withPolicy makes every request to s validate commands with v.
+func withPolicy(s *mcp.Server, v *validation.Validator) {
+	s.AddReceivingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			return next(fastly.WithValidator(ctx, v), method, req)
+		}
+	})
+}
+
+// createPolicyServers creates one server per configured policy.
+func createPolicyServers() (map[string]*mcp.Server, error) {
+	servers := make(map[string]*mcp.Server, len(httpPolicies))
+	for name, v := range httpPolicies {
+		s, err := CreateServer()
+		if err != nil {
+			return nil, err
+		}
+		withPolicy(s, v)
+		servers[name] = s
+	}
+	return servers, nil
+}
+
+// requestPolicy returns the policy an HTTP request selects with PolicyHeader
+// or PolicyPathPrefix, or the default policy. It reports false if the request
+// selects two different policies.
+func requestPolicy(r *http.Request) (string, bool) {
+	header := strings.TrimSpace(r.Header.Get(PolicyHeader))
+	path := ""
+	if rest, ok := strings.CutPrefix(r.URL.Path, PolicyPathPrefix); ok {
+		path, _, _ = strings.Cut(rest, "/")
+	}
+
+	switch {
+	case header != "" && path != "" && header != path:
+		return "", false
+	case header != "":
+		return header, true
+	case path != "":
+		return path, true
+	default:
+		return httpDefaultPolicy, true
+	}
+}
+
+// policyServer returns the server of the policy an HTTP request selects, or
+// nil, which the MCP handlers answer with 400 Bad Request, if the policy is
+// unknown or none is selected without a default.
+func policyServer(servers map[string]*mcp.Server, r *http.Request) *mcp.Server {
+	name, ok := requestPolicy(r)
+	if !ok || name == "" {
+		return nil
+	}
+	return servers[name]
+}
+
+// printPolicyInfo tells how to select the configured policies, if any.
+func printPolicyInfo(addr string) {
+	if len(httpPolicies) == 0 {
+		return
+	}
+	fmt.Printf("\nPolicies: %s\n", strings.Join(policyNames(), ", "))
+	if httpDefaultPolicy != "" {
+		fmt.Printf("  Default: %s\n", httpDefaultPolicy)
+	} else {
+		fmt.Printf("  Default: none, sessions that select no policy are refused\n")
+	}
+	fmt.Printf("  Select one with the %s header or the URL http://%s%s<name>\n", PolicyHeader, addr, PolicyPathPrefix)
+}
+
+// policyNames returns the names of the configured policies, sorted.
+func policyNames() []string {
+	names := make([]string, 0, len(httpPolicies))
+	for name := range httpPolicies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,122 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+	"github.com/fastly/mcp/internal/validation"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// headerTransport adds a header to every request.
+type headerTransport struct {
+	name, value string
+}
+
+func (h headerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set(h.name, h.value)
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestHTTPPolicies(t *testing.T) {
+	dir := t.TempDir()
+	mockPath := filepath.Join(dir, "fastly")
+	if err := os.WriteFile(mockPath, []byte("#!/bin/sh\necho '[]'\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("FASTLY_CLI_PATH", mockPath)
+
+	SetHTTPPolicies(map[string]*validation.Validator{
+		"ops":        validation.NewValidator(),
+		"restricted": validation.NewValidatorWithCommandsAndDenied(validation.DefaultAllowedCommands(), map[string]bool{"service list": true}),
+	}, "ops")
+	defer SetHTTPPolicies(nil, "")
+
+	servers, err := createPolicyServers()
+	if err != nil {
+		t.Fatalf("createPolicyServers failed: %v", err)
+	}
+	httpServer := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(r *http.Request) *mcp.Server {
+		return policyServer(servers, r)
+	}, nil))
+	defer httpServer.Close()
+
+	connect := func(endpoint string, client *http.Client) (*mcp.ClientSession, error) {
+		transport := &mcp.StreamableClientTransport{Endpoint: endpoint, HTTPClient: client, MaxRetries: -1, DisableStandaloneSSE: true}
+		return mcp.NewClient(&mcp.Implementation{Name: "policy-test", Version: "1.0.0"}, nil).Connect(context.Background(), transport, nil)
+	}
+	serviceList := func(session *mcp.ClientSession) types.CommandResponse {
+		t.Helper()
+		result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+			Name:      "fastly_execute",
+			Arguments: map[string]interface{}{"command": "service", "args": []string{"list"}},
+		})
+		if err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		var response types.CommandResponse
+		if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return response
+	}
+
+	tests := []struct {
+		name     string
+		endpoint string
+		client   *http.Client
+		allowed  bool
+	}{
+		{"default policy", httpServer.URL, nil, true},
+		{"policy path", httpServer.URL + PolicyPathPrefix + "restricted", nil, false},
+		{"policy header", httpServer.URL, &http.Client{Transport: headerTransport{PolicyHeader, "restricted"}}, false},
+		{"explicit ops header", httpServer.URL, &http.Client{Transport: headerTransport{PolicyHeader, "ops"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session, err := connect(tt.endpoint, tt.client)
+			if err != nil {
+				t.Fatalf("Connect failed: %v", err)
+			}
+			defer func() { _ = session.Close() }()
+
+			response := serviceList(session)
+			if tt.allowed && !response.Success {
+				t.Errorf("Expected service list to be allowed, got %+v", response)
+			}
+			if !tt.allowed && response.ErrorCode != "COMMAND_NOT_AVAILABLE" {
+				t.Errorf("Expected service list to be denied, got %+v", response)
+			}
+		})
+	}
+
+	// Unknown and conflicting policies are refused
+	if session, err := connect(httpServer.URL+PolicyPathPrefix+"unknown", nil); err == nil {
+		_ = session.Close()
+		t.Error("Expected an unknown policy to be refused")
+	}
+	if session, err := connect(httpServer.URL+PolicyPathPrefix+"ops", &http.Client{Transport: headerTransport{PolicyHeader, "restricted"}}); err == nil {
+		_ = session.Close()
+		t.Error("Expected conflicting policies to be refused")
+	}
+}
+
+func TestRequestPolicyWithoutDefault(t *testing.T) {
+	SetHTTPPolicies(map[string]*validation.Validator{"ops": validation.NewValidator()}, "")
+	defer SetHTTPPolicies(nil, "")
+
+	if server := policyServer(map[string]*mcp.Server{"ops": {}}, httptest.NewRequest(http.MethodPost, "/", nil)); server != nil {
+		t.Error("Expected a session selecting no policy to be refused without a default")
+	}
+	if server := policyServer(map[string]*mcp.Server{"ops": {}}, httptest.NewRequest(http.MethodPost, "/policy/ops/", nil)); server == nil {
+		t.Error("Expected the policy path to select the ops policy")
+	}
+}
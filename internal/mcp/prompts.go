@@ -0,0 +1,158 @@
+// Package mcp implements the Model Context Protocol server for Fastly CLI operations.
+// This file contains parameterized workflow prompts that expand into step-by-step tool-call plans.
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// workflowArgument describes a single prompt argument. Optional arguments fall
+// back to Default when they are not provided.
+type workflowArgument struct {
+	Name        string
+	Description string
+	Required    bool
+	Default     string
+}
+
+// workflowPrompt is a prompt template for a common multi-step task. Template
+// placeholders are written as {{argument_name}}.
+type workflowPrompt struct {
+	Name        string
+	Description string
+	Arguments   []workflowArgument
+	Template    string
+}
+
+// workflowPrompts lists the workflow prompts registered by CreateServer.
+var workflowPrompts = []workflowPrompt{
+	{
+		Name:        "add_backend",
+		Description: "Step-by-step plan to add a backend to a service on a new version",
+		Arguments: []workflowArgument{
+			{Name: "service_id", Description: "ID of the service to add the backend to", Required: true},
+			{Name: "backend_name", Description: "Name of the new backend", Required: true},
+			{Name: "address", Description: "Hostname or IP address of the origin", Required: true},
+			{Name: "port", Description: "Port of the origin (default: 443)", Default: "443"},
+		},
+		Template: `Add the backend "{{backend_name}}" ({{address}}:{{port}}) to service {{service_id}}. Follow these steps in order and stop if any step fails.
+
+1. Find the active version:
+   fastly_execute {"command":"service-version","args":["list"],"flags":[{"name":"service-id","value":"{{service_id}}"}]}
+
+2. Check the existing backends on the active version so the name does not clash:
+   fastly_execute {"command":"backend","args":["list"],"flags":[{"name":"service-id","value":"{{service_id}}"},{"name":"version","value":"active"}]}
+
+3. Clone the active version. Explain to the user that this creates a new draft version, get their approval, then run:
+   fastly_execute {"command":"service-version","args":["clone"],"flags":[{"name":"service-id","value":"{{service_id}}"},{"name":"version","value":"active"},{"name":"user-reviewed"}]}
+   Note the new version number from the output.
+
+4. Create the backend on the new version. Show the user the exact command and get their approval, then run:
+   fastly_execute {"command":"backend","args":["create"],"flags":[{"name":"service-id","value":"{{service_id}}"},{"name":"version","value":"<new version>"},{"name":"name","value":"{{backend_name}}"},{"name":"address","value":"{{address}}"},{"name":"port","value":"{{port}}"},{"name":"user-reviewed"}]}
+
+5. Confirm the backend exists on the new version:
+   fastly_execute {"command":"backend","args":["describe"],"flags":[{"name":"service-id","value":"{{service_id}}"},{"name":"version","value":"<new version>"},{"name":"name","value":"{{backend_name}}"}]}
+
+6. Activating the version changes production traffic. Only activate when the user explicitly confirms:
+   fastly_execute {"command":"service-version","args":["activate"],"flags":[{"name":"service-id","value":"{{service_id}}"},{"name":"version","value":"<new version>"},{"name":"user-reviewed"}]}
+
+Never add the user-reviewed flag without the user's explicit approval of that specific command.`,
+	},
+	{
+		Name:        "purge_path",
+		Description: "Step-by-step plan to purge a single URL from the cache of a service",
+		Arguments: []workflowArgument{
+			{Name: "service_id", Description: "ID of the service to purge from", Required: true},
+			{Name: "url", Description: "Full URL of the path to purge (e.g. https://www.example.com/images/logo.png)", Required: true},
+			{Name: "soft", Description: "Mark the content stale instead of removing it: true or false (default: false)", Default: "false"},
+		},
+		Template: `Purge {{url}} from the cache of service {{service_id}} (soft purge: {{soft}}). Follow these steps in order and stop if any step fails.
+
+1. Confirm the service exists and serves the domain of the URL:
+   fastly_execute {"command":"domain","args":["list"],"flags":[{"name":"service-id","value":"{{service_id}}"},{"name":"version","value":"active"}]}
+
+2. Check the purge options:
+   fastly_describe {"command":"purge"}
+
+3. Purging sends requests for this URL back to the origin. Tell the user the URL that will be purged, get their approval, then run:
+   fastly_execute {"command":"purge","flags":[{"name":"service-id","value":"{{service_id}}"},{"name":"url","value":"{{url}}"},{"name":"user-reviewed"}]}
+   If soft purge is true, also add {"name":"soft"} to the flags.
+
+4. Report the purge ID from the output to the user.
+
+Never add the user-reviewed flag without the user's explicit approval of that specific command.`,
+	},
+}
+
+// registerWorkflowPrompts adds every workflow prompt to the server.
+func registerWorkflowPrompts(s *mcp.Server) {
+	for _, workflow := range workflowPrompts {
+		arguments := make([]*mcp.PromptArgument, 0, len(workflow.Arguments))
+		for _, arg := range workflow.Arguments {
+			arguments = append(arguments, &mcp.PromptArgument{
+				Name:        arg.Name,
+				Description: arg.Description,
+				Required:    arg.Required,
+			})
+		}
+
+		s.AddPrompt(&mcp.Prompt{
+			Name:        workflow.Name,
+			Description: workflow.Description,
+			Arguments:   arguments,
+		}, makeWorkflowPromptHandler(workflow))
+	}
+}
+
+// makeWorkflowPromptHandler creates a handler that renders a workflow prompt
+// with the arguments supplied by the client.
+func makeWorkflowPromptHandler(workflow workflowPrompt) mcp.PromptHandler {
+	return func(ctx context.Context, request *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		var provided map[string]string
+		if request.Params != nil {
+			provided = request.Params.Arguments
+		}
+
+		text, err := renderWorkflowPrompt(workflow, provided)
+		if err != nil {
+			return nil, err
+		}
+
+		return &mcp.GetPromptResult{
+			Description: workflow.Description,
+			Messages: []*mcp.PromptMessage{
+				{
+					Role: "user",
+					Content: &mcp.TextContent{
+						Text: text,
+					},
+				},
+			},
+		}, nil
+	}
+}
+
+// renderWorkflowPrompt substitutes the provided arguments into the workflow
+// template, applying defaults for omitted optional arguments.
+func renderWorkflowPrompt(workflow workflowPrompt, provided map[string]string) (string, error) {
+	replacements := make([]string, 0, len(workflow.Arguments)*2)
+	for _, arg := range workflow.Arguments {
+		value := strings.TrimSpace(provided[arg.Name])
+		if value == "" {
+			if arg.Required {
+				return "", fmt.Errorf("prompt %s requires the %s argument", workflow.Name, arg.Name)
+			}
+			value = arg.Default
+		}
+		if strings.ContainsAny(value, "\r\n\"") {
+			return "", fmt.Errorf("prompt %s: the %s argument must be a single line without quotes", workflow.Name, arg.Name)
+		}
+		replacements = append(replacements, "{{"+arg.Name+"}}", value)
+	}
+
+	return strings.NewReplacer(replacements...).Replace(workflow.Template), nil
+}
@@ -0,0 +1,34 @@
+package mcp
+
+import "fmt"
+
+// DefaultMaxRequestItems is the default maximum number of args, raw args and
+// flags in a single fastly_execute request or fastly_execute_batch step. Real commands use a few dozen at most.
+const DefaultMaxRequestItems = 100
+
+// maxRequestItems bounds the combined args, raw args and flags of a request, so that a
+// pathological request is refused before preprocessing and validation iterate
+// over it. It can be configured via SetMaxRequestItems().
+var maxRequestItems = DefaultMaxRequestItems
+
+// SetMaxRequestItems sets the maximum combined number of args, raw args and
+// flags accepted in one request. Non-positive values restore the default.
+func SetMaxRequestItems(max int) {
+	if max <= 0 {
+		max = DefaultMaxRequestItems
+	}
+	maxRequestItems = max
+}
+
+// checkRequestItems rejects a request whose args, raw args and flags together
+// exceed maxRequestItems. It only counts the raw parameters, so it is cheap enough to
+// run before anything else looks at them.
+func checkRequestItems(params map[string]interface{}) error {
+	args, _ := params["args"].([]interface{})
+	rawArgs, _ := params["raw_args"].([]interface{})
+	flags, _ := params["flags"].([]interface{})
+	if count := len(args) + len(rawArgs) + len(flags); count > maxRequestItems {
+		return fmt.Errorf("request has %d args, raw args and flags, more than the maximum of %d", count, maxRequestItems)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestExecuteRejectsExcessiveFlags(t *testing.T) {
+	dir := t.TempDir()
+	callsFile := filepath.Join(dir, "calls")
+	script := "#!/bin/sh\necho \"$@\" >> \"" + callsFile + "\"\necho '{}'\n"
+	mockPath := filepath.Join(dir, "fastly")
+	if err := os.WriteFile(mockPath, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("FASTLY_CLI_PATH", mockPath)
+
+	SetMaxRequestItems(10)
+	defer SetMaxRequestItems(0)
+
+	globalContext.mu.RLock()
+	recorded := len(globalContext.RecentCommands)
+	globalContext.mu.RUnlock()
+
+	flags := make([]map[string]interface{}, 0, 11)
+	for i := 0; i < 11; i++ {
+		flags = append(flags, map[string]interface{}{"name": "flag-" + strconv.Itoa(i), "value": "x"})
+	}
+
+	session := connectTestClient(t)
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "fastly_execute",
+		Arguments: map[string]interface{}{"command": "service", "args": []string{"list"}, "flags": flags},
+	})
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+
+	var response types.CommandResponse
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if !result.IsError || response.ErrorCode != "request_too_large" {
+		t.Errorf("Expected a request_too_large error, got %+v", response)
+	}
+
+	globalContext.mu.RLock()
+	defer globalContext.mu.RUnlock()
+	if len(globalContext.RecentCommands) != recorded {
+		t.Error("Expected the request to be rejected before preprocessing")
+	}
+	if _, err := os.Stat(callsFile); !os.IsNotExist(err) {
+		t.Error("Expected the CLI not to be run")
+	}
+}
+
+func TestExecuteBatchRejectsExcessiveFlags(t *testing.T) {
+	SetMaxRequestItems(10)
+	defer SetMaxRequestItems(0)
+
+	flags := make([]map[string]interface{}, 0, 11)
+	for i := 0; i < 11; i++ {
+		flags = append(flags, map[string]interface{}{"name": "flag-" + strconv.Itoa(i), "value": "x"})
+	}
+	steps := []map[string]interface{}{
+		{"command": "service", "args": []string{"list"}},
+		{"command": "service", "args": []string{"list"}, "flags": flags},
+	}
+
+	session := connectTestClient(t)
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "fastly_execute_batch",
+		Arguments: map[string]interface{}{"steps": steps, "plan_only": true},
+	})
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+
+	var response types.BatchResponse
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if !result.IsError || response.ErrorCode != "request_too_large" || response.PlanToken != "" {
+		t.Errorf("Expected a request_too_large error without a plan, got %+v", response)
+	}
+	if !strings.Contains(response.Error, "step 1") {
+		t.Errorf("Expected the error to name the oversized step, got %q", response.Error)
+	}
+}
+
+func TestCheckRequestItems(t *testing.T) {
+	SetMaxRequestItems(3)
+	defer SetMaxRequestItems(0)
+
+	within := map[string]interface{}{
+		"args":  []interface{}{"list"},
+		"flags": []interface{}{map[string]interface{}{"name": "json"}, map[string]interface{}{"name": "verbose"}},
+	}
+	if err := checkRequestItems(within); err != nil {
+		t.Errorf("Expected 3 items to be accepted, got %v", err)
+	}
+
+	within["args"] = []interface{}{"list", "extra"}
+	if err := checkRequestItems(within); err == nil {
+		t.Error("Expected args and flags to be counted together")
+	}
+
+	within["args"] = []interface{}{"list"}
+	within["raw_args"] = []interface{}{"--extra"}
+	if err := checkRequestItems(within); err == nil {
+		t.Error("Expected raw args to be counted as well")
+	}
+
+	SetMaxRequestItems(0)
+	if maxRequestItems != DefaultMaxRequestItems {
+		t.Errorf("Expected a non-positive maximum to restore the default, got %d", maxRequestItems)
+	}
+}
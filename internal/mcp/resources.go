@@ -0,0 +1,112 @@
+// Package mcp implements the Model Context Protocol server for Fastly CLI operations.
+// This file exposes cached command results through the MCP resources capability.
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fastly/mcp/internal/cache"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ResultResourceScheme is the URI scheme for cached results exposed as resources.
+// A cached result with ID "abc" is available as fastly-result://abc.
+const ResultResourceScheme = "fastly-result://"
+
+// registerResultResources exposes cached results as MCP resources. A resource
+// template makes every cached result readable by URI, and a receiving middleware
+// adds the currently cached results to resources/list so clients can discover
+// them without knowing their IDs.
+func registerResultResources(s *mcp.Server) {
+	s.AddResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "fastly_result",
+		Title:       "Cached Fastly command result",
+		Description: "Full output of a large Fastly command that was cached. The same data is available through the fastly_result_* tools.",
+		URITemplate: ResultResourceScheme + "{result_id}",
+	}, readResultResource)
+
+	s.AddReceivingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			result, err := next(ctx, method, req)
+			if err != nil || method != "resources/list" {
+				return result, err
+			}
+
+			// Cached results are appended after any static resources, on the last page
+			if list, ok := result.(*mcp.ListResourcesResult); ok && list.NextCursor == "" {
+				list.Resources = append(list.Resources, listResultResources()...)
+			}
+			return result, nil
+		}
+	})
+}
+
+// listResultResources describes every active cached result as a resource,
+// oldest first.
+func listResultResources() []*mcp.Resource {
+	results := cache.GetStore().List()
+	sort.SliceStable(results, func(i, j int) bool {
+		ti, _ := results[i]["created_at"].(time.Time)
+		tj, _ := results[j]["created_at"].(time.Time)
+		return ti.Before(tj)
+	})
+
+	resources := make([]*mcp.Resource, 0, len(results))
+	for _, result := range results {
+		id, _ := result["id"].(string)
+		command, _ := result["command"].(string)
+		args, _ := result["args"].([]string)
+		dataType, _ := result["data_type"].(string)
+		size, _ := result["size"].(int)
+
+		commandLine := strings.TrimSpace(strings.Join(append([]string{"fastly", command}, args...), " "))
+		resources = append(resources, &mcp.Resource{
+			URI:         ResultResourceScheme + id,
+			Name:        id,
+			Title:       commandLine,
+			Description: fmt.Sprintf("Cached output of '%s' (%s)", commandLine, dataType),
+			MIMEType:    resultMIMEType(dataType),
+			Size:        int64(size),
+		})
+	}
+
+	return resources
+}
+
+// readResultResource returns the full output of a cached result.
+func readResultResource(ctx context.Context, request *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	uri := request.Params.URI
+	resultID := strings.TrimPrefix(uri, ResultResourceScheme)
+
+	result, err := cache.GetStore().Get(resultID)
+	if err != nil {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+
+	text := result.RawOutput
+	if tokenCrypto != nil && tokenCrypto.Enabled {
+		text = tokenCrypto.EncryptTokensInString(text)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{
+			{
+				URI:      uri,
+				MIMEType: resultMIMEType(result.Metadata.DataType),
+				Text:     text,
+			},
+		},
+	}, nil
+}
+
+// resultMIMEType maps a cached data type to the MIME type of its raw output.
+func resultMIMEType(dataType string) string {
+	if strings.HasPrefix(dataType, "json") {
+		return "application/json"
+	}
+	return "text/plain"
+}
@@ -0,0 +1,99 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/cache"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// connectTestClient connects an in-memory client to a freshly created server.
+func connectTestClient(t *testing.T) *mcp.ClientSession {
+	t.Helper()
+
+	server, err := CreateServer()
+	if err != nil {
+		t.Fatalf("CreateServer failed: %v", err)
+	}
+
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("Server connect failed: %v", err)
+	}
+	t.Cleanup(func() { _ = serverSession.Close() })
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("Client connect failed: %v", err)
+	}
+	t.Cleanup(func() { _ = session.Close() })
+
+	return session
+}
+
+func TestCachedResultListedAsResource(t *testing.T) {
+	output := `[{"id":"svc1","name":"first"},{"id":"svc2","name":"second"}]`
+	resultID := cache.GetStore().Store(output, "service", []string{"list"}, nil)
+
+	session := connectTestClient(t)
+
+	list, err := session.ListResources(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListResources failed: %v", err)
+	}
+
+	var found *mcp.Resource
+	for _, resource := range list.Resources {
+		if resource.URI == ResultResourceScheme+resultID {
+			found = resource
+		}
+	}
+	if found == nil {
+		t.Fatalf("Expected cached result %s in resources list, got %d resources", resultID, len(list.Resources))
+	}
+	if found.MIMEType != "application/json" {
+		t.Errorf("Expected application/json MIME type, got %q", found.MIMEType)
+	}
+	if !strings.Contains(found.Title, "fastly service list") {
+		t.Errorf("Expected title to describe the command, got %q", found.Title)
+	}
+}
+
+func TestReadCachedResultResource(t *testing.T) {
+	output := "line one\nline two\nline three"
+	resultID := cache.GetStore().Store(output, "log-tail", nil, nil)
+
+	session := connectTestClient(t)
+
+	result, err := session.ReadResource(context.Background(), &mcp.ReadResourceParams{
+		URI: ResultResourceScheme + resultID,
+	})
+	if err != nil {
+		t.Fatalf("ReadResource failed: %v", err)
+	}
+	if len(result.Contents) != 1 {
+		t.Fatalf("Expected one content item, got %d", len(result.Contents))
+	}
+	if result.Contents[0].Text != output {
+		t.Errorf("Expected full cached output, got %q", result.Contents[0].Text)
+	}
+	if result.Contents[0].MIMEType != "text/plain" {
+		t.Errorf("Expected text/plain MIME type, got %q", result.Contents[0].MIMEType)
+	}
+}
+
+func TestReadUnknownResultResource(t *testing.T) {
+	session := connectTestClient(t)
+
+	_, err := session.ReadResource(context.Background(), &mcp.ReadResourceParams{
+		URI: ResultResourceScheme + "does-not-exist",
+	})
+	if err == nil {
+		t.Fatal("Expected an error reading an unknown cached result")
+	}
+}
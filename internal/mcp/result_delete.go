@@ -0,0 +1,46 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fastly/mcp/internal/cache"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// allResults is the result_id that deletes every cached result of the caller.
+const allResults = "all"
+
+// makeResultDeleteHandler creates a handler for releasing cached results
+// before their TTL expires. In HTTP mode, 'all' deletes only the results of
+// the calling client or session, never those of other clients.
+func makeResultDeleteHandler() mcp.ToolHandler {
+	return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		params := getArguments(request)
+		resultID, ok := params["result_id"].(string)
+		if !ok || resultID == "" {
+			return nil, fmt.Errorf("result_id is required")
+		}
+
+		store := cache.GetStore()
+		if resultID == allResults {
+			return newSuccessResult(map[string]interface{}{
+				"success": true,
+				"deleted": store.ClearOwner(callerScope(request)),
+			}), nil
+		}
+
+		if err := store.Delete(resultID); err != nil {
+			return newErrorResult(map[string]interface{}{
+				"error":     err.Error(),
+				"result_id": resultID,
+			}), nil
+		}
+
+		return newSuccessResult(map[string]interface{}{
+			"success":   true,
+			"result_id": resultID,
+			"deleted":   1,
+		}), nil
+	}
+}
@@ -0,0 +1,60 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/fastly/mcp/internal/cache"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestResultDelete(t *testing.T) {
+	store := cache.GetStore()
+	resultID := store.Store(`[{"name":"origin-1"}]`, "backend", []string{"list"}, nil)
+
+	session := connectTestClient(t)
+	deleteResult := func(id string) (bool, map[string]interface{}) {
+		t.Helper()
+		result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+			Name:      "fastly_result_delete",
+			Arguments: map[string]interface{}{"result_id": id},
+		})
+		if err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		var response map[string]interface{}
+		if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return result.IsError, response
+	}
+
+	isError, response := deleteResult(resultID)
+	if isError || response["deleted"] != float64(1) {
+		t.Fatalf("Expected the result to be deleted, got %v", response)
+	}
+	if _, err := store.Get(resultID); err == nil {
+		t.Error("Expected the deleted result to be gone from the store")
+	}
+
+	isError, response = deleteResult(resultID)
+	if !isError || response["error"] == nil {
+		t.Errorf("Expected an error for an absent result, got %v", response)
+	}
+
+	store.Store("first", "version", []string{"list"}, nil)
+	store.Store("second", "version", []string{"list"}, nil)
+	otherID := store.StoreFor("session:other", "third", "version", []string{"list"}, nil)
+	defer func() { _ = store.Delete(otherID) }()
+	isError, response = deleteResult("all")
+	if isError || response["deleted"].(float64) < 2 {
+		t.Errorf("Expected every result of the caller to be deleted, got %v", response)
+	}
+	if stats := store.Stats(); stats.Entries != 1 {
+		t.Errorf("Expected only the result of the other session to remain, got %d entries", stats.Entries)
+	}
+	if _, err := store.Get(otherID); err != nil {
+		t.Errorf("Expected 'all' to keep the results of other sessions, got %v", err)
+	}
+}
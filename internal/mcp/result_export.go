@@ -0,0 +1,215 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fastly/mcp/internal/cache"
+	"github.com/fastly/mcp/internal/validation"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// DefaultExportDir returns the default directory of fastly_result_export,
+// fastly-mcp-exports in the temporary directory.
+func DefaultExportDir() string {
+	return filepath.Join(os.TempDir(), "fastly-mcp-exports")
+}
+
+// exportDir is the only directory fastly_result_export writes to. It can be
+// configured via SetExportDir().
+var exportDir = DefaultExportDir()
+
+// SetExportDir sets the directory that fastly_result_export writes into. An
+// empty dir restores the default.
+func SetExportDir(dir string) error {
+	if dir == "" {
+		exportDir = DefaultExportDir()
+		return nil
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("invalid export directory %q: %w", dir, err)
+	}
+	exportDir = abs
+	return nil
+}
+
+// makeResultExportHandler creates a handler for writing a cached result to a
+// file, so that a human can work with the full output without it passing
+// through the model. Files are only written inside exportDir. Read-only mode
+// does not apply: it guards Fastly resources, and an export only copies a
+// cached result into that directory.
+func makeResultExportHandler() mcp.ToolHandler {
+	return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		params := getArguments(request)
+		resultID, ok := params["result_id"].(string)
+		if !ok {
+			return nil, fmt.Errorf("result_id is required")
+		}
+		path, ok := params["path"].(string)
+		if !ok || path == "" {
+			return nil, fmt.Errorf("path is required")
+		}
+		overwrite, _ := params["overwrite"].(bool)
+
+		format, _ := params["format"].(string)
+		if format != "" && format != "raw" && format != "json" {
+			return newErrorResult(map[string]interface{}{
+				"error": fmt.Sprintf("unsupported format %q: use 'raw' or 'json'", format),
+			}), nil
+		}
+
+		if err := validation.NewValidator().ValidatePath(path); err != nil {
+			return newErrorResult(map[string]interface{}{
+				"error": fmt.Sprintf("invalid path: %v", err),
+			}), nil
+		}
+
+		target, err := resolveExportPath(path)
+		if err != nil {
+			return newErrorResult(map[string]interface{}{
+				"error": err.Error(),
+			}), nil
+		}
+
+		result, err := cache.GetStore().Get(resultID)
+		if err != nil {
+			return newErrorResult(map[string]interface{}{
+				"error": err.Error(),
+			}), nil
+		}
+
+		content, err := exportContent(result, format)
+		if err != nil {
+			return newErrorResult(map[string]interface{}{
+				"error": err.Error(),
+			}), nil
+		}
+
+		written, err := writeExportFile(target, content, overwrite)
+		if err != nil {
+			return newErrorResult(map[string]interface{}{
+				"error": err.Error(),
+			}), nil
+		}
+
+		return newSuccessResult(map[string]interface{}{
+			"success":       true,
+			"result_id":     resultID,
+			"path":          target,
+			"bytes_written": written,
+		}), nil
+	}
+}
+
+// exportContent returns the bytes to export for a cached result: the raw
+// output, or for the "json" format the parsed JSON data indented for reading.
+func exportContent(result *cache.CachedResult, format string) ([]byte, error) {
+	if format != "json" {
+		return []byte(result.RawOutput), nil
+	}
+	if result.Data == nil {
+		return nil, fmt.Errorf("json format requires a cached JSON result; use format 'raw' for text output")
+	}
+	data, err := json.MarshalIndent(result.Data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize result: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// resolveExportPath resolves path, relative to exportDir unless it is
+// absolute, and rejects it if it lies outside exportDir or if it or any
+// directory below exportDir leading to it is a symbolic link.
+func resolveExportPath(path string) (string, error) {
+	dir := filepath.Clean(exportDir)
+	target := path
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(dir, target)
+	}
+	target = filepath.Clean(target)
+
+	rel, err := filepath.Rel(dir, target)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside the export directory %s", path, dir)
+	}
+
+	current := dir
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		current = filepath.Join(current, part)
+		info, err := os.Lstat(current)
+		if errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to check export path: %w", err)
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return "", fmt.Errorf("%s is a symbolic link; exports do not follow links", current)
+		}
+	}
+	return target, nil
+}
+
+// writeExportFile writes content to path, refusing to replace an existing file
+// unless overwrite is set. It returns the number of bytes written. An existing
+// file is opened without creating or truncating it, and only emptied once it is
+// known to be the regular file at path, so a symbolic link swapped in after
+// resolveExportPath is not followed.
+func writeExportFile(path string, content []byte, overwrite bool) (int, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return 0, fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	// O_EXCL also refuses a symbolic link, even one to a missing file
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if errors.Is(err, os.ErrExist) && overwrite {
+		file, err = os.OpenFile(path, os.O_WRONLY, 0)
+		if err == nil {
+			if err := checkExportFile(file, path); err != nil {
+				_ = file.Close()
+				return 0, err
+			}
+		}
+	}
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return 0, fmt.Errorf("%s already exists; pass overwrite=true to replace it", path)
+		}
+		return 0, fmt.Errorf("failed to create export file: %w", err)
+	}
+
+	written, err := file.Write(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return written, fmt.Errorf("failed to write export file: %w", err)
+	}
+	return written, nil
+}
+
+// checkExportFile makes sure the opened file is the regular file at path, not
+// one reached through a symbolic link, and empties it for the new content.
+func checkExportFile(file *os.File, path string) error {
+	opened, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to check export file: %w", err)
+	}
+	link, err := os.Lstat(path)
+	if err != nil {
+		return fmt.Errorf("failed to check export file: %w", err)
+	}
+	if link.Mode()&os.ModeSymlink != 0 || !opened.Mode().IsRegular() || !os.SameFile(opened, link) {
+		return fmt.Errorf("%s is not a regular file; exports do not follow links", path)
+	}
+	if err := file.Truncate(0); err != nil {
+		return fmt.Errorf("failed to truncate export file: %w", err)
+	}
+	return nil
+}
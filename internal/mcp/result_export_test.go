@@ -0,0 +1,138 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fastly/mcp/internal/cache"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestResultExport(t *testing.T) {
+	output := `[{"name":"origin-1"},{"name":"origin-2"}]`
+	resultID := cache.GetStore().Store(output, "backend", []string{"list"}, nil)
+	dir := t.TempDir()
+	if err := SetExportDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = SetExportDir("") }()
+	path := filepath.Join(dir, "backends.json")
+
+	session := connectTestClient(t)
+	export := func(arguments map[string]interface{}) (bool, map[string]interface{}) {
+		t.Helper()
+		result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "fastly_result_export", Arguments: arguments})
+		if err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		var response map[string]interface{}
+		if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return result.IsError, response
+	}
+
+	isError, response := export(map[string]interface{}{"result_id": resultID, "path": path})
+	if isError || response["bytes_written"] != float64(len(output)) {
+		t.Fatalf("Expected %d bytes written, got %v", len(output), response)
+	}
+	if data, _ := os.ReadFile(path); string(data) != output {
+		t.Errorf("Expected the raw output in the file, got %q", data)
+	}
+
+	isError, response = export(map[string]interface{}{"result_id": resultID, "path": path, "format": "json"})
+	if !isError || !strings.Contains(response["error"].(string), "overwrite=true") {
+		t.Errorf("Expected an existing file not to be replaced, got %v", response)
+	}
+
+	isError, response = export(map[string]interface{}{"result_id": resultID, "path": path, "format": "json", "overwrite": true})
+	if isError {
+		t.Fatalf("Expected the export to overwrite the file, got %v", response)
+	}
+	if data, _ := os.ReadFile(path); !strings.Contains(string(data), "\n  {\n    \"name\": \"origin-1\"") {
+		t.Errorf("Expected indented JSON in the file, got %q", data)
+	}
+
+	// Relative paths are written into the export directory
+	isError, response = export(map[string]interface{}{"result_id": resultID, "path": "relative.json"})
+	if isError || response["path"] != filepath.Join(dir, "relative.json") {
+		t.Errorf("Expected a relative path inside the export directory, got %v", response)
+	}
+
+	isError, response = export(map[string]interface{}{"result_id": resultID, "path": dir + "/../escape.json"})
+	if !isError || !strings.Contains(response["error"].(string), "path traversal") {
+		t.Errorf("Expected a traversal path to be rejected, got %v", response)
+	}
+
+	isError, _ = export(map[string]interface{}{"result_id": "result_missing", "path": filepath.Join(dir, "missing.json")})
+	if !isError {
+		t.Error("Expected an unknown result ID to be reported as an error")
+	}
+}
+
+func TestResultExportConfinedToExportDir(t *testing.T) {
+	resultID := cache.GetStore().Store("secret output", "backend", []string{"list"}, nil)
+	dir := t.TempDir()
+	if err := SetExportDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = SetExportDir("") }()
+
+	outside := filepath.Join(t.TempDir(), "outside.txt")
+	if err := os.WriteFile(outside, []byte("original"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(dir, "link.txt")); err != nil {
+		t.Skipf("Symbolic links are not supported: %v", err)
+	}
+	if err := os.Symlink(filepath.Dir(outside), filepath.Join(dir, "linkdir")); err != nil {
+		t.Fatal(err)
+	}
+
+	session := connectTestClient(t)
+	for _, path := range []string{outside, "link.txt", "linkdir/new.txt", dir} {
+		result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+			Name:      "fastly_result_export",
+			Arguments: map[string]interface{}{"result_id": resultID, "path": path, "overwrite": true},
+		})
+		if err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		if !result.IsError {
+			t.Errorf("Expected %s to be rejected, got %s", path, result.Content[0].(*mcp.TextContent).Text)
+		}
+	}
+
+	if data, _ := os.ReadFile(outside); string(data) != "original" {
+		t.Errorf("Expected the file outside the export directory to be unchanged, got %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(outside), "new.txt")); !os.IsNotExist(err) {
+		t.Error("Expected no file to be created through a linked directory")
+	}
+}
+
+func TestWriteExportFileRefusesSymlink(t *testing.T) {
+	dir := t.TempDir()
+	outside := filepath.Join(t.TempDir(), "outside.txt")
+	if err := os.WriteFile(outside, []byte("original"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "link.txt")
+	if err := os.Symlink(outside, link); err != nil {
+		t.Skipf("Symbolic links are not supported: %v", err)
+	}
+
+	// A link swapped in after the path was resolved is not followed either
+	for _, overwrite := range []bool{false, true} {
+		if _, err := writeExportFile(link, []byte("replaced"), overwrite); err == nil {
+			t.Errorf("Expected writing through a symbolic link to fail with overwrite %v", overwrite)
+		}
+	}
+	if data, _ := os.ReadFile(outside); string(data) != "original" {
+		t.Errorf("Expected the link target to be unchanged, got %q", data)
+	}
+}
@@ -0,0 +1,53 @@
+package mcp
+
+import "fmt"
+
+// DefaultResultListLimit is the default maximum number of cached results
+// returned by one fastly_result_list call.
+const DefaultResultListLimit = 20
+
+// resultListLimit caps the results returned by fastly_result_list so that the
+// listing stays small in long sessions. It can be configured via
+// SetResultListLimit().
+var resultListLimit = DefaultResultListLimit
+
+// SetResultListLimit sets the maximum number of cached results returned by one
+// fastly_result_list call. Non-positive values restore the default.
+func SetResultListLimit(limit int) {
+	if limit <= 0 {
+		limit = DefaultResultListLimit
+	}
+	resultListLimit = limit
+}
+
+// pageResultList returns the page of results starting at offset, with at most
+// limit entries capped at resultListLimit, and a note telling the agent how to
+// fetch the rest. The note is empty when the page holds every result.
+func pageResultList(results []map[string]interface{}, offset, limit int) ([]map[string]interface{}, string) {
+	if limit <= 0 || limit > resultListLimit {
+		limit = resultListLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(results) {
+		offset = len(results)
+	}
+	end := offset + limit
+	if end > len(results) {
+		end = len(results)
+	}
+
+	page := results[offset:end]
+	if offset == 0 && end == len(results) {
+		return page, ""
+	}
+	note := fmt.Sprintf("Showing results %d to %d of %d cached results, most recent first.", offset+1, end, len(results))
+	if len(page) == 0 {
+		note = fmt.Sprintf("No results at offset %d; there are %d cached results.", offset, len(results))
+	}
+	if end < len(results) {
+		note += fmt.Sprintf(" Call fastly_result_list with offset=%d for more.", end)
+	}
+	return page, note
+}
@@ -0,0 +1,58 @@
+package mcp
+
+import (
+	"github.com/fastly/mcp/internal/types"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// schemaCapability is the key of the experimental client capability with
+// which a client requests a response envelope version during initialization,
+// e.g. {"experimental": {"fastly": {"schema_version": 1}}}.
+const schemaCapability = "fastly"
+
+// globalSchemaVersion is the envelope version used for clients that do not
+// request one. It can be configured via SetSchemaVersion().
+var globalSchemaVersion = types.CurrentSchemaVersion
+
+// SetSchemaVersion configures the response envelope version used for clients
+// that do not request one when they initialize.
+func SetSchemaVersion(version int) {
+	globalSchemaVersion = version
+}
+
+// SchemaVersion returns the configured response envelope version.
+func SchemaVersion() int {
+	return globalSchemaVersion
+}
+
+// negotiatedSchemaVersion returns the envelope version requested by the
+// client of a request, or the configured version if it requested none or one
+// outside the supported range.
+func negotiatedSchemaVersion(request *mcp.CallToolRequest) int {
+	if request == nil || request.Session == nil {
+		return globalSchemaVersion
+	}
+	params := request.Session.InitializeParams()
+	if params == nil || params.Capabilities == nil {
+		return globalSchemaVersion
+	}
+	settings, ok := params.Capabilities.Experimental[schemaCapability].(map[string]interface{})
+	if !ok {
+		return globalSchemaVersion
+	}
+	version, ok := settings["schema_version"].(float64)
+	if !ok || int(version) < types.SchemaVersion1 || int(version) > types.CurrentSchemaVersion {
+		return globalSchemaVersion
+	}
+	return int(version)
+}
+
+// newCommandResult returns a command response in the envelope negotiated with
+// the client, as an error result if the command failed.
+func newCommandResult(request *mcp.CallToolRequest, response types.CommandResponse) *mcp.CallToolResult {
+	response = response.ForSchemaVersion(negotiatedSchemaVersion(request))
+	if response.Success {
+		return newSuccessResult(response)
+	}
+	return newErrorResult(response)
+}
@@ -0,0 +1,82 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fastly/mcp/internal/types"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestExecuteSchemaVersionNegotiation(t *testing.T) {
+	dir := t.TempDir()
+	mockPath := filepath.Join(dir, "fastly")
+	if err := os.WriteFile(mockPath, []byte("#!/bin/sh\necho '[]'\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("FASTLY_CLI_PATH", mockPath)
+
+	execute := func(capabilities *mcp.ClientCapabilities) map[string]interface{} {
+		t.Helper()
+		server, err := CreateServer()
+		if err != nil {
+			t.Fatalf("CreateServer failed: %v", err)
+		}
+		ctx := context.Background()
+		serverTransport, clientTransport := mcp.NewInMemoryTransports()
+		serverSession, err := server.Connect(ctx, serverTransport, nil)
+		if err != nil {
+			t.Fatalf("Server connect failed: %v", err)
+		}
+		defer serverSession.Close()
+
+		client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, &mcp.ClientOptions{Capabilities: capabilities})
+		session, err := client.Connect(ctx, clientTransport, nil)
+		if err != nil {
+			t.Fatalf("Client connect failed: %v", err)
+		}
+		defer session.Close()
+
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{
+			Name:      "fastly_execute",
+			Arguments: map[string]interface{}{"command": "service", "args": []string{"list"}},
+		})
+		if err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		var response map[string]interface{}
+		if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return response
+	}
+
+	current := execute(nil)
+	if current["schema_version"] != float64(types.CurrentSchemaVersion) {
+		t.Errorf("Expected the current schema version by default, got %v", current["schema_version"])
+	}
+	if metadata, _ := current["metadata"].(map[string]interface{}); metadata["timeout_seconds"] == nil {
+		t.Errorf("Expected newer metadata fields in the current envelope, got %v", current["metadata"])
+	}
+
+	older := execute(&mcp.ClientCapabilities{Experimental: map[string]any{"fastly": map[string]any{"schema_version": 1}}})
+	if older["schema_version"] != float64(types.SchemaVersion1) {
+		t.Errorf("Expected the requested schema version, got %v", older["schema_version"])
+	}
+	metadata, _ := older["metadata"].(map[string]interface{})
+	if metadata == nil || metadata["operation_type"] == nil {
+		t.Fatalf("Expected the version 1 metadata, got %v", older["metadata"])
+	}
+	if _, found := metadata["timeout_seconds"]; found {
+		t.Errorf("Expected newer metadata fields to be omitted, got %v", metadata)
+	}
+
+	// Unsupported versions fall back to the configured version
+	unsupported := execute(&mcp.ClientCapabilities{Experimental: map[string]any{"fastly": map[string]any{"schema_version": 99}}})
+	if unsupported["schema_version"] != float64(types.CurrentSchemaVersion) {
+		t.Errorf("Expected the configured version for an unsupported request, got %v", unsupported["schema_version"])
+	}
+}
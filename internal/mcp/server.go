@@ -11,9 +11,14 @@ import (
 	"log"
 	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
+	"github.com/fastly/mcp/internal/background"
 	"github.com/fastly/mcp/internal/cache"
 	"github.com/fastly/mcp/internal/fastly"
 	"github.com/fastly/mcp/internal/types"
@@ -36,6 +41,9 @@ func (ft *FastlyTool) checkSetup() error {
 	if !ft.setupChecked {
 		ft.setupError = fastly.CheckSetup()
 		ft.setupChecked = true
+		if version := fastly.DetectedCLIVersion(); version != "" {
+			fmt.Fprintf(os.Stderr, "Using Fastly CLI version %s (minimum supported: %s)\n", version, fastly.MinimumCLIVersion)
+		}
 	}
 	return ft.setupError
 }
@@ -46,6 +54,8 @@ func (ft *FastlyTool) checkSetup() error {
 //   - fastly_describe: Provides detailed help for specific operations
 //   - fastly_execute: Executes Fastly CLI commands with safety checks
 //   - current_time: Utility tool for getting current time information
+//
+// Tool names carry the prefix configured with SetToolPrefix, if any.
 func CreateServer() (*mcp.Server, error) {
 	fastlyTool := &FastlyTool{}
 
@@ -54,7 +64,7 @@ func CreateServer() (*mcp.Server, error) {
 		Version: version.GetVersion(),
 	}, nil)
 
-	s.AddTool(&mcp.Tool{
+	addTool(s, &mcp.Tool{
 		Name:        "fastly_list_commands",
 		Description: "List all available Fastly operations and commands",
 		InputSchema: map[string]interface{}{
@@ -63,7 +73,7 @@ func CreateServer() (*mcp.Server, error) {
 		},
 	}, fastlyTool.makeListCommandsHandler())
 
-	s.AddTool(&mcp.Tool{
+	addTool(s, &mcp.Tool{
 		Name:        "fastly_describe",
 		Description: "Get detailed information about a Fastly operation, including parameters and examples",
 		InputSchema: map[string]interface{}{
@@ -73,12 +83,30 @@ func CreateServer() (*mcp.Server, error) {
 					"type":        "string",
 					"description": "The Fastly command to describe (e.g., 'service', 'service list', 'backend create')",
 				},
+				"include_hidden_flags": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Also list flags that are normally hidden (e.g., 'verbose'). Use only for advanced cases.",
+					"default":     false,
+				},
+				"service": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional service name or ID the command will run on. Names are resolved to IDs like in fastly_execute, and the ID is returned in service_id.",
+				},
+				"recursive": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Also describe the subcommands, returned as a nested subcommand_tree. Heavier than a plain describe; use it to explore a whole command family.",
+					"default":     false,
+				},
+				"depth": map[string]interface{}{
+					"type":        "number",
+					"description": fmt.Sprintf("Levels of subcommands a recursive describe walks (default: %d, max: %d)", fastly.DefaultDescribeDepth, fastly.MaxDescribeDepth),
+				},
 			},
 			"required": []string{"command"},
 		},
 	}, fastlyTool.makeDescribeHandler())
 
-	s.AddTool(&mcp.Tool{
+	addTool(s, &mcp.Tool{
 		Name:        "fastly_execute",
 		Description: "Execute a Fastly operation. Examples: For 'service list' use {\"command\":\"service\",\"args\":[\"list\"]}. For 'backend create' use {\"command\":\"backend\",\"args\":[\"create\"]}.",
 		InputSchema: map[string]interface{}{
@@ -109,16 +137,136 @@ func CreateServer() (*mcp.Server, error) {
 								"type":        "string",
 								"description": "Flag value (omit for boolean flags)",
 							},
+							"lines": map[string]interface{}{
+								"type":        "array",
+								"description": "Multi-line value as an array of lines, only for flags that take file content (e.g., 'content')",
+								"items": map[string]interface{}{
+									"type": "string",
+								},
+							},
 						},
 						"required": []string{"name"},
 					},
 				},
+				"idempotency_key": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional key for create operations. Retrying the identical create with the same key within a few minutes returns the original result instead of creating a duplicate.",
+				},
+				"timeout_seconds": map[string]interface{}{
+					"type":        "number",
+					"description": fmt.Sprintf("Optional timeout for this call in seconds (default: %d), capped at the server's configured maximum. Use for known-slow operations such as compute deploy.", int(fastly.DefaultCommandTimeout().Seconds())),
+				},
+				"from": map[string]interface{}{
+					"type":        "string",
+					"description": "Start of the time range for stats commands: 'now', a relative time such as '-7d', '-24h' or '-30m', an RFC 3339 timestamp or a Unix timestamp. Translated into --from using the current_time clock.",
+				},
+				"to": map[string]interface{}{
+					"type":        "string",
+					"description": "End of the time range for stats commands, in the same formats as 'from'. Translated into --to.",
+				},
+				"keys": map[string]interface{}{
+					"type":        "array",
+					"description": fmt.Sprintf("Batch of surrogate keys to purge (purge command only, up to %d). Each key is validated and purged separately with per-key results; user-reviewed is required once for the whole batch.", fastly.MaxPurgeBatchKeys),
+					"items": map[string]interface{}{
+						"type": "string",
+					},
+				},
+				"binary_safe": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Return output that is not valid UTF-8 base64-encoded in 'output', with output_encoding set to 'base64', instead of replacing the invalid bytes. Use for commands that print binary data.",
+				},
+				"raw_args": map[string]interface{}{
+					"type":        "array",
+					"description": "Escape hatch for arguments the wrapper does not model: tokens appended after a '--' separator, so the CLI never parses them as flags. Each token is validated like args. Only accepted when the server is started with --allow-raw-args.",
+					"items": map[string]interface{}{
+						"type": "string",
+					},
+				},
 			},
 			"required": []string{"command"},
 		},
 	}, fastlyTool.makeExecuteHandler())
 
-	s.AddTool(&mcp.Tool{
+	addTool(s, &mcp.Tool{
+		Name:        "fastly_execute_batch",
+		Description: "Plan and execute a sequence of Fastly operations. Call with plan_only to get the resolved command line and danger classification of each step and a plan_token, without executing anything. After the human user approves the whole plan, call again with the same steps and the plan_token to run them in order; the batch stops at the first failed step.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"steps": map[string]interface{}{
+					"type":        "array",
+					"description": fmt.Sprintf("The operations to run, in order (up to %d). Each step takes command, args and flags as in fastly_execute.", fastly.MaxBatchSteps),
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"command": map[string]interface{}{"type": "string", "description": "The Fastly CLI command to execute"},
+							"args": map[string]interface{}{
+								"type":        "array",
+								"description": "Command arguments",
+								"items":       map[string]interface{}{"type": "string"},
+							},
+							"flags": map[string]interface{}{
+								"type":        "array",
+								"description": "Command flags",
+								"items": map[string]interface{}{
+									"type": "object",
+									"properties": map[string]interface{}{
+										"name":  map[string]interface{}{"type": "string", "description": "Flag name without dashes"},
+										"value": map[string]interface{}{"type": "string", "description": "Flag value"},
+									},
+									"required": []string{"name"},
+								},
+							},
+						},
+						"required": []string{"command"},
+					},
+				},
+				"plan_only": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Return the plan and a plan_token without executing anything",
+				},
+				"plan_token": map[string]interface{}{
+					"type":        "string",
+					"description": "The plan_token of an approved plan for the same steps; executes them",
+				},
+			},
+			"required": []string{"steps"},
+		},
+	}, fastlyTool.makeExecuteBatchHandler())
+
+	addTool(s, &mcp.Tool{
+		Name:        "fastly_version_diff",
+		Description: "Compare two versions of a service. Returns a structured diff of backends, domains and health checks (added, removed and changed items with old and new field values). Use before activating a new version to review exactly what changes.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"service_id": map[string]interface{}{
+					"type":        "string",
+					"description": "The ID of the service, or its name as shown by a previous service list",
+				},
+				"from_version": map[string]interface{}{
+					"type":        "string",
+					"description": "The older version number, or 'active' or 'latest'",
+				},
+				"to_version": map[string]interface{}{
+					"type":        "string",
+					"description": "The newer version number, or 'active' or 'latest'",
+				},
+			},
+			"required": []string{"service_id", "from_version", "to_version"},
+		},
+	}, fastlyTool.makeVersionDiffHandler())
+
+	addTool(s, &mcp.Tool{
+		Name:        "fastly_diagnose",
+		Description: "Diagnose the Fastly CLI setup when commands fail with setup or authentication errors. Checks step by step that the CLI is installed and runs, whether FASTLY_API_TOKEN is set, whether a profile is configured and whether the token is accepted, and reports the first failed step with how to fix it.",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+		},
+	}, fastlyTool.makeDiagnoseHandler())
+
+	addTool(s, &mcp.Tool{
 		Name:        "current_time",
 		Description: "Get current timestamp for logs, API calls, scheduling, or time-based operations. Returns Unix timestamp, ISO 8601, UTC, and local time formats. Use when: generating timestamps for API calls, time-based filtering for stats/logs, recording operation times, or calculating time windows.",
 		InputSchema: map[string]interface{}{
@@ -128,7 +276,7 @@ func CreateServer() (*mcp.Server, error) {
 	}, getCurrentTime)
 
 	// Cache retrieval tools
-	s.AddTool(&mcp.Tool{
+	addTool(s, &mcp.Tool{
 		Name:        "fastly_result_read",
 		Description: "Read paginated data from a cached result. Use this to retrieve portions of large command outputs.",
 		InputSchema: map[string]interface{}{
@@ -148,14 +296,55 @@ func CreateServer() (*mcp.Server, error) {
 					"description": "Number of items/lines to return (default: 20)",
 					"default":     20,
 				},
+				"format": map[string]interface{}{
+					"type":        "string",
+					"description": "Output format: 'json' (default) or 'markdown' to render an array of objects as a Markdown table for human review",
+					"enum":        []string{"json", "markdown"},
+					"default":     "json",
+				},
 			},
 			"required": []string{"result_id"},
 		},
 	}, makeResultReadHandler())
 
-	s.AddTool(&mcp.Tool{
+	addTool(s, &mcp.Tool{
+		Name:        "fastly_result_read_multi",
+		Description: "Read paginated data from several cached results in one call. Returns a map of result_id to data. The combined response size is capped; results that do not fit must be read separately.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"reads": map[string]interface{}{
+					"type":        "array",
+					"description": fmt.Sprintf("Results to read (at most %d)", cache.MaxMultiReadResults),
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"result_id": map[string]interface{}{
+								"type":        "string",
+								"description": "The ID of the cached result to read from",
+							},
+							"offset": map[string]interface{}{
+								"type":        "number",
+								"description": "Starting position (0-based index for arrays/lines)",
+								"default":     0,
+							},
+							"limit": map[string]interface{}{
+								"type":        "number",
+								"description": "Number of items/lines to return (default: 20)",
+								"default":     20,
+							},
+						},
+						"required": []string{"result_id"},
+					},
+				},
+			},
+			"required": []string{"reads"},
+		},
+	}, makeResultReadMultiHandler())
+
+	addTool(s, &mcp.Tool{
 		Name:        "fastly_result_query",
-		Description: "Query/filter cached result data. For arrays: use 'field=value' filters. For text: searches for matching lines.",
+		Description: "Query/filter cached result data. For arrays: use 'field=value' filters, with dotted paths for nested fields ('tls.cert_hostname=example.com'), and project fields with '[].name' or '[].{name,address}', optionally after a filter ('status=active | [].name'). For objects: use paths such as 'service.name' or 'backends[].name'. For text: searches for matching lines.",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
@@ -165,16 +354,16 @@ func CreateServer() (*mcp.Server, error) {
 				},
 				"filter": map[string]interface{}{
 					"type":        "string",
-					"description": "Filter expression (e.g., 'name=production', 'error', 'status.code=200')",
+					"description": "Filter expression (e.g., 'name=production', 'error', 'tls.cert_hostname=example.com', '[].name', 'status=active | [].{name,address}')",
 				},
 			},
 			"required": []string{"result_id", "filter"},
 		},
 	}, makeResultQueryHandler())
 
-	s.AddTool(&mcp.Tool{
+	addTool(s, &mcp.Tool{
 		Name:        "fastly_result_summary",
-		Description: "Get a summary of cached result including metadata, structure, and statistics.",
+		Description: "Get a summary of cached result including metadata, structure, and statistics. Large text results include a section index of line ranges (offset, limit and first line) to pass to fastly_result_read. 'limits' reports the cache threshold and truncation limits in effect.",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
@@ -187,17 +376,84 @@ func CreateServer() (*mcp.Server, error) {
 		},
 	}, makeResultSummaryHandler())
 
-	s.AddTool(&mcp.Tool{
+	addTool(s, &mcp.Tool{
 		Name:        "fastly_result_list",
-		Description: "List all currently cached results with their IDs and metadata.",
+		Description: "List currently cached results with their IDs and metadata, most recent first. Long lists are returned in pages; 'total' counts every matching result.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"service": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional service name or ID; only list results produced for this service",
+				},
+				"offset": map[string]interface{}{
+					"type":        "number",
+					"description": "Number of most recent results to skip (default: 0)",
+				},
+				"limit": map[string]interface{}{
+					"type":        "number",
+					"description": fmt.Sprintf("Maximum number of results to return (default and maximum: %d)", resultListLimit),
+				},
+			},
+		},
+	}, makeResultListHandler())
+
+	addTool(s, &mcp.Tool{
+		Name:        "fastly_result_stats",
+		Description: "Report the memory footprint of the result cache: number of cached results, their total size in bytes, and the oldest and newest creation times.",
 		InputSchema: map[string]interface{}{
 			"type":       "object",
 			"properties": map[string]interface{}{},
 		},
-	}, makeResultListHandler())
+	}, makeResultStatsHandler())
+
+	addTool(s, &mcp.Tool{
+		Name:        "fastly_result_export",
+		Description: "Write the full output of a cached result to a file in the export directory of the server, so a human can use all the data without it passing through the conversation. Refuses to replace an existing file unless overwrite is true. Returns the path written and the number of bytes written.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"result_id": map[string]interface{}{
+					"type":        "string",
+					"description": "The ID of the cached result to export",
+				},
+				"path": map[string]interface{}{
+					"type":        "string",
+					"description": "File to write, relative to the export directory (e.g., 'services.json'); paths outside it and symbolic links are rejected",
+				},
+				"format": map[string]interface{}{
+					"type":        "string",
+					"description": "Output format: 'raw' (default) writes the command output as returned, 'json' writes the parsed JSON indented",
+					"enum":        []string{"raw", "json"},
+					"default":     "raw",
+				},
+				"overwrite": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Replace the file if it already exists (default: false)",
+					"default":     false,
+				},
+			},
+			"required": []string{"result_id", "path"},
+		},
+	}, makeResultExportHandler())
+
+	addTool(s, &mcp.Tool{
+		Name:        "fastly_result_delete",
+		Description: "Delete a cached result you no longer need, freeing its memory before it expires. Pass result_id 'all' to delete every cached result of this client. Returns the number of results deleted.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"result_id": map[string]interface{}{
+					"type":        "string",
+					"description": "The ID of the cached result to delete, or 'all' to delete every cached result of this client",
+				},
+			},
+			"required": []string{"result_id"},
+		},
+	}, makeResultDeleteHandler())
 
 	// Background streaming command tools
-	s.AddTool(&mcp.Tool{
+	addTool(s, &mcp.Tool{
 		Name:        "fastly_background_start",
 		Description: "Start a streaming command (like log-tail) in the background. The command runs until stopped and output can be read with pagination.",
 		InputSchema: map[string]interface{}{
@@ -224,12 +480,16 @@ func CreateServer() (*mcp.Server, error) {
 						"required": []string{"name"},
 					},
 				},
+				"label": map[string]interface{}{
+					"type":        "string",
+					"description": fmt.Sprintf("Optional label to recognize the job by in list and status responses (e.g., 'production errors'), at most %d characters", background.MaxLabelLength),
+				},
 			},
 			"required": []string{"command"},
 		},
 	}, fastlyTool.makeBackgroundStartHandler())
 
-	s.AddTool(&mcp.Tool{
+	addTool(s, &mcp.Tool{
 		Name:        "fastly_background_stop",
 		Description: "Stop a running background streaming job.",
 		InputSchema: map[string]interface{}{
@@ -244,16 +504,25 @@ func CreateServer() (*mcp.Server, error) {
 		},
 	}, makeBackgroundStopHandler())
 
-	s.AddTool(&mcp.Tool{
+	addTool(s, &mcp.Tool{
+		Name:        "fastly_background_stop_all",
+		Description: "Stop every running background streaming job, reporting the result for each. Use this to clean up at the end of a conversation.",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+		},
+	}, makeBackgroundStopAllHandler())
+
+	addTool(s, &mcp.Tool{
 		Name:        "fastly_background_list",
-		Description: "List all background streaming jobs and their status.",
+		Description: "List all background streaming jobs with their command line, start time, duration, line count and whether the process is still alive.",
 		InputSchema: map[string]interface{}{
 			"type":       "object",
 			"properties": map[string]interface{}{},
 		},
 	}, makeBackgroundListHandler())
 
-	s.AddTool(&mcp.Tool{
+	addTool(s, &mcp.Tool{
 		Name:        "fastly_background_status",
 		Description: "Get detailed status of a background streaming job including output size and line count.",
 		InputSchema: map[string]interface{}{
@@ -268,7 +537,7 @@ func CreateServer() (*mcp.Server, error) {
 		},
 	}, makeBackgroundStatusHandler())
 
-	s.AddTool(&mcp.Tool{
+	addTool(s, &mcp.Tool{
 		Name:        "fastly_background_read",
 		Description: "Read output from a background streaming job with pagination.",
 		InputSchema: map[string]interface{}{
@@ -293,7 +562,7 @@ func CreateServer() (*mcp.Server, error) {
 		},
 	}, makeBackgroundReadHandler())
 
-	s.AddTool(&mcp.Tool{
+	addTool(s, &mcp.Tool{
 		Name:        "fastly_background_query",
 		Description: "Search output from a background streaming job using a pattern or regex.",
 		InputSchema: map[string]interface{}{
@@ -322,6 +591,10 @@ func CreateServer() (*mcp.Server, error) {
 		Description: "Returns the Fastly MCP system prompt that describes available tools and workflow",
 	}, handleSystemPrompt)
 
+	registerWorkflowPrompts(s)
+	registerResultResources(s)
+	withCallerScope(s)
+
 	return s, nil
 }
 
@@ -354,7 +627,8 @@ func InitServer(logCommandsFile string) error {
 //   - useSSE: If true, uses Server-Sent Events; otherwise uses StreamableHTTP
 //   - logCommandsFile: If non-empty, log commands to this file
 //
-// The server will print connection information and wait for incoming connections.
+// The server will print connection information and wait for incoming connections
+// until SIGINT or SIGTERM, then shut down gracefully and return.
 func RunHTTPServer(addr string, useSSE bool, logCommandsFile string) {
 	// Initialize command logger if specified
 	if err := InitializeCommandLogger(logCommandsFile); err != nil {
@@ -371,50 +645,72 @@ func RunHTTPServer(addr string, useSSE bool, logCommandsFile string) {
 	if err != nil {
 		log.Fatalf("Failed to create MCP server: %v", err)
 	}
+	getServer := func(r *http.Request) *mcp.Server {
+		return mcpServer
+	}
+
+	// With named policies each session gets the server of the policy it selects
+	if len(httpPolicies) > 0 {
+		servers, err := createPolicyServers()
+		if err != nil {
+			log.Fatalf("Failed to create MCP server: %v", err)
+		}
+		getServer = func(r *http.Request) *mcp.Server {
+			return policyServer(servers, r)
+		}
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatalf("Failed to start HTTP server: %v", err)
+	}
 
 	var transport string
 	var handler http.Handler
 
 	if useSSE {
 		transport = "SSE"
-		handler = mcp.NewSSEHandler(func(r *http.Request) *mcp.Server {
-			return mcpServer
-		}, nil)
+		handler = withHealth(withCORS(withBearerAuth(withMetrics(mcp.NewSSEHandler(getServer, nil)))))
 
 		fmt.Printf("\nFastly MCP Server running in HTTP mode with %s transport\n", transport)
 		fmt.Printf("Server address: http://%s\n", addr)
 		fmt.Printf("\nConfigure your AI agent with:\n")
 		fmt.Printf("  URL: http://%s\n", addr)
 		fmt.Printf("  Transport: %s\n", transport)
+		printPolicyInfo(addr)
+		printAuthInfo()
+		printCORSInfo()
+		fmt.Printf("  Probes: http://%s%s (liveness), http://%s%s (readiness)\n", addr, HealthzPath, addr, ReadyzPath)
 		fmt.Printf("\nThe server is ready to accept connections.\n")
-
-		if err := http.ListenAndServe(addr, handler); err != nil {
-			log.Fatalf("Failed to start SSE server: %v", err)
-		}
 	} else {
 		transport = "StreamableHTTP"
-		handler = mcp.NewStreamableHTTPHandler(func(r *http.Request) *mcp.Server {
-			return mcpServer
-		}, nil)
+		handler = withHealth(withCORS(withBearerAuth(withMetrics(withBatching(mcp.NewStreamableHTTPHandler(getServer, &mcp.StreamableHTTPOptions{CrossOriginProtection: corsProtection()}))))))
 
 		fmt.Printf("\nFastly MCP Server running in HTTP mode with %s transport\n", transport)
 		fmt.Printf("Server address: http://%s\n", addr)
 		fmt.Printf("\nConfigure your AI agent with:\n")
 		fmt.Printf("  URL: http://%s\n", addr)
 		fmt.Printf("  Transport: %s\n", transport)
+		printPolicyInfo(addr)
+		printAuthInfo()
+		printCORSInfo()
+		fmt.Printf("  Probes: http://%s%s (liveness), http://%s%s (readiness)\n", addr, HealthzPath, addr, ReadyzPath)
 		fmt.Printf("\nThe server is ready to accept connections.\n")
+	}
 
-		if err := http.ListenAndServe(addr, handler); err != nil {
-			log.Fatalf("Failed to start HTTP server: %v", err)
-		}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := serveHTTP(ctx, handler, ln); err != nil {
+		log.Fatalf("%s server failed: %v", transport, err)
 	}
+	fmt.Fprintf(os.Stderr, "Server stopped\n")
 }
 
 // Helper functions to convert between internal and external flag types
 func convertFlags(flags []types.Flag) []Flag {
 	result := make([]Flag, len(flags))
 	for i, f := range flags {
-		result[i] = Flag{Name: f.Name, Value: f.Value}
+		result[i] = Flag{Name: f.Name, Value: f.Value, Lines: f.Lines}
 	}
 	return result
 }
@@ -422,7 +718,7 @@ func convertFlags(flags []types.Flag) []Flag {
 func convertFlagsBack(flags []Flag) []types.Flag {
 	result := make([]types.Flag, len(flags))
 	for i, f := range flags {
-		result[i] = types.Flag{Name: f.Name, Value: f.Value}
+		result[i] = types.Flag{Name: f.Name, Value: f.Value, Lines: f.Lines}
 	}
 	return result
 }
@@ -484,6 +780,45 @@ func NormalizeAddress(addr string) string {
 	return net.JoinHostPort(host, port)
 }
 
+// IsLoopbackAddress reports whether a normalized host:port address only listens
+// on the loopback interface. "localhost" counts as loopback; an empty host or an
+// unspecified address such as 0.0.0.0 or :: listens on every interface.
+func IsLoopbackAddress(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// ValidateBindAddress refuses to bind the HTTP server to a non-loopback address
+// without authentication configured, unless allowRemote is set. The server
+// drives destructive Fastly operations, so without bearer tokens anyone who can
+// reach the port can use the configured Fastly credentials.
+func ValidateBindAddress(addr string, allowRemote, authConfigured bool) error {
+	if IsLoopbackAddress(addr) || authConfigured || allowRemote {
+		return nil
+	}
+
+	return fmt.Errorf("refusing to listen on non-loopback address %s without authentication: anyone who can reach it can run Fastly operations with your credentials. Bind to 127.0.0.1, set --http-auth-token, or pass --allow-remote-bind if the address is protected by other means", addr)
+}
+
+// BindAddressWarning returns the warning to print when the HTTP server listens
+// on a non-loopback address without authentication, which ValidateBindAddress
+// only allows with --allow-remote-bind, or an empty string.
+func BindAddressWarning(addr string, authConfigured bool) string {
+	if IsLoopbackAddress(addr) || authConfigured {
+		return ""
+	}
+	return fmt.Sprintf("Warning: listening on non-loopback address %s without authentication (--allow-remote-bind); anyone who can reach it can run Fastly operations. Set --http-auth-token unless the address is protected by other means", addr)
+}
+
 // makeListCommandsHandler creates the handler for the fastly_list_commands tool.
 // This handler returns a comprehensive list of all available Fastly CLI operations
 // by parsing the CLI's help output. The response includes command descriptions
@@ -494,12 +829,12 @@ func (ft *FastlyTool) makeListCommandsHandler() mcp.ToolHandler {
 		params := getArguments(request)
 
 		result, err := executeWithSetupCheck(ctx, ft, "list_commands", func() (*mcp.CallToolResult, error) {
-			commands := fastly.GetCommandList()
+			commands := fastly.GetCommandListContext(ctx)
 			return newSuccessResult(commands), nil
 		})
 
 		// Log the command
-		LogCommand("fastly_list_commands", params, result, err, time.Since(start))
+		LogCommand(request, "fastly_list_commands", params, result, err, time.Since(start))
 
 		return result, err
 	}
@@ -516,7 +851,7 @@ func (ft *FastlyTool) makeDescribeHandler() mcp.ToolHandler {
 		command, ok := params["command"].(string)
 		if !ok {
 			err := fmt.Errorf("command parameter must be a string")
-			LogCommand("fastly_describe", params, nil, err, time.Since(start))
+			LogCommand(request, "fastly_describe", params, nil, err, time.Since(start))
 			return nil, err
 		}
 
@@ -526,14 +861,29 @@ func (ft *FastlyTool) makeDescribeHandler() mcp.ToolHandler {
 				command = tokenCrypto.DecryptTokensInString(command)
 			}
 
+			service, _ := params["service"].(string)
+			if tokenCrypto != nil && tokenCrypto.Enabled {
+				service = tokenCrypto.DecryptTokensInString(service)
+			}
+			includeHidden, _ := params["include_hidden_flags"].(bool)
+			recursive, _ := params["recursive"].(bool)
+			depth, _ := params["depth"].(float64)
+
 			parts := strings.Fields(command)
-			helpInfo := fastly.DescribeCommand(parts)
+			helpInfo := fastly.DescribeCommandContext(ctx, parts, fastly.DescribeOptions{
+				IncludeHiddenFlags: includeHidden,
+				Recursive:          recursive,
+				Depth:              int(depth),
+			})
+			if service != "" {
+				helpInfo = withDescribeService(helpInfo, resolveServiceID(service))
+			}
 
 			return newSuccessResult(helpInfo), nil
 		})
 
 		// Log the command
-		LogCommand("fastly_describe", params, result, err, time.Since(start))
+		LogCommand(request, "fastly_describe", params, result, err, time.Since(start))
 
 		return result, err
 	}
@@ -555,10 +905,27 @@ func (ft *FastlyTool) makeExecuteHandler() mcp.ToolHandler {
 		command, ok := params["command"].(string)
 		if !ok {
 			err := fmt.Errorf("command parameter must be a string")
-			LogCommand("fastly_execute", params, nil, err, time.Since(start))
+			LogCommand(request, "fastly_execute", params, nil, err, time.Since(start))
 			return nil, err
 		}
 
+		// Refuse oversized requests before any preprocessing iterates over them
+		if err := checkRequestItems(params); err != nil {
+			result := newCommandResult(request, types.CommandResponse{
+				Success:      false,
+				Command:      command,
+				Error:        err.Error(),
+				ErrorCode:    "request_too_large",
+				Instructions: "The request has too many args, raw args and flags to be processed.",
+				NextSteps: []string{
+					"Pass only the args and flags the command needs",
+					"Split the work into several smaller requests",
+				},
+			})
+			LogCommand(request, "fastly_execute", params, result, nil, time.Since(start))
+			return result, nil
+		}
+
 		result, err := executeWithSetupCheck(ctx, ft, "execute", func() (*mcp.CallToolResult, error) {
 			// Decrypt any encrypted tokens in the command string
 			if tokenCrypto != nil && tokenCrypto.Enabled {
@@ -593,6 +960,13 @@ func (ft *FastlyTool) makeExecuteHandler() mcp.ToolHandler {
 							}
 							flag.Value = value
 						}
+						if lines, ok := flagMap["lines"].([]interface{}); ok {
+							for _, line := range lines {
+								if lineStr, ok := line.(string); ok {
+									flag.Lines = append(flag.Lines, lineStr)
+								}
+							}
+						}
 						if flag.Name != "" {
 							flags = append(flags, flag)
 						}
@@ -611,8 +985,52 @@ func (ft *FastlyTool) makeExecuteHandler() mcp.ToolHandler {
 				Args:    processedArgs,
 				Flags:   convertFlagsBack(processedFlags),
 			}
+			if idempotencyKey, ok := params["idempotency_key"].(string); ok {
+				cmdReq.IdempotencyKey = idempotencyKey
+			}
+			if timeoutSeconds, ok := params["timeout_seconds"].(float64); ok {
+				cmdReq.TimeoutSeconds = int(timeoutSeconds)
+			}
+			if keys, ok := params["keys"].([]interface{}); ok {
+				for _, key := range keys {
+					if keyStr, ok := key.(string); ok {
+						cmdReq.Keys = append(cmdReq.Keys, keyStr)
+					}
+				}
+			}
+			if binarySafe, ok := params["binary_safe"].(bool); ok {
+				cmdReq.BinarySafe = binarySafe
+			}
+			if rawArgs, ok := params["raw_args"].([]interface{}); ok {
+				for _, rawArg := range rawArgs {
+					if rawArgStr, ok := rawArg.(string); ok {
+						cmdReq.RawArgs = append(cmdReq.RawArgs, rawArgStr)
+					}
+				}
+			}
 
-			response := fastly.ExecuteCommand(cmdReq)
+			// Resolve relative stats time ranges with the current_time clock
+			baseCommand := ""
+			if parts := strings.Fields(cmdReq.Command); len(parts) > 0 {
+				baseCommand = parts[0]
+			}
+			cmdReq.Flags, err = applyStatsTimeRange(baseCommand, params, cmdReq.Flags, clock())
+			if err != nil {
+				return newCommandResult(request, types.CommandResponse{
+					Success:      false,
+					Command:      processedCmd,
+					Error:        err.Error(),
+					ErrorCode:    "invalid_time_range",
+					Instructions: "The from/to time range could not be translated into stats flags.",
+					NextSteps: []string{
+						"Use 'now', a relative time such as '-7d', '-24h' or '-30m', an RFC 3339 timestamp or a Unix timestamp",
+						"Pass from/to only with stats commands, and not together with --from/--to flags",
+						"Use the current_time tool to check the current time",
+					},
+				}), nil
+			}
+
+			response := fastly.ExecuteCommandContext(ctx, cmdReq)
 
 			// Extract context from the response for future use
 			ExtractContext(processedCmd, processedArgs, processedFlags, getRawCommandOutput(response), response.Success)
@@ -625,20 +1043,85 @@ func (ft *FastlyTool) makeExecuteHandler() mcp.ToolHandler {
 				}
 			}
 
-			// Use appropriate result helper based on success status
-			if response.Success {
-				return newSuccessResult(response), nil
+			// Remind the agent of background jobs it may have forgotten about
+			response.BackgroundJobs = background.GetManager().Summary().StatusLine()
+
+			// Return the response in the envelope version negotiated with the client
+			return newCommandResult(request, response), nil
+		})
+
+		// Log the command
+		LogCommand(request, "fastly_execute", params, result, err, time.Since(start))
+
+		return result, err
+	}
+}
+
+// withDescribeService records the service a described command will run on and,
+// if the command takes --service-id, tells the agent how to pass it.
+func withDescribeService(info types.HelpInfo, serviceID string) types.HelpInfo {
+	info.ServiceID = serviceID
+	for _, flag := range append(append([]types.FlagInfo{}, info.RequiredFlags...), info.Flags...) {
+		if flag.Name == "service-id" {
+			info.NextSteps = append([]string{
+				fmt.Sprintf("Run it on this service with fastly_execute flags: [{\"name\":\"service-id\",\"value\":\"%s\"}]", serviceID),
+			}, info.NextSteps...)
+			break
+		}
+	}
+	return info
+}
+
+// makeVersionDiffHandler creates the handler for the fastly_version_diff tool.
+// It lists the versioned resources of both versions and returns a structured diff.
+func (ft *FastlyTool) makeVersionDiffHandler() mcp.ToolHandler {
+	return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		params := getArguments(request)
+
+		serviceID, _ := params["service_id"].(string)
+		fromVersion := versionParam(params, "from_version")
+		toVersion := versionParam(params, "to_version")
+
+		result, err := executeWithSetupCheck(ctx, ft, "version_diff", func() (*mcp.CallToolResult, error) {
+			// Decrypt the service ID in case it was encrypted in an earlier response
+			if tokenCrypto != nil && tokenCrypto.Enabled {
+				serviceID = tokenCrypto.DecryptTokensInString(serviceID)
+			}
+			serviceID = resolveServiceID(serviceID)
+
+			diff, err := fastly.DiffServiceVersions(ctx, serviceID, fromVersion, toVersion)
+			if err != nil {
+				return newErrorResult(map[string]interface{}{
+					"success": false,
+					"error":   err.Error(),
+				}), nil
 			}
-			return newErrorResult(response), nil
+
+			return newSuccessResult(map[string]interface{}{
+				"success": true,
+				"diff":    diff,
+			}), nil
 		})
 
 		// Log the command
-		LogCommand("fastly_execute", params, result, err, time.Since(start))
+		LogCommand(request, "fastly_version_diff", params, result, err, time.Since(start))
 
 		return result, err
 	}
 }
 
+// versionParam reads a version parameter that may be sent as a string or a number.
+func versionParam(params map[string]interface{}, name string) string {
+	switch v := params[name].(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.Itoa(int(v))
+	}
+	return ""
+}
+
 // makeResultReadHandler creates a handler for reading cached results.
 func makeResultReadHandler() mcp.ToolHandler {
 	return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -658,6 +1141,13 @@ func makeResultReadHandler() mcp.ToolHandler {
 			limit = int(l)
 		}
 
+		format, _ := params["format"].(string)
+		if format != "" && format != "json" && format != "markdown" {
+			return newErrorResult(map[string]interface{}{
+				"error": fmt.Sprintf("unsupported format %q: use 'json' or 'markdown'", format),
+			}), nil
+		}
+
 		store := cache.GetStore()
 		data, err := store.Read(resultID, offset, limit)
 		if err != nil {
@@ -666,12 +1156,119 @@ func makeResultReadHandler() mcp.ToolHandler {
 			}), nil
 		}
 
-		return newSuccessResult(map[string]interface{}{
+		if format == "markdown" {
+			items, ok := data.([]interface{})
+			if !ok {
+				return newErrorResult(map[string]interface{}{
+					"error": "markdown format requires a cached JSON array",
+				}), nil
+			}
+			table, err := cache.RenderMarkdownTable(items)
+			if err != nil {
+				return newErrorResult(map[string]interface{}{
+					"error": err.Error(),
+				}), nil
+			}
+
+			return newSuccessResult(map[string]interface{}{
+				"success":  true,
+				"format":   "markdown",
+				"markdown": table,
+				"offset":   offset,
+				"limit":    limit,
+			}), nil
+		}
+
+		response := map[string]interface{}{
 			"success": true,
 			"data":    data,
 			"offset":  offset,
 			"limit":   limit,
-		}), nil
+		}
+		if items, ok := data.([]interface{}); ok {
+			if warning := fastly.ItemSoftLimitWarning(len(items)); warning != "" {
+				response["warning"] = warning
+			}
+		}
+
+		return newSuccessResult(response), nil
+	}
+}
+
+// makeResultReadMultiHandler creates a handler for reading several cached results at once.
+// Reads are served in order until the combined size reaches cache.MaxMultiReadSize; the
+// remaining results are reported as errors so they can be read separately.
+func makeResultReadMultiHandler() mcp.ToolHandler {
+	return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		params := getArguments(request)
+		reads, ok := params["reads"].([]interface{})
+		if !ok || len(reads) == 0 {
+			return nil, fmt.Errorf("reads is required")
+		}
+		if len(reads) > cache.MaxMultiReadResults {
+			return newErrorResult(map[string]interface{}{
+				"error": fmt.Sprintf("too many results requested: %d (maximum %d)", len(reads), cache.MaxMultiReadResults),
+			}), nil
+		}
+
+		store := cache.GetStore()
+		results := make(map[string]interface{})
+		readErrors := make(map[string]string)
+		totalSize := 0
+
+		for _, item := range reads {
+			read, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("each read must be an object with a result_id")
+			}
+			resultID, ok := read["result_id"].(string)
+			if !ok || resultID == "" {
+				return nil, fmt.Errorf("result_id is required for each read")
+			}
+
+			offset := 0
+			if o, ok := read["offset"].(float64); ok {
+				offset = int(o)
+			}
+
+			limit := 20
+			if l, ok := read["limit"].(float64); ok {
+				limit = int(l)
+			}
+
+			data, err := store.Read(resultID, offset, limit)
+			if err != nil {
+				readErrors[resultID] = err.Error()
+				continue
+			}
+
+			size := len(toJSON(data))
+			if totalSize+size > cache.MaxMultiReadSize {
+				readErrors[resultID] = fmt.Sprintf("combined size limit of %d bytes reached; read this result separately or with a smaller limit", cache.MaxMultiReadSize)
+				continue
+			}
+			totalSize += size
+
+			results[resultID] = map[string]interface{}{
+				"data":   data,
+				"offset": offset,
+				"limit":  limit,
+			}
+		}
+
+		response := map[string]interface{}{
+			"success":    len(results) > 0,
+			"results":    results,
+			"total_size": totalSize,
+		}
+		if len(readErrors) > 0 {
+			response["errors"] = readErrors
+		}
+
+		if len(results) == 0 {
+			return newErrorResult(response), nil
+		}
+		return newSuccessResult(response), nil
 	}
 }
 
@@ -722,6 +1319,14 @@ func makeResultSummaryHandler() mcp.ToolHandler {
 			}), nil
 		}
 
+		// Report the effective limits so agents know why the output was cached
+		maxOutputSize, maxJSONArrayItems := fastly.ResponseLimits()
+		summary["limits"] = map[string]interface{}{
+			"cache_threshold":      cache.OutputCacheThreshold,
+			"max_output_size":      maxOutputSize,
+			"max_json_array_items": maxJSONArrayItems,
+		}
+
 		return newSuccessResult(summary), nil
 	}
 }
@@ -732,10 +1337,44 @@ func makeResultListHandler() mcp.ToolHandler {
 		store := cache.GetStore()
 		results := store.List()
 
+		params := getArguments(request)
+		if service, _ := params["service"].(string); service != "" {
+			if tokenCrypto != nil && tokenCrypto.Enabled {
+				service = tokenCrypto.DecryptTokensInString(service)
+			}
+			serviceID := resolveServiceID(service)
+			filtered := make([]map[string]interface{}, 0, len(results))
+			for _, result := range results {
+				if result["service_id"] == serviceID {
+					filtered = append(filtered, result)
+				}
+			}
+			results = filtered
+		}
+
+		offset, _ := params["offset"].(float64)
+		limit, _ := params["limit"].(float64)
+		page, note := pageResultList(results, int(offset), int(limit))
+
+		response := map[string]interface{}{
+			"success": true,
+			"results": page,
+			"count":   len(page),
+			"total":   len(results),
+		}
+		if note != "" {
+			response["note"] = note
+		}
+		return newSuccessResult(response), nil
+	}
+}
+
+// makeResultStatsHandler creates a handler for reporting result cache usage.
+func makeResultStatsHandler() mcp.ToolHandler {
+	return func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		return newSuccessResult(map[string]interface{}{
 			"success": true,
-			"results": results,
-			"count":   len(results),
+			"stats":   cache.GetStore().Stats(),
 		}), nil
 	}
 }
@@ -748,22 +1387,32 @@ func handleSystemPrompt(ctx context.Context, request *mcp.GetPromptRequest) (*mc
 - **` + "`fastly_list_commands`" + `** - List available commands
 - **` + "`fastly_describe [command]`" + `** - Get command details/parameters
 - **` + "`fastly_execute`" + `** - Run commands with parameters
+- **` + "`fastly_execute_batch`" + `** - Plan a sequence of commands for approval, then run it with the plan token
+- **` + "`fastly_version_diff`" + `** - Compare two versions of a service
 - **` + "`current_time`" + `** - Get timestamps
+- **` + "`fastly_diagnose`" + `** - Find out why the setup or authentication fails
 
 #### Cache Tools (for large outputs):
 - **` + "`fastly_result_read`" + `** - Read paginated data from cached results
+- **` + "`fastly_result_read_multi`" + `** - Read several cached results in one call
 - **` + "`fastly_result_query`" + `** - Query/filter cached results
 - **` + "`fastly_result_summary`" + `** - Get summary of cached data
-- **` + "`fastly_result_list`" + `** - List all cached results
+- **` + "`fastly_result_list`" + `** - List cached results, most recent first
+- **` + "`fastly_result_stats`" + `** - Show the size of the result cache
+- **` + "`fastly_result_export`" + `** - Write a cached result to a file
+- **` + "`fastly_result_delete`" + `** - Delete cached results you are done with
 
 #### Background Streaming Tools (for log-tail, stats realtime):
 - **` + "`fastly_background_start`" + `** - Start a streaming command in the background
 - **` + "`fastly_background_stop`" + `** - Stop a running background job
+- **` + "`fastly_background_stop_all`" + `** - Stop every running background job
 - **` + "`fastly_background_list`" + `** - List all background jobs
 - **` + "`fastly_background_status`" + `** - Get detailed job status
 - **` + "`fastly_background_read`" + `** - Read output with pagination
 - **` + "`fastly_background_query`" + `** - Search output with pattern matching
 
+While jobs are running or have failed, ` + "`fastly_execute`" + ` responses include a ` + "`background_jobs`" + ` reminder. Stop jobs you no longer need.
+
 #### Core Operations:
 - **Services**: Create/update/list CDN services, manage versions
 - **Edge Config**: VCL, ACLs, dictionaries, Compute
@@ -776,6 +1425,7 @@ func handleSystemPrompt(ctx context.Context, request *mcp.GetPromptRequest) (*mc
 2. **Destructive operations require ` + "`--user-reviewed: true`" + `** flag after human approval:
    - ` + "`delete`" + `, ` + "`remove`" + `, ` + "`purge`" + `, ` + "`create`" + `, ` + "`update`" + ` commands
    - Always explain impact and get human confirmation first
+   - In safe mode, any command that may modify resources needs review, and deletions and purges also need the ` + "`confirmation-token`" + ` flag from the confirmation response
 3. **Some commands support JSON output via an extra command parameter**
 4. **Most commands need ` + "`--service-id`" + `**
 5. **Clone versions before changes**
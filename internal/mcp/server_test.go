@@ -1,11 +1,19 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/fastly/mcp/internal/cache"
+	"github.com/fastly/mcp/internal/fastly"
 	"github.com/fastly/mcp/internal/types"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 func TestToJSON(t *testing.T) {
@@ -101,6 +109,42 @@ func TestNormalizeAddress(t *testing.T) {
 	}
 }
 
+func TestValidateBindAddress(t *testing.T) {
+	tests := []struct {
+		name        string
+		addr        string
+		allowRemote bool
+		auth        bool
+		wantErr     bool
+		wantWarning bool
+	}{
+		{"IPv4 loopback", "127.0.0.1:8080", false, false, false, false},
+		{"other IPv4 loopback", "127.0.0.2:8080", false, false, false, false},
+		{"IPv6 loopback", "[::1]:8080", false, false, false, false},
+		{"localhost", "localhost:8080", false, false, false, false},
+		{"all IPv4 interfaces refused", "0.0.0.0:8080", false, false, true, true},
+		{"all IPv6 interfaces refused", "[::]:8080", false, false, true, true},
+		{"specific interface refused", "192.168.1.10:8080", false, false, true, true},
+		{"hostname refused", "example.com:8080", false, false, true, true},
+		{"all interfaces with override", "0.0.0.0:8080", true, false, false, true},
+		{"specific interface with override", "192.168.1.10:8080", true, false, false, true},
+		{"all interfaces with a token", "0.0.0.0:8080", false, true, false, false},
+		{"all interfaces with a token and override", "0.0.0.0:8080", true, true, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBindAddress(tt.addr, tt.allowRemote, tt.auth)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateBindAddress(%q, %v, %v) error = %v, wantErr %v", tt.addr, tt.allowRemote, tt.auth, err, tt.wantErr)
+			}
+			if warning := BindAddressWarning(tt.addr, tt.auth); (warning != "") != tt.wantWarning {
+				t.Errorf("BindAddressWarning(%q, %v) = %q, wantWarning %v", tt.addr, tt.auth, warning, tt.wantWarning)
+			}
+		})
+	}
+}
+
 func TestGetRawCommandOutput(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -140,3 +184,312 @@ func TestGetRawCommandOutput(t *testing.T) {
 		})
 	}
 }
+
+// callResultReadMulti calls fastly_result_read_multi and decodes the JSON response.
+func callResultReadMulti(t *testing.T, reads []map[string]interface{}) (bool, map[string]interface{}) {
+	t.Helper()
+
+	session := connectTestClient(t)
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "fastly_result_read_multi",
+		Arguments: map[string]interface{}{"reads": reads},
+	})
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	return result.IsError, response
+}
+
+func TestResultReadMulti(t *testing.T) {
+	store := cache.GetStore()
+	services := store.Store(`[{"id":"svc1"},{"id":"svc2"},{"id":"svc3"}]`, "service", []string{"list"}, nil)
+	backends := store.Store("backend-1\nbackend-2\nbackend-3\n", "backend", []string{"list"}, nil)
+
+	isError, response := callResultReadMulti(t, []map[string]interface{}{
+		{"result_id": services, "offset": 1, "limit": 2},
+		{"result_id": backends, "limit": 1},
+		{"result_id": "result_missing"},
+	})
+	if isError {
+		t.Fatalf("Expected success, got %v", response)
+	}
+
+	results := response["results"].(map[string]interface{})
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %v", results)
+	}
+
+	serviceData := results[services].(map[string]interface{})["data"].([]interface{})
+	if len(serviceData) != 2 || serviceData[0].(map[string]interface{})["id"] != "svc2" {
+		t.Errorf("Expected services slice [svc2 svc3], got %v", serviceData)
+	}
+	backendData := results[backends].(map[string]interface{})["data"].([]interface{})
+	if len(backendData) != 1 || backendData[0] != "backend-1" {
+		t.Errorf("Expected backends slice [backend-1], got %v", backendData)
+	}
+
+	errors, _ := response["errors"].(map[string]interface{})
+	if _, ok := errors["result_missing"]; !ok {
+		t.Errorf("Expected an error for the missing result, got %v", response["errors"])
+	}
+}
+
+func TestResultReadMultiSizeCap(t *testing.T) {
+	large := fmt.Sprintf(`[{"blob":%q}]`, strings.Repeat("x", cache.MaxMultiReadSize*2/3))
+	store := cache.GetStore()
+	first := store.Store(large, "service", []string{"list"}, nil)
+	second := store.Store(large, "service", []string{"list"}, nil)
+
+	_, response := callResultReadMulti(t, []map[string]interface{}{
+		{"result_id": first},
+		{"result_id": second},
+	})
+
+	results := response["results"].(map[string]interface{})
+	if _, ok := results[first]; !ok || len(results) != 1 {
+		t.Fatalf("Expected only the first result, got %d results", len(results))
+	}
+	errors := response["errors"].(map[string]interface{})
+	if !strings.Contains(errors[second].(string), "combined size limit") {
+		t.Errorf("Expected size limit error for second result, got %v", errors[second])
+	}
+	if size := int(response["total_size"].(float64)); size > cache.MaxMultiReadSize {
+		t.Errorf("Expected total size within cap, got %d", size)
+	}
+}
+
+func TestResultReadMarkdown(t *testing.T) {
+	resultID := cache.GetStore().Store(`[{"ID":"svc1","Name":"production"},{"ID":"svc2","Name":"staging"}]`, "service", []string{"list"}, nil)
+
+	session := connectTestClient(t)
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "fastly_result_read",
+		Arguments: map[string]interface{}{"result_id": resultID, "format": "markdown"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("Expected success, got %v", result.Content[0].(*mcp.TextContent).Text)
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	expected := "| ID | Name |\n| --- | --- |\n| svc1 | production |\n| svc2 | staging |\n"
+	if response["markdown"] != expected {
+		t.Errorf("Expected markdown table %q, got %q", expected, response["markdown"])
+	}
+}
+
+func TestResultReadItemSoftLimit(t *testing.T) {
+	resultID := cache.GetStore().Store(`[{"id":1},{"id":2},{"id":3}]`, "service", []string{"list"}, nil)
+
+	fastly.SetItemSoftLimit(2)
+	defer fastly.SetItemSoftLimit(0)
+
+	session := connectTestClient(t)
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "fastly_result_read",
+		Arguments: map[string]interface{}{"result_id": resultID, "limit": 3},
+	})
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if warning, _ := response["warning"].(string); !strings.Contains(warning, "3 items returned") {
+		t.Errorf("Expected a soft limit warning, got %v", response["warning"])
+	}
+}
+
+func TestServiceNameResolutionAcrossTools(t *testing.T) {
+	originalServiceNameToID := globalContext.ServiceNameToID
+	originalLastServiceID := globalContext.LastServiceID
+	defer func() {
+		globalContext.ServiceNameToID = originalServiceNameToID
+		globalContext.LastServiceID = originalLastServiceID
+	}()
+	globalContext.ServiceNameToID = make(map[string]string)
+	ExtractContext("service", []string{"list"}, nil, `[{"ID":"SU1Z0isxPaozGVKXdv0eY","Name":"production"}]`, true)
+
+	dir := t.TempDir()
+	callsFile := filepath.Join(dir, "calls")
+	script := `#!/bin/sh
+case "$*" in
+  *--help*)
+    printf 'USAGE\n  fastly service describe [<flags>]\n\nShow detailed information about a Fastly service\n\nOPTIONAL FLAGS\n  -s, --service-id=SERVICE-ID  Service ID\n'
+    ;;
+  *)
+    echo "$@" >> "` + callsFile + `"
+    echo '{}'
+    ;;
+esac
+`
+	mockPath := filepath.Join(dir, "fastly")
+	if err := os.WriteFile(mockPath, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// Execute runs FASTLY_CLI_PATH, while describe looks the CLI up in PATH
+	t.Setenv("FASTLY_CLI_PATH", mockPath)
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	session := connectTestClient(t)
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "fastly_describe",
+		Arguments: map[string]interface{}{"command": "service describe", "service": "production"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	var described types.HelpInfo
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &described); err != nil {
+		t.Fatalf("Failed to decode describe response: %v", err)
+	}
+	if described.ServiceID != "SU1Z0isxPaozGVKXdv0eY" {
+		t.Errorf("Expected describe to resolve the service name, got %q", described.ServiceID)
+	}
+	if len(described.NextSteps) == 0 || !strings.Contains(described.NextSteps[0], "SU1Z0isxPaozGVKXdv0eY") {
+		t.Errorf("Expected a next step passing the resolved service-id, got %v", described.NextSteps)
+	}
+
+	_, err = session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name: "fastly_execute",
+		Arguments: map[string]interface{}{
+			"command": "service",
+			"args":    []string{"describe"},
+			"flags":   []map[string]interface{}{{"name": "service-id", "value": "production"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	calls, err := os.ReadFile(callsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(calls), "service describe --service-id "+described.ServiceID) {
+		t.Errorf("Expected execute to resolve the name to the same ID as describe, got %q", calls)
+	}
+}
+
+func TestResultListServiceFilter(t *testing.T) {
+	originalServiceNameToID := globalContext.ServiceNameToID
+	defer func() { globalContext.ServiceNameToID = originalServiceNameToID }()
+	globalContext.ServiceNameToID = map[string]string{"staging": "SVCSTAGING"}
+
+	store := cache.GetStore()
+	wanted := store.Store(`[{"name":"origin"}]`, "backend", []string{"list"}, []types.Flag{{Name: "service-id", Value: "SVCSTAGING"}})
+	store.Store(`[{"name":"origin"}]`, "backend", []string{"list"}, []types.Flag{{Name: "service-id", Value: "SVCOTHER"}})
+
+	session := connectTestClient(t)
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "fastly_result_list",
+		Arguments: map[string]interface{}{"service": "staging"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+
+	var response struct {
+		Results []map[string]interface{} `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Results) != 1 || response.Results[0]["id"] != wanted {
+		t.Errorf("Expected only the result for the named service, got %v", response.Results)
+	}
+}
+
+func TestResultListCappedMostRecentFirst(t *testing.T) {
+	SetResultListLimit(3)
+	defer SetResultListLimit(DefaultResultListLimit)
+
+	store := cache.GetStore()
+	var ids []string
+	for i := 0; i < 5; i++ {
+		ids = append(ids, store.Store(fmt.Sprintf(`[{"name":"origin-%d"}]`, i), "backend", []string{"list"}, nil))
+		time.Sleep(time.Millisecond)
+	}
+
+	session := connectTestClient(t)
+	list := func(arguments map[string]interface{}) (response struct {
+		Results []map[string]interface{} `json:"results"`
+		Count   int                      `json:"count"`
+		Total   int                      `json:"total"`
+		Note    string                   `json:"note"`
+	}) {
+		t.Helper()
+		result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "fastly_result_list", Arguments: arguments})
+		if err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return response
+	}
+
+	// A larger requested limit is capped
+	first := list(map[string]interface{}{"limit": 10})
+	if first.Count != 3 || len(first.Results) != 3 || first.Total < 5 {
+		t.Fatalf("Expected 3 of at least 5 results, got count %d and total %d", first.Count, first.Total)
+	}
+	for i, want := range []string{ids[4], ids[3], ids[2]} {
+		if first.Results[i]["id"] != want {
+			t.Errorf("Expected result %d to be %s, got %v", i, want, first.Results[i]["id"])
+		}
+	}
+	if !strings.Contains(first.Note, fmt.Sprintf("of %d cached results", first.Total)) || !strings.Contains(first.Note, "offset=3") {
+		t.Errorf("Expected a note with the total and the next offset, got %q", first.Note)
+	}
+
+	second := list(map[string]interface{}{"offset": 3, "limit": 2})
+	if len(second.Results) != 2 || second.Results[0]["id"] != ids[1] || second.Results[1]["id"] != ids[0] {
+		t.Errorf("Expected the next page to continue with older results, got %v", second.Results)
+	}
+}
+
+func TestResultSummaryReportsLimits(t *testing.T) {
+	fastly.SetMaxJSONArrayItems(50)
+	defer fastly.SetMaxJSONArrayItems(0)
+
+	resultID := cache.GetStore().Store(`[{"name":"origin-1"}]`, "backend", []string{"list"}, nil)
+
+	session := connectTestClient(t)
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "fastly_result_summary",
+		Arguments: map[string]interface{}{"result_id": resultID},
+	})
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+
+	var summary struct {
+		Limits map[string]int `json:"limits"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &summary); err != nil {
+		t.Fatalf("Failed to decode summary: %v", err)
+	}
+	expected := map[string]int{
+		"cache_threshold":      cache.OutputCacheThreshold,
+		"max_output_size":      fastly.MaxOutputSize,
+		"max_json_array_items": 50,
+	}
+	for name, want := range expected {
+		if summary.Limits[name] != want {
+			t.Errorf("Expected limit %s = %d, got %v", name, want, summary.Limits)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/fastly/mcp/internal/background"
+	"github.com/fastly/mcp/internal/cache"
+)
+
+// DefaultShutdownGracePeriod is how long in-flight requests may finish after
+// a shutdown signal before their connections are closed. It stays below the
+// 30 second termination grace period of Kubernetes pods, leaving time to stop
+// background jobs.
+const DefaultShutdownGracePeriod = 20 * time.Second
+
+// shutdownGracePeriod is the grace period of HTTP mode. It can be configured
+// via SetShutdownGracePeriod().
+var shutdownGracePeriod = DefaultShutdownGracePeriod
+
+// SetShutdownGracePeriod configures how long the HTTP server waits for
+// in-flight requests on SIGINT or SIGTERM. Non-positive values restore the
+// default.
+func SetShutdownGracePeriod(d time.Duration) {
+	if d <= 0 {
+		d = DefaultShutdownGracePeriod
+	}
+	shutdownGracePeriod = d
+}
+
+// serveHTTP serves handler on ln until ctx is done, typically by a shutdown
+// signal, and then shuts down gracefully: it stops accepting connections,
+// waits up to the grace period for in-flight requests, closes connections
+// still open after it, such as SSE streams, and finally stops background jobs
+// and the result cache cleanup. It returns nil after a graceful shutdown and
+// the error of the server otherwise.
+func serveHTTP(ctx context.Context, handler http.Handler, ln net.Listener) error {
+	srv := &http.Server{Handler: handler}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	fmt.Fprintf(os.Stderr, "Shutting down, waiting up to %s for in-flight requests\n", shutdownGracePeriod)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownGracePeriod)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		fmt.Fprintf(os.Stderr, "Grace period expired, closing remaining connections\n")
+		_ = srv.Close()
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	manager := background.GetManager()
+	if running := manager.RunningCount(); running > 0 {
+		fmt.Fprintf(os.Stderr, "Stopping %d background jobs\n", running)
+	}
+	manager.Shutdown()
+	cache.GetStore().Shutdown()
+	return nil
+}
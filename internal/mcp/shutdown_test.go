@@ -0,0 +1,144 @@
+package mcp
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// sendSIGTERM delivers SIGTERM to the test process, which signal.NotifyContext
+// must already catch.
+func sendSIGTERM(t *testing.T) {
+	t.Helper()
+	process, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := process.Signal(syscall.SIGTERM); err != nil {
+		t.Fatalf("Failed to send SIGTERM: %v", err)
+	}
+}
+
+// waitForGoroutines waits until at most n goroutines are running.
+func waitForGoroutines(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > n {
+		if time.Now().After(deadline) {
+			buf := make([]byte, 1<<16)
+			t.Fatalf("Expected at most %d goroutines after shutdown, got %d:\n%s", n, runtime.NumGoroutine(), buf[:runtime.Stack(buf, true)])
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestServeHTTPGracefulShutdown(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SIGTERM cannot be sent to a process on Windows")
+	}
+	// os/signal keeps a goroutine of its own once signals are caught
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM)
+	defer stop()
+	before := runtime.NumGoroutine()
+
+	started, release := make(chan struct{}), make(chan struct{})
+	handler := withHealth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		_, _ = w.Write([]byte("done"))
+	}))
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	served := make(chan error, 1)
+	go func() {
+		served <- serveHTTP(ctx, handler, ln)
+	}()
+
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	responses := make(chan *http.Response, 1)
+	go func() {
+		resp, err := client.Get("http://" + ln.Addr().String() + "/slow")
+		if err != nil {
+			t.Errorf("In-flight request failed: %v", err)
+			close(responses)
+			return
+		}
+		_ = resp.Body.Close()
+		responses <- resp
+	}()
+	<-started
+
+	sendSIGTERM(t)
+	select {
+	case err := <-served:
+		t.Fatalf("Expected the server to wait for the in-flight request, it returned %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+	if conn, err := net.DialTimeout("tcp", ln.Addr().String(), time.Second); err == nil {
+		_ = conn.Close()
+		t.Error("Expected new connections to be refused while shutting down")
+	}
+
+	close(release)
+	if resp := <-responses; resp != nil && resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected the in-flight request to complete with 200, got %d", resp.StatusCode)
+	}
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Errorf("Expected a clean shutdown, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Server did not stop after the in-flight request completed")
+	}
+
+	client.CloseIdleConnections()
+	waitForGoroutines(t, before)
+}
+
+func TestServeHTTPGracePeriodExpires(t *testing.T) {
+	SetShutdownGracePeriod(100 * time.Millisecond)
+	defer SetShutdownGracePeriod(0)
+
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- serveHTTP(ctx, handler, ln)
+	}()
+
+	go func() {
+		if resp, err := http.Get("http://" + ln.Addr().String() + "/stream"); err == nil {
+			_ = resp.Body.Close()
+		}
+	}()
+	<-started
+
+	cancel()
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Errorf("Expected a clean shutdown after the grace period, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Server did not close a request running past the grace period")
+	}
+}
@@ -8,6 +8,10 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
+// clock returns the current time reported by current_time and used to resolve
+// relative time expressions. Tests replace it to get a fixed time.
+var clock = time.Now
+
 // TimeInfo represents the current time in multiple formats for AI consumption.
 // It provides various time representations to support different use cases:
 type TimeInfo struct {
@@ -38,7 +42,7 @@ func getCurrentTime(ctx context.Context, request *mcp.CallToolRequest) (*mcp.Cal
 	start := time.Now()
 	params := getArguments(request)
 
-	now := time.Now()
+	now := clock()
 
 	zone, offset := now.Zone()
 	offsetHours := offset / 3600
@@ -64,7 +68,7 @@ func getCurrentTime(ctx context.Context, request *mcp.CallToolRequest) (*mcp.Cal
 	result := newSuccessResult(timeInfo)
 
 	// Log the command
-	LogCommand("current_time", params, result, nil, time.Since(start))
+	LogCommand(request, "current_time", params, result, nil, time.Since(start))
 
 	return result, nil
 }
@@ -0,0 +1,92 @@
+package mcp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fastly/mcp/internal/types"
+)
+
+// timeUnits maps the unit suffix of a relative time expression to its duration.
+var timeUnits = map[byte]time.Duration{
+	's': time.Second,
+	'm': time.Minute,
+	'h': time.Hour,
+	'd': 24 * time.Hour,
+	'w': 7 * 24 * time.Hour,
+}
+
+// parseTimeExpression resolves "now", a relative expression in the past such
+// as "-7d" or "-90m", an RFC 3339 timestamp or a Unix timestamp into a time.
+func parseTimeExpression(expr string, now time.Time) (time.Time, error) {
+	expr = strings.TrimSpace(expr)
+	switch {
+	case expr == "":
+		return time.Time{}, fmt.Errorf("time expression is empty")
+	case strings.EqualFold(expr, "now"):
+		return now, nil
+	case strings.HasPrefix(expr, "-") && len(expr) > 2:
+		unit, ok := timeUnits[expr[len(expr)-1]]
+		amount, err := strconv.Atoi(expr[1 : len(expr)-1])
+		if !ok || err != nil || amount < 0 {
+			return time.Time{}, fmt.Errorf("invalid relative time %q: expected e.g. '-30m', '-24h' or '-7d'", expr)
+		}
+		return now.Add(-time.Duration(amount) * unit), nil
+	}
+
+	if unix, err := strconv.ParseInt(expr, 10, 64); err == nil {
+		return time.Unix(unix, 0), nil
+	}
+	if t, err := time.Parse(time.RFC3339, expr); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: expected 'now', a relative time such as '-7d', an RFC 3339 timestamp or a Unix timestamp", expr)
+}
+
+// applyStatsTimeRange translates the from and to parameters of a stats command
+// into --from and --to flags holding Unix timestamps, which the executor
+// reformats where a subcommand expects otherwise. Relative expressions are
+// resolved against now.
+func applyStatsTimeRange(command string, params map[string]interface{}, flags []types.Flag, now time.Time) ([]types.Flag, error) {
+	from, _ := params["from"].(string)
+	to, _ := params["to"].(string)
+	if from == "" && to == "" {
+		return flags, nil
+	}
+	if command != "stats" {
+		return nil, fmt.Errorf("from and to are only supported for stats commands")
+	}
+
+	var fromTime, toTime time.Time
+	for _, param := range []struct {
+		name  string
+		value string
+		dest  *time.Time
+	}{
+		{"from", from, &fromTime},
+		{"to", to, &toTime},
+	} {
+		if param.value == "" {
+			continue
+		}
+		for _, flag := range flags {
+			if flag.Name == param.name {
+				return nil, fmt.Errorf("%s is given both as a parameter and as a flag", param.name)
+			}
+		}
+
+		t, err := parseTimeExpression(param.value, now)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", param.name, err)
+		}
+		*param.dest = t
+		flags = append(flags, types.Flag{Name: param.name, Value: strconv.FormatInt(t.Unix(), 10)})
+	}
+
+	if !fromTime.IsZero() && !toTime.IsZero() && fromTime.After(toTime) {
+		return nil, fmt.Errorf("from (%s) is after to (%s)", fromTime.UTC().Format(time.RFC3339), toTime.UTC().Format(time.RFC3339))
+	}
+	return flags, nil
+}
@@ -0,0 +1,107 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fastly/mcp/internal/types"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestParseTimeExpression(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"now", now},
+		{"-7d", now.Add(-7 * 24 * time.Hour)},
+		{"-24h", now.Add(-24 * time.Hour)},
+		{"-30m", now.Add(-30 * time.Minute)},
+		{"-2w", now.Add(-14 * 24 * time.Hour)},
+		{"2026-10-01T00:00:00Z", time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)},
+		{"1760000000", time.Unix(1760000000, 0)},
+	}
+	for _, tt := range tests {
+		got, err := parseTimeExpression(tt.expr, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("parseTimeExpression(%q) = %v, %v; want %v", tt.expr, got, err, tt.want)
+		}
+	}
+
+	for _, expr := range []string{"", "-7x", "-d", "yesterday"} {
+		if _, err := parseTimeExpression(expr, now); err == nil {
+			t.Errorf("Expected an error for %q", expr)
+		}
+	}
+}
+
+func TestApplyStatsTimeRange(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	params := map[string]interface{}{"from": "-7d", "to": "now"}
+
+	flags, err := applyStatsTimeRange("stats", params, []types.Flag{{Name: "service-id", Value: "abc"}}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantFrom := strconv.FormatInt(now.Add(-7*24*time.Hour).Unix(), 10)
+	wantTo := strconv.FormatInt(now.Unix(), 10)
+	if len(flags) != 3 || flags[1].Name != "from" || flags[1].Value != wantFrom || flags[2].Name != "to" || flags[2].Value != wantTo {
+		t.Errorf("Expected --from %s --to %s, got %+v", wantFrom, wantTo, flags)
+	}
+
+	if _, err := applyStatsTimeRange("service", params, nil, now); err == nil {
+		t.Error("Expected an error for a non-stats command")
+	}
+	if _, err := applyStatsTimeRange("stats", params, []types.Flag{{Name: "from", Value: "1"}}, now); err == nil {
+		t.Error("Expected an error when from is also given as a flag")
+	}
+	if _, err := applyStatsTimeRange("stats", map[string]interface{}{"from": "now", "to": "-1h"}, nil, now); err == nil {
+		t.Error("Expected an error when from is after to")
+	}
+}
+
+func TestExecuteStatsTimeRange(t *testing.T) {
+	dir := t.TempDir()
+	callsFile := filepath.Join(dir, "calls")
+	script := "#!/bin/sh\necho \"$@\" >> \"" + callsFile + "\"\necho '{}'\n"
+	mockPath := filepath.Join(dir, "fastly")
+	if err := os.WriteFile(mockPath, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("FASTLY_CLI_PATH", mockPath)
+
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	clock = func() time.Time { return now }
+	defer func() { clock = time.Now }()
+
+	session := connectTestClient(t)
+	_, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name: "fastly_execute",
+		Arguments: map[string]interface{}{
+			"command": "stats",
+			"args":    []string{"historical"},
+			"flags":   []map[string]interface{}{{"name": "service-id", "value": "SU1Z0isxPaozGVKXdv0eY"}},
+			"from":    "-7d",
+			"to":      "now",
+		},
+	})
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+
+	calls, err := os.ReadFile(callsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "--from " + strconv.FormatInt(now.Add(-7*24*time.Hour).Unix(), 10) + " --to " + strconv.FormatInt(now.Unix(), 10)
+	if !strings.Contains(string(calls), "stats historical") || !strings.Contains(string(calls), want) {
+		t.Errorf("Expected the stats call to contain %q, got %q", want, calls)
+	}
+}
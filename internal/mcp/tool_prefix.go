@@ -0,0 +1,45 @@
+package mcp
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxToolPrefixLength keeps prefixed tool names well within the 128 characters
+// MCP clients accept.
+const maxToolPrefixLength = 64
+
+// toolPrefixPattern matches the characters allowed in tool names.
+var toolPrefixPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// toolPrefix namespaces the names of all registered tools, so several servers
+// can be registered with one client. It can be configured via SetToolPrefix().
+var toolPrefix = ""
+
+// SetToolPrefix makes CreateServer register every tool as prefix_name, e.g.
+// acct1_fastly_execute for the prefix "acct1". An empty prefix registers the
+// plain names.
+func SetToolPrefix(prefix string) error {
+	prefix = strings.TrimRight(prefix, "_")
+	if prefix == "" {
+		toolPrefix = ""
+		return nil
+	}
+	if len(prefix) > maxToolPrefixLength {
+		return fmt.Errorf("prefix must be at most %d characters", maxToolPrefixLength)
+	}
+	if !toolPrefixPattern.MatchString(prefix) {
+		return fmt.Errorf("prefix %q may only contain letters, digits, '_' and '-'", prefix)
+	}
+	toolPrefix = prefix + "_"
+	return nil
+}
+
+// addTool registers a tool under its name with the configured prefix.
+func addTool(s *mcp.Server, tool *mcp.Tool, handler mcp.ToolHandler) {
+	tool.Name = toolPrefix + tool.Name
+	s.AddTool(tool, handler)
+}
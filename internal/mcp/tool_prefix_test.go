@@ -0,0 +1,61 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestToolPrefix(t *testing.T) {
+	if err := SetToolPrefix("acct1"); err != nil {
+		t.Fatalf("SetToolPrefix failed: %v", err)
+	}
+	defer func() { _ = SetToolPrefix("") }()
+
+	session := connectTestClient(t)
+	tools, err := session.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListTools failed: %v", err)
+	}
+	if len(tools.Tools) == 0 {
+		t.Fatal("Expected registered tools")
+	}
+
+	names := make(map[string]bool, len(tools.Tools))
+	for _, tool := range tools.Tools {
+		if !strings.HasPrefix(tool.Name, "acct1_") {
+			t.Errorf("Expected tool %s to carry the prefix", tool.Name)
+		}
+		names[tool.Name] = true
+	}
+	for _, name := range []string{"acct1_fastly_execute", "acct1_fastly_describe", "acct1_current_time"} {
+		if !names[name] {
+			t.Errorf("Expected %s to be registered", name)
+		}
+	}
+
+	// Prefixed tools are called by their prefixed names
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "acct1_current_time"})
+	if err != nil || result.IsError {
+		t.Errorf("Expected the prefixed tool to be callable, got %v %v", result, err)
+	}
+}
+
+func TestSetToolPrefix(t *testing.T) {
+	defer func() { _ = SetToolPrefix("") }()
+
+	if err := SetToolPrefix("acct1_"); err != nil || toolPrefix != "acct1_" {
+		t.Errorf("Expected a trailing underscore not to be doubled, got %q (%v)", toolPrefix, err)
+	}
+	if err := SetToolPrefix("acct 1"); err == nil {
+		t.Error("Expected a prefix with a space to be rejected")
+	}
+	if err := SetToolPrefix(strings.Repeat("a", maxToolPrefixLength+1)); err == nil {
+		t.Error("Expected an overlong prefix to be rejected")
+	}
+	if err := SetToolPrefix(""); err != nil || toolPrefix != "" {
+		t.Errorf("Expected an empty prefix to register plain names, got %q", toolPrefix)
+	}
+}
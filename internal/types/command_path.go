@@ -0,0 +1,19 @@
+package types
+
+import "strings"
+
+// LongestCommandPathMatch returns the value of the longest command path in
+// paths, e.g. "service describe", that prefixes the command followed by its
+// arguments, so that "service describe" overrides "service" for "service
+// describe --service-id x". The command may hold several words. It reports
+// false when no path matches.
+func LongestCommandPathMatch[V any](paths map[string]V, command string, args []string) (V, bool) {
+	parts := append(strings.Fields(command), args...)
+	for n := len(parts); n > 0; n-- {
+		if value, ok := paths[strings.Join(parts[:n], " ")]; ok {
+			return value, true
+		}
+	}
+	var zero V
+	return zero, false
+}
@@ -0,0 +1,31 @@
+package types
+
+import "testing"
+
+func TestLongestCommandPathMatch(t *testing.T) {
+	paths := map[string]string{
+		"service":          "service",
+		"service describe": "service describe",
+		"version":          "version",
+	}
+
+	tests := []struct {
+		command string
+		args    []string
+		want    string
+		ok      bool
+	}{
+		{"service", []string{"describe", "extra"}, "service describe", true},
+		{"service", []string{"list"}, "service", true},
+		{"service describe", nil, "service describe", true},
+		{"backend", []string{"list"}, "", false},
+		{"", nil, "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := LongestCommandPathMatch(paths, tt.command, tt.args)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("LongestCommandPathMatch(%q, %v) = %q, %v; want %q, %v", tt.command, tt.args, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package types
+
+const (
+	// SchemaVersion1 is the original CommandResponse envelope: the command
+	// output, errors with instructions and next steps, pagination, cached
+	// results, and the resource, operation, safety and authentication metadata.
+	SchemaVersion1 = 1
+
+	// CurrentSchemaVersion is the envelope produced by this server, adding
+	// fields such as created_resource, explanation, confirmation_token and
+	// the extended operation metadata to SchemaVersion1.
+	CurrentSchemaVersion = 2
+)
+
+// ForSchemaVersion returns the response in the envelope of the given schema
+// version, omitting the fields that version does not define. Versions newer
+// than CurrentSchemaVersion get the current envelope.
+func (r CommandResponse) ForSchemaVersion(version int) CommandResponse {
+	if version >= CurrentSchemaVersion {
+		r.SchemaVersion = CurrentSchemaVersion
+		return r
+	}
+
+	v1 := CommandResponse{
+		SchemaVersion: SchemaVersion1,
+		Success:       r.Success,
+		Output:        r.Output,
+		OutputJSON:    r.OutputJSON,
+		Error:         r.Error,
+		ErrorCode:     r.ErrorCode,
+		Command:       r.Command,
+		CommandLine:   r.CommandLine,
+		Instructions:  r.Instructions,
+		NextSteps:     r.NextSteps,
+		Pagination:    r.Pagination,
+		ResultID:      r.ResultID,
+		Cached:        r.Cached,
+		CacheMetadata: r.CacheMetadata,
+		Preview:       r.Preview,
+	}
+	if r.Metadata != nil {
+		v1.Metadata = &OperationMetadata{
+			ResourceType:  r.Metadata.ResourceType,
+			OperationType: r.Metadata.OperationType,
+			IsSafe:        r.Metadata.IsSafe,
+			RequiresAuth:  r.Metadata.RequiresAuth,
+		}
+	}
+	return v1
+}
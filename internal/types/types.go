@@ -10,6 +10,16 @@ type CommandRequest struct {
 	Args []string `json:"args"`
 	// Flags are the command-line flags and their values
 	Flags []Flag `json:"flags,omitempty"`
+	// IdempotencyKey marks retries of the same create operation so they are not executed twice
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+	// TimeoutSeconds overrides the default command timeout for this call, up to a configured maximum
+	TimeoutSeconds int `json:"timeout_seconds,omitempty"`
+	// Keys is a batch of surrogate keys to purge, one purge per key (purge command only)
+	Keys []string `json:"keys,omitempty"`
+	// RawArgs are extra tokens passed after a "--" separator, when the server allows them
+	RawArgs []string `json:"raw_args,omitempty"`
+	// BinarySafe base64-encodes output that is not valid UTF-8 instead of replacing the invalid bytes
+	BinarySafe bool `json:"binary_safe,omitempty"`
 }
 
 // Flag represents a command-line flag with an optional value.
@@ -18,16 +28,24 @@ type Flag struct {
 	Name string `json:"name"`
 	// Value is the flag's value; empty for boolean flags
 	Value string `json:"value,omitempty"`
+	// Lines holds a multi-line value for flags that accept file content (e.g., "content")
+	Lines []string `json:"lines,omitempty"`
 }
 
 // CommandResponse represents the result of executing a Fastly CLI command.
 type CommandResponse struct {
+	// SchemaVersion identifies the envelope schema the response follows
+	SchemaVersion int `json:"schema_version,omitempty"`
 	// Success indicates whether the command executed successfully
 	Success bool `json:"success"`
 	// Output contains the command's text output with ANSI escape sequences removed
 	Output string `json:"output,omitempty"`
 	// OutputJSON contains parsed JSON
 	OutputJSON interface{} `json:"output_json,omitempty"`
+	// OutputEncoding is "base64" when Output holds base64-encoded bytes, in binary-safe mode
+	OutputEncoding string `json:"output_encoding,omitempty"`
+	// OutputWarnings holds warning lines stripped from before JSON output so it could be parsed
+	OutputWarnings []string `json:"output_warnings,omitempty"`
 	// Error contains the error message if the command failed
 	Error string `json:"error,omitempty"`
 	// ErrorCode provides a machine-readable error identifier
@@ -52,6 +70,102 @@ type CommandResponse struct {
 	CacheMetadata *CacheMetadata `json:"cache_metadata,omitempty"`
 	// Preview contains a small sample of cached data
 	Preview interface{} `json:"preview,omitempty"`
+	// Truncation describes truncated output uniformly for arrays, objects, text and cached results
+	Truncation *TruncationInfo `json:"truncation,omitempty"`
+	// BackgroundJobs reminds the agent of running or failed background jobs
+	BackgroundJobs string `json:"background_jobs,omitempty"`
+	// CreatedResource identifies the resource a successful create operation made
+	CreatedResource *CreatedResource `json:"created_resource,omitempty"`
+	// Explanation annotates the command line with the meaning of each flag, in explain mode
+	Explanation *CommandExplanation `json:"explanation,omitempty"`
+	// ConfirmationToken must accompany the retry of a destructive command in safe mode
+	ConfirmationToken string `json:"confirmation_token,omitempty"`
+	// PurgeImpact describes what a purge of all content affects, when the purge-all preflight is enabled
+	PurgeImpact *PurgeImpact `json:"purge_impact,omitempty"`
+	// ListSummary replaces the preview of a large list result when list summaries are enabled
+	ListSummary *ListSummary `json:"list_summary,omitempty"`
+	// OutputFile describes the file a command wrote its output to, when it was given an output file flag
+	OutputFile *OutputFile `json:"output_file,omitempty"`
+}
+
+// ListSummary is a compact overview of a large list result whose items are
+// cached rather than returned.
+type ListSummary struct {
+	// TotalItems is the number of items in the list
+	TotalItems int `json:"total_items"`
+	// Fields lists the field names found in the items, sorted
+	Fields []string `json:"fields"`
+	// FirstItems holds the first items of the list
+	FirstItems []interface{} `json:"first_items"`
+}
+
+// OutputFile describes a file written by a command instead of stdout.
+type OutputFile struct {
+	// Flag is the name of the flag that named the file
+	Flag string `json:"flag"`
+	// Path is the file path as passed to the command
+	Path string `json:"path"`
+	// SizeBytes is the size of the file after the command ran
+	SizeBytes int64 `json:"size_bytes"`
+}
+
+// PurgeImpact describes the blast radius of purging all cached content of a
+// service, for review before the purge runs.
+type PurgeImpact struct {
+	// ServiceID identifies the service whose cache would be purged
+	ServiceID string `json:"service_id,omitempty"`
+	// Domains lists the domains of the active service version
+	Domains []string `json:"domains,omitempty"`
+	// Traffic summarizes the recent traffic that the cache currently serves
+	Traffic *PurgeTraffic `json:"traffic,omitempty"`
+	// Notes explains lookups that could not be completed
+	Notes []string `json:"notes,omitempty"`
+}
+
+// PurgeTraffic summarizes the traffic of a service over a recent period.
+type PurgeTraffic struct {
+	// Period is the length of the summarized period (e.g., "24h0m0s")
+	Period string `json:"period"`
+	// Requests is the number of requests served in the period
+	Requests float64 `json:"requests"`
+	// BandwidthBytes is the number of bytes delivered in the period
+	BandwidthBytes float64 `json:"bandwidth_bytes"`
+}
+
+// CommandExplanation annotates a command line with what the command and each
+// of its flags do, taken from the command's help, for human review.
+type CommandExplanation struct {
+	// CommandLine is the command line being explained
+	CommandLine string `json:"command_line"`
+	// Description is what the command does
+	Description string `json:"description,omitempty"`
+	// Flags explains each flag of the command line, in order
+	Flags []FlagExplanation `json:"flags,omitempty"`
+}
+
+// FlagExplanation is the meaning of one flag on a command line.
+type FlagExplanation struct {
+	// Name is the flag name without leading dashes
+	Name string `json:"name"`
+	// Value is the value passed with the flag, if any
+	Value string `json:"value,omitempty"`
+	// Description is the flag's description from the command's help
+	Description string `json:"description"`
+}
+
+// CreatedResource identifies a resource made by a create operation, extracted
+// from the command output so agents can chain operations on it.
+type CreatedResource struct {
+	// Type is the kind of resource, e.g. "backend" or "logging s3"
+	Type string `json:"type"`
+	// ID is the resource ID, when the output reports one
+	ID string `json:"id,omitempty"`
+	// Name is the resource name, when the output reports one
+	Name string `json:"name,omitempty"`
+	// ServiceID is the service the resource belongs to
+	ServiceID string `json:"service_id,omitempty"`
+	// Version is the service version the resource was created in
+	Version int `json:"version,omitempty"`
 }
 
 // OperationMetadata describes the type and safety characteristics of an operation.
@@ -62,8 +176,24 @@ type OperationMetadata struct {
 	OperationType string `json:"operation_type"`
 	// IsSafe indicates whether the operation is non-destructive
 	IsSafe bool `json:"is_safe"`
+	// Severity classifies the risk of the operation ("info", "mutating", "destructive")
+	Severity string `json:"severity,omitempty"`
 	// RequiresAuth indicates whether the operation requires authentication
 	RequiresAuth bool `json:"requires_auth"`
+	// StrippedFlags lists operator-configured MCP-only flags removed before execution
+	StrippedFlags []string `json:"stripped_flags,omitempty"`
+	// InjectedFlags lists operator-configured flags appended to a create operation
+	InjectedFlags []string `json:"injected_flags,omitempty"`
+	// SkippedFlags lists operator-configured create flags the command does not accept
+	SkippedFlags []string `json:"skipped_flags,omitempty"`
+	// Flags lists every flag passed to the CLI when the echoed command line is elided
+	Flags []Flag `json:"flags,omitempty"`
+	// IdempotentReplay indicates the response was replayed from an identical recent create
+	IdempotentReplay bool `json:"idempotent_replay,omitempty"`
+	// TimeoutSeconds is the effective timeout the command ran with
+	TimeoutSeconds int `json:"timeout_seconds,omitempty"`
+	// Attempts is the number of times the command was run, including retries of transient failures
+	Attempts int `json:"attempts,omitempty"`
 }
 
 // PaginationInfo describes output that was truncated due to size limits.
@@ -78,6 +208,24 @@ type PaginationInfo struct {
 	TruncationNote string `json:"truncation_note,omitempty"`
 }
 
+// TruncationInfo is a machine-readable description of truncated output.
+type TruncationInfo struct {
+	// Truncated is always true when the object is present
+	Truncated bool `json:"truncated"`
+	// Kind is the shape of the truncated output ("array", "object", "text")
+	Kind string `json:"kind"`
+	// Total is the full size of the output, measured in Unit
+	Total int `json:"total"`
+	// Returned is how much of the output is included in the response, measured in Unit
+	Returned int `json:"returned"`
+	// Unit is what Total and Returned count ("items", "bytes", "lines")
+	Unit string `json:"unit"`
+	// ResultID references the cached full output, when it was cached
+	ResultID string `json:"result_id,omitempty"`
+	// ContinueWith explains how to retrieve the rest of the output
+	ContinueWith string `json:"continue_with"`
+}
+
 // CacheMetadata contains information about a cached result.
 type CacheMetadata struct {
 	// ResultID is the unique identifier for the cached result
@@ -92,6 +240,92 @@ type CacheMetadata struct {
 	TotalLines int `json:"total_lines,omitempty"`
 }
 
+// PopsByRegion is the normalized output of the pops command.
+type PopsByRegion struct {
+	// Pops is the raw list of POPs as returned by the CLI
+	Pops []interface{} `json:"pops"`
+	// ByRegion maps each region to the codes of the POPs it contains
+	ByRegion map[string][]string `json:"by_region"`
+}
+
+// PurgeBatchResult is the output of a purge of a batch of surrogate keys.
+type PurgeBatchResult struct {
+	// Purged is the number of keys that were purged
+	Purged int `json:"purged"`
+	// Failed is the number of keys that were invalid or failed to purge
+	Failed int `json:"failed"`
+	// Results holds the outcome for each key, in request order
+	Results []PurgeKeyResult `json:"results"`
+}
+
+// PurgeKeyResult is the outcome of purging one surrogate key of a batch.
+type PurgeKeyResult struct {
+	// Key is the surrogate key
+	Key string `json:"key"`
+	// Success indicates whether the key was purged
+	Success bool `json:"success"`
+	// Output is the CLI output for the key, parsed as JSON when possible
+	Output interface{} `json:"output,omitempty"`
+	// Error describes why the key was not purged
+	Error string `json:"error,omitempty"`
+	// ErrorCode classifies the error, e.g. "invalid_surrogate_key"
+	ErrorCode string `json:"error_code,omitempty"`
+}
+
+// BatchResponse is the result of planning or executing a batch of commands.
+type BatchResponse struct {
+	// Success indicates whether the plan was made or every step succeeded
+	Success bool `json:"success"`
+	// PlanToken approves the plan; pass it back with the same steps to execute them
+	PlanToken string `json:"plan_token,omitempty"`
+	// Plan lists the resolved steps in execution order
+	Plan []BatchStep `json:"plan,omitempty"`
+	// Results holds the response of each executed step, in order
+	Results []CommandResponse `json:"results,omitempty"`
+	// Executed is the number of steps that were run
+	Executed int `json:"executed"`
+	// Skipped is the number of steps not run because an earlier step failed
+	Skipped int `json:"skipped,omitempty"`
+	// Error describes why the batch was refused or stopped
+	Error string `json:"error,omitempty"`
+	// ErrorCode classifies the error, e.g. "plan_token_required"
+	ErrorCode string `json:"error_code,omitempty"`
+	// Instructions provides AI-agent guidance for the next call
+	Instructions string `json:"instructions,omitempty"`
+	// NextSteps suggests follow-up actions
+	NextSteps []string `json:"next_steps,omitempty"`
+}
+
+// BatchStep is one resolved step of a batch plan.
+type BatchStep struct {
+	// Index is the position of the step in the batch, starting at 0
+	Index int `json:"index"`
+	// CommandLine is the command line the step runs, without MCP-only flags
+	CommandLine string `json:"command_line"`
+	// OperationType is the kind of operation ("read", "create", "update", "delete", "purge" or "unknown")
+	OperationType string `json:"operation_type"`
+	// Dangerous indicates the step would require human review on its own
+	Dangerous bool `json:"dangerous"`
+	// Destructive indicates the step deletes resources or data
+	Destructive bool `json:"destructive,omitempty"`
+	// Warning explains why the step is dangerous
+	Warning string `json:"warning,omitempty"`
+}
+
+// MetricPoint is a single normalized stats sample for one metric.
+type MetricPoint struct {
+	// Timestamp is the start of the sample period as a Unix timestamp in seconds
+	Timestamp int64 `json:"timestamp"`
+	// ServiceID identifies the service the sample belongs to, when known
+	ServiceID string `json:"service_id,omitempty"`
+	// Metric is the stats field name (e.g., "requests", "hit_ratio")
+	Metric string `json:"metric"`
+	// Value is the metric value for the sample period
+	Value float64 `json:"value"`
+	// Unit describes the value (e.g., "count", "bytes", "seconds", "ratio")
+	Unit string `json:"unit"`
+}
+
 // HelpInfo provides structured help documentation for a Fastly CLI command.
 type HelpInfo struct {
 	// Command is the full command string (e.g., "service list")
@@ -108,6 +342,8 @@ type HelpInfo struct {
 	RequiredFlags []FlagInfo `json:"required_flags,omitempty"`
 	// Flags lists all available flags for the command
 	Flags []FlagInfo `json:"flags,omitempty"`
+	// HiddenFlags lists normally-filtered flags, present only when explicitly requested
+	HiddenFlags []FlagInfo `json:"hidden_flags,omitempty"`
 	// Subcommands lists available subcommands
 	Subcommands []SubcommandInfo `json:"subcommands,omitempty"`
 	// Instructions provides guidance for AI agents using this command
@@ -118,6 +354,18 @@ type HelpInfo struct {
 	Category string `json:"category,omitempty"`
 	// ResourceType identifies the Fastly resource type
 	ResourceType string `json:"resource_type,omitempty"`
+	// Severity classifies the risk of the command ("info", "mutating", "destructive")
+	Severity string `json:"severity,omitempty"`
+	// RequiredScope is the Fastly API token scope needed to run the command
+	RequiredScope string `json:"required_scope,omitempty"`
+	// ScopeNote explains when the command needs a different scope
+	ScopeNote string `json:"scope_note,omitempty"`
+	// ServiceID is the resolved ID of the service given to describe, if any
+	ServiceID string `json:"service_id,omitempty"`
+	// SubcommandTree describes the nested subcommands, present only for a recursive describe
+	SubcommandTree []CatalogEntry `json:"subcommand_tree,omitempty"`
+	// SubcommandTreeTruncated indicates the tree was cut short by the help invocation limit
+	SubcommandTreeTruncated bool `json:"subcommand_tree_truncated,omitempty"`
 }
 
 // FlagInfo describes a command-line flag and its usage.
@@ -149,3 +397,95 @@ type CommandListResponse struct {
 	// NextSteps suggests how to explore and use the commands
 	NextSteps []string `json:"next_steps"`
 }
+
+// CommandCatalog is the combined, parsed description of all available commands.
+type CommandCatalog struct {
+	// Commands lists every top-level command with its subcommands
+	Commands []CatalogEntry `json:"commands"`
+}
+
+// CatalogEntry describes one command path within a CommandCatalog.
+type CatalogEntry struct {
+	// Name is the full command path (e.g., "service list")
+	Name string `json:"name"`
+	// Description explains what the command does
+	Description string `json:"description"`
+	// Category groups the command by functional area
+	Category string `json:"category,omitempty"`
+	// ResourceType identifies the Fastly resource type
+	ResourceType string `json:"resource_type,omitempty"`
+	// Dangerous indicates the command requires user review before execution
+	Dangerous bool `json:"dangerous"`
+	// Severity classifies the risk of the command ("info", "mutating", "destructive")
+	Severity string `json:"severity,omitempty"`
+	// Warning explains why the command is dangerous
+	Warning string `json:"warning,omitempty"`
+	// RequiredFlags lists mandatory flags for the command
+	RequiredFlags []FlagInfo `json:"required_flags,omitempty"`
+	// Flags lists optional flags for the command
+	Flags []FlagInfo `json:"flags,omitempty"`
+	// Subcommands lists nested command paths
+	Subcommands []CatalogEntry `json:"subcommands,omitempty"`
+}
+
+// ResourceDiff describes the differences between two lists of resources that
+// are matched by a key field (for example backends matched by name).
+type ResourceDiff struct {
+	// Added lists resources that only exist in the newer list
+	Added []interface{} `json:"added"`
+	// Removed lists resources that only exist in the older list
+	Removed []interface{} `json:"removed"`
+	// Changed lists resources present in both lists whose fields differ
+	Changed []ResourceChange `json:"changed"`
+	// Unchanged is the number of resources that are identical in both lists
+	Unchanged int `json:"unchanged"`
+}
+
+// ResourceChange describes a resource whose fields differ between two lists.
+type ResourceChange struct {
+	// Key is the value of the key field identifying the resource
+	Key string `json:"key"`
+	// Fields maps each differing field to its old and new values
+	Fields map[string]FieldChange `json:"fields"`
+}
+
+// FieldChange holds the old and new value of a single field.
+type FieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// VersionDiff is a structured comparison of two versions of a service.
+type VersionDiff struct {
+	ServiceID   string `json:"service_id"`
+	FromVersion string `json:"from_version"`
+	ToVersion   string `json:"to_version"`
+	// Sections maps a resource kind (e.g. "backends") to its differences
+	Sections map[string]ResourceDiff `json:"sections"`
+	// Identical is true when no section has any difference
+	Identical bool `json:"identical"`
+}
+
+// SetupDiagnosis is a step-by-step report of the Fastly CLI setup.
+type SetupDiagnosis struct {
+	// Healthy is true when no step failed
+	Healthy bool `json:"healthy"`
+	// FailedStep names the first step that failed
+	FailedStep string `json:"failed_step,omitempty"`
+	// CLIVersion is the detected Fastly CLI version, when 'fastly version' reported one
+	CLIVersion string `json:"cli_version,omitempty"`
+	// Steps holds the result of each step, in the order they ran
+	Steps []DiagnosticStep `json:"steps"`
+}
+
+// DiagnosticStep is the result of one setup diagnosis probe.
+type DiagnosticStep struct {
+	// Name identifies the step (e.g. "cli_installed", "authentication")
+	Name string `json:"name"`
+	// Status is "ok", "warning", "failed" or "skipped"
+	Status string `json:"status"`
+	// Detail describes what the step found
+	Detail string `json:"detail"`
+	// Fix explains how to resolve a failed step or warning
+	Fix string `json:"fix,omitempty"`
+}
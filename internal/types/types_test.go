@@ -70,3 +70,42 @@ func TestCommandResponseJSON(t *testing.T) {
 		t.Error("Metadata not properly marshaled/unmarshaled")
 	}
 }
+
+func TestForSchemaVersion(t *testing.T) {
+	resp := CommandResponse{
+		Success:           true,
+		Output:            "ok",
+		Command:           "backend",
+		CommandLine:       "fastly backend create --name origin",
+		Metadata:          &OperationMetadata{ResourceType: "backend", OperationType: "create", TimeoutSeconds: 30},
+		CreatedResource:   &CreatedResource{Type: "backend", Name: "origin"},
+		ConfirmationToken: "confirm-abc",
+	}
+
+	current := resp.ForSchemaVersion(CurrentSchemaVersion)
+	if current.SchemaVersion != CurrentSchemaVersion || current.CreatedResource == nil || current.Metadata.TimeoutSeconds != 30 {
+		t.Errorf("Expected the full current envelope, got %+v", current)
+	}
+
+	data, err := json.Marshal(resp.ForSchemaVersion(SchemaVersion1))
+	if err != nil {
+		t.Fatalf("Failed to marshal CommandResponse: %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal CommandResponse: %v", err)
+	}
+
+	if decoded["schema_version"] != float64(SchemaVersion1) || decoded["output"] != "ok" {
+		t.Errorf("Expected version 1 with its fields, got %v", decoded)
+	}
+	for _, field := range []string{"created_resource", "confirmation_token"} {
+		if _, found := decoded[field]; found {
+			t.Errorf("Expected %s to be omitted in version 1, got %v", field, decoded)
+		}
+	}
+	metadata := decoded["metadata"].(map[string]interface{})
+	if _, found := metadata["timeout_seconds"]; found || metadata["resource_type"] != "backend" {
+		t.Errorf("Expected version 1 metadata, got %v", metadata)
+	}
+}
@@ -15,6 +15,20 @@ var commandFormatRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
 // deniedCommandFormatRegex validates denied command format - allows command-subcommand combinations with space
 var deniedCommandFormatRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+( +[a-zA-Z0-9_-]+)?$`)
 
+// allowedWildcardRegex validates wildcard allowlist entries - a command prefix followed by *
+var allowedWildcardRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+\*$`)
+
+// AllowedWildcardSuffix marks an allowlist entry as a prefix rule: "tls-*"
+// allows every command starting with "tls-".
+const AllowedWildcardSuffix = "*"
+
+// DeniedPatternPrefix marks a denylist entry as a regular expression matched
+// against command paths, such as "regex:^tls-" or "regex:^[a-z-]+ delete$".
+const DeniedPatternPrefix = "regex:"
+
+// MaxDeniedPatternLength limits the length of a regex denylist entry
+const MaxDeniedPatternLength = 256
+
 // LoadAllowedCommandsFromFile loads a list of allowed commands from a file.
 // The file should contain one command per line, with optional comments and empty lines.
 //
@@ -29,9 +43,13 @@ var deniedCommandFormatRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+( +[a-zA-Z0-9_
 //	stats
 //	log-tail
 //
+//	# Every TLS command
+//	tls-*
+//
 // Lines starting with # are treated as comments and ignored.
 // Empty lines are also ignored.
-// Commands must contain only alphanumeric characters, hyphens, and underscores.
+// Commands must contain only alphanumeric characters, hyphens, and underscores,
+// optionally followed by a trailing * that allows any command with that prefix.
 func LoadAllowedCommandsFromFile(filename string) (map[string]bool, error) {
 	file, err := os.Open(filename)
 	if err != nil {
@@ -60,7 +78,7 @@ func LoadAllowedCommandsFromFile(filename string) (map[string]bool, error) {
 		}
 
 		// Basic validation - commands should only contain alphanumeric, hyphens, and underscores
-		if !commandFormatRegex.MatchString(line) {
+		if !commandFormatRegex.MatchString(line) && !allowedWildcardRegex.MatchString(line) {
 			return nil, fmt.Errorf("invalid command format on line %d: %s", lineNum, line)
 		}
 
@@ -81,10 +99,11 @@ func LoadAllowedCommandsFromFile(filename string) (map[string]bool, error) {
 // ParseAllowedCommands parses a comma-separated list of allowed commands.
 // The commands must follow the same validation rules as file-based commands:
 //   - Only alphanumeric characters, hyphens, and underscores allowed
+//   - A trailing * allows any command with the preceding prefix (e.g., "tls-*")
 //   - Maximum length of 50 characters per command
 //   - Empty commands (e.g., from "cmd1,,cmd2") are ignored
 //
-// Example input: "service,backend,stats,version"
+// Example input: "service,backend,stats,version,tls-*"
 // Returns a map of command names for quick lookup.
 func ParseAllowedCommands(cmdList string) (map[string]bool, error) {
 	if cmdList == "" {
@@ -109,7 +128,7 @@ func ParseAllowedCommands(cmdList string) (map[string]bool, error) {
 		}
 
 		// Check format - commands should only contain alphanumeric, hyphens, and underscores
-		if !commandFormatRegex.MatchString(cmd) {
+		if !commandFormatRegex.MatchString(cmd) && !allowedWildcardRegex.MatchString(cmd) {
 			return nil, fmt.Errorf("invalid command format at position %d: %s", i+1, cmd)
 		}
 
@@ -134,9 +153,15 @@ func ParseAllowedCommands(cmdList string) (map[string]bool, error) {
 //	# Blocked individual commands
 //	some-dangerous-cmd
 //
+//	# Every delete subcommand and every TLS command
+//	regex:^[a-z0-9-]+ delete$
+//	regex:^tls-
+//
 // Lines starting with # are treated as comments and ignored.
 // Empty lines are also ignored.
 // Commands can be single commands or command-subcommand combinations separated by a space.
+// Lines starting with "regex:" hold a regular expression that IsDenied matches against
+// each command path ("a", "a b", ...); anchor it to avoid matching inside a path.
 func LoadDeniedCommandsFromFile(filename string) (map[string]bool, error) {
 	file, err := os.Open(filename)
 	if err != nil {
@@ -159,6 +184,16 @@ func LoadDeniedCommandsFromFile(filename string) (map[string]bool, error) {
 			continue
 		}
 
+		// Pattern entries are kept with their prefix and compiled by the validator
+		if pattern, ok := strings.CutPrefix(line, DeniedPatternPrefix); ok {
+			entry, err := deniedPatternEntry(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("pattern on line %d: %w", lineNum, err)
+			}
+			deniedCommands[entry] = true
+			continue
+		}
+
 		// Validate command format
 		if len(line) > MaxCommandLength*2+1 { // Allow for command + space + subcommand
 			return nil, fmt.Errorf("command on line %d exceeds maximum length", lineNum)
@@ -179,6 +214,19 @@ func LoadDeniedCommandsFromFile(filename string) (map[string]bool, error) {
 	return deniedCommands, nil
 }
 
+// deniedPatternEntry checks the regular expression of a pattern entry and
+// returns the entry with its DeniedPatternPrefix.
+func deniedPatternEntry(pattern string) (string, error) {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" || len(pattern) > MaxDeniedPatternLength {
+		return "", fmt.Errorf("must be between 1 and %d characters", MaxDeniedPatternLength)
+	}
+	if _, err := regexp.Compile(pattern); err != nil {
+		return "", fmt.Errorf("invalid pattern: %w", err)
+	}
+	return DeniedPatternPrefix + pattern, nil
+}
+
 // ParseDeniedCommands parses a comma-separated list of denied commands.
 // Commands can be single commands or command-subcommand combinations.
 //
@@ -220,3 +268,37 @@ func ParseDeniedCommands(cmdList string) (map[string]bool, error) {
 
 	return deniedCommands, nil
 }
+
+// ParseDisabledFamilies parses a comma-separated list of command families to
+// disable, such as "tools,object-storage". Each family must be a default
+// top-level command. The returned denylist entries name the family itself,
+// which IsDenied matches as a prefix of every subcommand in it.
+func ParseDisabledFamilies(familyList string) (map[string]bool, error) {
+	if familyList == "" {
+		return nil, fmt.Errorf("family list is empty")
+	}
+
+	knownFamilies := defaultAllowedCommands()
+	families := make(map[string]bool)
+	for i, family := range strings.Split(familyList, ",") {
+		family = strings.TrimSpace(family)
+		if family == "" {
+			continue
+		}
+
+		if !commandFormatRegex.MatchString(family) {
+			return nil, fmt.Errorf("invalid family format at position %d: %s", i+1, family)
+		}
+		if !knownFamilies[family] {
+			return nil, fmt.Errorf("unknown command family at position %d: %s", i+1, family)
+		}
+
+		families[family] = true
+	}
+
+	if len(families) == 0 {
+		return nil, fmt.Errorf("no valid families found in list")
+	}
+
+	return families, nil
+}
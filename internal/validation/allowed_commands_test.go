@@ -184,6 +184,30 @@ another-valid`,
 			wantErr: true,
 			errMsg:  "invalid command format",
 		},
+		{
+			name: "file with patterns",
+			content: `service delete
+regex:^tls-
+regex: ^[a-z0-9-]+ delete$
+`,
+			want: map[string]bool{
+				"service delete":            true,
+				"regex:^tls-":               true,
+				"regex:^[a-z0-9-]+ delete$": true,
+			},
+		},
+		{
+			name:    "invalid pattern",
+			content: `regex:^tls-(`,
+			wantErr: true,
+			errMsg:  "pattern on line 1: invalid pattern",
+		},
+		{
+			name:    "empty pattern",
+			content: `regex:`,
+			wantErr: true,
+			errMsg:  "pattern on line 1",
+		},
 		{
 			name:    "command too long",
 			content: strings.Repeat("a", 102),
@@ -270,3 +294,82 @@ func TestDeniedCommandFormatRegex(t *testing.T) {
 		})
 	}
 }
+
+func TestParseDisabledFamilies(t *testing.T) {
+	families, err := ParseDisabledFamilies("tools, object-storage")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	denied := DefaultDeniedCommands()
+	for family := range families {
+		denied[family] = true
+	}
+	v := NewValidatorWithCommandsAndDenied(DefaultAllowedCommands(), denied)
+
+	for _, cmd := range [][]string{
+		{"tools", "domain", "suggest"},
+		{"object-storage", "access-keys", "list"},
+		{"object-storage"},
+	} {
+		if !v.IsDenied(cmd[0], cmd[1:]) {
+			t.Errorf("Expected %q to be denied", strings.Join(cmd, " "))
+		}
+	}
+	if v.IsDenied("service", []string{"list"}) || v.IsDenied("kv-store", []string{"list"}) {
+		t.Error("Expected commands outside the disabled families to stay allowed")
+	}
+	if !v.IsDenied("stats", []string{"realtime"}) {
+		t.Error("Expected the default denylist to still apply")
+	}
+
+	for _, input := range []string{"", ",", "tools,unknown-family", "object-storage list"} {
+		if _, err := ParseDisabledFamilies(input); err == nil {
+			t.Errorf("Expected an error for %q", input)
+		}
+	}
+}
+
+func TestWildcardAllowedCommands(t *testing.T) {
+	allowed, err := ParseAllowedCommands("service, tls-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !allowed["tls-*"] {
+		t.Fatalf("Expected the wildcard entry to be kept, got %v", allowed)
+	}
+
+	denied := map[string]bool{"tls-subscription delete": true, "tls-platform": true}
+	v := NewValidatorWithCommandsAndDenied(allowed, denied)
+
+	for _, command := range []string{"service", "tls-subscription", "tls-custom", "tls-platform"} {
+		if err := v.ValidateCommand(command); err != nil {
+			t.Errorf("Expected %q to be allowed, got %v", command, err)
+		}
+	}
+	for _, command := range []string{"tls", "backend", "mtls-config", "tls-*"} {
+		if err := v.ValidateCommand(command); err == nil {
+			t.Errorf("Expected %q to be rejected", command)
+		}
+	}
+
+	// The denylist overrides wildcard allows
+	if v.IsDenied("tls-subscription", []string{"list"}) {
+		t.Error("Expected tls-subscription list to stay allowed")
+	}
+	if !v.IsDenied("tls-subscription", []string{"delete"}) || !v.IsDenied("tls-platform", []string{"list"}) {
+		t.Error("Expected the denylist to apply to wildcard-allowed commands")
+	}
+
+	// An exact entry takes precedence over a wildcard
+	v = NewValidatorWithCommandsAndDenied(map[string]bool{"tls-*": true, "tls-custom": false}, nil)
+	if err := v.ValidateCommand("tls-custom"); err == nil {
+		t.Error("Expected the exact entry to override the wildcard")
+	}
+
+	for _, input := range []string{"*", "tls*-", "tls-**", "tls *"} {
+		if _, err := ParseAllowedCommands(input); err == nil {
+			t.Errorf("Expected an error for %q", input)
+		}
+	}
+}
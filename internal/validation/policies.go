@@ -0,0 +1,117 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SourcePolicy marks entries of a named policy loaded with --policies-file
+const SourcePolicy = "policy"
+
+// PolicyFile is the format of a policies file: named policies, each with its
+// own allowlist and denylist, and the policy used by clients that select none.
+//
+//	{
+//	  "default": "readonly",
+//	  "policies": {
+//	    "readonly": {"allowed_commands": ["service", "stats"], "denied_commands": ["service delete"]},
+//	    "platform": {"allowed_commands": ["service", "backend", "tls-*"], "denied_commands": ["regex:^\\S+ delete$"]}
+//	  }
+//	}
+type PolicyFile struct {
+	// Default names the policy of clients that select none; without it they are refused
+	Default string `json:"default,omitempty"`
+	// Policies maps policy names to their command lists
+	Policies map[string]Policy `json:"policies"`
+}
+
+// Policy holds the command lists of a named policy. Entries follow the rules
+// of --allowed-commands-file and --denied-commands-file, including wildcard
+// and regex: entries. An omitted list falls back to the default one.
+type Policy struct {
+	// AllowedCommands replaces the default allowlist
+	AllowedCommands []string `json:"allowed_commands,omitempty"`
+	// DeniedCommands replaces the default denylist
+	DeniedCommands []string `json:"denied_commands,omitempty"`
+}
+
+// LoadPolicies reads a policies file and builds a validator for each policy.
+// It returns the validators by policy name and the name of the default
+// policy, which is empty if the file sets none.
+func LoadPolicies(filename string) (map[string]*Validator, string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read policies file: %w", err)
+	}
+
+	var file PolicyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, "", fmt.Errorf("invalid policies file: %w", err)
+	}
+	if len(file.Policies) == 0 {
+		return nil, "", fmt.Errorf("no policies found in file")
+	}
+
+	validators := make(map[string]*Validator, len(file.Policies))
+	for name, policy := range file.Policies {
+		if !commandFormatRegex.MatchString(name) {
+			return nil, "", fmt.Errorf("invalid policy name %q: only alphanumeric characters, hyphens, and underscores are allowed", name)
+		}
+		v, err := policyValidator(policy)
+		if err != nil {
+			return nil, "", fmt.Errorf("policy %q: %w", name, err)
+		}
+		validators[name] = v
+	}
+
+	if file.Default != "" && validators[file.Default] == nil {
+		return nil, "", fmt.Errorf("default policy %q is not defined", file.Default)
+	}
+
+	return validators, file.Default, nil
+}
+
+// policyValidator builds the validator of a policy, recording which entries
+// came from the policy and which from the default lists.
+func policyValidator(policy Policy) (*Validator, error) {
+	allowed, allowedSource := defaultAllowedCommands(), SourceDefault
+	if len(policy.AllowedCommands) > 0 {
+		allowed, allowedSource = make(map[string]bool, len(policy.AllowedCommands)), SourcePolicy
+		for _, entry := range policy.AllowedCommands {
+			entry = strings.TrimSpace(entry)
+			if len(entry) > MaxCommandLength {
+				return nil, fmt.Errorf("allowed command %q exceeds maximum length of %d characters", entry, MaxCommandLength)
+			}
+			if !commandFormatRegex.MatchString(entry) && !allowedWildcardRegex.MatchString(entry) {
+				return nil, fmt.Errorf("invalid allowed command format: %q", entry)
+			}
+			allowed[entry] = true
+		}
+	}
+
+	denied, deniedSource := defaultDeniedCommands(), SourceDefault
+	if len(policy.DeniedCommands) > 0 {
+		denied, deniedSource = make(map[string]bool, len(policy.DeniedCommands)), SourcePolicy
+		for _, entry := range policy.DeniedCommands {
+			entry = strings.TrimSpace(entry)
+			if pattern, ok := strings.CutPrefix(entry, DeniedPatternPrefix); ok {
+				patternEntry, err := deniedPatternEntry(pattern)
+				if err != nil {
+					return nil, fmt.Errorf("denied pattern %q: %w", entry, err)
+				}
+				denied[patternEntry] = true
+				continue
+			}
+			if len(entry) > MaxCommandLength*2+1 || !deniedCommandFormatRegex.MatchString(entry) {
+				return nil, fmt.Errorf("invalid denied command format: %q", entry)
+			}
+			denied[entry] = true
+		}
+	}
+
+	v := NewValidatorWithCommandsAndDenied(allowed, denied)
+	v.SetCommandSources(RecordSources(nil, allowed, allowedSource), RecordSources(nil, denied, deniedSource))
+	return v, nil
+}
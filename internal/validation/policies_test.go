@@ -0,0 +1,90 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writePolicies(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policies.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadPolicies(t *testing.T) {
+	path := writePolicies(t, `{
+		"default": "readonly",
+		"policies": {
+			"readonly": {"allowed_commands": ["service", "stats"], "denied_commands": ["service delete"]},
+			"platform": {"allowed_commands": ["service", "tls-*"], "denied_commands": ["regex:^tls-platform"]},
+			"defaults": {}
+		}
+	}`)
+
+	policies, defaultPolicy, err := LoadPolicies(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if defaultPolicy != "readonly" || len(policies) != 3 {
+		t.Fatalf("Expected three policies with the readonly default, got %d and %q", len(policies), defaultPolicy)
+	}
+
+	// The same command is allowed under one policy and denied under another
+	if !policies["readonly"].IsDenied("service", []string{"delete"}) || policies["platform"].IsDenied("service", []string{"delete"}) {
+		t.Error("Expected service delete to be denied only by the readonly policy")
+	}
+	if reason := policies["readonly"].DeniedReason("service", []string{"delete"}); reason != "denied by the policy denylist" {
+		t.Errorf("Expected the policy denylist to be named, got %q", reason)
+	}
+	if err := policies["readonly"].ValidateCommand("tls-subscription"); err == nil || !strings.Contains(err.Error(), "not in the policy allowlist") {
+		t.Errorf("Expected tls-subscription outside the readonly allowlist, got %v", err)
+	}
+	if err := policies["platform"].ValidateCommand("tls-subscription"); err != nil {
+		t.Errorf("Expected the platform wildcard to allow tls-subscription, got %v", err)
+	}
+	if !policies["platform"].IsDenied("tls-platform", []string{"update"}) {
+		t.Error("Expected the platform pattern to deny tls-platform")
+	}
+
+	// Omitted lists fall back to the defaults
+	if err := policies["defaults"].ValidateCommand("backend"); err != nil {
+		t.Errorf("Expected the default allowlist, got %v", err)
+	}
+	if !policies["defaults"].IsDenied("stats", []string{"realtime"}) {
+		t.Error("Expected the default denylist")
+	}
+}
+
+func TestLoadPoliciesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		errMsg  string
+	}{
+		{"invalid JSON", `{"policies": [`, "invalid policies file"},
+		{"no policies", `{"policies": {}}`, "no policies found"},
+		{"invalid name", `{"policies": {"team a": {}}}`, "invalid policy name"},
+		{"unknown default", `{"default": "other", "policies": {"team": {}}}`, "default policy \"other\" is not defined"},
+		{"invalid allowed command", `{"policies": {"team": {"allowed_commands": ["service;rm"]}}}`, "invalid allowed command format"},
+		{"invalid denied command", `{"policies": {"team": {"denied_commands": ["service delete now please"]}}}`, "invalid denied command format"},
+		{"invalid pattern", `{"policies": {"team": {"denied_commands": ["regex:^tls-("]}}}`, "invalid pattern"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := LoadPolicies(writePolicies(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Expected an error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+
+	if _, _, err := LoadPolicies(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
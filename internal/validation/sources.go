@@ -0,0 +1,76 @@
+package validation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Sources of allowlist and denylist entries, reported with the decisions they
+// governed so operators can debug merged configurations.
+const (
+	// SourceDefault marks entries of the built-in lists
+	SourceDefault = "default"
+	// SourceFile marks entries loaded from --allowed-commands-file or --denied-commands-file
+	SourceFile = "file"
+	// SourceInline marks entries given with --allowed-commands or --denied-commands
+	SourceInline = "inline"
+	// SourceDisabledFamily marks families denied with --disable-family
+	SourceDisabledFamily = "disable-family"
+)
+
+// RecordSources notes source as the origin of each command that has none yet,
+// so that the first list naming a command is reported for it. It returns the
+// updated map, allocating it if needed.
+func RecordSources(sources map[string]string, commands map[string]bool, source string) map[string]string {
+	if sources == nil {
+		sources = make(map[string]string, len(commands))
+	}
+	for command := range commands {
+		if _, exists := sources[command]; !exists {
+			sources[command] = source
+		}
+	}
+	return sources
+}
+
+// SetCommandSources records where the validator's allowlist and denylist
+// entries came from. Entries without a recorded source are reported without
+// one.
+func (v *Validator) SetCommandSources(allowed, denied map[string]string) {
+	v.allowedSources = allowed
+	v.deniedSources = denied
+}
+
+// DeniedReason describes which list denied a command, such as "denied by the
+// inline denylist", or returns an empty string if the command is not denied or
+// the source of the entry is unknown.
+func (v *Validator) DeniedReason(command string, args []string) string {
+	path, entry := v.deniedEntry(command, args)
+	if entry == "" {
+		return ""
+	}
+	switch source := v.deniedSources[entry]; source {
+	case "":
+		return ""
+	case SourceDisabledFamily:
+		return fmt.Sprintf("disabled with --disable-family %s", path)
+	default:
+		return fmt.Sprintf("denied by the %s denylist", source)
+	}
+}
+
+// allowlistSources describes where the allowlist came from, e.g. "default" or
+// "file or inline", for commands it does not list.
+func (v *Validator) allowlistSources() string {
+	seen := make(map[string]bool)
+	for _, source := range v.allowedSources {
+		seen[source] = true
+	}
+	sources := make([]string, 0, len(seen))
+	for source := range seen {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	return strings.Join(sources, " or ")
+}
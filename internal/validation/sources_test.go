@@ -0,0 +1,59 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDeniedReason(t *testing.T) {
+	if reason := NewValidator().DeniedReason("stats", []string{"realtime"}); reason != "denied by the default denylist" {
+		t.Errorf("Expected the default denylist, got %q", reason)
+	}
+
+	fileDenied := map[string]bool{"service delete": true}
+	inlineDenied := map[string]bool{"service delete": true, "backend create": true}
+	families := map[string]bool{"tools": true}
+	denied := map[string]bool{"service delete": true, "backend create": true, "tools": true}
+
+	sources := RecordSources(nil, fileDenied, SourceFile)
+	sources = RecordSources(sources, inlineDenied, SourceInline)
+	sources = RecordSources(sources, families, SourceDisabledFamily)
+
+	v := NewValidatorWithCommandsAndDenied(DefaultAllowedCommands(), denied)
+	v.SetCommandSources(nil, sources)
+
+	tests := []struct {
+		command string
+		args    []string
+		reason  string
+	}{
+		{"service", []string{"delete"}, "denied by the file denylist"},
+		{"backend", []string{"create"}, "denied by the inline denylist"},
+		{"tools", []string{"domain", "suggest"}, "disabled with --disable-family tools"},
+		{"service", []string{"list"}, ""},
+	}
+	for _, tt := range tests {
+		if reason := v.DeniedReason(tt.command, tt.args); reason != tt.reason {
+			t.Errorf("DeniedReason(%s %v) = %q, want %q", tt.command, tt.args, reason, tt.reason)
+		}
+	}
+
+	// Without recorded sources no reason is given
+	if reason := NewValidatorWithCommandsAndDenied(nil, denied).DeniedReason("service", []string{"delete"}); reason != "" {
+		t.Errorf("Expected no reason without sources, got %q", reason)
+	}
+}
+
+func TestValidateCommandNamesAllowlistSource(t *testing.T) {
+	if err := NewValidator().ValidateCommand("not-a-command"); err == nil || !strings.Contains(err.Error(), "not in the default allowlist") {
+		t.Errorf("Expected the default allowlist to be named, got %v", err)
+	}
+
+	allowed := map[string]bool{"service": true, "version": true}
+	v := NewValidatorWithCommandsAndDenied(allowed, nil)
+	sources := RecordSources(nil, map[string]bool{"service": true}, SourceFile)
+	v.SetCommandSources(RecordSources(sources, map[string]bool{"version": true}, SourceInline), nil)
+	if err := v.ValidateCommand("backend"); err == nil || !strings.Contains(err.Error(), "not in the file or inline allowlist") {
+		t.Errorf("Expected the merged allowlist sources to be named, got %v", err)
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"sort"
 	"strings"
 )
 
@@ -31,6 +32,8 @@ const (
 	MaxFlagValueLength = 500
 	// MaxPathLength prevents excessively long file paths that could cause issues
 	MaxPathLength = 256
+	// MaxFlagLines limits the number of lines accepted for a multi-line flag value
+	MaxFlagLines = 5000
 )
 
 // Validator provides comprehensive input validation for security.
@@ -39,8 +42,15 @@ const (
 type Validator struct {
 	// allowedCommands is the allowlist of permitted Fastly commands
 	allowedCommands map[string]bool
+	// allowedPrefixes are the prefixes of the wildcard allowlist entries
+	allowedPrefixes []string
 	// deniedCommands is the denylist of forbidden command-subcommand combinations
 	deniedCommands map[string]bool
+	// deniedPatterns are the regex denylist entries, matched against command paths
+	deniedPatterns []*regexp.Regexp
+	// allowedSources and deniedSources record the list each entry came from
+	allowedSources map[string]string
+	deniedSources  map[string]string
 	// shellMetaChars contains dangerous shell metacharacters to block
 	shellMetaChars []string
 	// flagNameRegex validates flag name format
@@ -56,12 +66,17 @@ type Validator struct {
 //
 // Only commands explicitly listed in allowedCommands can be executed.
 func NewValidator() *Validator {
-	return NewValidatorWithCommandsAndDenied(defaultAllowedCommands(), defaultDeniedCommands())
+	allowed, denied := defaultAllowedCommands(), defaultDeniedCommands()
+	v := NewValidatorWithCommandsAndDenied(allowed, denied)
+	v.SetCommandSources(RecordSources(nil, allowed, SourceDefault), RecordSources(nil, denied, SourceDefault))
+	return v
 }
 
 // NewValidatorWithCommandsAndDenied creates a validator with custom allowed and denied commands.
 // The denied commands take precedence over allowed commands and represent command-subcommand
-// combinations that should be blocked (e.g., "stats realtime").
+// combinations that should be blocked (e.g., "stats realtime"). Denied entries with the
+// DeniedPatternPrefix are regular expressions matched against command paths; entries
+// that do not compile are ignored, as LoadDeniedCommandsFromFile already rejects them.
 func NewValidatorWithCommandsAndDenied(allowedCommands, deniedCommands map[string]bool) *Validator {
 	shellMetaChars := []string{
 		";", "|", "&", "&&", "||", "`", "$", "(", ")", "<", ">", ">>", "<<",
@@ -81,13 +96,48 @@ func NewValidatorWithCommandsAndDenied(allowedCommands, deniedCommands map[strin
 
 	return &Validator{
 		allowedCommands:  allowedCommands,
+		allowedPrefixes:  allowedWildcardPrefixes(allowedCommands),
 		deniedCommands:   deniedCommands,
+		deniedPatterns:   compileDeniedPatterns(deniedCommands),
 		shellMetaChars:   shellMetaChars,
 		flagNameRegex:    regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9-]*$`),
 		controlCharRegex: regexp.MustCompile(`[\x01-\x08\x0B-\x0C\x0E-\x1F\x7F]`),
 	}
 }
 
+// allowedWildcardPrefixes returns the prefixes of the wildcard entries of an
+// allowlist, such as "tls-" for "tls-*".
+func allowedWildcardPrefixes(allowedCommands map[string]bool) []string {
+	var prefixes []string
+	for entry, allowed := range allowedCommands {
+		if prefix, ok := strings.CutSuffix(entry, AllowedWildcardSuffix); ok && allowed {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	sort.Strings(prefixes)
+	return prefixes
+}
+
+// compileDeniedPatterns compiles the pattern entries of a denylist, sorted so
+// that the first matching pattern reported for a command is stable.
+func compileDeniedPatterns(deniedCommands map[string]bool) []*regexp.Regexp {
+	var entries []string
+	for entry := range deniedCommands {
+		if strings.HasPrefix(entry, DeniedPatternPrefix) {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Strings(entries)
+
+	var patterns []*regexp.Regexp
+	for _, entry := range entries {
+		if re, err := regexp.Compile(strings.TrimPrefix(entry, DeniedPatternPrefix)); err == nil {
+			patterns = append(patterns, re)
+		}
+	}
+	return patterns
+}
+
 func cloneCommandMap(commands map[string]bool) map[string]bool {
 	if commands == nil {
 		return nil
@@ -202,6 +252,11 @@ func defaultAllowedCommands() map[string]bool {
 	}
 }
 
+// DefaultDeniedCommands returns a copy of the default denylist.
+func DefaultDeniedCommands() map[string]bool {
+	return cloneCommandMap(defaultDeniedCommands())
+}
+
 // defaultDeniedCommands returns the default denylist of command-subcommand combinations
 // that should be blocked. This list includes commands that may be unsafe or should
 // not be available through the MCP interface.
@@ -227,7 +282,7 @@ func defaultDeniedCommands() map[string]bool {
 //   - Is not empty
 //   - Does not exceed maximum length (50 characters)
 //   - Contains no null bytes
-//   - Exists in the allowed commands list
+//   - Exists in the allowed commands list or matches one of its wildcard entries
 //
 // This is the first line of defense against arbitrary command execution.
 func (v *Validator) ValidateCommand(command string) error {
@@ -236,14 +291,34 @@ func (v *Validator) ValidateCommand(command string) error {
 		return err
 	}
 
-	// Check allowlist
-	if !v.allowedCommands[command] {
+	// Check allowlist; an exact entry takes precedence over wildcard entries,
+	// which only match commands and never themselves
+	allowed, listed := v.allowedCommands[command]
+	if strings.HasSuffix(command, AllowedWildcardSuffix) {
+		allowed = false
+	} else if !listed {
+		allowed = v.hasAllowedPrefix(command)
+	}
+	if !allowed {
+		if sources := v.allowlistSources(); sources != "" {
+			return fmt.Errorf("command '%s' is not available (not in the %s allowlist)", command, sources)
+		}
 		return fmt.Errorf("command '%s' is not available", command)
 	}
 
 	return nil
 }
 
+// hasAllowedPrefix reports whether a command matches a wildcard allowlist entry.
+func (v *Validator) hasAllowedPrefix(command string) bool {
+	for _, prefix := range v.allowedPrefixes {
+		if strings.HasPrefix(command, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // ValidateArgs validates command arguments for safety.
 // Each argument is checked for:
 //   - Maximum length (100 characters)
@@ -287,6 +362,31 @@ func (v *Validator) ValidateFlagValue(value string) error {
 	return v.ValidateInput(value, MaxFlagValueLength, "flag value", true)
 }
 
+// ValidateFlagLines validates the lines of a multi-line flag value.
+// Each line is checked for length, null bytes, embedded line breaks and control
+// characters. Shell metacharacters are permitted because the joined content is
+// written to a file and never becomes part of the command line.
+func (v *Validator) ValidateFlagLines(lines []string) error {
+	if len(lines) > MaxFlagLines {
+		return fmt.Errorf("multi-line value exceeds maximum of %d lines", MaxFlagLines)
+	}
+
+	for i, line := range lines {
+		fieldName := fmt.Sprintf("line %d", i+1)
+		if err := v.ValidateInput(line, MaxFlagValueLength, fieldName, false); err != nil {
+			return err
+		}
+		if strings.ContainsAny(line, "\r\n") {
+			return fmt.Errorf("%s contains a line break; split it into separate lines", fieldName)
+		}
+		if v.controlCharRegex.MatchString(line) {
+			return fmt.Errorf("%s contains control characters", fieldName)
+		}
+	}
+
+	return nil
+}
+
 // ValidatePath validates a file path to prevent traversal attacks.
 // It blocks:
 //   - Paths exceeding 256 characters
@@ -401,44 +501,50 @@ func isWindowsDriveLetter(r rune) bool {
 
 // IsDenied checks if a command-args combination is in the denylist.
 // It progressively builds command paths (e.g., "a", "a b", "a b c", "a b c d") and checks
-// if any are explicitly denied. Returns true if the command should be blocked.
+// if any are explicitly denied or match a denied pattern. Returns true if the command
+// should be blocked.
 // For performance, we limit checking to a maximum depth of 4 levels (command + 3 args).
 func (v *Validator) IsDenied(command string, args []string) bool {
-	// Check the command alone first (level 1)
-	if v.deniedCommands[command] {
-		return true
-	}
-
-	// Progressively check deeper command paths (levels 2-4)
-	fullCommand := command
-	maxDepth := 3 // Check up to 3 arguments (total 4 levels)
-	for i := 0; i < len(args) && i < maxDepth; i++ {
-		fullCommand += " " + args[i]
-		if v.deniedCommands[fullCommand] {
-			return true
-		}
-	}
-
-	return false
+	_, entry := v.deniedEntry(command, args)
+	return entry != ""
 }
 
 // GetDeniedCommand returns the specific command path that was denied, or empty string if not denied.
+// A path denied by a pattern is followed by the pattern, e.g. "tls-subscription list (regex:^tls-)".
 // This is useful for generating accurate error messages.
 func (v *Validator) GetDeniedCommand(command string, args []string) string {
-	// Check the command alone first (level 1)
-	if v.deniedCommands[command] {
-		return command
+	path, entry := v.deniedEntry(command, args)
+	if entry != "" && entry != path {
+		return fmt.Sprintf("%s (%s)", path, entry)
 	}
+	return path
+}
 
-	// Progressively check deeper command paths (levels 2-4)
-	fullCommand := command
+// deniedEntry returns the denied command path and the denylist entry that
+// denied it, which is the path itself for exact entries, or empty strings if
+// the command is not denied. Exact entries are checked at every level before
+// any pattern, so that patterns cost nothing when the denylist has none.
+func (v *Validator) deniedEntry(command string, args []string) (string, string) {
+	// Progressively build the command paths (levels 1-4)
+	paths := []string{command}
 	maxDepth := 3 // Check up to 3 arguments (total 4 levels)
 	for i := 0; i < len(args) && i < maxDepth; i++ {
-		fullCommand += " " + args[i]
-		if v.deniedCommands[fullCommand] {
-			return fullCommand
+		paths = append(paths, paths[i]+" "+args[i])
+	}
+
+	for _, path := range paths {
+		if v.deniedCommands[path] {
+			return path, path
+		}
+	}
+
+	for _, path := range paths {
+		for _, pattern := range v.deniedPatterns {
+			if pattern.MatchString(path) {
+				return path, DeniedPatternPrefix + pattern.String()
+			}
 		}
 	}
 
-	return ""
+	return "", ""
 }
@@ -874,3 +874,75 @@ func TestArbitraryDepthDeniedCommands(t *testing.T) {
 		})
 	}
 }
+
+func TestDeniedPatterns(t *testing.T) {
+	deniedCommands := map[string]bool{
+		"stats realtime":                     true,
+		DeniedPatternPrefix + `^tls-`:        true,
+		DeniedPatternPrefix + `^\S+ delete$`: true,
+		DeniedPatternPrefix + `(`:            true, // Does not compile and is ignored
+	}
+
+	v := NewValidatorWithCommandsAndDenied(nil, deniedCommands)
+
+	tests := []struct {
+		name          string
+		command       string
+		args          []string
+		wantDenied    bool
+		wantDeniedCmd string
+	}{
+		{"literal entry", "stats", []string{"realtime"}, true, "stats realtime"},
+		{"prefix pattern", "tls-subscription", []string{"list"}, true, "tls-subscription (regex:^tls-)"},
+		{"subcommand pattern", "service", []string{"delete"}, true, "service delete (regex:^\\S+ delete$)"},
+		{"subcommand pattern with more args", "backend", []string{"delete", "origin"}, true, "backend delete (regex:^\\S+ delete$)"},
+		{"anchored pattern", "service", []string{"version", "delete"}, false, ""},
+		{"no match", "service", []string{"list"}, false, ""},
+		{"anchored prefix pattern", "mtls", []string{"list"}, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if isDenied := v.IsDenied(tt.command, tt.args); isDenied != tt.wantDenied {
+				t.Errorf("IsDenied(%q, %v) = %v, want %v", tt.command, tt.args, isDenied, tt.wantDenied)
+			}
+			if deniedCmd := v.GetDeniedCommand(tt.command, tt.args); deniedCmd != tt.wantDeniedCmd {
+				t.Errorf("GetDeniedCommand(%q, %v) = %q, want %q", tt.command, tt.args, deniedCmd, tt.wantDeniedCmd)
+			}
+		})
+	}
+
+	// The reason names the list the pattern came from
+	v.SetCommandSources(nil, RecordSources(nil, deniedCommands, SourceFile))
+	if reason := v.DeniedReason("tls-config", []string{"describe"}); reason != "denied by the file denylist" {
+		t.Errorf("Expected the file denylist, got %q", reason)
+	}
+}
+
+func TestValidateFlagLines(t *testing.T) {
+	v := NewValidator()
+
+	tests := []struct {
+		name    string
+		lines   []string
+		wantErr bool
+	}{
+		{"shell characters allowed", []string{"if (req.http.host) {", "  set req.http.x = \"$1\";", "}"}, false},
+		{"tab allowed", []string{"\tindented"}, false},
+		{"embedded newline", []string{"a\nb"}, true},
+		{"carriage return", []string{"a\rb"}, true},
+		{"null byte", []string{"a\x00b"}, true},
+		{"control character", []string{"a\x07b"}, true},
+		{"line too long", []string{strings.Repeat("a", MaxFlagValueLength+1)}, true},
+		{"too many lines", make([]string, MaxFlagLines+1), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.ValidateFlagLines(tt.lines)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateFlagLines() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}